@@ -31,6 +31,19 @@ func convertDatadogAgentSpec(src *DatadogAgentSpecAgentSpec, dst *v2alpha1.Datad
 		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).Name = &src.DaemonsetName
 	}
 
+	if src.KeepLabels != "" {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).KeepLabels = &src.KeepLabels
+	}
+
+	if src.KeepAnnotations != "" {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).KeepAnnotations = &src.KeepAnnotations
+	}
+
+	// src.DeploymentStrategy.ReconcileFrequency not forwarded as there is no equivalent in v2
+	if src.DeploymentStrategy != nil {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).UpdateStrategy = convertDeploymentStrategy(src.DeploymentStrategy)
+	}
+
 	if src.Config != nil {
 		if src.Config.SecurityContext != nil {
 			getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).SecurityContext = src.Config.SecurityContext
@@ -227,6 +240,28 @@ func convertDatadogAgentSpec(src *DatadogAgentSpecAgentSpec, dst *v2alpha1.Datad
 	convertSecurityAgentSpec(src.Security, dst)
 }
 
+func convertDeploymentStrategy(src *DaemonSetDeploymentStrategy) *v2alpha1.UpdateStrategy {
+	dst := &v2alpha1.UpdateStrategy{
+		Canary: src.Canary,
+	}
+	if src.UpdateStrategyType != nil {
+		dst.Type = v2alpha1.UpdateStrategyType(*src.UpdateStrategyType)
+	}
+
+	rollingUpdate := src.RollingUpdate
+	if dst.Type != v2alpha1.OnDeleteStrategyType && rollingUpdate != (DaemonSetRollingUpdateSpec{}) {
+		dst.RollingUpdate = &v2alpha1.RollingUpdate{
+			MaxUnavailable:            rollingUpdate.MaxUnavailable,
+			MaxPodSchedulerFailure:    rollingUpdate.MaxPodSchedulerFailure,
+			MaxParallelPodCreation:    rollingUpdate.MaxParallelPodCreation,
+			SlowStartIntervalDuration: rollingUpdate.SlowStartIntervalDuration,
+			SlowStartAdditiveIncrease: rollingUpdate.SlowStartAdditiveIncrease,
+		}
+	}
+
+	return dst
+}
+
 func convertAPMSpec(src *APMSpec, dst *v2alpha1.DatadogAgent) {
 	if src == nil {
 		return
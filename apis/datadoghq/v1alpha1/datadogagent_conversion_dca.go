@@ -114,6 +114,14 @@ func convertClusterAgentSpec(src *DatadogAgentSpecClusterAgentSpec, dst *v2alpha
 		getV2TemplateOverride(&dst.Spec, v2alpha1.ClusterAgentComponentName).Labels = src.AdditionalLabels
 	}
 
+	if src.KeepLabels != "" {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.ClusterAgentComponentName).KeepLabels = &src.KeepLabels
+	}
+
+	if src.KeepAnnotations != "" {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.ClusterAgentComponentName).KeepAnnotations = &src.KeepAnnotations
+	}
+
 	if src.PriorityClassName != "" {
 		getV2TemplateOverride(&dst.Spec, v2alpha1.ClusterAgentComponentName).PriorityClassName = &src.PriorityClassName
 	}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+)
+
+// DatadogAgentExtensionSpec defines the desired state of DatadogAgentExtension
+// +k8s:openapi-gen=true
+type DatadogAgentExtensionSpec struct {
+	// Order is used to sort the extensions applied to a DatadogAgent: extensions are applied
+	// by increasing order, then by name. Extensions are always applied after the operator features.
+	// +optional
+	Order int32 `json:"order,omitempty"`
+
+	// DatadogAgentSelector selects the DatadogAgents of the namespace this extension applies to.
+	// If not set, the extension applies to all the DatadogAgents of the namespace.
+	// +optional
+	DatadogAgentSelector *metav1.LabelSelector `json:"datadogAgentSelector,omitempty"`
+
+	// NodeAgent contains the configuration added to the Node Agent.
+	// +optional
+	NodeAgent *DatadogAgentExtensionComponent `json:"nodeAgent,omitempty"`
+
+	// ClusterAgent contains the configuration added to the Cluster Agent.
+	// +optional
+	ClusterAgent *DatadogAgentExtensionComponent `json:"clusterAgent,omitempty"`
+
+	// ClusterChecksRunner contains the configuration added to the Cluster Checks Runner.
+	// +optional
+	ClusterChecksRunner *DatadogAgentExtensionComponent `json:"clusterChecksRunner,omitempty"`
+}
+
+// DatadogAgentExtensionComponent contains the configuration an extension adds to a component.
+// +k8s:openapi-gen=true
+type DatadogAgentExtensionComponent struct {
+	// Required can be set to true to require the component, or to false to disable it.
+	// If not set, the extension doesn't change whether the component is deployed.
+	// +optional
+	Required *bool `json:"required,omitempty"`
+
+	// Containers lists the agent containers required by the extension.
+	// Env vars and volume mounts are added to these containers, or to all the containers of the component if empty.
+	// +optional
+	// +listType=set
+	Containers []commonv1.AgentContainerName `json:"containers,omitempty"`
+
+	// Env contains the environment variables added to the containers.
+	// An environment variable already set with a different value is not changed and reported as a conflict.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Volumes contains the volumes added to the pod.
+	// A volume already defined with a different source is not changed and reported as a conflict.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts contains the volume mounts added to the containers.
+	// A volume mount using an existing name or path is not changed and reported as a conflict.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// SidecarContainers contains the containers added to the pod.
+	// A container with the name of an existing container is not added and reported as a conflict.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SidecarContainers []corev1.Container `json:"sidecarContainers,omitempty"`
+
+	// PolicyRules contains the rules granted to the component's service account in the DatadogAgent namespace.
+	// +optional
+	// +listType=atomic
+	PolicyRules []rbacv1.PolicyRule `json:"policyRules,omitempty"`
+
+	// ClusterPolicyRules contains the rules granted to the component's service account at the cluster level.
+	// +optional
+	// +listType=atomic
+	ClusterPolicyRules []rbacv1.PolicyRule `json:"clusterPolicyRules,omitempty"`
+}
+
+// DatadogAgentExtension allows to add configuration to the DatadogAgents of its namespace
+// without changing the operator.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=datadogagentextensions,scope=Namespaced,shortName=ddaext
+// +kubebuilder:printcolumn:name="order",type="integer",JSONPath=".spec.order"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogAgentExtension struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatadogAgentExtensionSpec `json:"spec,omitempty"`
+}
+
+// DatadogAgentExtensionList contains a list of DatadogAgentExtension
+// +kubebuilder:object:root=true
+type DatadogAgentExtensionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogAgentExtension `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogAgentExtension{}, &DatadogAgentExtensionList{})
+}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogDowntimeSpec defines the desired state of DatadogDowntime
+// +k8s:openapi-gen=true
+type DatadogDowntimeSpec struct {
+	// Scope is the scope of the downtime, for instance env:prod or host:foo. Defaults to *, all the groups.
+	// +optional
+	// +listType=set
+	Scope []string `json:"scope,omitempty"`
+	// MonitorTags selects the monitors silenced by the downtime by their tags.
+	// All the monitors are silenced if neither MonitorTags nor MonitorRefs are set.
+	// +optional
+	// +listType=set
+	MonitorTags []string `json:"monitorTags,omitempty"`
+	// MonitorRefs selects the monitors silenced by the downtime by referencing DatadogMonitors of the namespace.
+	// A downtime is created in Datadog for each DatadogMonitor.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	MonitorRefs []DatadogDowntimeMonitorReference `json:"monitorRefs,omitempty"`
+	// Message is the message of the downtime, it can mention users and teams with @.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Start is the start of the downtime. The downtime starts when it's created if not set.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+	// End is the end of the downtime. The downtime never ends if not set.
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+	// Timezone is the timezone of the downtime, for instance Europe/Paris. Defaults to UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// Recurrence repeats the downtime, from Start to End, on a schedule.
+	// +optional
+	Recurrence *DatadogDowntimeRecurrence `json:"recurrence,omitempty"`
+	// MuteFirstRecoveryNotification mutes the first recovery notification of the monitors once the downtime ends.
+	// +optional
+	MuteFirstRecoveryNotification *bool `json:"muteFirstRecoveryNotification,omitempty"`
+	// DatadogAgentRollout ties the downtime to the rollouts of a DatadogAgent of the namespace: the downtime is created
+	// when a rollout of the DatadogAgent starts, and canceled when it completes. Start, End and Recurrence can't be set.
+	// +optional
+	DatadogAgentRollout *DatadogDowntimeAgentRollout `json:"datadogAgentRollout,omitempty"`
+}
+
+// DatadogDowntimeMonitorReference references a DatadogMonitor of the namespace of the DatadogDowntime
+// +k8s:openapi-gen=true
+type DatadogDowntimeMonitorReference struct {
+	// Name is the name of the DatadogMonitor.
+	Name string `json:"name"`
+}
+
+// DatadogDowntimeRecurrenceType is the unit of the period of a DatadogDowntimeRecurrence
+type DatadogDowntimeRecurrenceType string
+
+const (
+	// DatadogDowntimeRecurrenceTypeDays repeats the downtime every Period days
+	DatadogDowntimeRecurrenceTypeDays DatadogDowntimeRecurrenceType = "days"
+	// DatadogDowntimeRecurrenceTypeWeeks repeats the downtime every Period weeks, on the WeekDays
+	DatadogDowntimeRecurrenceTypeWeeks DatadogDowntimeRecurrenceType = "weeks"
+	// DatadogDowntimeRecurrenceTypeMonths repeats the downtime every Period months
+	DatadogDowntimeRecurrenceTypeMonths DatadogDowntimeRecurrenceType = "months"
+	// DatadogDowntimeRecurrenceTypeYears repeats the downtime every Period years
+	DatadogDowntimeRecurrenceTypeYears DatadogDowntimeRecurrenceType = "years"
+	// DatadogDowntimeRecurrenceTypeRrule repeats the downtime according to the Rrule
+	DatadogDowntimeRecurrenceTypeRrule DatadogDowntimeRecurrenceType = "rrule"
+)
+
+// DatadogDowntimeRecurrence defines the schedule of a recurring downtime
+// +k8s:openapi-gen=true
+type DatadogDowntimeRecurrence struct {
+	// Type is the unit of the period: days, weeks, months or years. Set to rrule to use the Rrule.
+	// +kubebuilder:validation:Enum=days;weeks;months;years;rrule
+	Type DatadogDowntimeRecurrenceType `json:"type"`
+	// Period repeats the downtime every Period Type, for instance every 2 weeks.
+	// +optional
+	Period *int32 `json:"period,omitempty"`
+	// WeekDays are the days of the week the downtime repeats on with the weeks type, for instance Mon or Tue.
+	// +optional
+	// +listType=set
+	WeekDays []string `json:"weekDays,omitempty"`
+	// Rrule is the recurrence rule (RFC 5545) of the rrule type, for instance FREQ=MONTHLY;BYSETPOS=3;BYDAY=WE.
+	// +optional
+	Rrule string `json:"rrule,omitempty"`
+	// UntilDate is the date the recurrence ends.
+	// +optional
+	UntilDate *metav1.Time `json:"untilDate,omitempty"`
+	// UntilOccurrences is the number of times the downtime repeats.
+	// +optional
+	UntilOccurrences *int32 `json:"untilOccurrences,omitempty"`
+}
+
+// DatadogDowntimeAgentRollout references the DatadogAgent whose rollouts are silenced
+// +k8s:openapi-gen=true
+type DatadogDowntimeAgentRollout struct {
+	// Name is the name of the DatadogAgent.
+	Name string `json:"name"`
+}
+
+// DatadogDowntimeStatus defines the observed state of DatadogDowntime
+// +k8s:openapi-gen=true
+type DatadogDowntimeStatus struct {
+	// Conditions Represents the latest available observations of a DatadogDowntime's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Downtimes are the downtimes created in Datadog, one per referenced DatadogMonitor.
+	// +optional
+	// +listType=atomic
+	Downtimes []DatadogDowntimeInstance `json:"downtimes,omitempty"`
+	// Active is true when one of the downtimes is currently silencing monitors.
+	// +optional
+	Active bool `json:"active,omitempty"`
+	// DowntimeLastForceSyncTime is the last time the downtimes were last force synced with the DatadogDowntime resource
+	// +optional
+	DowntimeLastForceSyncTime *metav1.Time `json:"downtimeLastForceSyncTime,omitempty"`
+	// CurrentHash tracks the hash of the current DatadogDowntimeSpec and of the referenced monitor IDs
+	// to know if the downtimes need an update
+	// +optional
+	CurrentHash string `json:"currentHash,omitempty"`
+}
+
+// DatadogDowntimeInstance describes a downtime created in Datadog
+// +k8s:openapi-gen=true
+type DatadogDowntimeInstance struct {
+	// ID is the downtime ID generated in Datadog.
+	ID int `json:"id"`
+	// MonitorName is the name of the DatadogMonitor silenced by the downtime, not set if MonitorRefs isn't used.
+	// +optional
+	MonitorName string `json:"monitorName,omitempty"`
+	// MonitorID is the ID of the monitor silenced by the downtime, not set if MonitorRefs isn't used.
+	// +optional
+	MonitorID int `json:"monitorID,omitempty"`
+	// Active is true when the downtime is currently silencing monitors.
+	// +optional
+	Active bool `json:"active,omitempty"`
+}
+
+const (
+	// DatadogDowntimeConditionTypeActive means the downtimes of the DatadogDowntime are in sync with Datadog
+	DatadogDowntimeConditionTypeActive = "Active"
+	// DatadogDowntimeConditionTypeError means the DatadogDowntime has an error
+	DatadogDowntimeConditionTypeError = "Error"
+)
+
+// DatadogDowntime allows to define and manage Downtimes from your Kubernetes Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogdowntimes,scope=Namespaced
+// +kubebuilder:printcolumn:name="active",type="boolean",JSONPath=".status.active"
+// +kubebuilder:printcolumn:name="last sync",type="string",format="date",JSONPath=".status.downtimeLastForceSyncTime"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogDowntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogDowntimeSpec   `json:"spec,omitempty"`
+	Status DatadogDowntimeStatus `json:"status,omitempty"`
+}
+
+// DatadogDowntimeList contains a list of DatadogDowntimes
+// +kubebuilder:object:root=true
+type DatadogDowntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogDowntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogDowntime{}, &DatadogDowntimeList{})
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// IsValidDatadogDowntime use to check if a DatadogDowntimeSpec is valid by checking
+// that the scope and the schedule of the downtime are consistent
+func IsValidDatadogDowntime(spec *DatadogDowntimeSpec) error {
+	var errs []error
+	if len(spec.MonitorTags) > 0 && len(spec.MonitorRefs) > 0 {
+		errs = append(errs, fmt.Errorf("spec.MonitorTags and spec.MonitorRefs can't be both defined"))
+	}
+
+	for _, ref := range spec.MonitorRefs {
+		if ref.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.MonitorRefs.Name must be defined"))
+		}
+	}
+
+	if spec.Start != nil && spec.End != nil && !spec.End.After(spec.Start.Time) {
+		errs = append(errs, fmt.Errorf("spec.End must be after spec.Start"))
+	}
+
+	if recurrence := spec.Recurrence; recurrence != nil {
+		if spec.Start == nil {
+			errs = append(errs, fmt.Errorf("spec.Start must be defined to use spec.Recurrence"))
+		}
+		if recurrence.Type == DatadogDowntimeRecurrenceTypeRrule {
+			if recurrence.Rrule == "" {
+				errs = append(errs, fmt.Errorf("spec.Recurrence.Rrule must be defined with the rrule type"))
+			}
+		} else if recurrence.Period == nil || *recurrence.Period <= 0 {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.Period must be defined with the %s type", recurrence.Type))
+		}
+		if len(recurrence.WeekDays) > 0 && recurrence.Type != DatadogDowntimeRecurrenceTypeWeeks {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.WeekDays can only be defined with the weeks type"))
+		}
+		if recurrence.UntilDate != nil && recurrence.UntilOccurrences != nil {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.UntilDate and spec.Recurrence.UntilOccurrences can't be both defined"))
+		}
+	}
+
+	if rollout := spec.DatadogAgentRollout; rollout != nil {
+		if rollout.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.DatadogAgentRollout.Name must be defined"))
+		}
+		if spec.Start != nil || spec.End != nil || spec.Recurrence != nil {
+			errs = append(errs, fmt.Errorf("spec.Start, spec.End and spec.Recurrence can't be defined with spec.DatadogAgentRollout"))
+		}
+		// A downtime created at each rollout must not silence all the monitors of the organization
+		if len(spec.Scope) == 0 {
+			errs = append(errs, fmt.Errorf("spec.Scope must be defined with spec.DatadogAgentRollout"))
+		}
+		if len(spec.MonitorTags) == 0 && len(spec.MonitorRefs) == 0 {
+			errs = append(errs, fmt.Errorf("spec.MonitorTags or spec.MonitorRefs must be defined with spec.DatadogAgentRollout"))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
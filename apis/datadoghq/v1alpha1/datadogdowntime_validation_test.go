@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestIsValidDatadogDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2022, time.June, 1, 22, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(2 * time.Hour))
+
+	testCases := []struct {
+		name    string
+		spec    *DatadogDowntimeSpec
+		wantErr string
+	}{
+		{
+			name: "minimum valid downtime",
+			spec: &DatadogDowntimeSpec{},
+		},
+		{
+			name: "weekly maintenance window",
+			spec: &DatadogDowntimeSpec{
+				Scope:       []string{"env:prod"},
+				MonitorTags: []string{"service:web"},
+				Start:       &start,
+				End:         &end,
+				Recurrence:  &DatadogDowntimeRecurrence{Type: DatadogDowntimeRecurrenceTypeWeeks, Period: apiutils.NewInt32Pointer(1), WeekDays: []string{"Sat", "Sun"}},
+			},
+		},
+		{
+			name: "monitor tags and refs",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"service:web"},
+				MonitorRefs: []DatadogDowntimeMonitorReference{{Name: "web-latency"}},
+			},
+			wantErr: "spec.MonitorTags and spec.MonitorRefs can't be both defined",
+		},
+		{
+			name:    "end before start",
+			spec:    &DatadogDowntimeSpec{Start: &end, End: &start},
+			wantErr: "spec.End must be after spec.Start",
+		},
+		{
+			name: "rrule recurrence without rule",
+			spec: &DatadogDowntimeSpec{
+				Start:      &start,
+				Recurrence: &DatadogDowntimeRecurrence{Type: DatadogDowntimeRecurrenceTypeRrule},
+			},
+			wantErr: "spec.Recurrence.Rrule must be defined with the rrule type",
+		},
+		{
+			name: "recurrence without start and period",
+			spec: &DatadogDowntimeSpec{
+				Recurrence: &DatadogDowntimeRecurrence{Type: DatadogDowntimeRecurrenceTypeDays},
+			},
+			wantErr: "[spec.Start must be defined to use spec.Recurrence, spec.Recurrence.Period must be defined with the days type]",
+		},
+		{
+			name: "rollout",
+			spec: &DatadogDowntimeSpec{
+				Scope:               []string{"kube_namespace:datadog"},
+				MonitorRefs:         []DatadogDowntimeMonitorReference{{Name: "agent-down"}},
+				DatadogAgentRollout: &DatadogDowntimeAgentRollout{Name: "datadog"},
+			},
+		},
+		{
+			name: "rollout with schedule",
+			spec: &DatadogDowntimeSpec{
+				Scope:               []string{"kube_namespace:datadog"},
+				MonitorTags:         []string{"team:containers"},
+				Start:               &start,
+				DatadogAgentRollout: &DatadogDowntimeAgentRollout{Name: "datadog"},
+			},
+			wantErr: "spec.Start, spec.End and spec.Recurrence can't be defined with spec.DatadogAgentRollout",
+		},
+		{
+			name: "rollout without scope and monitors",
+			spec: &DatadogDowntimeSpec{
+				DatadogAgentRollout: &DatadogDowntimeAgentRollout{Name: "datadog"},
+			},
+			wantErr: "[spec.Scope must be defined with spec.DatadogAgentRollout, spec.MonitorTags or spec.MonitorRefs must be defined with spec.DatadogAgentRollout]",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result := IsValidDatadogDowntime(test.spec)
+			if test.wantErr != "" {
+				assert.EqualError(t, result, test.wantErr)
+			} else {
+				assert.NoError(t, result)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ type DatadogMonitorSpec struct {
 	// Priority is an integer from 1 (high) to 5 (low) indicating alert severity
 	Priority int64 `json:"priority,omitempty"`
 	// Query is the Datadog monitor query
+	// The query of composite monitors can reference the other DatadogMonitors of the namespace by name, ${<name>},
+	// replaced with the IDs of their monitors.
 	Query string `json:"query,omitempty"`
 	// RestrictedRoles is a list of unique role identifiers to define which roles are allowed to edit the monitor.
 	// `restricted_roles` is the successor of `locked`. For more information about `locked` and `restricted_roles`,
@@ -33,6 +35,34 @@ type DatadogMonitorSpec struct {
 
 	// ControllerOptions are the optional parameters in the DatadogMonitor controller
 	ControllerOptions DatadogMonitorControllerOptions `json:"controllerOptions,omitempty"`
+
+	// AdoptMonitorID is the ID of an existing monitor to manage instead of creating a new one, for instance a monitor
+	// created in the Datadog UI. The monitor is updated with the DatadogMonitor spec. It's only used when the DatadogMonitor
+	// doesn't manage a monitor yet: a new monitor is created if the adopted monitor is deleted in Datadog.
+	// +optional
+	AdoptMonitorID int `json:"adoptMonitorID,omitempty"`
+
+	// Credentials reference the Secret containing the API and application keys used to manage the monitor,
+	// for instance to manage it in another Datadog organization. The operator keys are used if not set.
+	// +optional
+	Credentials *DatadogMonitorCredentials `json:"credentials,omitempty"`
+	// Site is the Datadog site of the organization of the Credentials, for instance datadoghq.eu.
+	// The operator site is used if not set.
+	// +optional
+	Site string `json:"site,omitempty"`
+}
+
+// DatadogMonitorCredentials references the Secret containing the Datadog API and application keys of a DatadogMonitor
+// +k8s:openapi-gen=true
+type DatadogMonitorCredentials struct {
+	// SecretName is the name of the Secret, in the DatadogMonitor namespace.
+	SecretName string `json:"secretName"`
+	// APIKeyName is the key of the API key in the Secret. Defaults to api_key.
+	// +optional
+	APIKeyName string `json:"apiKeyName,omitempty"`
+	// AppKeyName is the key of the application key in the Secret. Defaults to app_key.
+	// +optional
+	AppKeyName string `json:"appKeyName,omitempty"`
 }
 
 // DatadogMonitorType defines the type of monitor
@@ -63,6 +93,12 @@ const (
 	DatadogMonitorTypeAudit DatadogMonitorType = "audit alert"
 	// DatadogMonitorTypeComposite is the composite alert monitor type
 	DatadogMonitorTypeComposite DatadogMonitorType = "composite"
+	// DatadogMonitorTypeCIPipelines is the ci-pipelines alert monitor type
+	DatadogMonitorTypeCIPipelines DatadogMonitorType = "ci-pipelines alert"
+	// DatadogMonitorTypeCITests is the ci-tests alert monitor type
+	DatadogMonitorTypeCITests DatadogMonitorType = "ci-tests alert"
+	// DatadogMonitorTypeErrorTracking is the error-tracking alert monitor type
+	DatadogMonitorTypeErrorTracking DatadogMonitorType = "error-tracking alert"
 )
 
 // DatadogMonitorOptions define the optional parameters of a monitor
@@ -102,6 +138,125 @@ type DatadogMonitorOptions struct {
 	Thresholds *DatadogMonitorOptionsThresholds `json:"thresholds,omitempty"`
 	// A struct of the alerting time window options.
 	ThresholdWindows *DatadogMonitorOptionsThresholdWindows `json:"thresholdWindows,omitempty"`
+	// The number of times re-notification messages are sent on the current status at the renotifyInterval.
+	RenotifyOccurrences *int64 `json:"renotifyOccurrences,omitempty"`
+	// The monitor statuses re-notifying at the renotifyInterval: alert, warn and no data.
+	// +listType=set
+	RenotifyStatuses []DatadogMonitorRenotifyStatus `json:"renotifyStatuses,omitempty"`
+	// Time (in seconds) to allow a host to boot and applications to fully start before starting the evaluation of
+	// monitor results. Deprecated in favor of newGroupDelay.
+	NewHostDelay *int64 `json:"newHostDelay,omitempty"`
+	// The minimum number of locations in failure at the same time to trigger a synthetics alert.
+	MinLocationFailed *int64 `json:"minLocationFailed,omitempty"`
+	// The behavior of the monitor when data stops reporting, for the monitors with a formula and function query:
+	// show_no_data, show_and_notify_no_data, resolve or default. It replaces notifyNoData and noDataTimeframe.
+	// +optional
+	// +kubebuilder:validation:Enum=show_no_data;show_and_notify_no_data;resolve;default
+	OnMissingData string `json:"onMissingData,omitempty"`
+	// The time span after which the groups not reporting data are removed from the monitor, like 2d or 1w.
+	// +optional
+	GroupRetentionDuration string `json:"groupRetentionDuration,omitempty"`
+	// The tags of the groups notifying separately, the other groups of the query are aggregated: with a query
+	// grouped by service and env, notifyBy [service] sends one notification per service.
+	// +listType=set
+	NotifyBy []string `json:"notifyBy,omitempty"`
+	// The information included in the notifications: show_all, hide_query, hide_handles or hide_all.
+	// +optional
+	// +kubebuilder:validation:Enum=show_all;hide_query;hide_handles;hide_all
+	NotificationPresetName string `json:"notificationPresetName,omitempty"`
+	// The scheduling options of the monitor, to evaluate it over cumulative time windows.
+	SchedulingOptions *DatadogMonitorOptionsSchedulingOptions `json:"schedulingOptions,omitempty"`
+	// The queries of a formula and function query, for the ci-pipelines, ci-tests and rum alert monitors.
+	// The query of the monitor is a formula of their names.
+	// +listType=map
+	// +listMapKey=name
+	Variables []DatadogMonitorFormulaAndFunctionEventQueryDefinition `json:"variables,omitempty"`
+}
+
+// DatadogMonitorRenotifyStatus is a monitor status re-notifying at the renotifyInterval
+// +kubebuilder:validation:Enum=alert;warn;no data
+type DatadogMonitorRenotifyStatus string
+
+// DatadogMonitorOptionsSchedulingOptions is a struct of the scheduling options of a monitor
+type DatadogMonitorOptionsSchedulingOptions struct {
+	// The cumulative time window over which the monitor is evaluated. The query timeframe must be
+	// a cumulative one like current_1d.
+	EvaluationWindow *DatadogMonitorOptionsEvaluationWindow `json:"evaluationWindow,omitempty"`
+}
+
+// DatadogMonitorOptionsEvaluationWindow defines the start of a cumulative evaluation window
+type DatadogMonitorOptionsEvaluationWindow struct {
+	// The time of the day at which a one day cumulative window starts, in UTC, as HH:mm.
+	DayStarts *string `json:"dayStarts,omitempty"`
+	// The minute of the hour at which a one hour cumulative window starts.
+	HourStarts *int32 `json:"hourStarts,omitempty"`
+	// The day of the month at which a one month cumulative window starts.
+	MonthStarts *int32 `json:"monthStarts,omitempty"`
+}
+
+// DatadogMonitorFormulaAndFunctionEventQueryDefinition is an events platform query of a formula and function query
+type DatadogMonitorFormulaAndFunctionEventQueryDefinition struct {
+	// Name of the query for use in the formula.
+	Name string `json:"name"`
+	// Data source of the query: rum, ci_pipelines or ci_tests.
+	// +kubebuilder:validation:Enum=rum;ci_pipelines;ci_tests
+	DataSource string `json:"dataSource"`
+	// Compute options of the query.
+	Compute DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute `json:"compute"`
+	// Search options of the query.
+	// +optional
+	Search *DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch `json:"search,omitempty"`
+	// Names of the indexes to query, all the indexes if not set.
+	// +listType=set
+	Indexes []string `json:"indexes,omitempty"`
+	// Group by options of the query.
+	// +listType=atomic
+	GroupBy []DatadogMonitorFormulaAndFunctionEventQueryGroupBy `json:"groupBy,omitempty"`
+}
+
+// DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute defines the compute options of an events platform query
+type DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute struct {
+	// Aggregation method, like count, cardinality, avg or pc99.
+	// +kubebuilder:validation:Enum=count;cardinality;median;pc75;pc90;pc95;pc98;pc99;sum;min;max;avg
+	Aggregation string `json:"aggregation"`
+	// A time interval in milliseconds.
+	// +optional
+	Interval *int64 `json:"interval,omitempty"`
+	// Measurable attribute to compute.
+	// +optional
+	Metric *string `json:"metric,omitempty"`
+}
+
+// DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch defines the search options of an events platform query
+type DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch struct {
+	// Events search string.
+	Query string `json:"query"`
+}
+
+// DatadogMonitorFormulaAndFunctionEventQueryGroupBy defines a group by of an events platform query
+type DatadogMonitorFormulaAndFunctionEventQueryGroupBy struct {
+	// Event facet.
+	Facet string `json:"facet"`
+	// Number of groups to return.
+	// +optional
+	Limit *int64 `json:"limit,omitempty"`
+	// Options for sorting the groups.
+	// +optional
+	Sort *DatadogMonitorFormulaAndFunctionEventQueryGroupBySort `json:"sort,omitempty"`
+}
+
+// DatadogMonitorFormulaAndFunctionEventQueryGroupBySort defines the sort of the groups of an events platform query
+type DatadogMonitorFormulaAndFunctionEventQueryGroupBySort struct {
+	// Aggregation method, like count, cardinality, avg or pc99.
+	// +kubebuilder:validation:Enum=count;cardinality;median;pc75;pc90;pc95;pc98;pc99;sum;min;max;avg
+	Aggregation string `json:"aggregation"`
+	// Metric used for sorting the groups.
+	// +optional
+	Metric *string `json:"metric,omitempty"`
+	// Direction of the sort: asc or desc.
+	// +optional
+	// +kubebuilder:validation:Enum=asc;desc
+	Order string `json:"order,omitempty"`
 }
 
 // DatadogMonitorOptionsThresholds is a struct of the different monitor threshold values
@@ -132,8 +287,43 @@ type DatadogMonitorOptionsThresholdWindows struct {
 type DatadogMonitorControllerOptions struct {
 	// DisableRequiredTags disables the automatic addition of required tags to monitors.
 	DisableRequiredTags *bool `json:"disableRequiredTags,omitempty"`
+	// DeletionPolicy defines what happens to the monitor in Datadog when the DatadogMonitor is deleted:
+	// Delete deletes it, Orphan keeps it and tags it with orphaned:kubernetes instead of generated:kubernetes.
+	// Defaults to the deletion policy of the operator, Delete unless set with its datadogMonitorDeletionPolicy flag.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	DeletionPolicy DatadogMonitorDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// DriftPolicy defines what happens when the monitor is changed outside of the DatadogMonitor, for instance in the Datadog UI.
+	// The monitor is compared with the spec at each periodic sync: Revert reports the changes and reverts them,
+	// Report only reports them, and Ignore doesn't compare the monitor. The changes are reported in the DriftDetected
+	// condition and with an event. Defaults to Revert.
+	// +optional
+	// +kubebuilder:validation:Enum=Revert;Report;Ignore
+	DriftPolicy DatadogMonitorDriftPolicy `json:"driftPolicy,omitempty"`
 }
 
+// DatadogMonitorDeletionPolicy defines what happens to the monitor in Datadog when the DatadogMonitor is deleted
+type DatadogMonitorDeletionPolicy string
+
+const (
+	// DatadogMonitorDeletionPolicyDelete deletes the monitor in Datadog
+	DatadogMonitorDeletionPolicyDelete DatadogMonitorDeletionPolicy = "Delete"
+	// DatadogMonitorDeletionPolicyOrphan keeps the monitor in Datadog
+	DatadogMonitorDeletionPolicyOrphan DatadogMonitorDeletionPolicy = "Orphan"
+)
+
+// DatadogMonitorDriftPolicy defines what happens when the monitor is changed outside of the DatadogMonitor
+type DatadogMonitorDriftPolicy string
+
+const (
+	// DatadogMonitorDriftPolicyRevert reports the changes and reverts them
+	DatadogMonitorDriftPolicyRevert DatadogMonitorDriftPolicy = "Revert"
+	// DatadogMonitorDriftPolicyReport reports the changes and keeps them
+	DatadogMonitorDriftPolicyReport DatadogMonitorDriftPolicy = "Report"
+	// DatadogMonitorDriftPolicyIgnore neither reports nor reverts the changes
+	DatadogMonitorDriftPolicyIgnore DatadogMonitorDriftPolicy = "Ignore"
+)
+
 // DatadogMonitorStatus defines the observed state of DatadogMonitor
 type DatadogMonitorStatus struct {
 	// Conditions Represents the latest available observations of a DatadogMonitor's current state.
@@ -169,6 +359,21 @@ type DatadogMonitorStatus struct {
 	// CurrentHash tracks the hash of the current DatadogMonitorSpec to know
 	// if the Spec has changed and needs an update
 	CurrentHash string `json:"currentHash,omitempty"`
+
+	// Credentials are the credentials that manage the monitor in Datadog, and that delete it when the
+	// DatadogMonitor is deleted. Not set if the operator keys and site are used.
+	Credentials *DatadogMonitorCredentialsStatus `json:"credentials,omitempty"`
+}
+
+// DatadogMonitorCredentialsStatus describes the credentials managing a monitor in Datadog
+// +k8s:openapi-gen=true
+type DatadogMonitorCredentialsStatus struct {
+	// Secret references the Secret containing the keys, not set if the operator keys are used.
+	Secret *DatadogMonitorCredentials `json:"secret,omitempty"`
+	// Site is the Datadog site, not set if the operator site is used.
+	Site string `json:"site,omitempty"`
+	// Hash identifies the keys and the site, without exposing the keys.
+	Hash string `json:"hash,omitempty"`
 }
 
 // DatadogMonitorCondition describes the current state of a DatadogMonitor
@@ -204,6 +409,8 @@ const (
 	DatadogMonitorConditionTypeUpdated DatadogMonitorConditionType = "Updated"
 	// DatadogMonitorConditionTypeError means the DatadogMonitor has an error
 	DatadogMonitorConditionTypeError DatadogMonitorConditionType = "Error"
+	// DatadogMonitorConditionTypeDriftDetected means the monitor was changed outside of the DatadogMonitor
+	DatadogMonitorConditionTypeDriftDetected DatadogMonitorConditionType = "DriftDetected"
 )
 
 // DatadogMonitorState represents the overall DatadogMonitor state
@@ -31,5 +31,17 @@ func IsValidDatadogMonitor(spec *DatadogMonitorSpec) error {
 		errs = append(errs, fmt.Errorf("spec.Message must be defined"))
 	}
 
+	if spec.AdoptMonitorID < 0 {
+		errs = append(errs, fmt.Errorf("spec.AdoptMonitorID must be a monitor ID"))
+	}
+
+	if spec.Credentials != nil && spec.Credentials.SecretName == "" {
+		errs = append(errs, fmt.Errorf("spec.Credentials.SecretName must be defined"))
+	}
+
+	if spec.Site != "" && spec.Credentials == nil {
+		errs = append(errs, fmt.Errorf("spec.Credentials must be defined to use spec.Site"))
+	}
+
 	return utilserrors.NewAggregate(errs)
 }
@@ -39,6 +39,12 @@ func TestIsValidDatadogMonitor(t *testing.T) {
 		Type:  "metric alert",
 		Name:  "Test Monitor",
 	}
+	missingSecretName := minimumValid.DeepCopy()
+	missingSecretName.Credentials = &DatadogMonitorCredentials{APIKeyName: "api-key"}
+	siteWithoutCredentials := minimumValid.DeepCopy()
+	siteWithoutCredentials.Site = "datadoghq.eu"
+	invalidAdoptMonitorID := minimumValid.DeepCopy()
+	invalidAdoptMonitorID.AdoptMonitorID = -1
 
 	testCases := []struct {
 		name    string
@@ -69,6 +75,21 @@ func TestIsValidDatadogMonitor(t *testing.T) {
 			spec:    missingMessage,
 			wantErr: "spec.Message must be defined",
 		},
+		{
+			name:    "monitor credentials missing secret name",
+			spec:    missingSecretName,
+			wantErr: "spec.Credentials.SecretName must be defined",
+		},
+		{
+			name:    "monitor site without credentials",
+			spec:    siteWithoutCredentials,
+			wantErr: "spec.Credentials must be defined to use spec.Site",
+		},
+		{
+			name:    "monitor with invalid adopted monitor ID",
+			spec:    invalidAdoptMonitorID,
+			wantErr: "spec.AdoptMonitorID must be a monitor ID",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,190 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogSLOSpec defines the desired state of DatadogSLO
+// +k8s:openapi-gen=true
+type DatadogSLOSpec struct {
+	// Name is the name of the SLO.
+	Name string `json:"name"`
+	// Description is the description of the SLO, it supports Markdown.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Type is the type of the SLO: metric, computed from the good and total events of the Query,
+	// or monitor, computed from the uptime of monitors.
+	// +kubebuilder:validation:Enum=metric;monitor
+	Type DatadogSLOType `json:"type"`
+	// Query is the good and total events queries of a metric SLO.
+	// +optional
+	Query *DatadogSLOQuery `json:"query,omitempty"`
+	// MonitorIDs are the IDs of the monitors of a monitor SLO.
+	// +optional
+	// +listType=set
+	MonitorIDs []int64 `json:"monitorIDs,omitempty"`
+	// MonitorRefs references the DatadogMonitors of the namespace used by a monitor SLO, in addition to the MonitorIDs.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	MonitorRefs []DatadogSLOMonitorReference `json:"monitorRefs,omitempty"`
+	// Groups are the groups of a multi-alert monitor tracked by a monitor SLO using a single monitor, for instance env:prod.
+	// +optional
+	// +listType=set
+	Groups []string `json:"groups,omitempty"`
+	// Thresholds are the targets of the SLO, one per timeframe.
+	// +listType=map
+	// +listMapKey=timeframe
+	Thresholds []DatadogSLOThreshold `json:"thresholds"`
+	// Tags is the SLO tags, the tag generated:kubernetes is always added.
+	// +optional
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+}
+
+// DatadogSLOType is the type of a DatadogSLO
+type DatadogSLOType string
+
+const (
+	// DatadogSLOTypeMetric is a SLO computed from the good and total events of a query
+	DatadogSLOTypeMetric DatadogSLOType = "metric"
+	// DatadogSLOTypeMonitor is a SLO computed from the uptime of monitors
+	DatadogSLOTypeMonitor DatadogSLOType = "monitor"
+)
+
+// DatadogSLOQuery defines the good and total events queries of a metric SLO
+// +k8s:openapi-gen=true
+type DatadogSLOQuery struct {
+	// Numerator is the sum of the good events, for instance sum:requests.success{service:web}.as_count().
+	Numerator string `json:"numerator"`
+	// Denominator is the sum of the total events, for instance sum:requests.total{service:web}.as_count().
+	Denominator string `json:"denominator"`
+}
+
+// DatadogSLOMonitorReference references a DatadogMonitor of the namespace of the DatadogSLO
+// +k8s:openapi-gen=true
+type DatadogSLOMonitorReference struct {
+	// Name is the name of the DatadogMonitor.
+	Name string `json:"name"`
+}
+
+// DatadogSLOTimeframe is the rolling window of a DatadogSLOThreshold
+type DatadogSLOTimeframe string
+
+const (
+	// DatadogSLOTimeframe7d is a 7 days rolling window
+	DatadogSLOTimeframe7d DatadogSLOTimeframe = "7d"
+	// DatadogSLOTimeframe30d is a 30 days rolling window
+	DatadogSLOTimeframe30d DatadogSLOTimeframe = "30d"
+	// DatadogSLOTimeframe90d is a 90 days rolling window
+	DatadogSLOTimeframe90d DatadogSLOTimeframe = "90d"
+)
+
+// DatadogSLOThreshold defines the target of a DatadogSLO over a timeframe
+// +k8s:openapi-gen=true
+type DatadogSLOThreshold struct {
+	// Timeframe is the rolling window of the threshold: 7d, 30d or 90d.
+	// +kubebuilder:validation:Enum="7d";"30d";"90d"
+	Timeframe DatadogSLOTimeframe `json:"timeframe"`
+	// Target is the percentage of good events or uptime the SLO must meet over the timeframe, for instance 99.9.
+	Target string `json:"target"`
+	// Warning is a percentage above the Target, showing the SLO at risk when it isn't met.
+	// +optional
+	Warning *string `json:"warning,omitempty"`
+}
+
+// DatadogSLOStatus defines the observed state of DatadogSLO
+// +k8s:openapi-gen=true
+type DatadogSLOStatus struct {
+	// Conditions Represents the latest available observations of a DatadogSLO's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ID is the SLO ID generated in Datadog
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Creator is the identity of the SLO creator
+	// +optional
+	Creator string `json:"creator,omitempty"`
+	// Created is the time the SLO was created
+	// +optional
+	Created *metav1.Time `json:"created,omitempty"`
+	// MonitorIDs are the IDs of the monitors of the SLO, including the ones of the referenced DatadogMonitors
+	// +optional
+	// +listType=set
+	MonitorIDs []int64 `json:"monitorIDs,omitempty"`
+	// Thresholds reports the current SLI value and remaining error budget of each threshold of the SLO
+	// +optional
+	// +listType=map
+	// +listMapKey=timeframe
+	Thresholds []DatadogSLOThresholdStatus `json:"thresholds,omitempty"`
+	// SLILastUpdateTime is the last time the SLI values were read from Datadog
+	// +optional
+	SLILastUpdateTime *metav1.Time `json:"sliLastUpdateTime,omitempty"`
+	// SLOLastForceSyncTime is the last time the SLO was last force synced with the DatadogSLO resource
+	// +optional
+	SLOLastForceSyncTime *metav1.Time `json:"sloLastForceSyncTime,omitempty"`
+	// CurrentHash tracks the hash of the current DatadogSLOSpec and of the referenced monitor IDs
+	// to know if the SLO needs an update
+	// +optional
+	CurrentHash string `json:"currentHash,omitempty"`
+}
+
+// DatadogSLOThresholdStatus reports the state of the SLO over the timeframe of a threshold
+// +k8s:openapi-gen=true
+type DatadogSLOThresholdStatus struct {
+	// Timeframe is the rolling window of the threshold.
+	Timeframe DatadogSLOTimeframe `json:"timeframe"`
+	// SLI is the percentage of good events or uptime over the timeframe.
+	// +optional
+	SLI string `json:"sli,omitempty"`
+	// ErrorBudgetRemaining is the percentage of the error budget of the threshold remaining over the timeframe,
+	// negative when the target isn't met.
+	// +optional
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining,omitempty"`
+}
+
+const (
+	// DatadogSLOConditionTypeActive means the DatadogSLO is in sync with the SLO in Datadog
+	DatadogSLOConditionTypeActive = "Active"
+	// DatadogSLOConditionTypeError means the DatadogSLO has an error
+	DatadogSLOConditionTypeError = "Error"
+)
+
+// DatadogSLO allows to define and manage Service Level Objectives from your Kubernetes Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogslos,scope=Namespaced
+// +kubebuilder:printcolumn:name="id",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="timeframe",type="string",JSONPath=".status.thresholds[0].timeframe"
+// +kubebuilder:printcolumn:name="sli",type="string",JSONPath=".status.thresholds[0].sli"
+// +kubebuilder:printcolumn:name="error budget",type="string",JSONPath=".status.thresholds[0].errorBudgetRemaining"
+// +kubebuilder:printcolumn:name="last sync",type="string",format="date",JSONPath=".status.sloLastForceSyncTime"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogSLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogSLOSpec   `json:"spec,omitempty"`
+	Status DatadogSLOStatus `json:"status,omitempty"`
+}
+
+// DatadogSLOList contains a list of DatadogSLOs
+// +kubebuilder:object:root=true
+type DatadogSLOList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogSLO `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogSLO{}, &DatadogSLOList{})
+}
@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// IsValidDatadogSLO use to check if a DatadogSLOSpec is valid by checking
+// that the required fields of its type are defined and that its thresholds are percentages
+func IsValidDatadogSLO(spec *DatadogSLOSpec) error {
+	var errs []error
+	if spec.Name == "" {
+		errs = append(errs, fmt.Errorf("spec.Name must be defined"))
+	}
+
+	switch spec.Type {
+	case DatadogSLOTypeMetric:
+		if spec.Query == nil || spec.Query.Numerator == "" || spec.Query.Denominator == "" {
+			errs = append(errs, fmt.Errorf("spec.Query.Numerator and spec.Query.Denominator must be defined with the metric type"))
+		}
+		if len(spec.MonitorIDs) > 0 || len(spec.MonitorRefs) > 0 || len(spec.Groups) > 0 {
+			errs = append(errs, fmt.Errorf("spec.MonitorIDs, spec.MonitorRefs and spec.Groups can only be defined with the monitor type"))
+		}
+	case DatadogSLOTypeMonitor:
+		monitors := len(spec.MonitorIDs) + len(spec.MonitorRefs)
+		if monitors == 0 {
+			errs = append(errs, fmt.Errorf("spec.MonitorIDs or spec.MonitorRefs must be defined with the monitor type"))
+		}
+		if len(spec.Groups) > 0 && monitors > 1 {
+			errs = append(errs, fmt.Errorf("spec.Groups can only be defined with a single monitor"))
+		}
+		if spec.Query != nil {
+			errs = append(errs, fmt.Errorf("spec.Query can only be defined with the metric type"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("spec.Type must be metric or monitor"))
+	}
+
+	for _, ref := range spec.MonitorRefs {
+		if ref.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.MonitorRefs.Name must be defined"))
+		}
+	}
+
+	if len(spec.Thresholds) == 0 {
+		errs = append(errs, fmt.Errorf("spec.Thresholds must be defined"))
+	}
+	for _, threshold := range spec.Thresholds {
+		target, err := strconv.ParseFloat(threshold.Target, 64)
+		if err != nil || target <= 0 || target >= 100 {
+			errs = append(errs, fmt.Errorf("spec.Thresholds.Target of the %s timeframe must be a percentage between 0 and 100", threshold.Timeframe))
+			continue
+		}
+		if threshold.Warning != nil {
+			warning, err := strconv.ParseFloat(*threshold.Warning, 64)
+			if err != nil || warning <= target || warning >= 100 {
+				errs = append(errs, fmt.Errorf("spec.Thresholds.Warning of the %s timeframe must be a percentage between the target and 100", threshold.Timeframe))
+			}
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestIsValidDatadogSLO(t *testing.T) {
+	thresholds := []DatadogSLOThreshold{{Timeframe: DatadogSLOTimeframe30d, Target: "99.9", Warning: apiutils.NewStringPointer("99.95")}}
+
+	testCases := []struct {
+		name    string
+		spec    *DatadogSLOSpec
+		wantErr string
+	}{
+		{
+			name: "metric SLO",
+			spec: &DatadogSLOSpec{
+				Name:       "web availability",
+				Type:       DatadogSLOTypeMetric,
+				Query:      &DatadogSLOQuery{Numerator: "sum:requests.success{service:web}.as_count()", Denominator: "sum:requests.total{service:web}.as_count()"},
+				Thresholds: thresholds,
+			},
+		},
+		{
+			name: "monitor SLO with groups",
+			spec: &DatadogSLOSpec{
+				Name:        "web latency",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorRefs: []DatadogSLOMonitorReference{{Name: "web-latency"}},
+				Groups:      []string{"env:prod"},
+				Thresholds:  thresholds,
+			},
+		},
+		{
+			name:    "missing name, type and thresholds",
+			spec:    &DatadogSLOSpec{},
+			wantErr: "[spec.Name must be defined, spec.Type must be metric or monitor, spec.Thresholds must be defined]",
+		},
+		{
+			name: "metric SLO without query",
+			spec: &DatadogSLOSpec{
+				Name:       "web availability",
+				Type:       DatadogSLOTypeMetric,
+				MonitorIDs: []int64{1234},
+				Thresholds: thresholds,
+			},
+			wantErr: "[spec.Query.Numerator and spec.Query.Denominator must be defined with the metric type, spec.MonitorIDs, spec.MonitorRefs and spec.Groups can only be defined with the monitor type]",
+		},
+		{
+			name: "monitor SLO without monitors",
+			spec: &DatadogSLOSpec{
+				Name:       "web latency",
+				Type:       DatadogSLOTypeMonitor,
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.MonitorIDs or spec.MonitorRefs must be defined with the monitor type",
+		},
+		{
+			name: "groups with several monitors",
+			spec: &DatadogSLOSpec{
+				Name:        "web latency",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorIDs:  []int64{1234},
+				MonitorRefs: []DatadogSLOMonitorReference{{Name: "web-latency"}},
+				Groups:      []string{"env:prod"},
+				Thresholds:  thresholds,
+			},
+			wantErr: "spec.Groups can only be defined with a single monitor",
+		},
+		{
+			name: "invalid thresholds",
+			spec: &DatadogSLOSpec{
+				Name:       "web latency",
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int64{1234},
+				Thresholds: []DatadogSLOThreshold{
+					{Timeframe: DatadogSLOTimeframe7d, Target: "100"},
+					{Timeframe: DatadogSLOTimeframe30d, Target: "99", Warning: apiutils.NewStringPointer("98")},
+				},
+			},
+			wantErr: "[spec.Thresholds.Target of the 7d timeframe must be a percentage between 0 and 100, spec.Thresholds.Warning of the 30d timeframe must be a percentage between the target and 100]",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result := IsValidDatadogSLO(test.spec)
+			if test.wantErr != "" {
+				assert.EqualError(t, result, test.wantErr)
+			} else {
+				assert.NoError(t, result)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	apiv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -589,6 +590,166 @@ func (in *DatadogAgentCondition) DeepCopy() *DatadogAgentCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentExtension) DeepCopyInto(out *DatadogAgentExtension) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentExtension.
+func (in *DatadogAgentExtension) DeepCopy() *DatadogAgentExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentExtension) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentExtensionComponent) DeepCopyInto(out *DatadogAgentExtensionComponent) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]v1.AgentContainerName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PolicyRules != nil {
+		in, out := &in.PolicyRules, &out.PolicyRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterPolicyRules != nil {
+		in, out := &in.ClusterPolicyRules, &out.ClusterPolicyRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentExtensionComponent.
+func (in *DatadogAgentExtensionComponent) DeepCopy() *DatadogAgentExtensionComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentExtensionComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentExtensionList) DeepCopyInto(out *DatadogAgentExtensionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogAgentExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentExtensionList.
+func (in *DatadogAgentExtensionList) DeepCopy() *DatadogAgentExtensionList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentExtensionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentExtensionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentExtensionSpec) DeepCopyInto(out *DatadogAgentExtensionSpec) {
+	*out = *in
+	if in.DatadogAgentSelector != nil {
+		in, out := &in.DatadogAgentSelector, &out.DatadogAgentSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAgent != nil {
+		in, out := &in.NodeAgent, &out.NodeAgent
+		*out = new(DatadogAgentExtensionComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterAgent != nil {
+		in, out := &in.ClusterAgent, &out.ClusterAgent
+		*out = new(DatadogAgentExtensionComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterChecksRunner != nil {
+		in, out := &in.ClusterChecksRunner, &out.ClusterChecksRunner
+		*out = new(DatadogAgentExtensionComponent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentExtensionSpec.
+func (in *DatadogAgentExtensionSpec) DeepCopy() *DatadogAgentExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogAgentList) DeepCopyInto(out *DatadogAgentList) {
 	*out = *in
@@ -1005,115 +1166,88 @@ func (in *DatadogCredentials) DeepCopy() *DatadogCredentials {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogFeatures) DeepCopyInto(out *DatadogFeatures) {
+func (in *DatadogDowntime) DeepCopyInto(out *DatadogDowntime) {
 	*out = *in
-	if in.OrchestratorExplorer != nil {
-		in, out := &in.OrchestratorExplorer, &out.OrchestratorExplorer
-		*out = new(OrchestratorExplorerConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.KubeStateMetricsCore != nil {
-		in, out := &in.KubeStateMetricsCore, &out.KubeStateMetricsCore
-		*out = new(KubeStateMetricsCore)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PrometheusScrape != nil {
-		in, out := &in.PrometheusScrape, &out.PrometheusScrape
-		*out = new(PrometheusScrapeConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkMonitoring != nil {
-		in, out := &in.NetworkMonitoring, &out.NetworkMonitoring
-		*out = new(NetworkMonitoringConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LogCollection != nil {
-		in, out := &in.LogCollection, &out.LogCollection
-		*out = new(LogCollectionConfig)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogFeatures.
-func (in *DatadogFeatures) DeepCopy() *DatadogFeatures {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntime.
+func (in *DatadogDowntime) DeepCopy() *DatadogDowntime {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogFeatures)
+	out := new(DatadogDowntime)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogDowntime) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMetric) DeepCopyInto(out *DatadogMetric) {
+func (in *DatadogDowntimeAgentRollout) DeepCopyInto(out *DatadogDowntimeAgentRollout) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetric.
-func (in *DatadogMetric) DeepCopy() *DatadogMetric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeAgentRollout.
+func (in *DatadogDowntimeAgentRollout) DeepCopy() *DatadogDowntimeAgentRollout {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMetric)
+	out := new(DatadogDowntimeAgentRollout)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatadogMetric) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMetricCondition) DeepCopyInto(out *DatadogMetricCondition) {
+func (in *DatadogDowntimeInstance) DeepCopyInto(out *DatadogDowntimeInstance) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricCondition.
-func (in *DatadogMetricCondition) DeepCopy() *DatadogMetricCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeInstance.
+func (in *DatadogDowntimeInstance) DeepCopy() *DatadogDowntimeInstance {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMetricCondition)
+	out := new(DatadogDowntimeInstance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMetricList) DeepCopyInto(out *DatadogMetricList) {
+func (in *DatadogDowntimeList) DeepCopyInto(out *DatadogDowntimeList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DatadogMetric, len(*in))
+		*out = make([]DatadogDowntime, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricList.
-func (in *DatadogMetricList) DeepCopy() *DatadogMetricList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeList.
+func (in *DatadogDowntimeList) DeepCopy() *DatadogDowntimeList {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMetricList)
+	out := new(DatadogDowntimeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatadogMetricList) DeepCopyObject() runtime.Object {
+func (in *DatadogDowntimeList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1121,119 +1255,521 @@ func (in *DatadogMetricList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMetricSpec) DeepCopyInto(out *DatadogMetricSpec) {
+func (in *DatadogDowntimeMonitorReference) DeepCopyInto(out *DatadogDowntimeMonitorReference) {
 	*out = *in
-	out.MaxAge = in.MaxAge
-	out.TimeWindow = in.TimeWindow
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricSpec.
-func (in *DatadogMetricSpec) DeepCopy() *DatadogMetricSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeMonitorReference.
+func (in *DatadogDowntimeMonitorReference) DeepCopy() *DatadogDowntimeMonitorReference {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMetricSpec)
+	out := new(DatadogDowntimeMonitorReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMetricStatus) DeepCopyInto(out *DatadogMetricStatus) {
+func (in *DatadogDowntimeRecurrence) DeepCopyInto(out *DatadogDowntimeRecurrence) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]DatadogMetricCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WeekDays != nil {
+		in, out := &in.WeekDays, &out.WeekDays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UntilDate != nil {
+		in, out := &in.UntilDate, &out.UntilDate
+		*out = (*in).DeepCopy()
+	}
+	if in.UntilOccurrences != nil {
+		in, out := &in.UntilOccurrences, &out.UntilOccurrences
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricStatus.
-func (in *DatadogMetricStatus) DeepCopy() *DatadogMetricStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeRecurrence.
+func (in *DatadogDowntimeRecurrence) DeepCopy() *DatadogDowntimeRecurrence {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMetricStatus)
+	out := new(DatadogDowntimeRecurrence)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMonitor) DeepCopyInto(out *DatadogMonitor) {
+func (in *DatadogDowntimeSpec) DeepCopyInto(out *DatadogDowntimeSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorTags != nil {
+		in, out := &in.MonitorTags, &out.MonitorTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorRefs != nil {
+		in, out := &in.MonitorRefs, &out.MonitorRefs
+		*out = make([]DatadogDowntimeMonitorReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	if in.Recurrence != nil {
+		in, out := &in.Recurrence, &out.Recurrence
+		*out = new(DatadogDowntimeRecurrence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MuteFirstRecoveryNotification != nil {
+		in, out := &in.MuteFirstRecoveryNotification, &out.MuteFirstRecoveryNotification
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DatadogAgentRollout != nil {
+		in, out := &in.DatadogAgentRollout, &out.DatadogAgentRollout
+		*out = new(DatadogDowntimeAgentRollout)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitor.
-func (in *DatadogMonitor) DeepCopy() *DatadogMonitor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeSpec.
+func (in *DatadogDowntimeSpec) DeepCopy() *DatadogDowntimeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMonitor)
+	out := new(DatadogDowntimeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatadogMonitor) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMonitorCondition) DeepCopyInto(out *DatadogMonitorCondition) {
+func (in *DatadogDowntimeStatus) DeepCopyInto(out *DatadogDowntimeStatus) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorCondition.
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Downtimes != nil {
+		in, out := &in.Downtimes, &out.Downtimes
+		*out = make([]DatadogDowntimeInstance, len(*in))
+		copy(*out, *in)
+	}
+	if in.DowntimeLastForceSyncTime != nil {
+		in, out := &in.DowntimeLastForceSyncTime, &out.DowntimeLastForceSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeStatus.
+func (in *DatadogDowntimeStatus) DeepCopy() *DatadogDowntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogFeatures) DeepCopyInto(out *DatadogFeatures) {
+	*out = *in
+	if in.OrchestratorExplorer != nil {
+		in, out := &in.OrchestratorExplorer, &out.OrchestratorExplorer
+		*out = new(OrchestratorExplorerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeStateMetricsCore != nil {
+		in, out := &in.KubeStateMetricsCore, &out.KubeStateMetricsCore
+		*out = new(KubeStateMetricsCore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusScrape != nil {
+		in, out := &in.PrometheusScrape, &out.PrometheusScrape
+		*out = new(PrometheusScrapeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkMonitoring != nil {
+		in, out := &in.NetworkMonitoring, &out.NetworkMonitoring
+		*out = new(NetworkMonitoringConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogCollection != nil {
+		in, out := &in.LogCollection, &out.LogCollection
+		*out = new(LogCollectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogFeatures.
+func (in *DatadogFeatures) DeepCopy() *DatadogFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetric) DeepCopyInto(out *DatadogMetric) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetric.
+func (in *DatadogMetric) DeepCopy() *DatadogMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogMetric) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetricCondition) DeepCopyInto(out *DatadogMetricCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricCondition.
+func (in *DatadogMetricCondition) DeepCopy() *DatadogMetricCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetricCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetricList) DeepCopyInto(out *DatadogMetricList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricList.
+func (in *DatadogMetricList) DeepCopy() *DatadogMetricList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetricList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogMetricList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetricSpec) DeepCopyInto(out *DatadogMetricSpec) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+	out.TimeWindow = in.TimeWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricSpec.
+func (in *DatadogMetricSpec) DeepCopy() *DatadogMetricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetricStatus) DeepCopyInto(out *DatadogMetricStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]DatadogMetricCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetricStatus.
+func (in *DatadogMetricStatus) DeepCopy() *DatadogMetricStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetricStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitor) DeepCopyInto(out *DatadogMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitor.
+func (in *DatadogMonitor) DeepCopy() *DatadogMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorCondition) DeepCopyInto(out *DatadogMonitorCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorCondition.
 func (in *DatadogMonitorCondition) DeepCopy() *DatadogMonitorCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMonitorCondition)
+	out := new(DatadogMonitorCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorControllerOptions) DeepCopyInto(out *DatadogMonitorControllerOptions) {
+	*out = *in
+	if in.DisableRequiredTags != nil {
+		in, out := &in.DisableRequiredTags, &out.DisableRequiredTags
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorControllerOptions.
+func (in *DatadogMonitorControllerOptions) DeepCopy() *DatadogMonitorControllerOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorControllerOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorCredentials) DeepCopyInto(out *DatadogMonitorCredentials) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorCredentials.
+func (in *DatadogMonitorCredentials) DeepCopy() *DatadogMonitorCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorCredentialsStatus) DeepCopyInto(out *DatadogMonitorCredentialsStatus) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(DatadogMonitorCredentials)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorCredentialsStatus.
+func (in *DatadogMonitorCredentialsStatus) DeepCopy() *DatadogMonitorCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorDowntimeStatus) DeepCopyInto(out *DatadogMonitorDowntimeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorDowntimeStatus.
+func (in *DatadogMonitorDowntimeStatus) DeepCopy() *DatadogMonitorDowntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorDowntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinition) DeepCopyInto(out *DatadogMonitorFormulaAndFunctionEventQueryDefinition) {
+	*out = *in
+	in.Compute.DeepCopyInto(&out.Compute)
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = new(DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch)
+		**out = **in
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]DatadogMonitorFormulaAndFunctionEventQueryGroupBy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorFormulaAndFunctionEventQueryDefinition.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinition) DeepCopy() *DatadogMonitorFormulaAndFunctionEventQueryDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorFormulaAndFunctionEventQueryDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute) DeepCopyInto(out *DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute) DeepCopy() *DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMonitorControllerOptions) DeepCopyInto(out *DatadogMonitorControllerOptions) {
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch) DeepCopyInto(out *DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch) {
 	*out = *in
-	if in.DisableRequiredTags != nil {
-		in, out := &in.DisableRequiredTags, &out.DisableRequiredTags
-		*out = new(bool)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch) DeepCopy() *DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryGroupBy) DeepCopyInto(out *DatadogMonitorFormulaAndFunctionEventQueryGroupBy) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
 		**out = **in
 	}
+	if in.Sort != nil {
+		in, out := &in.Sort, &out.Sort
+		*out = new(DatadogMonitorFormulaAndFunctionEventQueryGroupBySort)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorControllerOptions.
-func (in *DatadogMonitorControllerOptions) DeepCopy() *DatadogMonitorControllerOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorFormulaAndFunctionEventQueryGroupBy.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryGroupBy) DeepCopy() *DatadogMonitorFormulaAndFunctionEventQueryGroupBy {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMonitorControllerOptions)
+	out := new(DatadogMonitorFormulaAndFunctionEventQueryGroupBy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatadogMonitorDowntimeStatus) DeepCopyInto(out *DatadogMonitorDowntimeStatus) {
+func (in *DatadogMonitorFormulaAndFunctionEventQueryGroupBySort) DeepCopyInto(out *DatadogMonitorFormulaAndFunctionEventQueryGroupBySort) {
 	*out = *in
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorDowntimeStatus.
-func (in *DatadogMonitorDowntimeStatus) DeepCopy() *DatadogMonitorDowntimeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorFormulaAndFunctionEventQueryGroupBySort.
+func (in *DatadogMonitorFormulaAndFunctionEventQueryGroupBySort) DeepCopy() *DatadogMonitorFormulaAndFunctionEventQueryGroupBySort {
 	if in == nil {
 		return nil
 	}
-	out := new(DatadogMonitorDowntimeStatus)
+	out := new(DatadogMonitorFormulaAndFunctionEventQueryGroupBySort)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1343,6 +1879,43 @@ func (in *DatadogMonitorOptions) DeepCopyInto(out *DatadogMonitorOptions) {
 		*out = new(DatadogMonitorOptionsThresholdWindows)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RenotifyOccurrences != nil {
+		in, out := &in.RenotifyOccurrences, &out.RenotifyOccurrences
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RenotifyStatuses != nil {
+		in, out := &in.RenotifyStatuses, &out.RenotifyStatuses
+		*out = make([]DatadogMonitorRenotifyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NewHostDelay != nil {
+		in, out := &in.NewHostDelay, &out.NewHostDelay
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinLocationFailed != nil {
+		in, out := &in.MinLocationFailed, &out.MinLocationFailed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NotifyBy != nil {
+		in, out := &in.NotifyBy, &out.NotifyBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(DatadogMonitorOptionsSchedulingOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]DatadogMonitorFormulaAndFunctionEventQueryDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorOptions.
@@ -1355,6 +1928,56 @@ func (in *DatadogMonitorOptions) DeepCopy() *DatadogMonitorOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorOptionsEvaluationWindow) DeepCopyInto(out *DatadogMonitorOptionsEvaluationWindow) {
+	*out = *in
+	if in.DayStarts != nil {
+		in, out := &in.DayStarts, &out.DayStarts
+		*out = new(string)
+		**out = **in
+	}
+	if in.HourStarts != nil {
+		in, out := &in.HourStarts, &out.HourStarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MonthStarts != nil {
+		in, out := &in.MonthStarts, &out.MonthStarts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorOptionsEvaluationWindow.
+func (in *DatadogMonitorOptionsEvaluationWindow) DeepCopy() *DatadogMonitorOptionsEvaluationWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorOptionsEvaluationWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorOptionsSchedulingOptions) DeepCopyInto(out *DatadogMonitorOptionsSchedulingOptions) {
+	*out = *in
+	if in.EvaluationWindow != nil {
+		in, out := &in.EvaluationWindow, &out.EvaluationWindow
+		*out = new(DatadogMonitorOptionsEvaluationWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorOptionsSchedulingOptions.
+func (in *DatadogMonitorOptionsSchedulingOptions) DeepCopy() *DatadogMonitorOptionsSchedulingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorOptionsSchedulingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogMonitorOptionsThresholdWindows) DeepCopyInto(out *DatadogMonitorOptionsThresholdWindows) {
 	*out = *in
@@ -1440,6 +2063,11 @@ func (in *DatadogMonitorSpec) DeepCopyInto(out *DatadogMonitorSpec) {
 	}
 	in.Options.DeepCopyInto(&out.Options)
 	in.ControllerOptions.DeepCopyInto(&out.ControllerOptions)
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(DatadogMonitorCredentials)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorSpec.
@@ -1486,6 +2114,11 @@ func (in *DatadogMonitorStatus) DeepCopyInto(out *DatadogMonitorStatus) {
 		}
 	}
 	out.DowntimeStatus = in.DowntimeStatus
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(DatadogMonitorCredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorStatus.
@@ -1514,6 +2147,221 @@ func (in *DatadogMonitorTriggeredState) DeepCopy() *DatadogMonitorTriggeredState
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLO) DeepCopyInto(out *DatadogSLO) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLO.
+func (in *DatadogSLO) DeepCopy() *DatadogSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLO) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOList) DeepCopyInto(out *DatadogSLOList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogSLO, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOList.
+func (in *DatadogSLOList) DeepCopy() *DatadogSLOList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLOList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOMonitorReference) DeepCopyInto(out *DatadogSLOMonitorReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOMonitorReference.
+func (in *DatadogSLOMonitorReference) DeepCopy() *DatadogSLOMonitorReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOMonitorReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOQuery) DeepCopyInto(out *DatadogSLOQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOQuery.
+func (in *DatadogSLOQuery) DeepCopy() *DatadogSLOQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOSpec) DeepCopyInto(out *DatadogSLOSpec) {
+	*out = *in
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(DatadogSLOQuery)
+		**out = **in
+	}
+	if in.MonitorIDs != nil {
+		in, out := &in.MonitorIDs, &out.MonitorIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorRefs != nil {
+		in, out := &in.MonitorRefs, &out.MonitorRefs
+		*out = make([]DatadogSLOMonitorReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]DatadogSLOThreshold, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOSpec.
+func (in *DatadogSLOSpec) DeepCopy() *DatadogSLOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOStatus) DeepCopyInto(out *DatadogSLOStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+	if in.MonitorIDs != nil {
+		in, out := &in.MonitorIDs, &out.MonitorIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]DatadogSLOThresholdStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SLILastUpdateTime != nil {
+		in, out := &in.SLILastUpdateTime, &out.SLILastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SLOLastForceSyncTime != nil {
+		in, out := &in.SLOLastForceSyncTime, &out.SLOLastForceSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOStatus.
+func (in *DatadogSLOStatus) DeepCopy() *DatadogSLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOThreshold) DeepCopyInto(out *DatadogSLOThreshold) {
+	*out = *in
+	if in.Warning != nil {
+		in, out := &in.Warning, &out.Warning
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOThreshold.
+func (in *DatadogSLOThreshold) DeepCopy() *DatadogSLOThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOThresholdStatus) DeepCopyInto(out *DatadogSLOThresholdStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOThresholdStatus.
+func (in *DatadogSLOThresholdStatus) DeepCopy() *DatadogSLOThresholdStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOThresholdStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DogstatsdConfig) DeepCopyInto(out *DogstatsdConfig) {
 	*out = *in
@@ -2,6 +2,7 @@ package v2alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -23,8 +24,12 @@ const (
 	DatadogAgentStateRunning DatadogAgentState = "Running"
 	// DatadogAgentStateUpdating the deployment is currently under a rolling update.
 	DatadogAgentStateUpdating DatadogAgentState = "Updating"
-	// DatadogAgentStateCanary the deployment is currently under a canary testing (EDS only).
+	// DatadogAgentStateCanary the deployment is currently under a canary testing (EDS or DaemonSet canary).
 	DatadogAgentStateCanary DatadogAgentState = "Canary"
+	// DatadogAgentStateCanaryPaused the canary testing is paused because of pod restarts (DaemonSet canary only).
+	DatadogAgentStateCanaryPaused DatadogAgentState = "CanaryPaused"
+	// DatadogAgentStateCanaryFailed the canary testing failed and was rolled back (DaemonSet canary only).
+	DatadogAgentStateCanaryFailed DatadogAgentState = "CanaryFailed"
 	// DatadogAgentStateFailed the current state of the deployment is considered as Failed.
 	DatadogAgentStateFailed DatadogAgentState = "Failed"
 )
@@ -71,6 +76,28 @@ func SetDatadogAgentStatusCondition(status *DatadogAgentStatus, condition *metav
 	}
 }
 
+// DeleteDatadogAgentStatusCondition is used to remove a condition from the status
+func DeleteDatadogAgentStatusCondition(status *DatadogAgentStatus, conditionType string) {
+	if idCondition := getIndexForConditionType(status, conditionType); idCondition >= 0 {
+		status.Conditions = append(status.Conditions[:idCondition], status.Conditions[idCondition+1:]...)
+	}
+}
+
+// GetFeatureReconcileConditionType returns the ReconcileConditionType of a feature.
+// For instance, the condition type of the `event_collection` feature is `EventCollectionFeatureReconcile`.
+func GetFeatureReconcileConditionType(featureID string) string {
+	var conditionType strings.Builder
+	for _, word := range strings.Split(featureID, "_") {
+		if word == "" {
+			continue
+		}
+		conditionType.WriteString(strings.ToUpper(word[:1]))
+		conditionType.WriteString(word[1:])
+	}
+	conditionType.WriteString(FeatureReconcileConditionTypeSuffix)
+	return conditionType.String()
+}
+
 // NewDatadogAgentStatusCondition returns new metav1.Condition instance
 func NewDatadogAgentStatusCondition(conditionType string, conditionStatus metav1.ConditionStatus, now metav1.Time, reason, message string) metav1.Condition {
 	return metav1.Condition{
@@ -10,8 +10,18 @@ const (
 	ClusterChecksRunnerReconcileConditionType = "ClusterChecksRunnerReconcile"
 	// OverrideReconcileConflictConditionType ReconcileConditionType for override conflict
 	OverrideReconcileConflictConditionType = "OverrideReconcileConflict"
+	// ConflictConditionType ConditionType for the resources shared with another DatadogAgent
+	ConflictConditionType = "Conflict"
 	// DatadogAgentReconcileErrorConditionType ReconcileConditionType for DatadogAgent reconcile error
 	DatadogAgentReconcileErrorConditionType = "DatadogAgentReconcileError"
+	// InvalidSpecConditionType ConditionType for a DatadogAgent spec rejected by the validation
+	InvalidSpecConditionType = "InvalidSpec"
+	// AgentUpdateStrategyConflictConditionType ConditionType for the node Agent update strategy settings ignored by the ExtendedDaemonSet
+	AgentUpdateStrategyConflictConditionType = "AgentUpdateStrategyConflict"
+	// AgentCanaryNotValidatedConditionType ConditionType for a DaemonSet canary that can't be validated because it has no pod
+	AgentCanaryNotValidatedConditionType = "AgentCanaryNotValidated"
+	// FeatureReconcileConditionTypeSuffix suffix of the ReconcileConditionType of each feature, see GetFeatureReconcileConditionType
+	FeatureReconcileConditionTypeSuffix = "FeatureReconcile"
 
 	// ExtraConfdConfigMapName is the name of the ConfigMap storing Custom Confd data
 	ExtraConfdConfigMapName = "%s-extra-confd"
@@ -6,9 +6,12 @@
 package v2alpha1
 
 import (
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	securityv1 "github.com/openshift/api/security/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
 )
@@ -38,6 +41,33 @@ type DatadogAgentSpec struct {
 	// Override the default configurations of the agents
 	// +optional
 	Override map[ComponentName]*DatadogAgentComponentOverride `json:"override,omitempty"`
+
+	// Profiles deploy the node Agent with a different configuration on some nodes. Each profile runs
+	// its own node Agent DaemonSet, on the nodes that match its node selector and no previous profile.
+	// The default node Agent DaemonSet runs on the nodes that match no profile.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Profiles []AgentProfile `json:"profiles,omitempty"`
+}
+
+// AgentProfile configures the node Agent on the nodes that match a node selector.
+// +k8s:openapi-gen=true
+type AgentProfile struct {
+	// Name of the profile, used as suffix of the name of the node Agent DaemonSet of the profile.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=30
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the profile.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Override of the node Agent configuration on the nodes of the profile, applied after `spec.override.nodeAgent`.
+	// `name`, `replicas`, `createRbac`, `customConfigurations`, `extraConfd`, `extraChecksd`,
+	// `securityContextConstraints`, `pdb` and `autoscaling` are not supported.
+	// +optional
+	Override *DatadogAgentComponentOverride `json:"override,omitempty"`
 }
 
 // DatadogFeatures are features running on the Agent and Cluster Agent.
@@ -691,6 +721,16 @@ type GlobalConfig struct {
 	// Path to the container runtime socket (if different from Docker).
 	// +optional
 	CriSocketPath *string `json:"criSocketPath,omitempty"`
+
+	// KeepLabels is a glob pattern matching the labels, not managed by the operator, that are kept when the operator
+	// updates the resources it manages, for instance `{argocd.argoproj.io/*,istio.io/*}`.
+	// +optional
+	KeepLabels *string `json:"keepLabels,omitempty"`
+
+	// KeepAnnotations is a glob pattern matching the annotations, not managed by the operator, that are kept when the
+	// operator updates the resources it manages, for instance `{argocd.argoproj.io/*,istio.io/*}`.
+	// +optional
+	KeepAnnotations *string `json:"keepAnnotations,omitempty"`
 }
 
 // DatadogCredentials is a generic structure that holds credentials to access Datadog.
@@ -867,6 +907,23 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
 
+	// Configure the PodDisruptionBudget of the component.
+	// Only applicable to the Cluster Agent and the Cluster Checks Runner.
+	// +optional
+	PDB *PodDisruptionBudgetConfig `json:"pdb,omitempty"`
+
+	// Configure the HorizontalPodAutoscaler of the component.
+	// When enabled, the number of replicas of the component is managed by the HorizontalPodAutoscaler.
+	// Only applicable to the Cluster Agent and the Cluster Checks Runner.
+	// +optional
+	Autoscaling *AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// Configure how the pods of the component are replaced when it is updated: the update strategy
+	// of the node Agent DaemonSet or ExtendedDaemonSet, or the strategy of the Cluster Agent and
+	// Cluster Checks Runner Deployments.
+	// +optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+
 	// If specified, indicates the pod's priority. "system-node-critical" and "system-cluster-critical"
 	// are two special keywords which indicate the highest priorities with the former being the highest priority.
 	// Any other name must be defined by creating a PriorityClass object with that name. If not specified,
@@ -902,6 +959,16 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	HostPID *bool `json:"hostPID,omitempty"`
 
+	// KeepLabels is a glob pattern matching the labels, not managed by the operator, that are kept on the workload
+	// of the component. Overrides spec.global.keepLabels.
+	// +optional
+	KeepLabels *string `json:"keepLabels,omitempty"`
+
+	// KeepAnnotations is a glob pattern matching the annotations, not managed by the operator, that are kept on the
+	// workload of the component. Overrides spec.global.keepAnnotations.
+	// +optional
+	KeepAnnotations *string `json:"keepAnnotations,omitempty"`
+
 	// Disabled force disables a component.
 	// +optional
 	Disabled *bool `json:"disabled,omitempty"`
@@ -920,6 +987,142 @@ type SecurityContextConstraintsConfig struct {
 	CustomConfiguration *securityv1.SecurityContextConstraints `json:"customConfiguration,omitempty"`
 }
 
+// PodDisruptionBudgetConfig provides PodDisruptionBudget configurations for the components.
+// +k8s:openapi-gen=true
+type PodDisruptionBudgetConfig struct {
+	// Enabled defines whether to create a PodDisruptionBudget for the current component.
+	// Default: `true` if the component has more than one replica.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinAvailable is the number or percentage of pods that must remain available during a voluntary disruption.
+	// Cannot be set at the same time as MaxUnavailable.
+	// Default: 1 if MaxUnavailable is not set.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during a voluntary disruption.
+	// Cannot be set at the same time as MinAvailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingConfig provides HorizontalPodAutoscaler configurations for the components.
+// +k8s:openapi-gen=true
+type AutoscalingConfig struct {
+	// Enabled defines whether to create an autoscaling/v2 HorizontalPodAutoscaler for the current component.
+	// Default: false
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinReplicas is the lower limit for the number of replicas. It is also the number of replicas
+	// of the component when it is created.
+	// Default: 1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas. Required if the autoscaling is enabled.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of the pods,
+	// as a percentage of the requested CPU.
+	// Default: 80 if no other target is set.
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetMemoryUtilizationPercentage is the target average memory utilization of the pods,
+	// as a percentage of the requested memory.
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+
+	// ExternalMetric configures an external metric target, served by the Cluster Agent external metrics server.
+	// Requires the `externalMetricsServer` feature.
+	// +optional
+	ExternalMetric *AutoscalingExternalMetricConfig `json:"externalMetric,omitempty"`
+}
+
+// AutoscalingExternalMetricConfig provides the configuration of an external metric target of a HorizontalPodAutoscaler.
+// +k8s:openapi-gen=true
+type AutoscalingExternalMetricConfig struct {
+	// Name of the metric, for instance `datadogmetric@<namespace>:<name>` to use a DatadogMetric.
+	Name string `json:"name"`
+
+	// Selector is the label selector of the metric, passed to the external metrics server.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// TargetAverageValue is the target value of the metric, averaged over the pods.
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// UpdateStrategyType is the type of update strategy of a component.
+type UpdateStrategyType string
+
+const (
+	// RollingUpdateStrategyType replaces the pods progressively. It is the default.
+	RollingUpdateStrategyType UpdateStrategyType = "RollingUpdate"
+	// OnDeleteStrategyType only replaces the pods of a DaemonSet when they are deleted.
+	OnDeleteStrategyType UpdateStrategyType = "OnDelete"
+	// RecreateStrategyType deletes all the pods of a Deployment before creating the new ones.
+	RecreateStrategyType UpdateStrategyType = "Recreate"
+)
+
+// UpdateStrategy provides the update strategy configuration of the components.
+// +k8s:openapi-gen=true
+type UpdateStrategy struct {
+	// Type of the update strategy: `RollingUpdate` or `OnDelete` for a DaemonSet,
+	// `RollingUpdate` or `Recreate` for a Deployment. An ExtendedDaemonSet always uses a rolling update.
+	// Default: `RollingUpdate`
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete;Recreate
+	// +optional
+	Type UpdateStrategyType `json:"type,omitempty"`
+
+	// RollingUpdate configures the rolling update. Only used if Type is `RollingUpdate`.
+	// +optional
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+
+	// Canary configures the canary deployment of the node Agent ExtendedDaemonSet, or the canary of the
+	// node Agent DaemonSet if it is enabled in the operator. The fields that are not set use the
+	// `edsCanary*` flags of the operator.
+	// +optional
+	Canary *edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary `json:"canary,omitempty"`
+}
+
+// RollingUpdate provides the rolling update configuration of the components.
+// +k8s:openapi-gen=true
+type RollingUpdate struct {
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during the update.
+	// For an ExtendedDaemonSet, the default is the `edsMaxPodUnavailable` flag of the operator.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the number or percentage of pods that can be created above the desired number of pods
+	// during the update. Only applicable to a DaemonSet or a Deployment.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxPodSchedulerFailure is the number or percentage of pods that can be unschedulable during the update.
+	// Only applicable to an ExtendedDaemonSet. The default is the `edsMaxPodSchedulerFailure` flag of the operator.
+	// +optional
+	MaxPodSchedulerFailure *intstr.IntOrString `json:"maxPodSchedulerFailure,omitempty"`
+
+	// MaxParallelPodCreation is the maximum number of pods created in parallel.
+	// Only applicable to an ExtendedDaemonSet.
+	// +optional
+	MaxParallelPodCreation *int32 `json:"maxParallelPodCreation,omitempty"`
+
+	// SlowStartIntervalDuration is the duration between two increases of the number of pods created in parallel.
+	// Only applicable to an ExtendedDaemonSet.
+	// +optional
+	SlowStartIntervalDuration *metav1.Duration `json:"slowStartIntervalDuration,omitempty"`
+
+	// SlowStartAdditiveIncrease is the number or percentage of pods added to the number of pods created in parallel
+	// after each interval. Only applicable to an ExtendedDaemonSet.
+	// +optional
+	SlowStartAdditiveIncrease *intstr.IntOrString `json:"slowStartAdditiveIncrease,omitempty"`
+}
+
 // DatadogAgentGenericContainer is the generic structure describing any container's common configuration.
 // +k8s:openapi-gen=true
 type DatadogAgentGenericContainer struct {
@@ -988,6 +1191,15 @@ type DatadogAgentGenericContainer struct {
 	AppArmorProfileName *string `json:"appArmorProfileName,omitempty"`
 }
 
+// AgentProfileStatus is the state of the node Agent of a profile.
+// +k8s:openapi-gen=true
+type AgentProfileStatus struct {
+	// Name of the profile.
+	Name string `json:"name"`
+
+	commonv1.DaemonSetStatus `json:",inline"`
+}
+
 // DatadogAgentStatus defines the observed state of DatadogAgent.
 // +k8s:openapi-gen=true
 type DatadogAgentStatus struct {
@@ -1005,6 +1217,16 @@ type DatadogAgentStatus struct {
 	// The actual state of the Cluster Checks Runner as a deployment.
 	// +optional
 	ClusterChecksRunner *commonv1.DeploymentStatus `json:"clusterChecksRunner,omitempty"`
+	// The actual state of the node Agent of each profile.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	AgentProfiles []AgentProfileStatus `json:"agentProfiles,omitempty"`
+	// ActiveFeatures lists the features configured by the DatadogAgent.
+	// The reconcile state of each feature is reported in its `<Feature>FeatureReconcile` condition.
+	// +optional
+	// +listType=set
+	ActiveFeatures []string `json:"activeFeatures,omitempty"`
 }
 
 // DatadogAgent Deployment with the Datadog Operator.
@@ -0,0 +1,346 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+// IsValidDatadogAgent use to check if a DatadogAgentSpec is valid.
+// The spec is expected to be defaulted, so that the default host ports and endpoints are taken into account.
+func IsValidDatadogAgent(spec *DatadogAgentSpec) error {
+	var errs []error
+
+	if err := isValidCredentials(spec.Global); err != nil {
+		errs = append(errs, err)
+	}
+
+	if spec.Features != nil {
+		errs = append(errs, isValidFeatures(spec.Features)...)
+	}
+
+	// Sort the components to return the errors in a stable order.
+	components := make([]string, 0, len(spec.Override))
+	for name := range spec.Override {
+		components = append(components, string(name))
+	}
+	sort.Strings(components)
+	for _, name := range components {
+		errs = append(errs, isValidOverride(ComponentName(name), spec.Override[ComponentName(name)])...)
+		errs = append(errs, isValidAutoscaling(ComponentName(name), spec.Override[ComponentName(name)], spec.Features)...)
+		errs = append(errs, isValidUpdateStrategy(ComponentName(name), spec.Override[ComponentName(name)])...)
+	}
+
+	errs = append(errs, isValidProfiles(spec.Profiles)...)
+
+	return utilserrors.NewAggregate(errs)
+}
+
+// IsValidCustomConfig used to check if a CustomConfig is properly set
+func IsValidCustomConfig(config *CustomConfig) error {
+	if config != nil && config.ConfigData != nil && config.ConfigMap != nil {
+		return fmt.Errorf("'configData' and 'configMap' should not be set at the same time")
+	}
+
+	return nil
+}
+
+// IsValidMultiCustomConfig used to check if a MultiCustomConfig is properly set
+func IsValidMultiCustomConfig(config *MultiCustomConfig) error {
+	if config != nil && len(config.ConfigDataMap) > 0 && config.ConfigMap != nil {
+		return fmt.Errorf("'configDataMap' and 'configMap' should not be set at the same time")
+	}
+
+	return nil
+}
+
+func appendCustomConfigError(errs []error, path string, config *CustomConfig) []error {
+	if err := IsValidCustomConfig(config); err != nil {
+		return append(errs, fmt.Errorf("invalid %s, err: %w", path, err))
+	}
+	return errs
+}
+
+func isValidCredentials(global *GlobalConfig) error {
+	if global == nil || global.Credentials == nil {
+		return fmt.Errorf("spec.global.credentials must be set")
+	}
+	if global.Credentials.APIKey == nil && global.Credentials.APISecret == nil {
+		return fmt.Errorf("spec.global.credentials must set either apiKey or apiSecret")
+	}
+
+	return nil
+}
+
+func isValidFeatures(features *DatadogFeatures) []error {
+	var errs []error
+
+	if features.Dogstatsd != nil {
+		errs = appendCustomConfigError(errs, "spec.features.dogstatsd.mapperProfiles", features.Dogstatsd.MapperProfiles)
+	}
+	if features.CSPM != nil {
+		errs = appendCustomConfigError(errs, "spec.features.cspm.customBenchmarks", features.CSPM.CustomBenchmarks)
+	}
+	if features.CWS != nil {
+		errs = appendCustomConfigError(errs, "spec.features.cws.customPolicies", features.CWS.CustomPolicies)
+	}
+	if features.OrchestratorExplorer != nil {
+		errs = appendCustomConfigError(errs, "spec.features.orchestratorExplorer.conf", features.OrchestratorExplorer.Conf)
+	}
+	if features.KubeStateMetricsCore != nil {
+		errs = appendCustomConfigError(errs, "spec.features.kubeStateMetricsCore.conf", features.KubeStateMetricsCore.Conf)
+	}
+
+	return append(errs, isValidHostPorts(features)...)
+}
+
+// hostPort describes a port that a feature exposes on the host.
+type hostPort struct {
+	path     string
+	port     int32
+	protocol corev1.Protocol
+}
+
+// isValidHostPorts checks the endpoints of the features exposing a host port on the node Agent,
+// and that they don't use the same port and protocol.
+func isValidHostPorts(features *DatadogFeatures) []error {
+	var errs []error
+	var hostPorts []hostPort
+
+	if features.APM != nil && apiutils.BoolValue(features.APM.Enabled) && isHostPortEnabled(features.APM.HostPortConfig) {
+		hostPorts = append(hostPorts, hostPort{path: "spec.features.apm.hostPortConfig.hostPort", port: *features.APM.HostPortConfig.Port, protocol: corev1.ProtocolTCP})
+	}
+	if features.Dogstatsd != nil && isHostPortEnabled(features.Dogstatsd.HostPortConfig) {
+		hostPorts = append(hostPorts, hostPort{path: "spec.features.dogstatsd.hostPortConfig.hostPort", port: *features.Dogstatsd.HostPortConfig.Port, protocol: corev1.ProtocolUDP})
+	}
+	if grpc := otlpGRPCConfig(features); grpc != nil && apiutils.BoolValue(grpc.Enabled) && grpc.Endpoint != nil {
+		port, err := extractOTLPGRPCEndpointPort(*grpc.Endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.features.otlp.receiver.protocols.grpc.endpoint, err: %w", err))
+		} else {
+			hostPorts = append(hostPorts, hostPort{path: "spec.features.otlp.receiver.protocols.grpc.endpoint", port: port, protocol: corev1.ProtocolTCP})
+		}
+	}
+	if http := otlpHTTPConfig(features); http != nil && apiutils.BoolValue(http.Enabled) && http.Endpoint != nil {
+		port, err := ExtractOTLPEndpointPort(*http.Endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.features.otlp.receiver.protocols.http.endpoint, err: %w", err))
+		} else {
+			hostPorts = append(hostPorts, hostPort{path: "spec.features.otlp.receiver.protocols.http.endpoint", port: port, protocol: corev1.ProtocolTCP})
+		}
+	}
+
+	for i := range hostPorts {
+		for j := i + 1; j < len(hostPorts); j++ {
+			if hostPorts[i].port == hostPorts[j].port && hostPorts[i].protocol == hostPorts[j].protocol {
+				errs = append(errs, fmt.Errorf("%s and %s use the same host port %d/%s", hostPorts[i].path, hostPorts[j].path, hostPorts[i].port, hostPorts[i].protocol))
+			}
+		}
+	}
+
+	return errs
+}
+
+// extractOTLPGRPCEndpointPort validates the OTLP/gRPC endpoint before extracting its port, like the OTLP feature does.
+func extractOTLPGRPCEndpointPort(endpoint string) (int32, error) {
+	if err := ValidateOTLPGRPCEndpoint(endpoint); err != nil {
+		return 0, err
+	}
+	return ExtractOTLPEndpointPort(endpoint)
+}
+
+func isHostPortEnabled(config *HostPortConfig) bool {
+	return config != nil && apiutils.BoolValue(config.Enabled) && config.Port != nil
+}
+
+func otlpGRPCConfig(features *DatadogFeatures) *OTLPGRPCConfig {
+	if features.OTLP == nil {
+		return nil
+	}
+	return features.OTLP.Receiver.Protocols.GRPC
+}
+
+func otlpHTTPConfig(features *DatadogFeatures) *OTLPHTTPConfig {
+	if features.OTLP == nil {
+		return nil
+	}
+	return features.OTLP.Receiver.Protocols.HTTP
+}
+
+func isValidOverride(name ComponentName, override *DatadogAgentComponentOverride) []error {
+	switch name {
+	case NodeAgentComponentName, ClusterAgentComponentName, ClusterChecksRunnerComponentName:
+	default:
+		return []error{fmt.Errorf("unknown component %q in spec.override, supported components are %q, %q and %q", name, NodeAgentComponentName, ClusterAgentComponentName, ClusterChecksRunnerComponentName)}
+	}
+	if override == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, fileName := range sortedConfigFileNames(override.CustomConfigurations) {
+		config := override.CustomConfigurations[fileName]
+		errs = appendCustomConfigError(errs, fmt.Sprintf("spec.override.%s.customConfigurations[%s]", name, fileName), &config)
+	}
+	if err := IsValidMultiCustomConfig(override.ExtraConfd); err != nil {
+		errs = append(errs, fmt.Errorf("invalid spec.override.%s.extraConfd, err: %w", name, err))
+	}
+	if err := IsValidMultiCustomConfig(override.ExtraChecksd); err != nil {
+		errs = append(errs, fmt.Errorf("invalid spec.override.%s.extraChecksd, err: %w", name, err))
+	}
+	if override.PDB != nil {
+		if name == NodeAgentComponentName {
+			errs = append(errs, fmt.Errorf("spec.override.%s.pdb is only supported by the %q and %q components", name, ClusterAgentComponentName, ClusterChecksRunnerComponentName))
+		} else if override.PDB.MinAvailable != nil && override.PDB.MaxUnavailable != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.override.%s.pdb, err: 'minAvailable' and 'maxUnavailable' should not be set at the same time", name))
+		}
+	}
+	for containerName, container := range override.Containers {
+		if container != nil && container.SeccompConfig != nil {
+			errs = appendCustomConfigError(errs, fmt.Sprintf("spec.override.%s.containers.%s.seccompConfig.customProfile", name, containerName), container.SeccompConfig.CustomProfile)
+		}
+	}
+
+	return errs
+}
+
+// isValidAutoscaling checks the HorizontalPodAutoscaler configuration of a component override.
+func isValidAutoscaling(name ComponentName, override *DatadogAgentComponentOverride, features *DatadogFeatures) []error {
+	if override == nil || override.Autoscaling == nil {
+		return nil
+	}
+	if name == NodeAgentComponentName {
+		return []error{fmt.Errorf("spec.override.%s.autoscaling is only supported by the %q and %q components", name, ClusterAgentComponentName, ClusterChecksRunnerComponentName)}
+	}
+	autoscaling := override.Autoscaling
+	if !apiutils.BoolValue(autoscaling.Enabled) {
+		return nil
+	}
+
+	var errs []error
+	minReplicas := int32(1)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	switch {
+	case autoscaling.MaxReplicas == nil:
+		errs = append(errs, fmt.Errorf("spec.override.%s.autoscaling.maxReplicas must be set", name))
+	case *autoscaling.MaxReplicas < minReplicas:
+		errs = append(errs, fmt.Errorf("spec.override.%s.autoscaling.maxReplicas must be greater than or equal to minReplicas (%d)", name, minReplicas))
+	}
+	if minReplicas < 1 {
+		errs = append(errs, fmt.Errorf("spec.override.%s.autoscaling.minReplicas must be greater than 0", name))
+	}
+	if autoscaling.ExternalMetric != nil {
+		if features == nil || features.ExternalMetricsServer == nil || !apiutils.BoolValue(features.ExternalMetricsServer.Enabled) {
+			errs = append(errs, fmt.Errorf("spec.override.%s.autoscaling.externalMetric requires the spec.features.externalMetricsServer feature", name))
+		}
+		if autoscaling.ExternalMetric.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.override.%s.autoscaling.externalMetric.name must be set", name))
+		}
+	}
+
+	return errs
+}
+
+// isValidUpdateStrategy checks that the update strategy of a component override is supported by its workload.
+func isValidUpdateStrategy(name ComponentName, override *DatadogAgentComponentOverride) []error {
+	if override == nil || override.UpdateStrategy == nil {
+		return nil
+	}
+	strategy := override.UpdateStrategy
+
+	var errs []error
+	if name == NodeAgentComponentName {
+		if strategy.Type == RecreateStrategyType {
+			errs = append(errs, fmt.Errorf("spec.override.%s.updateStrategy.type %q is only supported by the %q and %q components", name, strategy.Type, ClusterAgentComponentName, ClusterChecksRunnerComponentName))
+		}
+	} else {
+		if strategy.Type == OnDeleteStrategyType {
+			errs = append(errs, fmt.Errorf("spec.override.%s.updateStrategy.type %q is only supported by the %q component", name, strategy.Type, NodeAgentComponentName))
+		}
+		if strategy.Canary != nil {
+			errs = append(errs, fmt.Errorf("spec.override.%s.updateStrategy.canary is only supported by the %q component", name, NodeAgentComponentName))
+		}
+	}
+	if strategy.RollingUpdate != nil && strategy.Type != "" && strategy.Type != RollingUpdateStrategyType {
+		errs = append(errs, fmt.Errorf("spec.override.%s.updateStrategy.rollingUpdate requires the %q type", name, RollingUpdateStrategyType))
+	}
+
+	return errs
+}
+
+// reservedProfileName is the suffix of the node Agent canary DaemonSet, which can't be used by a profile DaemonSet.
+const reservedProfileName = "canary"
+
+// isValidProfiles checks that the node Agent profiles have a unique name, a node selector,
+// and only override the settings that can differ between node Agent DaemonSets.
+func isValidProfiles(profiles []AgentProfile) []error {
+	var errs []error
+	names := make(map[string]bool, len(profiles))
+	for i, profile := range profiles {
+		path := fmt.Sprintf("spec.profiles[%d]", i)
+		if profile.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name must be set", path))
+		} else if profile.Name == reservedProfileName {
+			errs = append(errs, fmt.Errorf("%s.name %q is reserved for the node Agent canary", path, profile.Name))
+		} else if names[profile.Name] {
+			errs = append(errs, fmt.Errorf("%s.name %q is already used by another profile", path, profile.Name))
+		}
+		names[profile.Name] = true
+		if len(profile.NodeSelector) == 0 {
+			errs = append(errs, fmt.Errorf("%s.nodeSelector must be set", path))
+		}
+		if profile.Override == nil {
+			continue
+		}
+
+		unsupported := []struct {
+			field string
+			set   bool
+		}{
+			{"name", profile.Override.Name != nil},
+			{"replicas", profile.Override.Replicas != nil},
+			{"createRbac", profile.Override.CreateRbac != nil},
+			{"customConfigurations", len(profile.Override.CustomConfigurations) > 0},
+			{"extraConfd", profile.Override.ExtraConfd != nil},
+			{"extraChecksd", profile.Override.ExtraChecksd != nil},
+			{"securityContextConstraints", profile.Override.SecurityContextConstraints != nil},
+			{"pdb", profile.Override.PDB != nil},
+			{"autoscaling", profile.Override.Autoscaling != nil},
+		}
+		for _, field := range unsupported {
+			if field.set {
+				errs = append(errs, fmt.Errorf("%s.override.%s is not supported by profiles", path, field.field))
+			}
+		}
+		if strategy := profile.Override.UpdateStrategy; strategy != nil {
+			if strategy.Type == RecreateStrategyType {
+				errs = append(errs, fmt.Errorf("%s.override.updateStrategy.type %q is not supported by the node Agent", path, strategy.Type))
+			}
+			if strategy.RollingUpdate != nil && strategy.Type != "" && strategy.Type != RollingUpdateStrategyType {
+				errs = append(errs, fmt.Errorf("%s.override.updateStrategy.rollingUpdate requires the %q type", path, RollingUpdateStrategyType))
+			}
+		}
+	}
+
+	return errs
+}
+
+func sortedConfigFileNames(configs map[AgentConfigFileName]CustomConfig) []AgentConfigFileName {
+	names := make([]AgentConfigFileName, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
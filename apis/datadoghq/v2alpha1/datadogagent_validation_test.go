@@ -0,0 +1,329 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"testing"
+	"time"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestValidateDatadogAgent(t *testing.T) {
+	credentials := &DatadogCredentials{APIKey: apiutils.NewStringPointer("0000000000000000000000")}
+	configMap := &commonv1.ConfigMapConfig{Name: "foo"}
+	one := intstr.FromInt(1)
+
+	tests := []struct {
+		name    string
+		spec    DatadogAgentSpec
+		wantErr string
+	}{
+		{
+			name: "valid minimal spec",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+			},
+		},
+		{
+			name:    "missing credentials",
+			spec:    DatadogAgentSpec{},
+			wantErr: "spec.global.credentials must be set",
+		},
+		{
+			name: "credentials without API key",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: &DatadogCredentials{AppKey: apiutils.NewStringPointer("0000")}},
+			},
+			wantErr: "spec.global.credentials must set either apiKey or apiSecret",
+		},
+		{
+			name: "unsupported OTLP gRPC endpoint",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+						GRPC: &OTLPGRPCConfig{Enabled: apiutils.NewBoolPointer(true), Endpoint: apiutils.NewStringPointer("unix:///var/run/otlp.sock")},
+					}}},
+				},
+			},
+			wantErr: `invalid spec.features.otlp.receiver.protocols.grpc.endpoint, err: "unix" protocol is not currently supported`,
+		},
+		{
+			name: "APM host port conflicts with the default OTLP gRPC endpoint",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						Enabled:        apiutils.NewBoolPointer(true),
+						HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true), Port: apiutils.NewInt32Pointer(4317)},
+					},
+					OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+						GRPC: &OTLPGRPCConfig{Enabled: apiutils.NewBoolPointer(true)},
+					}}},
+				},
+			},
+			wantErr: "spec.features.apm.hostPortConfig.hostPort and spec.features.otlp.receiver.protocols.grpc.endpoint use the same host port 4317/TCP",
+		},
+		{
+			name: "APM and DogStatsD can share a host port number with different protocols",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						Enabled:        apiutils.NewBoolPointer(true),
+						HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true), Port: apiutils.NewInt32Pointer(8125)},
+					},
+					Dogstatsd: &DogstatsdFeatureConfig{
+						HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true)},
+					},
+				},
+			},
+		},
+		{
+			name: "OTLP gRPC and HTTP on the same port",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+						GRPC: &OTLPGRPCConfig{Enabled: apiutils.NewBoolPointer(true), Endpoint: apiutils.NewStringPointer("0.0.0.0:4318")},
+						HTTP: &OTLPHTTPConfig{Enabled: apiutils.NewBoolPointer(true)},
+					}}},
+				},
+			},
+			wantErr: "spec.features.otlp.receiver.protocols.grpc.endpoint and spec.features.otlp.receiver.protocols.http.endpoint use the same host port 4318/TCP",
+		},
+		{
+			name: "feature custom config with both configData and configMap",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					KubeStateMetricsCore: &KubeStateMetricsCoreFeatureConfig{
+						Conf: &CustomConfig{ConfigData: apiutils.NewStringPointer("foo: bar"), ConfigMap: configMap},
+					},
+				},
+			},
+			wantErr: "invalid spec.features.kubeStateMetricsCore.conf, err: 'configData' and 'configMap' should not be set at the same time",
+		},
+		{
+			name: "override custom configuration with both configData and configMap",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						CustomConfigurations: map[AgentConfigFileName]CustomConfig{
+							AgentGeneralConfigFile: {ConfigData: apiutils.NewStringPointer("foo: bar"), ConfigMap: configMap},
+						},
+					},
+				},
+			},
+			wantErr: "invalid spec.override.nodeAgent.customConfigurations[datadog.yaml], err: 'configData' and 'configMap' should not be set at the same time",
+		},
+		{
+			name: "override extraConfd with both configDataMap and configMap",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterAgentComponentName: {
+						ExtraConfd: &MultiCustomConfig{ConfigDataMap: map[string]string{"foo.yaml": "bar"}, ConfigMap: configMap},
+					},
+				},
+			},
+			wantErr: "invalid spec.override.clusterAgent.extraConfd, err: 'configDataMap' and 'configMap' should not be set at the same time",
+		},
+		{
+			name: "override pdb with both minAvailable and maxUnavailable",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterChecksRunnerComponentName: {
+						PDB: &PodDisruptionBudgetConfig{MinAvailable: &one, MaxUnavailable: &one},
+					},
+				},
+			},
+			wantErr: "invalid spec.override.clusterChecksRunner.pdb, err: 'minAvailable' and 'maxUnavailable' should not be set at the same time",
+		},
+		{
+			name: "override pdb on the node Agent",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						PDB: &PodDisruptionBudgetConfig{Enabled: apiutils.NewBoolPointer(true)},
+					},
+				},
+			},
+			wantErr: `spec.override.nodeAgent.pdb is only supported by the "clusterAgent" and "clusterChecksRunner" components`,
+		},
+		{
+			name: "override autoscaling without maxReplicas",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterChecksRunnerComponentName: {
+						Autoscaling: &AutoscalingConfig{Enabled: apiutils.NewBoolPointer(true), MinReplicas: apiutils.NewInt32Pointer(2)},
+					},
+				},
+			},
+			wantErr: "spec.override.clusterChecksRunner.autoscaling.maxReplicas must be set",
+		},
+		{
+			name: "override autoscaling with an external metric without the external metrics server",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterChecksRunnerComponentName: {
+						Autoscaling: &AutoscalingConfig{
+							Enabled:        apiutils.NewBoolPointer(true),
+							MinReplicas:    apiutils.NewInt32Pointer(2),
+							MaxReplicas:    apiutils.NewInt32Pointer(1),
+							ExternalMetric: &AutoscalingExternalMetricConfig{Name: "datadogmetric@default:checks"},
+						},
+					},
+				},
+			},
+			wantErr: "[spec.override.clusterChecksRunner.autoscaling.maxReplicas must be greater than or equal to minReplicas (2), spec.override.clusterChecksRunner.autoscaling.externalMetric requires the spec.features.externalMetricsServer feature]",
+		},
+		{
+			name: "valid override autoscaling with an external metric",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Features: &DatadogFeatures{
+					ExternalMetricsServer: &ExternalMetricsServerFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+				},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterAgentComponentName: {
+						Autoscaling: &AutoscalingConfig{
+							Enabled:        apiutils.NewBoolPointer(true),
+							MaxReplicas:    apiutils.NewInt32Pointer(3),
+							ExternalMetric: &AutoscalingExternalMetricConfig{Name: "datadogmetric@default:checks"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "override update strategy not supported by the workloads",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: RecreateStrategyType},
+					},
+					ClusterAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{
+							Type:          OnDeleteStrategyType,
+							RollingUpdate: &RollingUpdate{MaxSurge: &intstr.IntOrString{Type: intstr.Int, IntVal: 1}},
+							Canary:        &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{},
+						},
+					},
+				},
+			},
+			wantErr: `[spec.override.clusterAgent.updateStrategy.type "OnDelete" is only supported by the "nodeAgent" component, spec.override.clusterAgent.updateStrategy.canary is only supported by the "nodeAgent" component, spec.override.clusterAgent.updateStrategy.rollingUpdate requires the "RollingUpdate" type, spec.override.nodeAgent.updateStrategy.type "Recreate" is only supported by the "clusterAgent" and "clusterChecksRunner" components]`,
+		},
+		{
+			name: "valid override update strategy",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{
+							RollingUpdate: &RollingUpdate{MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "10%"}},
+							Canary:        &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{Duration: &metav1.Duration{Duration: time.Hour}},
+						},
+					},
+					ClusterChecksRunnerComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: RecreateStrategyType},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown override key",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					"agent": {},
+				},
+			},
+			wantErr: `unknown component "agent" in spec.override, supported components are "nodeAgent", "clusterAgent" and "clusterChecksRunner"`,
+		},
+		{
+			name: "invalid profiles",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Profiles: []AgentProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{Name: "gpu"},
+					{
+						Name:         "highmem",
+						NodeSelector: map[string]string{"pool": "highmem"},
+						Override: &DatadogAgentComponentOverride{
+							Replicas:       apiutils.NewInt32Pointer(2),
+							ExtraConfd:     &MultiCustomConfig{},
+							UpdateStrategy: &UpdateStrategy{Type: RecreateStrategyType},
+						},
+					},
+				},
+			},
+			wantErr: `[spec.profiles[1].name "gpu" is already used by another profile, spec.profiles[1].nodeSelector must be set, spec.profiles[2].override.replicas is not supported by profiles, spec.profiles[2].override.extraConfd is not supported by profiles, spec.profiles[2].override.updateStrategy.type "Recreate" is not supported by the node Agent]`,
+		},
+		{
+			name: "valid profiles",
+			spec: DatadogAgentSpec{
+				Global: &GlobalConfig{Credentials: credentials},
+				Profiles: []AgentProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{
+						Name:         "highmem",
+						NodeSelector: map[string]string{"pool": "highmem"},
+						Override: &DatadogAgentComponentOverride{
+							Image:             &commonv1.AgentImageConfig{Tag: "7.50.0"},
+							PriorityClassName: apiutils.NewStringPointer("high"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{Spec: tt.spec}
+			original := dda.DeepCopy()
+
+			err := ValidateDatadogAgent(dda)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+			assert.Equal(t, original, dda, "the DatadogAgent should not be defaulted in place")
+		})
+	}
+}
+
+func TestValidateDatadogAgent_validateFuncs(t *testing.T) {
+	dda := &DatadogAgent{
+		Spec: DatadogAgentSpec{
+			Global: &GlobalConfig{Credentials: &DatadogCredentials{APIKey: apiutils.NewStringPointer("0000")}},
+		},
+	}
+
+	var defaulted bool
+	err := ValidateDatadogAgent(dda, func(dda *DatadogAgent) error {
+		defaulted = dda.Spec.Features != nil
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, defaulted, "the validateFuncs should receive a defaulted DatadogAgent")
+}
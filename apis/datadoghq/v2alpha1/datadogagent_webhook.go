@@ -6,12 +6,78 @@
 package v2alpha1
 
 import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupWebhookWithManager starts the conversion webhook
-func (r *DatadogAgent) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// ValidateFunc validates a defaulted DatadogAgent.
+// It allows the packages that know more than the DatadogAgent API (e.g. which components the features require)
+// to extend the validating webhook.
+// +kubebuilder:object:generate=false
+type ValidateFunc func(dda *DatadogAgent) error
+
+// SetupWebhookWithManager starts the conversion webhook and the validating webhook.
+// The validateFuncs are run in addition to IsValidDatadogAgent on creation and update.
+func (r *DatadogAgent) SetupWebhookWithManager(mgr ctrl.Manager, validateFuncs ...ValidateFunc) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&datadogAgentValidator{validateFuncs: validateFuncs}).
 		Complete()
 }
+
+//+kubebuilder:webhook:path=/validate-datadoghq-com-v2alpha1-datadogagent,mutating=false,failurePolicy=fail,sideEffects=None,groups=datadoghq.com,resources=datadogagents,verbs=create;update,versions=v2alpha1,name=vdatadogagent.kb.io,admissionReviewVersions=v1
+
+// datadogAgentValidator implements admission.CustomValidator for the DatadogAgent.
+type datadogAgentValidator struct {
+	validateFuncs []ValidateFunc
+}
+
+var _ admission.CustomValidator = &datadogAgentValidator{}
+
+// ValidateCreate validates a DatadogAgent on creation.
+func (v *datadogAgentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+// ValidateUpdate validates a DatadogAgent on update.
+func (v *datadogAgentValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+// ValidateDelete doesn't validate anything: a DatadogAgent can always be deleted.
+func (v *datadogAgentValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *datadogAgentValidator) validate(obj runtime.Object) error {
+	dda, ok := obj.(*DatadogAgent)
+	if !ok {
+		return fmt.Errorf("expected a DatadogAgent, got %T", obj)
+	}
+
+	return ValidateDatadogAgent(dda, v.validateFuncs...)
+}
+
+// ValidateDatadogAgent defaults a copy of the DatadogAgent, then runs IsValidDatadogAgent and the validateFuncs on it.
+// The DatadogAgent passed as argument is not modified.
+func ValidateDatadogAgent(dda *DatadogAgent, validateFuncs ...ValidateFunc) error {
+	instance := dda.DeepCopy()
+	DefaultDatadogAgent(instance)
+
+	var errs []error
+	if err := IsValidDatadogAgent(&instance.Spec); err != nil {
+		errs = append(errs, err)
+	}
+	for _, validateFunc := range validateFuncs {
+		if err := validateFunc(instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
@@ -7,6 +7,9 @@ package v2alpha1
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -14,6 +17,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var (
+	otlpEndpointPortRegexp = regexp.MustCompile(":([0-9]+)$")
+)
+
 // GetConfName get the name of the Configmap for a CustomConfigSpec
 func GetConfName(owner metav1.Object, conf *CustomConfig, defaultName string) string {
 	// `configData` and `configMap` can't be set together.
@@ -115,3 +122,36 @@ func ShouldCreateSCC(dda *DatadogAgent, componentName ComponentName) bool {
 	}
 	return false
 }
+
+// ValidateOTLPGRPCEndpoint returns an error if the OTLP/gRPC endpoint uses a naming scheme that the Datadog Operator doesn't support
+func ValidateOTLPGRPCEndpoint(endpoint string) error {
+	for _, protocol := range []string{"unix", "unix-abstract"} {
+		if strings.HasPrefix(endpoint, protocol+":") {
+			return fmt.Errorf("%q protocol is not currently supported", protocol)
+		}
+	}
+
+	return nil
+}
+
+// ExtractOTLPEndpointPort returns the port of an OTLP endpoint in the 'host:port' format
+func ExtractOTLPEndpointPort(endpoint string) (int32, error) {
+	if match := otlpEndpointPortRegexp.FindStringSubmatch(endpoint); match != nil {
+		if len(match) < 2 {
+			return 0, fmt.Errorf("no match for port on %q", endpoint)
+		}
+		portStr := match[1]
+
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return 0, fmt.Errorf("could not cast port %q from endpoint %q to int: %w", portStr, endpoint, err)
+		}
+
+		if port < 0 || port > 65535 {
+			return 0, fmt.Errorf("port is outside valid range: %d", port)
+		}
+
+		return int32(port), nil
+	}
+	return 0, fmt.Errorf("%q does not have a port explicitly set", endpoint)
+}
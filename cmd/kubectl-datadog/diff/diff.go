@@ -0,0 +1,427 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+var diffExample = `
+  # show the changes that the operator would do in the cluster if the DatadogAgent foo was replaced by datadog-agent.yaml
+  %[1]s diff foo -f datadog-agent.yaml
+
+  # show the changes that the operator would do in the cluster for the current DatadogAgent foo
+  %[1]s diff foo
+
+  # take the DatadogAgentExtensions of the namespace into account
+  %[1]s diff foo -f datadog-agent.yaml --extensions
+`
+
+// options provides information required by Datadog diff command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args                      []string
+	userDatadogAgentName      string
+	filePath                  string
+	extensions                bool
+	supportExtendedDaemonset  bool
+	supportCilium             bool
+	edsMaxPodUnavailable      string
+	edsMaxPodSchedulerFailure string
+	edsCanaryDuration         time.Duration
+	edsCanaryReplicas         string
+	edsCanaryAutoPauseEnabled bool
+	edsCanaryAutoFailEnabled  bool
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "diff" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "diff <DatadogAgent name> [-f <DatadogAgent file>]",
+		Short:        "Show the changes that the operator would do in the cluster for a modified DatadogAgent",
+		Example:      fmt.Sprintf(diffExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filePath, "file", "f", "", "Path to the modified v2alpha1 DatadogAgent manifest, \"-\" to read from stdin. The DatadogAgent of the cluster is used if not set")
+	cmd.Flags().BoolVar(&o.extensions, "extensions", false, "Apply the DatadogAgentExtensions of the namespace, as the operator does when they are enabled")
+	cmd.Flags().BoolVar(&o.supportExtendedDaemonset, "support-extendeddaemonset", false, "The operator deploys an ExtendedDaemonSet instead of a DaemonSet for the Agent")
+	cmd.Flags().BoolVar(&o.supportCilium, "support-cilium", false, "The operator manages the Cilium network policies")
+	cmd.Flags().StringVar(&o.edsMaxPodUnavailable, "edsMaxPodUnavailable", "", "ExtendedDaemonset number of max unavailable pods during the rolling update")
+	cmd.Flags().StringVar(&o.edsMaxPodSchedulerFailure, "edsMaxPodSchedulerFailure", "", "ExtendedDaemonset number of max pod scheduler failures")
+	cmd.Flags().DurationVar(&o.edsCanaryDuration, "edsCanaryDuration", 10*time.Minute, "ExtendedDaemonset canary duration")
+	cmd.Flags().StringVar(&o.edsCanaryReplicas, "edsCanaryReplicas", "", "ExtendedDaemonset number of canary pods")
+	cmd.Flags().BoolVar(&o.edsCanaryAutoPauseEnabled, "edsCanaryAutoPauseEnabled", true, "ExtendedDaemonset canary auto pause enabled")
+	cmd.Flags().BoolVar(&o.edsCanaryAutoFailEnabled, "edsCanaryAutoFailEnabled", true, "ExtendedDaemonset canary auto fail enabled")
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	if len(args) > 0 {
+		o.userDatadogAgentName = args[0]
+	}
+
+	// The client must be able to read every kind of object managed by the operator.
+	utilruntime.Must(apiregistrationv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(edsv1alpha1.AddToScheme(scheme.Scheme))
+
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.args) != 1 {
+		return errors.New("the DatadogAgent name must be provided")
+	}
+	if !o.IsDatadogAgentV2Available() {
+		return errors.New("the v2alpha1 DatadogAgent is not available in the cluster")
+	}
+	return nil
+}
+
+// run runs the diff command.
+func (o *options) run() error {
+	ctx := context.TODO()
+
+	current := &v2alpha1.DatadogAgent{}
+	err := o.Client.Get(ctx, client.ObjectKey{Namespace: o.UserNamespace, Name: o.userDatadogAgentName}, current)
+	if err != nil && apierrors.IsNotFound(err) {
+		return fmt.Errorf("DatadogAgent %s/%s not found", o.UserNamespace, o.userDatadogAgentName)
+	} else if err != nil {
+		return fmt.Errorf("unable to get DatadogAgent: %w", err)
+	}
+
+	dda := current
+	if o.filePath != "" {
+		if dda, err = o.readDatadogAgent(current); err != nil {
+			return err
+		}
+	}
+
+	renderOptions, err := o.renderOptions(ctx, dda.Namespace)
+	if err != nil {
+		return err
+	}
+
+	changes, err := datadogagent.DiffV2(ctx, o.Client, dda, renderOptions)
+	if err != nil {
+		return fmt.Errorf("unable to compute the changes of DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	return printChanges(o.Out, changes)
+}
+
+// readDatadogAgent reads the modified DatadogAgent. The fields set by the api-server and the status,
+// which contains the generated Cluster Agent token, are copied from the DatadogAgent of the cluster.
+func (o *options) readDatadogAgent(current *v2alpha1.DatadogAgent) (*v2alpha1.DatadogAgent, error) {
+	var data []byte
+	var err error
+	if o.filePath == "-" {
+		data, err = io.ReadAll(o.In)
+	} else {
+		data, err = os.ReadFile(o.filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", o.filePath, err)
+	}
+
+	dda := &v2alpha1.DatadogAgent{}
+	if err = yaml.UnmarshalStrict(data, dda); err != nil {
+		return nil, fmt.Errorf("unable to decode DatadogAgent from %s: %w", o.filePath, err)
+	}
+	if dda.APIVersion != "" && dda.APIVersion != v2alpha1.GroupVersion.String() {
+		return nil, fmt.Errorf("only %s DatadogAgent can be compared, got %s", v2alpha1.GroupVersion.String(), dda.APIVersion)
+	}
+	if (dda.Name != "" && dda.Name != current.Name) || (dda.Namespace != "" && dda.Namespace != current.Namespace) {
+		return nil, fmt.Errorf("the DatadogAgent of %s is %s/%s, expected %s/%s", o.filePath, dda.Namespace, dda.Name, current.Namespace, current.Name)
+	}
+
+	dda.Name = current.Name
+	dda.Namespace = current.Namespace
+	dda.UID = current.UID
+	dda.Status = *current.Status.DeepCopy()
+
+	return dda, nil
+}
+
+// renderOptions gets from the api-server the information used by the operator to render the objects.
+func (o *options) renderOptions(ctx context.Context, namespace string) (*datadogagent.RenderOptions, error) {
+	restConfig, err := o.GetClientConfig().ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get rest client config: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get discovery client: %w", err)
+	}
+	versionInfo, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get APIServer version: %w", err)
+	}
+	groups, resources, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("unable to get API resource versions: %w", err)
+	}
+
+	var extensions []v1alpha1.DatadogAgentExtension
+	if o.extensions {
+		extensionList := &v1alpha1.DatadogAgentExtensionList{}
+		if err = o.Client.List(ctx, extensionList, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("unable to list DatadogAgentExtensions: %w", err)
+		}
+		extensions = extensionList.Items
+	}
+
+	return &datadogagent.RenderOptions{
+		ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+			Enabled:                o.supportExtendedDaemonset,
+			MaxPodUnavailable:      o.edsMaxPodUnavailable,
+			MaxPodSchedulerFailure: o.edsMaxPodSchedulerFailure,
+			CanaryDuration:         o.edsCanaryDuration,
+			CanaryReplicas:         o.edsCanaryReplicas,
+			CanaryAutoPauseEnabled: o.edsCanaryAutoPauseEnabled,
+			CanaryAutoFailEnabled:  o.edsCanaryAutoFailEnabled,
+		},
+		SupportCilium: o.supportCilium,
+		VersionInfo:   versionInfo,
+		PlatformInfo:  kubernetes.NewPlatformInfo(versionInfo, groups, resources),
+		Scheme:        scheme.Scheme,
+		Logger:        logr.Discard(),
+		Extensions:    extensions,
+	}, nil
+}
+
+// printChanges writes a unified diff for each change, followed by a summary of the changes.
+func printChanges(out io.Writer, changes []datadogagent.ObjectChange) error {
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No changes")
+		return nil
+	}
+
+	for _, change := range changes {
+		diff, err := unifiedDiff(change.Change)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, diff)
+	}
+
+	fmt.Fprintln(out, "\nSummary:")
+	for _, change := range changes {
+		line := fmt.Sprintf("  %s %s", change.Type, objectName(change.Change))
+		if change.Field != "" {
+			line += fmt.Sprintf(" (%s)", change.Field)
+		}
+		if change.RollsPods {
+			line += ": pods would roll"
+		}
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// unifiedDiff returns the diff between the normalized current and desired objects of a change.
+func unifiedDiff(change dependencies.Change) (string, error) {
+	var desired, current map[string]interface{}
+	var err error
+	if change.Desired != nil {
+		if desired, err = normalize(change.Desired); err != nil {
+			return "", err
+		}
+	}
+	if change.Current != nil && change.Type == dependencies.UpdateChange {
+		if current, err = normalize(change.Current); err != nil {
+			return "", err
+		}
+		// The fields that are not managed by the operator, like the defaults set by the api-server, are ignored.
+		current = prune(current, desired).(map[string]interface{})
+	}
+
+	if change.Kind == kubernetes.SecretsKind {
+		maskSecrets(current, desired)
+	}
+
+	name := objectName(change)
+	if change.Type == dependencies.DeleteChange {
+		return fmt.Sprintf("--- %s\n+++ /dev/null\n", name), nil
+	}
+
+	desiredYAML, err := toYAML(desired)
+	if err != nil {
+		return "", err
+	}
+	currentYAML, err := toYAML(current)
+	if err != nil {
+		return "", err
+	}
+	fromFile := name
+	if change.Type == dependencies.CreateChange {
+		fromFile = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(currentYAML),
+		B:        difflib.SplitLines(desiredYAML),
+		FromFile: fromFile,
+		ToFile:   name,
+		Context:  3,
+	})
+}
+
+func objectName(change dependencies.Change) string {
+	obj := change.Object()
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", change.Kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", change.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// normalize converts an object to unstructured, without the status and the metadata fields set by the api-server.
+func normalize(obj client.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	delete(content, "apiVersion")
+	delete(content, "kind")
+
+	metadata, _ := content["metadata"].(map[string]interface{})
+	normalizedMetadata := map[string]interface{}{}
+	for _, key := range []string{"name", "namespace", "labels", "annotations", "ownerReferences"} {
+		if value, found := metadata[key]; found {
+			normalizedMetadata[key] = value
+		}
+	}
+	content["metadata"] = normalizedMetadata
+
+	return content, nil
+}
+
+// maskSecrets replaces the values of the Secret data like `kubectl diff`, to not print the credentials:
+// the unchanged values are replaced by "***", the changed ones by "*** (before)" and "*** (after)".
+func maskSecrets(current, desired map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		currentData, _ := current[field].(map[string]interface{})
+		desiredData, _ := desired[field].(map[string]interface{})
+		for key, currentValue := range currentData {
+			desiredValue, found := desiredData[key]
+			if !found {
+				currentData[key] = "***"
+				continue
+			}
+			if reflect.DeepEqual(currentValue, desiredValue) {
+				currentData[key] = "***"
+				desiredData[key] = "***"
+				continue
+			}
+			currentData[key] = "*** (before)"
+			desiredData[key] = "*** (after)"
+		}
+		for key := range desiredData {
+			if _, found := currentData[key]; !found {
+				desiredData[key] = "***"
+			}
+		}
+	}
+}
+
+// prune removes from current the map keys that are not in desired. Lists are pruned element by element.
+func prune(current, desired interface{}) interface{} {
+	switch currentValue := current.(type) {
+	case map[string]interface{}:
+		desiredMap, ok := desired.(map[string]interface{})
+		if !ok {
+			return current
+		}
+		pruned := make(map[string]interface{}, len(desiredMap))
+		for key, value := range currentValue {
+			if desiredValue, found := desiredMap[key]; found {
+				pruned[key] = prune(value, desiredValue)
+			}
+		}
+		return pruned
+	case []interface{}:
+		desiredList, ok := desired.([]interface{})
+		if !ok {
+			return current
+		}
+		pruned := make([]interface{}, len(currentValue))
+		for i, value := range currentValue {
+			if i < len(desiredList) {
+				pruned[i] = prune(value, desiredList[i])
+			} else {
+				pruned[i] = value
+			}
+		}
+		return pruned
+	}
+	return current
+}
+
+func toYAML(content map[string]interface{}) (string, error) {
+	if content == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func Test_prune(t *testing.T) {
+	current := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas":        int64(1),
+			"revisionHistory": int64(10),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "agent", "imagePullPolicy": "IfNotPresent"},
+				map[string]interface{}{"name": "trace-agent"},
+			},
+		},
+	}
+	desired := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "agent"},
+			},
+		},
+	}
+
+	want := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "agent"},
+				map[string]interface{}{"name": "trace-agent"},
+			},
+		},
+	}
+	assert.Equal(t, want, prune(current, desired))
+}
+
+func Test_printChanges(t *testing.T) {
+	current := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-config", ResourceVersion: "42"},
+		Data:       map[string]string{"config.yaml": "a: b\n"},
+	}
+	desired := current.DeepCopy()
+	desired.ResourceVersion = ""
+	desired.Data["config.yaml"] = "a: c\n"
+
+	changes := []datadogagent.ObjectChange{
+		{
+			Change:    dependencies.Change{Type: dependencies.UpdateChange, Kind: kubernetes.DaemonSetsKind, Desired: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"}}, Current: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"}}, Field: "spec.template"},
+			RollsPods: true,
+		},
+		{Change: dependencies.Change{Type: dependencies.UpdateChange, Kind: kubernetes.ConfigMapKind, Desired: desired, Current: current, Field: "data"}},
+		{Change: dependencies.Change{Type: dependencies.DeleteChange, Kind: kubernetes.ClusterRolesKind, Current: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "foo-old"}}}},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printChanges(out, changes))
+
+	assert.Contains(t, out.String(), `--- configmaps/bar/foo-config
++++ configmaps/bar/foo-config
+@@ -1,6 +1,6 @@
+ data:
+   config.yaml: |
+-    a: b
++    a: c
+ metadata:
+   name: foo-config
+   namespace: bar
+`)
+	assert.Contains(t, out.String(), "--- clusterroles/foo-old\n+++ /dev/null\n")
+	assert.Contains(t, out.String(), `Summary:
+  update daemonsets/bar/foo-agent (spec.template): pods would roll
+  update configmaps/bar/foo-config (data)
+  delete clusterroles/foo-old
+`)
+
+	out.Reset()
+	require.NoError(t, printChanges(out, nil))
+	assert.Equal(t, "No changes\n", out.String())
+}
+
+func Test_unifiedDiff_secrets(t *testing.T) {
+	current := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-secret"},
+		Data: map[string][]byte{
+			"api_key":     []byte("current-api-key"),
+			"app_key":     []byte("app-key"),
+			"removed_key": []byte("removed"),
+		},
+	}
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-secret"},
+		Data: map[string][]byte{
+			"api_key": []byte("new-api-key"),
+			"app_key": []byte("app-key"),
+			"new_key": []byte("new"),
+		},
+		StringData: map[string]string{"token": "cluster-agent-token"},
+	}
+
+	diff, err := unifiedDiff(dependencies.Change{Type: dependencies.UpdateChange, Kind: kubernetes.SecretsKind, Desired: desired, Current: current})
+	require.NoError(t, err)
+	assert.Equal(t, `--- secrets/bar/foo-secret
++++ secrets/bar/foo-secret
+@@ -1,7 +1,10 @@
+ data:
+-  api_key: '*** (before)'
++  api_key: '*** (after)'
+   app_key: '***'
++  new_key: '***'
+ metadata:
+   name: foo-secret
+   namespace: bar
++stringData:
++  token: '***'
+ 
+`, diff)
+
+	// The values of a created Secret are masked too
+	diff, err = unifiedDiff(dependencies.Change{Type: dependencies.CreateChange, Kind: kubernetes.SecretsKind, Desired: desired})
+	require.NoError(t, err)
+	assert.NotContains(t, diff, "cluster-agent-token")
+	assert.NotContains(t, diff, base64.StdEncoding.EncodeToString([]byte("new-api-key")))
+}
@@ -0,0 +1,360 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	// pageSize is the number of monitors requested per page when listing or searching monitors
+	pageSize = 100
+	// maxNameLength is the maximum length of the generated DatadogMonitor names
+	maxNameLength = 63
+)
+
+var exportExample = `
+  # export the monitors 1234 and 5678 as DatadogMonitors of the datadog namespace
+  %[1]s export --id 1234,5678 -n datadog
+
+  # export the monitors tagged with team:web, one file per DatadogMonitor
+  %[1]s export --tags team:web --output-dir ./monitors
+
+  # export the monitors matching a monitor search query, without adopting them
+  %[1]s export --query "type:metric status:alert" --adopt=false
+`
+
+var (
+	// invalidNameCharsRegexp matches the characters that can't be used in a DatadogMonitor name
+	invalidNameCharsRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+	// monitorIDRegexp matches the monitor IDs of a composite query
+	monitorIDRegexp = regexp.MustCompile(`\b[0-9]+\b`)
+)
+
+// options provides information required by the monitor export command.
+type options struct {
+	genericclioptions.IOStreams
+	ids       []int
+	tags      []string
+	query     string
+	apiKey    string
+	appKey    string
+	site      string
+	namespace string
+	outputDir string
+	adopt     bool
+	ddClient  datadogclient.DatadogClient
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		IOStreams: streams,
+	}
+}
+
+// New provides a cobra command wrapping options for "export" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "export [flags]",
+		Short:        "Export Datadog monitors as DatadogMonitor manifests",
+		Example:      fmt.Sprintf(exportExample, "kubectl datadog monitor"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().IntSliceVar(&o.ids, "id", nil, "IDs of the monitors to export")
+	cmd.Flags().StringSliceVar(&o.tags, "tags", nil, "Export the monitors with all these monitor tags")
+	cmd.Flags().StringVar(&o.query, "query", "", "Export the monitors matching this monitor search query")
+	cmd.Flags().StringVar(&o.apiKey, "api-key", "", "Datadog API key, defaults to $DD_API_KEY")
+	cmd.Flags().StringVar(&o.appKey, "app-key", "", "Datadog application key, defaults to $DD_APP_KEY")
+	cmd.Flags().StringVar(&o.site, "site", "", "Datadog site of the monitors, like datadoghq.eu, defaults to $DD_SITE")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "default", "Namespace of the DatadogMonitors")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "Write one file per DatadogMonitor in this directory instead of printing them")
+	cmd.Flags().BoolVar(&o.adopt, "adopt", true, "Set spec.adoptMonitorID so that the DatadogMonitors take ownership of the exported monitors")
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are allowed, use --id, --tags or --query to select the monitors")
+	}
+
+	// The keys are read from the environment here rather than as flag defaults, so that they aren't printed by --help
+	if o.apiKey == "" {
+		o.apiKey = os.Getenv(config.DDAPIKeyEnvVar)
+	}
+	if o.appKey == "" {
+		o.appKey = os.Getenv(config.DDAppKeyEnvVar)
+	}
+
+	ddClient, err := datadogclient.InitDatadogClientForSite(logr.Discard(), config.Creds{APIKey: o.apiKey, AppKey: o.appKey}, o.site)
+	if err != nil {
+		return fmt.Errorf("unable to create the Datadog client, check --api-key and --app-key: %w", err)
+	}
+	o.ddClient = ddClient
+
+	return nil
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.ids) == 0 && len(o.tags) == 0 && o.query == "" {
+		return errors.New("at least one of --id, --tags or --query must be provided")
+	}
+	return nil
+}
+
+// run runs the export command.
+func (o *options) run() error {
+	monitors, err := o.getMonitors()
+	if err != nil {
+		return err
+	}
+
+	dms := buildDatadogMonitors(o.ErrOut, monitors, o.namespace, o.adopt)
+	if o.outputDir == "" {
+		return printDatadogMonitors(o.Out, dms)
+	}
+
+	if err = os.MkdirAll(o.outputDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create %s: %w", o.outputDir, err)
+	}
+	for _, dm := range dms {
+		path := filepath.Join(o.outputDir, dm.Name+".yaml")
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %w", path, err)
+		}
+		err = printDatadogMonitors(f, []*v1alpha1.DatadogMonitor{dm})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write %s: %w", path, err)
+		}
+		fmt.Fprintf(o.ErrOut, "DatadogMonitor %s written to %s\n", dm.Name, path)
+	}
+
+	return nil
+}
+
+// getMonitors returns the monitors selected by the flags, sorted by ID.
+func (o *options) getMonitors() ([]datadogapiclientv1.Monitor, error) {
+	auth, client := o.ddClient.Auth, o.ddClient.Client
+	byID := map[int64]datadogapiclientv1.Monitor{}
+
+	ids := make([]int64, 0, len(o.ids))
+	for _, id := range o.ids {
+		ids = append(ids, int64(id))
+	}
+
+	if len(o.tags) > 0 {
+		params := datadogapiclientv1.NewListMonitorsOptionalParameters().
+			WithMonitorTags(strings.Join(o.tags, ",")).
+			WithPageSize(pageSize)
+		for page := int64(0); ; page++ {
+			monitors, httpResp, err := client.MonitorsApi.ListMonitors(auth, *params.WithPage(page))
+			if err != nil {
+				return nil, fmt.Errorf("unable to list the monitors with tags %s: %w", strings.Join(o.tags, ","), err)
+			}
+			if err = datadogmonitor.SetMonitorsAdditionalOptions(monitors, httpResp); err != nil {
+				return nil, fmt.Errorf("unable to read the options of the monitors with tags %s: %w", strings.Join(o.tags, ","), err)
+			}
+			for _, m := range monitors {
+				byID[m.GetId()] = m
+			}
+			if len(monitors) < pageSize {
+				break
+			}
+		}
+	}
+
+	if o.query != "" {
+		params := datadogapiclientv1.NewSearchMonitorsOptionalParameters().
+			WithQuery(o.query).
+			WithPerPage(pageSize)
+		for page := int64(0); ; page++ {
+			resp, _, err := client.MonitorsApi.SearchMonitors(auth, *params.WithPage(page))
+			if err != nil {
+				return nil, fmt.Errorf("unable to search the monitors matching %q: %w", o.query, err)
+			}
+			// The search results don't include the monitor definitions, they are fetched by ID
+			for _, result := range resp.GetMonitors() {
+				ids = append(ids, result.GetId())
+			}
+			metadata := resp.GetMetadata()
+			if page+1 >= metadata.GetPageCount() {
+				break
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, found := byID[id]; found {
+			continue
+		}
+		m, httpResp, err := client.MonitorsApi.GetMonitor(auth, id)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the monitor %d: %w", id, err)
+		}
+		if err = datadogmonitor.SetMonitorAdditionalOptions(&m, httpResp); err != nil {
+			return nil, fmt.Errorf("unable to read the options of the monitor %d: %w", id, err)
+		}
+		byID[id] = m
+	}
+
+	monitors := make([]datadogapiclientv1.Monitor, 0, len(byID))
+	for _, m := range byID {
+		monitors = append(monitors, m)
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		return monitors[i].GetId() < monitors[j].GetId()
+	})
+
+	return monitors, nil
+}
+
+// buildDatadogMonitors converts the monitors to DatadogMonitors of the namespace.
+// The monitors of unsupported types are skipped, and the composite monitors reference
+// the other exported monitors by DatadogMonitor name.
+func buildDatadogMonitors(warnings io.Writer, monitors []datadogapiclientv1.Monitor, namespace string, adopt bool) []*v1alpha1.DatadogMonitor {
+	names := map[int64]string{}
+	used := map[string]bool{}
+	supported := make([]datadogapiclientv1.Monitor, 0, len(monitors))
+	for _, m := range monitors {
+		if !datadogmonitor.IsSupportedMonitorType(v1alpha1.DatadogMonitorType(m.GetType())) {
+			fmt.Fprintf(warnings, "Skipping monitor %d: monitors of type %s can't be managed with a DatadogMonitor\n", m.GetId(), m.GetType())
+			continue
+		}
+		name := datadogMonitorName(m.GetName(), m.GetId(), used)
+		used[name] = true
+		names[m.GetId()] = name
+		supported = append(supported, m)
+	}
+
+	dms := make([]*v1alpha1.DatadogMonitor, 0, len(supported))
+	for _, m := range supported {
+		spec := datadogmonitor.BuildDatadogMonitorSpec(m)
+		if spec.Type == v1alpha1.DatadogMonitorTypeComposite {
+			spec.Query = compositeQueryWithReferences(spec.Query, names)
+		}
+		if adopt {
+			spec.AdoptMonitorID = int(m.GetId())
+		}
+
+		dms = append(dms, &v1alpha1.DatadogMonitor{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "DatadogMonitor",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      names[m.GetId()],
+			},
+			Spec: spec,
+		})
+	}
+
+	return dms
+}
+
+// datadogMonitorName returns a DatadogMonitor name derived from the monitor name, unique among the used names.
+func datadogMonitorName(monitorName string, id int64, used map[string]bool) string {
+	name := strings.Trim(invalidNameCharsRegexp.ReplaceAllString(strings.ToLower(monitorName), "-"), "-")
+	if len(name) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength], "-")
+	}
+	if name == "" {
+		name = fmt.Sprintf("monitor-%d", id)
+	}
+
+	// Suffix the name with the monitor ID, then with a counter if another monitor is named like the suffixed name
+	candidate := name
+	for i := 0; used[candidate]; i++ {
+		suffix := "-" + strconv.FormatInt(id, 10)
+		if i > 0 {
+			suffix += "-" + strconv.Itoa(i)
+		}
+		base := name
+		if len(base)+len(suffix) > maxNameLength {
+			base = strings.TrimRight(base[:maxNameLength-len(suffix)], "-")
+		}
+		candidate = base + suffix
+	}
+	return candidate
+}
+
+// compositeQueryWithReferences replaces the IDs of the exported monitors in a composite query with ${<DatadogMonitor name>}.
+func compositeQueryWithReferences(query string, names map[int64]string) string {
+	return monitorIDRegexp.ReplaceAllStringFunc(query, func(match string) string {
+		id, err := strconv.ParseInt(match, 10, 64)
+		if err != nil {
+			return match
+		}
+		if name, found := names[id]; found {
+			return "${" + name + "}"
+		}
+		return match
+	})
+}
+
+// printDatadogMonitors writes the DatadogMonitors as a multi-document YAML stream, without their empty status.
+func printDatadogMonitors(out io.Writer, dms []*v1alpha1.DatadogMonitor) error {
+	for i, dm := range dms {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dm)
+		if err != nil {
+			return err
+		}
+		delete(obj, "status")
+		unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err = out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_datadogMonitorName(t *testing.T) {
+	used := map[string]bool{"disk-usage": true, "monitor-5678": true, "web-5678": true, "web": true}
+	tests := []struct {
+		name        string
+		monitorName string
+		id          int64
+		want        string
+	}{
+		{
+			name:        "lower case with dashes",
+			monitorName: "[Prod] High CPU usage on {{host.name}}",
+			want:        "prod-high-cpu-usage-on-host-name",
+		},
+		{
+			name:        "name already used",
+			monitorName: "Disk usage",
+			want:        "disk-usage-1234",
+		},
+		{
+			name:        "no valid character",
+			monitorName: "⚠️",
+			want:        "monitor-1234",
+		},
+		{
+			name:        "named like the fallback of another monitor",
+			monitorName: "Monitor 5678",
+			id:          5678,
+			want:        "monitor-5678-5678",
+		},
+		{
+			name:        "fallback already used",
+			monitorName: "⚠️",
+			id:          5678,
+			want:        "monitor-5678-5678",
+		},
+		{
+			name:        "suffixed name already used",
+			monitorName: "Web",
+			id:          5678,
+			want:        "web-5678-1",
+		},
+		{
+			name:        "truncated",
+			monitorName: strings.Repeat("a", 60) + " bcdef",
+			want:        strings.Repeat("a", 60) + "-bc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.id
+			if id == 0 {
+				id = 1234
+			}
+			assert.Equal(t, tt.want, datadogMonitorName(tt.monitorName, id, used))
+		})
+	}
+}
+
+func Test_compositeQueryWithReferences(t *testing.T) {
+	names := map[int64]string{1234: "high-cpu", 5678: "disk-usage"}
+	assert.Equal(t, "${high-cpu} && !${disk-usage} || 42", compositeQueryWithReferences("1234 && !5678 || 42", names))
+}
+
+func Test_buildDatadogMonitors(t *testing.T) {
+	newMonitor := func(id int64, name string, monitorType datadogapiclientv1.MonitorType, query string) datadogapiclientv1.Monitor {
+		m := datadogapiclientv1.NewMonitor(query, monitorType)
+		m.SetId(id)
+		m.SetName(name)
+		m.SetMessage("something is wrong")
+		return *m
+	}
+	monitors := []datadogapiclientv1.Monitor{
+		newMonitor(1234, "High CPU", datadogapiclientv1.MONITORTYPE_METRIC_ALERT, "avg(last_5m):avg:system.cpu.user{*} > 90"),
+		newMonitor(2345, "Homepage check", datadogapiclientv1.MONITORTYPE_SYNTHETICS_ALERT, `"http".over("*").by("*").last(2).count_by_status()`),
+		newMonitor(3456, "High CPU and disk", datadogapiclientv1.MONITORTYPE_COMPOSITE, "1234 && 5678"),
+	}
+
+	warnings := &bytes.Buffer{}
+	dms := buildDatadogMonitors(warnings, monitors, "datadog", true)
+	require.Len(t, dms, 2)
+	assert.Contains(t, warnings.String(), "Skipping monitor 2345")
+
+	assert.Equal(t, "high-cpu", dms[0].Name)
+	assert.Equal(t, "datadog", dms[0].Namespace)
+	assert.Equal(t, "DatadogMonitor", dms[0].Kind)
+	assert.Equal(t, v1alpha1.DatadogMonitorTypeMetric, dms[0].Spec.Type)
+	assert.Equal(t, 1234, dms[0].Spec.AdoptMonitorID)
+
+	// 5678 isn't exported, it stays referenced by ID
+	assert.Equal(t, "high-cpu-and-disk", dms[1].Name)
+	assert.Equal(t, "${high-cpu} && 5678", dms[1].Spec.Query)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printDatadogMonitors(out, dms))
+	assert.Contains(t, out.String(), "adoptMonitorID: 1234\n")
+	assert.NotContains(t, out.String(), "status:")
+	assert.NotContains(t, out.String(), "creationTimestamp:")
+	assert.Equal(t, 1, strings.Count(out.String(), "\n---\n"))
+}
@@ -0,0 +1,25 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package monitor
+
+import (
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/monitor/export"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// New provides a cobra command wrapping the "monitor" sub commands
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor [subcommand] [flags]",
+		Short: "Manage Datadog monitors as DatadogMonitors",
+	}
+
+	cmd.AddCommand(export.New(streams))
+
+	return cmd
+}
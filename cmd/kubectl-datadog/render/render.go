@@ -0,0 +1,255 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+const defaultKubeVersion = "v1.25.0"
+
+var renderExample = `
+  # render the objects that the operator would create for the DatadogAgent defined in datadog-agent.yaml
+  %[1]s render -f datadog-agent.yaml
+
+  # render for a Kubernetes v1.20 cluster with the ExtendedDaemonSet support enabled
+  %[1]s render -f datadog-agent.yaml --kube-version v1.20.0 --support-extendeddaemonset
+
+  # render for a cluster exposing PodDisruptionBudget only in policy/v1beta1
+  %[1]s render -f datadog-agent.yaml --api-groups policy/v1beta1/PodDisruptionBudget
+`
+
+// options provides information required by Datadog render command.
+type options struct {
+	genericclioptions.IOStreams
+	filePath                  string
+	kubeVersion               string
+	apiGroups                 []string
+	supportExtendedDaemonset  bool
+	supportCilium             bool
+	edsMaxPodUnavailable      string
+	edsMaxPodSchedulerFailure string
+	edsCanaryDuration         time.Duration
+	edsCanaryReplicas         string
+	edsCanaryAutoPauseEnabled bool
+	edsCanaryAutoFailEnabled  bool
+	versionInfo               *version.Info
+	platformInfo              kubernetes.PlatformInfo
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		IOStreams: streams,
+	}
+}
+
+// New provides a cobra command wrapping options for "render" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "render -f <DatadogAgent file>",
+		Short:        "Render the objects created by the operator for a DatadogAgent, without cluster access",
+		Example:      fmt.Sprintf(renderExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filePath, "file", "f", "", "Path to the v2alpha1 DatadogAgent manifest, \"-\" to read from stdin")
+	cmd.Flags().StringVar(&o.kubeVersion, "kube-version", defaultKubeVersion, "Kubernetes version of the target cluster")
+	cmd.Flags().StringSliceVar(&o.apiGroups, "api-groups", nil, "Preferred API versions of the target cluster, as <group>/<version>/<Kind> (<version>/<Kind> for the core group)")
+	cmd.Flags().BoolVar(&o.supportExtendedDaemonset, "support-extendeddaemonset", false, "Render an ExtendedDaemonSet instead of a DaemonSet for the Agent")
+	cmd.Flags().BoolVar(&o.supportCilium, "support-cilium", false, "Render the Cilium network policies")
+	cmd.Flags().StringVar(&o.edsMaxPodUnavailable, "edsMaxPodUnavailable", "", "ExtendedDaemonset number of max unavailable pods during the rolling update")
+	cmd.Flags().StringVar(&o.edsMaxPodSchedulerFailure, "edsMaxPodSchedulerFailure", "", "ExtendedDaemonset number of max pod scheduler failures")
+	cmd.Flags().DurationVar(&o.edsCanaryDuration, "edsCanaryDuration", 10*time.Minute, "ExtendedDaemonset canary duration")
+	cmd.Flags().StringVar(&o.edsCanaryReplicas, "edsCanaryReplicas", "", "ExtendedDaemonset number of canary pods")
+	cmd.Flags().BoolVar(&o.edsCanaryAutoPauseEnabled, "edsCanaryAutoPauseEnabled", true, "ExtendedDaemonset canary auto pause enabled")
+	cmd.Flags().BoolVar(&o.edsCanaryAutoFailEnabled, "edsCanaryAutoFailEnabled", true, "ExtendedDaemonset canary auto fail enabled")
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	versionInfo, err := parseKubeVersion(o.kubeVersion)
+	if err != nil {
+		return err
+	}
+	o.versionInfo = versionInfo
+
+	preferred, err := parseAPIGroups(o.apiGroups)
+	if err != nil {
+		return err
+	}
+	o.platformInfo = kubernetes.NewPlatformInfoFromVersionMaps(versionInfo, preferred, map[string]string{})
+
+	return nil
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if o.filePath == "" {
+		return errors.New("a DatadogAgent file must be provided with --file")
+	}
+	return nil
+}
+
+// run runs the render command.
+func (o *options) run() error {
+	dda, err := o.readDatadogAgent()
+	if err != nil {
+		return err
+	}
+
+	scheme := newScheme()
+	objs, err := datadogagent.RenderV2(dda, &datadogagent.RenderOptions{
+		ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+			Enabled:                o.supportExtendedDaemonset,
+			MaxPodUnavailable:      o.edsMaxPodUnavailable,
+			MaxPodSchedulerFailure: o.edsMaxPodSchedulerFailure,
+			CanaryDuration:         o.edsCanaryDuration,
+			CanaryReplicas:         o.edsCanaryReplicas,
+			CanaryAutoPauseEnabled: o.edsCanaryAutoPauseEnabled,
+			CanaryAutoFailEnabled:  o.edsCanaryAutoFailEnabled,
+		},
+		SupportCilium: o.supportCilium,
+		VersionInfo:   o.versionInfo,
+		PlatformInfo:  o.platformInfo,
+		Scheme:        scheme,
+		Logger:        logr.Discard(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to render DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	return printObjects(o.Out, scheme, objs)
+}
+
+func (o *options) readDatadogAgent() (*v2alpha1.DatadogAgent, error) {
+	var data []byte
+	var err error
+	if o.filePath == "-" {
+		data, err = io.ReadAll(o.In)
+	} else {
+		data, err = os.ReadFile(o.filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", o.filePath, err)
+	}
+
+	dda := &v2alpha1.DatadogAgent{}
+	if err = yaml.UnmarshalStrict(data, dda); err != nil {
+		return nil, fmt.Errorf("unable to decode DatadogAgent from %s: %w", o.filePath, err)
+	}
+	if dda.APIVersion != "" && dda.APIVersion != v2alpha1.GroupVersion.String() {
+		return nil, fmt.Errorf("only %s DatadogAgent can be rendered, got %s", v2alpha1.GroupVersion.String(), dda.APIVersion)
+	}
+	if dda.Namespace == "" {
+		dda.Namespace = "default"
+	}
+
+	return dda, nil
+}
+
+// printObjects writes the objects as a multi-document YAML stream.
+func printObjects(out io.Writer, scheme *runtime.Scheme, objs []client.Object) error {
+	for i, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err = out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiregistrationv1.AddToScheme(scheme))
+	utilruntime.Must(edsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v2alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// parseKubeVersion converts a version like "v1.25.0" or "1.25" into a version.Info.
+func parseKubeVersion(kubeVersion string) (*version.Info, error) {
+	gitVersion := kubeVersion
+	if !strings.HasPrefix(gitVersion, "v") {
+		gitVersion = "v" + gitVersion
+	}
+	parts := strings.SplitN(strings.TrimPrefix(gitVersion, "v"), ".", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid Kubernetes version %q, expected <major>.<minor>[.<patch>]", kubeVersion)
+	}
+	if len(parts) == 2 {
+		gitVersion += ".0"
+	}
+
+	return &version.Info{
+		Major:      parts[0],
+		Minor:      parts[1],
+		GitVersion: gitVersion,
+	}, nil
+}
+
+// parseAPIGroups converts <group>/<version>/<Kind> entries into the map of preferred
+// group versions by Kind used by kubernetes.PlatformInfo.
+func parseAPIGroups(apiGroups []string) (map[string]string, error) {
+	preferred := map[string]string{}
+	for _, apiGroup := range apiGroups {
+		idx := strings.LastIndex(apiGroup, "/")
+		if idx <= 0 || idx == len(apiGroup)-1 {
+			return nil, fmt.Errorf("invalid API group %q, expected <group>/<version>/<Kind>", apiGroup)
+		}
+		preferred[apiGroup[idx+1:]] = apiGroup[:idx]
+	}
+	return preferred, nil
+}
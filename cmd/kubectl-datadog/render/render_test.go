@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func Test_parseKubeVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		kubeVersion string
+		want        *version.Info
+		wantErr     bool
+	}{
+		{
+			name:        "full version",
+			kubeVersion: "v1.25.3",
+			want:        &version.Info{Major: "1", Minor: "25", GitVersion: "v1.25.3"},
+		},
+		{
+			name:        "no prefix, no patch",
+			kubeVersion: "1.20",
+			want:        &version.Info{Major: "1", Minor: "20", GitVersion: "v1.20.0"},
+		},
+		{
+			name:        "invalid",
+			kubeVersion: "v1",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKubeVersion(tt.kubeVersion)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseAPIGroups(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiGroups []string
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "group and core group",
+			apiGroups: []string{"policy/v1beta1/PodDisruptionBudget", "v1/ConfigMap"},
+			want: map[string]string{
+				"PodDisruptionBudget": "policy/v1beta1",
+				"ConfigMap":           "v1",
+			},
+		},
+		{
+			name:      "missing kind",
+			apiGroups: []string{"policy/v1/"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing version",
+			apiGroups: []string{"PodDisruptionBudget"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAPIGroups(tt.apiGroups)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
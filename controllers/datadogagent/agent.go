@@ -222,8 +222,8 @@ func (r *Reconciler) updateExtendedDaemonSet(logger logr.Logger, dda *datadoghqv
 	// Copy possibly changed fields
 	updatedEds := eds.DeepCopy()
 	updatedEds.Spec = *newEDS.Spec.DeepCopy()
-	updatedEds.Annotations = mergeAnnotationsLabels(logger, eds.GetAnnotations(), newEDS.GetAnnotations(), dda.Spec.Agent.KeepAnnotations)
-	updatedEds.Labels = mergeAnnotationsLabels(logger, eds.GetLabels(), newEDS.GetLabels(), dda.Spec.Agent.KeepLabels)
+	updatedEds.Annotations = kubernetes.MergeAnnotationsLabels(logger, eds.GetAnnotations(), newEDS.GetAnnotations(), dda.Spec.Agent.KeepAnnotations)
+	updatedEds.Labels = kubernetes.MergeAnnotationsLabels(logger, eds.GetLabels(), newEDS.GetLabels(), dda.Spec.Agent.KeepLabels)
 
 	err = kubernetes.UpdateFromObject(context.TODO(), r.client, updatedEds, eds.ObjectMeta)
 	if err != nil {
@@ -263,8 +263,8 @@ func (r *Reconciler) updateDaemonSet(logger logr.Logger, dda *datadoghqv1alpha1.
 	// Copy possibly changed fields
 	updatedDS := ds.DeepCopy()
 	updatedDS.Spec = *newDS.Spec.DeepCopy()
-	updatedDS.Annotations = mergeAnnotationsLabels(logger, ds.GetAnnotations(), newDS.GetAnnotations(), dda.Spec.Agent.KeepAnnotations)
-	updatedDS.Labels = mergeAnnotationsLabels(logger, ds.GetLabels(), newDS.GetLabels(), dda.Spec.Agent.KeepLabels)
+	updatedDS.Annotations = kubernetes.MergeAnnotationsLabels(logger, ds.GetAnnotations(), newDS.GetAnnotations(), dda.Spec.Agent.KeepAnnotations)
+	updatedDS.Labels = kubernetes.MergeAnnotationsLabels(logger, ds.GetLabels(), newDS.GetLabels(), dda.Spec.Agent.KeepLabels)
 
 	err = kubernetes.UpdateFromObject(context.TODO(), r.client, updatedDS, ds.ObjectMeta)
 	if err != nil {
@@ -145,9 +145,9 @@ func (r *Reconciler) updateClusterAgentDeployment(logger logr.Logger, features [
 	// Copy possibly changed fields
 	updateDca := dca.DeepCopy()
 	updateDca.Spec = *newDCA.Spec.DeepCopy()
-	updateDca.Spec.Replicas = getReplicas(dca.Spec.Replicas, updateDca.Spec.Replicas)
-	updateDca.Annotations = mergeAnnotationsLabels(logger, dca.GetAnnotations(), newDCA.GetAnnotations(), dda.Spec.ClusterAgent.KeepAnnotations)
-	updateDca.Labels = mergeAnnotationsLabels(logger, dca.GetLabels(), newDCA.GetLabels(), dda.Spec.ClusterAgent.KeepLabels)
+	updateDca.Spec.Replicas = getReplicas(dca.Spec.Replicas, updateDca.Spec.Replicas, false)
+	updateDca.Annotations = kubernetes.MergeAnnotationsLabels(logger, dca.GetAnnotations(), newDCA.GetAnnotations(), dda.Spec.ClusterAgent.KeepAnnotations)
+	updateDca.Labels = kubernetes.MergeAnnotationsLabels(logger, dca.GetLabels(), newDCA.GetLabels(), dda.Spec.ClusterAgent.KeepLabels)
 
 	now := metav1.NewTime(time.Now())
 	err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateDca, dca.ObjectMeta)
@@ -147,7 +147,7 @@ func (r *Reconciler) updateClusterChecksRunnerDeployment(logger logr.Logger, dda
 	// Copy possibly changed fields
 	updateCLCR := dep.DeepCopy()
 	updateCLCR.Spec = *newCLCR.Spec.DeepCopy()
-	updateCLCR.Spec.Replicas = getReplicas(dep.Spec.Replicas, updateCLCR.Spec.Replicas)
+	updateCLCR.Spec.Replicas = getReplicas(dep.Spec.Replicas, updateCLCR.Spec.Replicas, false)
 	for k, v := range newCLCR.Annotations {
 		updateCLCR.Annotations[k] = v
 	}
@@ -72,6 +72,22 @@ type ExtendedDaemonsetOptions struct {
 	CanaryAutoFailMaxRestarts  int32
 }
 
+// DaemonSetCanaryOptions defines the options of the canary rollout of the Agent DaemonSet,
+// used when the ExtendedDaemonSet isn't. The canary duration, auto pause and auto fail
+// settings are the ones of the ExtendedDaemonsetOptions.
+type DaemonSetCanaryOptions struct {
+	Enabled bool
+
+	// NodeLabelKey and NodeLabelValue select the nodes running the canary DaemonSet.
+	NodeLabelKey   string
+	NodeLabelValue string
+}
+
+// CanaryStrategy returns the canary strategy of the ExtendedDaemonSet options, with the ExtendedDaemonSet defaults.
+func CanaryStrategy(options *ExtendedDaemonsetOptions) *edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary {
+	return defaultEDSSpec(options).Strategy.Canary
+}
+
 func defaultEDSSpec(options *ExtendedDaemonsetOptions) edsv1alpha1.ExtendedDaemonSetSpec {
 	spec := edsv1alpha1.ExtendedDaemonSetSpec{
 		Strategy: edsv1alpha1.ExtendedDaemonSetSpecStrategy{
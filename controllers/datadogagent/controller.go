@@ -67,12 +67,26 @@ type ReconcilerOptions struct {
 	SupportCilium            bool
 	OperatorMetricsEnabled   bool
 	V2Enabled                bool
+	// ServerSideApplyEnabled is used by the v2 reconciler to create and update the resources with server-side apply
+	ServerSideApplyEnabled bool
+	// UpdateFieldManager is the field manager of the regular updates done by the operator, migrated to server-side apply
+	UpdateFieldManager string
+	// DatadogAgentExtensionEnabled is used by the v2 reconciler to apply the DatadogAgentExtensions of the DatadogAgent namespace
+	DatadogAgentExtensionEnabled bool
+	// ExtensionClusterRulesEnabled allows the DatadogAgentExtensions to grant cluster-wide RBAC
+	ExtensionClusterRulesEnabled bool
+	// DaemonSetCanaryOptions is used by the v2 reconciler to roll out the Agent DaemonSet with a canary, when the ExtendedDaemonSet is not used
+	DaemonSetCanaryOptions componentagent.DaemonSetCanaryOptions
+	// AgentConflictPreventionEnabled is used by the v2 reconciler to not deploy the node Agent of a DatadogAgent
+	// on the nodes already running the node Agent of an older DatadogAgent
+	AgentConflictPreventionEnabled bool
 }
 
 // Reconciler is the internal reconciler for Datadog Agent
 type Reconciler struct {
 	options      ReconcilerOptions
 	client       client.Client
+	apiReader    client.Reader
 	versionInfo  *version.Info
 	platformInfo kubernetes.PlatformInfo
 	scheme       *runtime.Scheme
@@ -82,11 +96,12 @@ type Reconciler struct {
 }
 
 // NewReconciler returns a reconciler for DatadogAgent
-func NewReconciler(options ReconcilerOptions, client client.Client, versionInfo *version.Info, platformInfo kubernetes.PlatformInfo,
+func NewReconciler(options ReconcilerOptions, client client.Client, apiReader client.Reader, versionInfo *version.Info, platformInfo kubernetes.PlatformInfo,
 	scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder, metricForwarder datadog.MetricForwardersManager) (*Reconciler, error) {
 	return &Reconciler{
 		options:      options,
 		client:       client,
+		apiReader:    apiReader,
 		versionInfo:  versionInfo,
 		platformInfo: platformInfo,
 		scheme:       scheme,
@@ -159,8 +174,9 @@ func (r *Reconciler) internalReconcile(ctx context.Context, request reconcile.Re
 
 func reconcilerOptionsToFeatureOptions(opts *ReconcilerOptions, logger logr.Logger) *feature.Options {
 	return &feature.Options{
-		SupportExtendedDaemonset: opts.ExtendedDaemonsetOptions.Enabled,
-		Logger:                   logger,
+		SupportExtendedDaemonset:           opts.ExtendedDaemonsetOptions.Enabled,
+		ExtensionClusterPolicyRulesEnabled: opts.ExtensionClusterRulesEnabled,
+		Logger:                             logger,
 	}
 }
 
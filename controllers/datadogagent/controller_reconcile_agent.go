@@ -7,6 +7,8 @@ package datadogagent
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -27,63 +29,133 @@ import (
 )
 
 func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus, requiredContainers []common.AgentContainerName) (reconcile.Result, error) {
-	var result reconcile.Result
-	var eds *edsv1alpha1.ExtendedDaemonSet
-	var daemonset *appsv1.DaemonSet
-	var podManagers feature.PodTemplateManagers
-
 	daemonsetLogger := logger.WithValues("component", datadoghqv2alpha1.NodeAgentComponentName)
 
 	// requiredComponents needs to be taken into account in case a feature(s) changes and
 	// a requiredComponent becomes disabled, in addition to taking into account override.Disabled
-	disabledByOverride := false
+	disabledByOverride := !isV2AgentEnabled(dda)
 
 	agentEnabled := requiredComponents.Agent.IsEnabled()
 
-	if r.options.ExtendedDaemonsetOptions.Enabled {
-		// Start by creating the Default Agent extendeddaemonset
-		eds = componentagent.NewDefaultAgentExtendedDaemonset(dda, &r.options.ExtendedDaemonsetOptions, requiredContainers)
-		podManagers = feature.NewPodTemplateManagers(&eds.Spec.Template)
-
-		// Set Global setting on the default extendeddaemonset
-		eds.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
-
-		// Apply features changes on the Deployment.Spec.Template
-		for _, feat := range features {
-			if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
-				return result, errFeat
-			}
-		}
+	if disabledByOverride && agentEnabled {
+		// The override supersedes what's set in requiredComponents; update status to reflect the conflict
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+			newStatus,
+			metav1.NewTime(time.Now()),
+			datadoghqv2alpha1.OverrideReconcileConflictConditionType,
+			metav1.ConditionTrue,
+			"OverrideConflict",
+			"Agent component is set to disabled",
+			true,
+		)
+	}
+
+	// The ExtendedDaemonSet can't apply all the update strategies of a DaemonSet; update status to reflect the ignored settings
+	if unsupported := unsupportedExtendedDaemonSetStrategy(dda); r.options.ExtendedDaemonsetOptions.Enabled && len(unsupported) > 0 {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+			newStatus,
+			metav1.NewTime(time.Now()),
+			datadoghqv2alpha1.AgentUpdateStrategyConflictConditionType,
+			metav1.ConditionTrue,
+			"UnsupportedByExtendedDaemonSet",
+			fmt.Sprintf("%s ignored: not supported by the ExtendedDaemonSet", strings.Join(unsupported, ", ")),
+			true,
+		)
+	} else {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, metav1.NewTime(time.Now()), datadoghqv2alpha1.AgentUpdateStrategyConflictConditionType, metav1.ConditionFalse, "UpdateStrategyApplied", "", false)
+	}
+
+	result, err := r.reconcileV2AgentDefault(daemonsetLogger, features, dda, resourcesManager, newStatus, requiredContainers, disabledByOverride)
+	if err != nil {
+		return result, err
+	}
+
+	// The profiles are reconciled after the default node Agent, which leaves their nodes first,
+	// and even if the default node Agent is requeued, for instance during a canary.
+	if err = r.reconcileV2AgentProfiles(daemonsetLogger, features, dda, resourcesManager, newStatus, requiredContainers, !disabledByOverride); err != nil {
+		return reconcile.Result{}, err
+	}
+	return result, nil
+}
+
+// reconcileV2AgentDefault reconciles the default node Agent DaemonSet, or ExtendedDaemonSet,
+// which runs on the nodes that don't match any profile.
+func (r *Reconciler) reconcileV2AgentDefault(daemonsetLogger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus, requiredContainers []common.AgentContainerName, disabledByOverride bool) (reconcile.Result, error) {
+	var result reconcile.Result
 
-		// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
-		if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
-			if apiutils.BoolValue(componentOverride.Disabled) {
-				disabledByOverride = true
-			}
-			override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
-			override.ExtendedDaemonSet(eds, componentOverride)
+	if r.options.ExtendedDaemonsetOptions.Enabled {
+		eds, err := buildV2AgentExtendedDaemonSet(daemonsetLogger, features, dda, resourcesManager, &r.options.ExtendedDaemonsetOptions, requiredContainers, nil)
+		if err != nil {
+			return result, err
 		}
 		if disabledByOverride {
-			if agentEnabled {
-				// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-				datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-					newStatus,
-					metav1.NewTime(time.Now()),
-					datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-					metav1.ConditionTrue,
-					"OverrideConflict",
-					"Agent component is set to disabled",
-					true,
-				)
-			}
 			return r.cleanupV2ExtendedDaemonSet(daemonsetLogger, dda, eds, newStatus)
 		}
+		if err = r.setV2ReferencedConfigsChecksum(daemonsetLogger, dda, eds); err != nil {
+			return result, err
+		}
 		return r.createOrUpdateExtendedDaemonset(daemonsetLogger, dda, eds, newStatus, updateEDSStatusV2WithAgent)
 	}
 
+	daemonset, err := buildV2AgentDaemonSet(daemonsetLogger, features, dda, resourcesManager, requiredContainers, nil)
+	if err != nil {
+		return result, err
+	}
+	if disabledByOverride || !r.options.DaemonSetCanaryOptions.Enabled {
+		if err = r.cleanupV2AgentCanaryDaemonSet(daemonsetLogger, dda, daemonset); err != nil {
+			return result, err
+		}
+		datadoghqv2alpha1.DeleteDatadogAgentStatusCondition(newStatus, datadoghqv2alpha1.AgentCanaryNotValidatedConditionType)
+	}
+	if disabledByOverride {
+		return r.cleanupV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus)
+	}
+	if err = r.setV2ReferencedConfigsChecksum(daemonsetLogger, dda, daemonset); err != nil {
+		return result, err
+	}
+	if r.options.DaemonSetCanaryOptions.Enabled {
+		return r.createOrUpdateDaemonsetWithCanary(daemonsetLogger, dda, daemonset, newStatus)
+	}
+	return r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+}
+
+// buildV2AgentExtendedDaemonSet builds the node Agent ExtendedDaemonSet from the default one, the global settings,
+// the enabled features and the component override, followed by the profile override if profile is not nil.
+func buildV2AgentExtendedDaemonSet(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, edsOptions *componentagent.ExtendedDaemonsetOptions, requiredContainers []common.AgentContainerName, profile *datadoghqv2alpha1.AgentProfile) (*edsv1alpha1.ExtendedDaemonSet, error) {
+	// Start by creating the Default Agent extendeddaemonset
+	eds := componentagent.NewDefaultAgentExtendedDaemonset(dda, edsOptions, requiredContainers)
+	podManagers := feature.NewPodTemplateManagers(&eds.Spec.Template)
+
+	// Set Global setting on the default extendeddaemonset
+	eds.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
+			return nil, newFeatureError(feat.ID(), manageNodeAgentStep, errFeat)
+		}
+	}
+
+	// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.ExtendedDaemonSet(eds, componentOverride)
+	}
+	if profile != nil && profile.Override != nil {
+		override.PodTemplateSpec(logger, podManagers, profile.Override, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.ExtendedDaemonSet(eds, profile.Override)
+	}
+	setAgentProfilePlacement(dda, profile, &eds.ObjectMeta, eds.Spec.Selector, &eds.Spec.Template)
+
+	return eds, nil
+}
+
+// buildV2AgentDaemonSet builds the node Agent DaemonSet from the default one, the global settings,
+// the enabled features and the component override, followed by the profile override if profile is not nil.
+func buildV2AgentDaemonSet(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, requiredContainers []common.AgentContainerName, profile *datadoghqv2alpha1.AgentProfile) (*appsv1.DaemonSet, error) {
 	// Start by creating the Default Agent daemonset
-	daemonset = componentagent.NewDefaultAgentDaemonset(dda, requiredContainers)
-	podManagers = feature.NewPodTemplateManagers(&daemonset.Spec.Template)
+	daemonset := componentagent.NewDefaultAgentDaemonset(dda, requiredContainers)
+	podManagers := feature.NewPodTemplateManagers(&daemonset.Spec.Template)
 
 	// Set Global setting on the default daemonset
 	daemonset.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
@@ -91,34 +163,46 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 	// Apply features changes on the Deployment.Spec.Template
 	for _, feat := range features {
 		if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
-			return result, errFeat
+			return nil, newFeatureError(feat.ID(), manageNodeAgentStep, errFeat)
 		}
 	}
 
 	// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
-		if apiutils.BoolValue(componentOverride.Disabled) {
-			disabledByOverride = true
-		}
 		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
 		override.DaemonSet(daemonset, componentOverride)
 	}
-	if disabledByOverride {
-		if agentEnabled {
-			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-				newStatus,
-				metav1.NewTime(time.Now()),
-				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-				metav1.ConditionTrue,
-				"OverrideConflict",
-				"Agent component is set to disabled",
-				true,
-			)
-		}
-		return r.cleanupV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus)
+	if profile != nil && profile.Override != nil {
+		override.PodTemplateSpec(logger, podManagers, profile.Override, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.DaemonSet(daemonset, profile.Override)
 	}
-	return r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+	setAgentProfilePlacement(dda, profile, &daemonset.ObjectMeta, daemonset.Spec.Selector, &daemonset.Spec.Template)
+
+	return daemonset, nil
+}
+
+// isV2AgentEnabled returns true if the node Agent should be deployed: only the component override can disable it.
+func isV2AgentEnabled(dda *datadoghqv2alpha1.DatadogAgent) bool {
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return true
+}
+
+// unsupportedExtendedDaemonSetStrategy returns the update strategy settings of the node Agent override that only apply to a DaemonSet.
+func unsupportedExtendedDaemonSetStrategy(dda *datadoghqv2alpha1.DatadogAgent) []string {
+	componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]
+	if !ok || componentOverride.UpdateStrategy == nil {
+		return nil
+	}
+	var unsupported []string
+	if componentOverride.UpdateStrategy.Type == datadoghqv2alpha1.OnDeleteStrategyType {
+		unsupported = append(unsupported, fmt.Sprintf("spec.override.%s.updateStrategy.type %s", datadoghqv2alpha1.NodeAgentComponentName, datadoghqv2alpha1.OnDeleteStrategyType))
+	}
+	if componentOverride.UpdateStrategy.RollingUpdate != nil && componentOverride.UpdateStrategy.RollingUpdate.MaxSurge != nil {
+		unsupported = append(unsupported, fmt.Sprintf("spec.override.%s.updateStrategy.rollingUpdate.maxSurge", datadoghqv2alpha1.NodeAgentComponentName))
+	}
+	return unsupported
 }
 
 func updateDSStatusV2WithAgent(dda *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
@@ -0,0 +1,336 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+const (
+	canaryDaemonSetSuffix = "-canary"
+	// canaryLabelKey is set on the canary pods, so that the selector of the canary DaemonSet only matches the canary pods.
+	// The selector of the Agent DaemonSet is immutable and also matches them: the DaemonSet controller only manages the
+	// pods it owns, but the pods of the Agent DaemonSet can't be listed with its selector alone.
+	canaryLabelKey = "agent.datadoghq.com/canary"
+	// canaryVersionAnnotationKey stores the spec hash of the Agent DaemonSet version run by the canary DaemonSet
+	canaryVersionAnnotationKey = "agent.datadoghq.com/canary-version"
+	// canaryStartAnnotationKey stores when the canary DaemonSet started to run its version
+	canaryStartAnnotationKey = "agent.datadoghq.com/canary-start"
+	// canaryFailedVersionAnnotationKey stores the spec hash of the last Agent DaemonSet version whose canary failed
+	canaryFailedVersionAnnotationKey = "agent.datadoghq.com/canary-failed-version"
+)
+
+// createOrUpdateDaemonsetWithCanary rolls out the Agent DaemonSet with a canary: the nodes labeled with the canary
+// label run a canary DaemonSet, and the Agent DaemonSet runs on the other nodes. A new version is first deployed by the
+// canary DaemonSet. It is promoted to the Agent DaemonSet once the canary duration is over and the canary pods are ready,
+// or rolled back if the canary pods restart too much, following the ExtendedDaemonSet canary options.
+func (r *Reconciler) createOrUpdateDaemonsetWithCanary(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, daemonset *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
+	canaryOptions := &r.options.DaemonSetCanaryOptions
+	// Set again by evaluateCanary while a canary without pod is in progress.
+	datadoghqv2alpha1.DeleteDatadogAgentStatusCondition(newStatus, datadoghqv2alpha1.AgentCanaryNotValidatedConditionType)
+	stable := newStableDaemonSet(daemonset, canaryOptions)
+	hash, err := comparison.GenerateMD5ForSpec(stable.Spec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	currentStable := &appsv1.DaemonSet{}
+	if err = r.client.Get(context.TODO(), client.ObjectKeyFromObject(stable), currentStable); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		// Nothing runs yet, there is no previous version to compare with.
+		return r.createOrUpdateStableAndCanaryDaemonsets(logger, dda, stable, hash, newStatus)
+	}
+
+	currentCanary := &appsv1.DaemonSet{}
+	if err = r.client.Get(context.TODO(), client.ObjectKey{Namespace: stable.Namespace, Name: stable.Name + canaryDaemonSetSuffix}, currentCanary); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		// The canary was just enabled: the Agent DaemonSet has to be updated to leave the canary nodes anyway.
+		return r.createOrUpdateStableAndCanaryDaemonsets(logger, dda, stable, hash, newStatus)
+	}
+
+	if comparison.IsSameSpecMD5Hash(hash, currentStable.GetAnnotations()) {
+		// No new version: the canary DaemonSet runs the same version as the Agent DaemonSet,
+		// which also reverts a canary in progress when the DatadogAgent is reverted.
+		return r.createOrUpdateStableAndCanaryDaemonsets(logger, dda, stable, hash, newStatus)
+	}
+
+	canaryState := datadoghqv2alpha1.DatadogAgentStateCanary
+	now := time.Now()
+	switch {
+	case currentCanary.Annotations[canaryFailedVersionAnnotationKey] == hash:
+		// The canary of this version already failed; wait for a new version.
+		canaryState = datadoghqv2alpha1.DatadogAgentStateCanaryFailed
+	case currentCanary.Annotations[canaryVersionAnnotationKey] != hash:
+		logger.Info("Starting the canary of a new Agent DaemonSet version", "hash", hash)
+		canary := newCanaryDaemonSet(stable, canaryOptions, hash)
+		canary.Annotations[canaryStartAnnotationKey] = now.Format(time.RFC3339)
+		if _, err = r.createOrUpdateDaemonset(logger, dda, canary, newStatus, ignoreDSStatus); err != nil {
+			return reconcile.Result{}, err
+		}
+	default:
+		canaryState, err = r.evaluateCanary(logger, dda, currentStable, currentCanary, hash, now, newStatus)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if canaryState == datadoghqv2alpha1.DatadogAgentStateRunning {
+			logger.Info("Promoting the canary of the Agent DaemonSet", "hash", hash)
+			return r.createOrUpdateDaemonset(logger, dda, stable, newStatus, updateDSStatusV2WithAgent)
+		}
+	}
+
+	metaNow := metav1.NewTime(now)
+	newStatus.Agent = datadoghqv2alpha1.UpdateDaemonSetStatus(currentStable, newStatus.Agent, &metaNow)
+	newStatus.Agent.State = string(canaryState)
+	newStatus.Agent.Status = fmt.Sprintf("%v (%d/%d/%d)", canaryState, newStatus.Agent.Desired, newStatus.Agent.Ready, newStatus.Agent.UpToDate)
+
+	return reconcile.Result{}, nil
+}
+
+// evaluateCanary checks the restarts and the readiness of the canary pods. It returns DatadogAgentStateRunning if the
+// canary can be promoted, and rolls back the canary DaemonSet if it failed. A canary without pod is never promoted.
+func (r *Reconciler) evaluateCanary(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, currentStable, currentCanary *appsv1.DaemonSet, hash string, now time.Time, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (datadoghqv2alpha1.DatadogAgentState, error) {
+	strategy := componentagent.CanaryStrategy(&r.options.ExtendedDaemonsetOptions)
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		override.ExtendedDaemonSetCanary(strategy, componentOverride)
+	}
+	start, err := time.Parse(time.RFC3339, currentCanary.Annotations[canaryStartAnnotationKey])
+	if err != nil {
+		// The start is unknown, restart the canary duration.
+		start = now
+	}
+
+	restarts, err := r.countCanaryRestarts(currentCanary, start)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case isCanaryEnabled(strategy.AutoFail.Enabled) && restarts > *strategy.AutoFail.MaxRestarts:
+		logger.Info("The canary of the Agent DaemonSet failed, rolling back", "hash", hash, "restarts", restarts)
+		rollback := newCanaryDaemonSet(newDaemonSetFromCurrent(currentStable), &r.options.DaemonSetCanaryOptions, currentStable.Annotations[apicommon.MD5AgentDeploymentAnnotationKey])
+		rollback.Annotations[canaryFailedVersionAnnotationKey] = hash
+		if _, err = r.createOrUpdateDaemonset(logger, dda, rollback, newStatus, ignoreDSStatus); err != nil {
+			return "", err
+		}
+		return datadoghqv2alpha1.DatadogAgentStateCanaryFailed, nil
+	case isCanaryEnabled(strategy.AutoPause.Enabled) && restarts > *strategy.AutoPause.MaxRestarts:
+		return datadoghqv2alpha1.DatadogAgentStateCanaryPaused, nil
+	case currentCanary.Status.ObservedGeneration >= currentCanary.Generation && currentCanary.Status.DesiredNumberScheduled == 0:
+		logger.Info("The canary of the Agent DaemonSet has no pod, it can't be validated", "hash", hash)
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, metav1.NewTime(now), datadoghqv2alpha1.AgentCanaryNotValidatedConditionType, metav1.ConditionTrue, "NoCanaryPod",
+			fmt.Sprintf("The canary DaemonSet doesn't run on any node, label nodes with %s=%s to validate the new version", r.options.DaemonSetCanaryOptions.NodeLabelKey, r.options.DaemonSetCanaryOptions.NodeLabelValue), true)
+		return datadoghqv2alpha1.DatadogAgentStateCanary, nil
+	case now.Sub(start) >= strategy.Duration.Duration && isDaemonSetRolledOut(currentCanary):
+		return datadoghqv2alpha1.DatadogAgentStateRunning, nil
+	}
+	return datadoghqv2alpha1.DatadogAgentStateCanary, nil
+}
+
+// countCanaryRestarts returns the number of container restarts of the canary pods created since the canary start.
+func (r *Reconciler) countCanaryRestarts(canary *appsv1.DaemonSet, start time.Time) (int32, error) {
+	podList := &corev1.PodList{}
+	// The pods are read from the api-server: they are not cached by the manager.
+	if err := r.apiReader.List(context.TODO(), podList, client.InNamespace(canary.Namespace), client.MatchingLabels(canary.Spec.Selector.MatchLabels)); err != nil {
+		return 0, err
+	}
+
+	var restarts int32
+	for _, pod := range podList.Items {
+		// Pods are created with a one second precision.
+		if pod.CreationTimestamp.Time.Before(start.Truncate(time.Second)) {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			restarts += status.RestartCount
+		}
+	}
+	return restarts, nil
+}
+
+// createOrUpdateStableAndCanaryDaemonsets deploys the same version on the canary nodes and on the other nodes.
+func (r *Reconciler) createOrUpdateStableAndCanaryDaemonsets(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, stable *appsv1.DaemonSet, hash string, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
+	canary := newCanaryDaemonSet(stable, &r.options.DaemonSetCanaryOptions, hash)
+	if result, err := r.createOrUpdateDaemonset(logger, dda, canary, newStatus, ignoreDSStatus); err != nil {
+		return result, err
+	}
+	return r.createOrUpdateDaemonset(logger, dda, stable, newStatus, updateDSStatusV2WithAgent)
+}
+
+// cleanupV2AgentCanaryDaemonSet deletes the canary DaemonSet of the Agent, if it exists.
+func (r *Reconciler) cleanupV2AgentCanaryDaemonSet(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, daemonset *appsv1.DaemonSet) error {
+	canary := &appsv1.DaemonSet{}
+	canary.Namespace = daemonset.Namespace
+	canary.Name = daemonset.Name + canaryDaemonSetSuffix
+	if err := r.client.Delete(context.TODO(), canary); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	logger.Info("Delete canary DaemonSet", "daemonSet.Namespace", canary.Namespace, "daemonSet.Name", canary.Name)
+	r.recordEvent(dda, buildEventInfo(canary.Name, canary.Namespace, daemonSetKind, datadog.DeletionEvent))
+	return nil
+}
+
+// newStableDaemonSet returns a copy of the Agent DaemonSet that doesn't run on the canary nodes.
+func newStableDaemonSet(daemonset *appsv1.DaemonSet, options *componentagent.DaemonSetCanaryOptions) *appsv1.DaemonSet {
+	stable := daemonset.DeepCopy()
+	podSpec := &stable.Spec.Template.Spec
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// The terms are ORed: the requirement is added to each of them.
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, canaryNodeRequirement(options))
+	}
+	return stable
+}
+
+// newCanaryDaemonSet returns the canary DaemonSet running the version of an Agent DaemonSet built by newStableDaemonSet.
+func newCanaryDaemonSet(stable *appsv1.DaemonSet, options *componentagent.DaemonSetCanaryOptions, version string) *appsv1.DaemonSet {
+	canary := stable.DeepCopy()
+	canary.Name = stable.Name + canaryDaemonSetSuffix
+	if canary.Annotations == nil {
+		canary.Annotations = map[string]string{}
+	}
+	canary.Annotations[canaryVersionAnnotationKey] = version
+
+	if canary.Spec.Selector == nil {
+		canary.Spec.Selector = &metav1.LabelSelector{}
+	}
+	if canary.Spec.Selector.MatchLabels == nil {
+		canary.Spec.Selector.MatchLabels = map[string]string{}
+	}
+	canary.Spec.Selector.MatchLabels[canaryLabelKey] = "true"
+	if canary.Spec.Template.Labels == nil {
+		canary.Spec.Template.Labels = map[string]string{}
+	}
+	canary.Spec.Template.Labels[canaryLabelKey] = "true"
+
+	podSpec := &canary.Spec.Template.Spec
+	if podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = map[string]string{}
+	}
+	podSpec.NodeSelector[options.NodeLabelKey] = options.NodeLabelValue
+	removeCanaryNodeRequirement(podSpec, options)
+
+	return canary
+}
+
+// newDaemonSetFromCurrent returns a DaemonSet with the metadata and spec of a DaemonSet read from the api-server.
+func newDaemonSetFromCurrent(current *appsv1.DaemonSet) *appsv1.DaemonSet {
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        current.Name,
+			Namespace:   current.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *current.Spec.DeepCopy(),
+	}
+	for key, value := range current.Labels {
+		daemonset.Labels[key] = value
+	}
+	for key, value := range current.Annotations {
+		daemonset.Annotations[key] = value
+	}
+	return daemonset
+}
+
+func canaryNodeRequirement(options *componentagent.DaemonSetCanaryOptions) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{
+		Key:      options.NodeLabelKey,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{options.NodeLabelValue},
+	}
+}
+
+// removeCanaryNodeRequirement removes the requirement added by newStableDaemonSet, and the affinity left empty.
+func removeCanaryNodeRequirement(podSpec *corev1.PodSpec, options *componentagent.DaemonSetCanaryOptions) {
+	if podSpec.Affinity == nil || podSpec.Affinity.NodeAffinity == nil || podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return
+	}
+	requirement := canaryNodeRequirement(options)
+	required := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	terms := make([]corev1.NodeSelectorTerm, 0, len(required.NodeSelectorTerms))
+	for _, term := range required.NodeSelectorTerms {
+		expressions := make([]corev1.NodeSelectorRequirement, 0, len(term.MatchExpressions))
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == requirement.Key && expression.Operator == requirement.Operator &&
+				len(expression.Values) == 1 && expression.Values[0] == requirement.Values[0] {
+				continue
+			}
+			expressions = append(expressions, expression)
+		}
+		// An empty term matches no node: it can only have been created by newStableDaemonSet.
+		if len(expressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		term.MatchExpressions = expressions
+		terms = append(terms, term)
+	}
+	required.NodeSelectorTerms = terms
+
+	if len(required.NodeSelectorTerms) == 0 {
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nil
+	}
+	if podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil && len(podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution) == 0 {
+		podSpec.Affinity.NodeAffinity = nil
+	}
+	if podSpec.Affinity.NodeAffinity == nil && podSpec.Affinity.PodAffinity == nil && podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity = nil
+	}
+}
+
+// isDaemonSetRolledOut returns true if the DaemonSet has pods, and they all run its current version and are ready.
+func isDaemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation && ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled
+}
+
+func isCanaryEnabled(enabled *bool) bool {
+	return enabled != nil && *enabled
+}
+
+// ignoreDSStatus is used for the canary DaemonSet: the Agent status reflects the Agent DaemonSet.
+func ignoreDSStatus(*appsv1.DaemonSet, *datadoghqv2alpha1.DatadogAgentStatus, metav1.Time, metav1.ConditionStatus, string, string) {
+}
@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+)
+
+var testCanaryOptions = componentagent.DaemonSetCanaryOptions{
+	Enabled:        true,
+	NodeLabelKey:   "canary",
+	NodeLabelValue: "true",
+}
+
+func newCanaryTestDaemonSet(image string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": "agent"}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "agent", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func Test_newCanaryDaemonSet(t *testing.T) {
+	daemonset := newCanaryTestDaemonSet("agent:7")
+	daemonset.Spec.Template.Spec.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}}},
+				},
+			},
+		},
+	}
+
+	stable := newStableDaemonSet(daemonset, &testCanaryOptions)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: "os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}},
+		{Key: "canary", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}},
+	}, stable.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	canary := newCanaryDaemonSet(stable, &testCanaryOptions, "hash")
+	assert.Equal(t, "foo-agent-canary", canary.Name)
+	assert.Equal(t, "hash", canary.Annotations[canaryVersionAnnotationKey])
+	assert.Equal(t, map[string]string{"app": "agent", canaryLabelKey: "true"}, canary.Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"app": "agent", canaryLabelKey: "true"}, canary.Spec.Template.Labels)
+	assert.Equal(t, map[string]string{"canary": "true"}, canary.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, daemonset.Spec.Template.Spec.Affinity, canary.Spec.Template.Spec.Affinity, "the canary requirement should be removed")
+
+	// Without affinity, the affinity created for the Agent DaemonSet is removed
+	canary = newCanaryDaemonSet(newStableDaemonSet(newCanaryTestDaemonSet("agent:7"), &testCanaryOptions), &testCanaryOptions, "hash")
+	assert.Nil(t, canary.Spec.Template.Spec.Affinity)
+	// The DaemonSet passed as argument is not modified
+	assert.Len(t, daemonset.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+}
+
+func TestReconciler_createOrUpdateDaemonsetWithCanary(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	s := testutils.TestScheme(true)
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+	r := &Reconciler{
+		client:    c,
+		apiReader: c,
+		scheme:    s,
+		recorder:  record.NewBroadcaster().NewRecorder(s, corev1.EventSource{}),
+		options: ReconcilerOptions{
+			V2Enabled: true,
+			ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+				CanaryDuration:         10 * time.Minute,
+				CanaryAutoPauseEnabled: true,
+				CanaryAutoFailEnabled:  true,
+			},
+			DaemonSetCanaryOptions: testCanaryOptions,
+		},
+	}
+	reconcileDaemonSet := func(image string) *datadoghqv2alpha1.DatadogAgentStatus {
+		newStatus := &datadoghqv2alpha1.DatadogAgentStatus{}
+		_, err := r.createOrUpdateDaemonsetWithCanary(logr.Discard(), dda, newCanaryTestDaemonSet(image), newStatus)
+		require.NoError(t, err)
+		return newStatus
+	}
+	getDaemonSets := func() (stable, canary *appsv1.DaemonSet) {
+		stable, canary = &appsv1.DaemonSet{}, &appsv1.DaemonSet{}
+		require.NoError(t, r.client.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-agent"}, stable))
+		require.NoError(t, r.client.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-agent-canary"}, canary))
+		return stable, canary
+	}
+	image := func(ds *appsv1.DaemonSet) string {
+		return ds.Spec.Template.Spec.Containers[0].Image
+	}
+
+	// Both DaemonSets are created with the same version
+	reconcileDaemonSet("agent:7.40")
+	stable, canary := getDaemonSets()
+	assert.Equal(t, "agent:7.40", image(stable))
+	assert.Equal(t, "agent:7.40", image(canary))
+
+	// A new version is first deployed on the canary nodes
+	status := reconcileDaemonSet("agent:7.41")
+	stable, canary = getDaemonSets()
+	assert.Equal(t, "agent:7.40", image(stable))
+	assert.Equal(t, "agent:7.41", image(canary))
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanary), status.Agent.State)
+
+	// It isn't promoted before the end of the canary duration
+	status = reconcileDaemonSet("agent:7.41")
+	stable, canary = getDaemonSets()
+	assert.Equal(t, "agent:7.40", image(stable))
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanary), status.Agent.State)
+
+	// It isn't promoted without canary pod, even once the canary duration is over
+	canary.Annotations[canaryStartAnnotationKey] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	require.NoError(t, r.client.Update(context.TODO(), canary))
+	status = reconcileDaemonSet("agent:7.41")
+	stable, canary = getDaemonSets()
+	assert.Equal(t, "agent:7.40", image(stable))
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanary), status.Agent.State)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv2alpha1.AgentCanaryNotValidatedConditionType))
+
+	// It is promoted once the canary duration is over and the canary pods are ready
+	canary.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, UpdatedNumberScheduled: 1, NumberReady: 1}
+	require.NoError(t, r.client.Update(context.TODO(), canary))
+	status = reconcileDaemonSet("agent:7.41")
+	stable, _ = getDaemonSets()
+	assert.Equal(t, "agent:7.41", image(stable))
+	assert.Nil(t, meta.FindStatusCondition(status.Conditions, datadoghqv2alpha1.AgentCanaryNotValidatedConditionType))
+
+	// A canary whose pods restart a few times is paused
+	reconcileDaemonSet("agent:7.42")
+	_, canary = getDaemonSets()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "bar",
+			Name:              "foo-agent-canary-abcde",
+			Labels:            canary.Spec.Selector.MatchLabels,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(time.Second)),
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "agent", RestartCount: 3}},
+		},
+	}
+	require.NoError(t, r.client.Create(context.TODO(), pod))
+	status = reconcileDaemonSet("agent:7.42")
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanaryPaused), status.Agent.State)
+
+	// A canary whose pods restart too much is rolled back, and not retried
+	pod.Status.ContainerStatuses[0].RestartCount = 6
+	require.NoError(t, r.client.Update(context.TODO(), pod))
+	status = reconcileDaemonSet("agent:7.42")
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanaryFailed), status.Agent.State)
+	stable, canary = getDaemonSets()
+	assert.Equal(t, "agent:7.41", image(stable))
+	assert.Equal(t, "agent:7.41", image(canary))
+	assert.Equal(t, stable.Annotations[apicommon.MD5AgentDeploymentAnnotationKey], canary.Annotations[canaryVersionAnnotationKey])
+
+	status = reconcileDaemonSet("agent:7.42")
+	assert.Equal(t, string(datadoghqv2alpha1.DatadogAgentStateCanaryFailed), status.Agent.State)
+	_, canary = getDaemonSets()
+	assert.Equal(t, "agent:7.41", image(canary))
+
+	// The canary DaemonSet is deleted when the canary is disabled
+	require.NoError(t, r.cleanupV2AgentCanaryDaemonSet(logr.Discard(), dda, newCanaryTestDaemonSet("agent:7.42")))
+	err := r.client.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-agent-canary"}, &appsv1.DaemonSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+// agentProfileLabelKey is set to the profile name on the node Agent DaemonSets of the profiles and on their pods,
+// so that the selectors of the DaemonSets of the different profiles don't overlap.
+const agentProfileLabelKey = "agent.datadoghq.com/profile"
+
+// reconcileV2AgentProfiles deploys the node Agent DaemonSet, or ExtendedDaemonSet, of each profile,
+// reports their status, and deletes the DaemonSets of the profiles that were removed or disabled.
+func (r *Reconciler) reconcileV2AgentProfiles(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus, requiredContainers []common.AgentContainerName, agentEnabled bool) error {
+	newStatus.AgentProfiles = nil
+	desired := map[string]bool{}
+
+	for i := range dda.Spec.Profiles {
+		profile := &dda.Spec.Profiles[i]
+		if !agentEnabled || !isV2AgentProfileEnabled(profile) {
+			continue
+		}
+		profileLogger := logger.WithValues("profile", profile.Name)
+		profileStatus := &datadoghqv2alpha1.DatadogAgentStatus{}
+
+		if r.options.ExtendedDaemonsetOptions.Enabled {
+			eds, err := buildV2AgentExtendedDaemonSet(logger, features, dda, resourcesManager, &r.options.ExtendedDaemonsetOptions, requiredContainers, profile)
+			if err != nil {
+				return err
+			}
+			desired[eds.Name] = true
+			if err = r.setV2ReferencedConfigsChecksum(profileLogger, dda, eds); err != nil {
+				return err
+			}
+			if _, err = r.createOrUpdateExtendedDaemonset(profileLogger, dda, eds, profileStatus, updateEDSStatusV2WithAgent); err != nil {
+				return err
+			}
+		} else {
+			daemonset, err := buildV2AgentDaemonSet(logger, features, dda, resourcesManager, requiredContainers, profile)
+			if err != nil {
+				return err
+			}
+			desired[daemonset.Name] = true
+			if err = r.setV2ReferencedConfigsChecksum(profileLogger, dda, daemonset); err != nil {
+				return err
+			}
+			if _, err = r.createOrUpdateDaemonset(profileLogger, dda, daemonset, profileStatus, updateDSStatusV2WithAgent); err != nil {
+				return err
+			}
+		}
+
+		if profileStatus.Agent != nil {
+			newStatus.AgentProfiles = append(newStatus.AgentProfiles, datadoghqv2alpha1.AgentProfileStatus{
+				Name:            profile.Name,
+				DaemonSetStatus: *profileStatus.Agent,
+			})
+		}
+	}
+
+	return r.cleanupV2AgentProfiles(logger, dda, desired)
+}
+
+// cleanupV2AgentProfiles deletes the profile DaemonSets, or ExtendedDaemonSets, owned by the DatadogAgent that are not desired anymore.
+func (r *Reconciler) cleanupV2AgentProfiles(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, desired map[string]bool) error {
+	var list client.ObjectList
+	var kind string
+	if r.options.ExtendedDaemonsetOptions.Enabled {
+		list, kind = &edsv1alpha1.ExtendedDaemonSetList{}, extendedDaemonSetKind
+	} else {
+		list, kind = &appsv1.DaemonSetList{}, daemonSetKind
+	}
+	if err := r.client.List(context.TODO(), list, client.InNamespace(dda.Namespace), client.HasLabels{agentProfileLabelKey}); err != nil {
+		return err
+	}
+
+	var objs []client.Object
+	switch typedList := list.(type) {
+	case *edsv1alpha1.ExtendedDaemonSetList:
+		for i := range typedList.Items {
+			objs = append(objs, &typedList.Items[i])
+		}
+	case *appsv1.DaemonSetList:
+		for i := range typedList.Items {
+			objs = append(objs, &typedList.Items[i])
+		}
+	}
+
+	for _, obj := range objs {
+		if desired[obj.GetName()] || !metav1.IsControlledBy(obj, dda) {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), obj); err != nil {
+			return err
+		}
+		logger.Info("Delete profile DaemonSet", "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName(), "profile", obj.GetLabels()[agentProfileLabelKey])
+		r.recordEvent(dda, buildEventInfo(obj.GetName(), obj.GetNamespace(), kind, datadog.DeletionEvent))
+	}
+	return nil
+}
+
+// isV2AgentProfileEnabled returns true if the node Agent of a profile should be deployed. The nodes
+// of a disabled profile still don't run the default node Agent, nor the node Agent of a next profile.
+func isV2AgentProfileEnabled(profile *datadoghqv2alpha1.AgentProfile) bool {
+	return profile.Override == nil || !apiutils.BoolValue(profile.Override.Disabled)
+}
+
+// setAgentProfilePlacement places a node Agent DaemonSet: the DaemonSet of a profile runs on the nodes selected by
+// the profile and by no previous profile, and the default DaemonSet, when profile is nil, on the nodes selected by no profile.
+func setAgentProfilePlacement(dda *datadoghqv2alpha1.DatadogAgent, profile *datadoghqv2alpha1.AgentProfile, objMeta *metav1.ObjectMeta, selector *metav1.LabelSelector, template *corev1.PodTemplateSpec) {
+	var excluded []map[string]string
+	for _, previous := range dda.Spec.Profiles {
+		if profile != nil && previous.Name == profile.Name {
+			break
+		}
+		excluded = append(excluded, previous.NodeSelector)
+	}
+	if len(excluded) > 0 {
+		template.Spec.Affinity = excludeNodeSelectors(template.Spec.Affinity, excluded)
+	}
+	if profile == nil {
+		return
+	}
+
+	objMeta.Name = agentProfileDaemonSetName(objMeta.Name, profile.Name)
+	objMeta.Labels = setLabel(objMeta.Labels, agentProfileLabelKey, profile.Name)
+	if selector != nil {
+		selector.MatchLabels = setLabel(selector.MatchLabels, agentProfileLabelKey, profile.Name)
+	}
+	template.Labels = setLabel(template.Labels, agentProfileLabelKey, profile.Name)
+	for key, value := range profile.NodeSelector {
+		template.Spec.NodeSelector = setLabel(template.Spec.NodeSelector, key, value)
+	}
+}
+
+// agentProfileDaemonSetName returns the name of the node Agent DaemonSet of a profile.
+func agentProfileDaemonSetName(defaultName, profileName string) string {
+	return defaultName + "-" + profileName
+}
+
+// excludeNodeSelectors returns a copy of the affinity that doesn't select the nodes matching any of the node selectors.
+// A node doesn't match a node selector when one of its labels is different, and the node selector terms are ORed:
+// each term is replaced by one term per label of the node selector, with a NotIn requirement on that label.
+func excludeNodeSelectors(affinity *corev1.Affinity, nodeSelectors []map[string]string) *corev1.Affinity {
+	affinity = affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	terms := required.NodeSelectorTerms
+	if len(terms) == 0 {
+		terms = []corev1.NodeSelectorTerm{{}}
+	}
+
+	for _, nodeSelector := range nodeSelectors {
+		// An empty node selector is rejected by the validation: it would exclude all the nodes.
+		if len(nodeSelector) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(nodeSelector))
+		for key := range nodeSelector {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		newTerms := make([]corev1.NodeSelectorTerm, 0, len(terms)*len(keys))
+		for _, term := range terms {
+			for _, key := range keys {
+				newTerm := term.DeepCopy()
+				newTerm.MatchExpressions = append(newTerm.MatchExpressions, corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{nodeSelector[key]},
+				})
+				newTerms = append(newTerms, *newTerm)
+			}
+		}
+		terms = newTerms
+	}
+	required.NodeSelectorTerms = terms
+
+	return affinity
+}
+
+func setLabel(labels map[string]string, key, value string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[key] = value
+	return labels
+}
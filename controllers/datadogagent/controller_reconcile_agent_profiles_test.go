@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+)
+
+func notIn(key, value string) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: []string{value}}
+}
+
+func Test_excludeNodeSelectors(t *testing.T) {
+	linux := corev1.NodeSelectorRequirement{Key: "os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{linux}}},
+			},
+		},
+	}
+
+	got := excludeNodeSelectors(affinity, []map[string]string{
+		{"pool": "gpu"},
+		{"pool": "highmem", "zone": "a"},
+	})
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{linux, notIn("pool", "gpu"), notIn("pool", "highmem")}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{linux, notIn("pool", "gpu"), notIn("zone", "a")}},
+	}, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	assert.Len(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1, "the affinity passed as argument should not be modified")
+
+	// Without affinity, a node affinity is created
+	got = excludeNodeSelectors(nil, []map[string]string{{"pool": "gpu"}})
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notIn("pool", "gpu")}},
+	}, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func Test_setAgentProfilePlacement(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.Spec.Profiles = []datadoghqv2alpha1.AgentProfile{
+		{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+		{Name: "highmem", NodeSelector: map[string]string{"pool": "highmem"}},
+	}
+
+	// The default DaemonSet doesn't run on the nodes of the profiles
+	daemonset := newCanaryTestDaemonSet("agent:7")
+	setAgentProfilePlacement(dda, nil, &daemonset.ObjectMeta, daemonset.Spec.Selector, &daemonset.Spec.Template)
+	assert.Equal(t, "foo-agent", daemonset.Name)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notIn("pool", "gpu"), notIn("pool", "highmem")}},
+	}, daemonset.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// The DaemonSet of a profile runs on its nodes, except the nodes of the previous profiles
+	daemonset = newCanaryTestDaemonSet("agent:7")
+	setAgentProfilePlacement(dda, &dda.Spec.Profiles[1], &daemonset.ObjectMeta, daemonset.Spec.Selector, &daemonset.Spec.Template)
+	assert.Equal(t, "foo-agent-highmem", daemonset.Name)
+	assert.Equal(t, "highmem", daemonset.Labels[agentProfileLabelKey])
+	assert.Equal(t, map[string]string{"app": "agent", agentProfileLabelKey: "highmem"}, daemonset.Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"app": "agent", agentProfileLabelKey: "highmem"}, daemonset.Spec.Template.Labels)
+	assert.Equal(t, map[string]string{"pool": "highmem"}, daemonset.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notIn("pool", "gpu")}},
+	}, daemonset.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// The first profile only runs on its nodes
+	daemonset = newCanaryTestDaemonSet("agent:7")
+	setAgentProfilePlacement(dda, &dda.Spec.Profiles[0], &daemonset.ObjectMeta, daemonset.Spec.Selector, &daemonset.Spec.Template)
+	assert.Equal(t, "foo-agent-gpu", daemonset.Name)
+	assert.Nil(t, daemonset.Spec.Template.Spec.Affinity)
+}
+
+func TestReconciler_cleanupV2AgentProfiles(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.UID = "dda-uid"
+	s := testutils.TestScheme(true)
+
+	newProfileDaemonSet := func(name, profile string, owned bool) *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: name, Labels: map[string]string{agentProfileLabelKey: profile}},
+		}
+		if owned {
+			require.NoError(t, controllerutil.SetControllerReference(dda, ds, s))
+		}
+		return ds
+	}
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			newProfileDaemonSet("foo-agent-gpu", "gpu", true),
+			newProfileDaemonSet("foo-agent-highmem", "highmem", true),
+			newProfileDaemonSet("other-agent-highmem", "highmem", false),
+			newCanaryTestDaemonSet("agent:7"),
+		).Build(),
+		scheme:   s,
+		recorder: record.NewBroadcaster().NewRecorder(s, corev1.EventSource{}),
+		options:  ReconcilerOptions{V2Enabled: true},
+	}
+
+	require.NoError(t, r.cleanupV2AgentProfiles(logr.Discard(), dda, map[string]bool{"foo-agent-gpu": true}))
+
+	list := &appsv1.DaemonSetList{}
+	require.NoError(t, r.client.List(context.TODO(), list, client.InNamespace("bar")))
+	var names []string
+	for _, ds := range list.Items {
+		names = append(names, ds.Name)
+	}
+	assert.ElementsMatch(t, []string{"foo-agent", "foo-agent-gpu", "other-agent-highmem"}, names)
+}
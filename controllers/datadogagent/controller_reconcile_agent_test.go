@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+)
+
+func Test_unsupportedExtendedDaemonSetStrategy(t *testing.T) {
+	one := intstr.FromInt(1)
+	tests := []struct {
+		name     string
+		strategy *datadoghqv2alpha1.UpdateStrategy
+		want     []string
+	}{
+		{
+			name: "no update strategy",
+		},
+		{
+			name: "rolling update supported by the ExtendedDaemonSet",
+			strategy: &datadoghqv2alpha1.UpdateStrategy{
+				Type:          datadoghqv2alpha1.RollingUpdateStrategyType,
+				RollingUpdate: &datadoghqv2alpha1.RollingUpdate{MaxUnavailable: &one},
+			},
+		},
+		{
+			name:     "on delete",
+			strategy: &datadoghqv2alpha1.UpdateStrategy{Type: datadoghqv2alpha1.OnDeleteStrategyType},
+			want:     []string{"spec.override.nodeAgent.updateStrategy.type OnDelete"},
+		},
+		{
+			name:     "max surge",
+			strategy: &datadoghqv2alpha1.UpdateStrategy{RollingUpdate: &datadoghqv2alpha1.RollingUpdate{MaxSurge: &one}},
+			want:     []string{"spec.override.nodeAgent.updateStrategy.rollingUpdate.maxSurge"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			if tt.strategy != nil {
+				dda.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.NodeAgentComponentName: {UpdateStrategy: tt.strategy},
+				}
+			}
+			assert.Equal(t, tt.want, unsupportedExtendedDaemonSetStrategy(dda))
+		})
+	}
+}
@@ -27,36 +27,21 @@ import (
 func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
 	var result reconcile.Result
 
-	// Start by creating the Default Cluster-Agent deployment
-	deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
-	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
-
-	// Set Global setting on the default deployment
-	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterChecksRunnerComponentName)
-
-	// Apply features changes on the Deployment.Spec.Template
-	for _, feat := range features {
-		if errFeat := feat.ManageClusterChecksRunner(podManagers); errFeat != nil {
-			return result, errFeat
-		}
+	deployment, err := buildV2ClusterChecksRunnerDeployment(logger, features, dda, resourcesManager)
+	if err != nil {
+		return result, err
 	}
 
 	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType)
 
 	// The requiredComponents can change depending on if updates to features result in disabled components
 	ccrEnabled := requiredComponents.ClusterChecksRunner.IsEnabled()
-	dcaEnabled := requiredComponents.ClusterAgent.IsEnabled()
 
 	// If the Cluster Agent is disabled, then CCR should be disabled too
-	if dcaOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok {
-		if apiutils.BoolValue(dcaOverride.Disabled) {
-			return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
-		}
-	} else if !dcaEnabled {
+	if !isV2ClusterAgentEnabled(requiredComponents, dda) {
 		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
 
-	// If Override is defined for the CCR component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok {
 		if apiutils.BoolValue(componentOverride.Disabled) {
 			if ccrEnabled {
@@ -74,13 +59,60 @@ func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, required
 			// Delete CCR
 			return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 		}
-		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterChecksRunnerComponentName, dda.Name)
-		override.Deployment(deployment, componentOverride)
 	} else if !ccrEnabled {
 		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
 
-	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterChecksRunner)
+	componentOverride := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]
+	if err = override.PodDisruptionBudget(resourcesManager, deployment, componentOverride); err != nil {
+		return result, err
+	}
+	if err = override.HorizontalPodAutoscaler(resourcesManager, deployment, componentOverride); err != nil {
+		return result, err
+	}
+	if err = r.setV2ReferencedConfigsChecksum(deploymentLogger, dda, deployment); err != nil {
+		return result, err
+	}
+
+	return r.createOrUpdateDeployment(deploymentLogger, dda, datadoghqv2alpha1.ClusterChecksRunnerComponentName, deployment, override.IsAutoscalingEnabled(componentOverride), newStatus, updateStatusV2WithClusterChecksRunner)
+}
+
+// buildV2ClusterChecksRunnerDeployment builds the Cluster Checks Runner Deployment from the default one, the global settings,
+// the enabled features and the component override.
+func buildV2ClusterChecksRunnerDeployment(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers) (*appsv1.Deployment, error) {
+	// Start by creating the Default Cluster-Agent deployment
+	deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
+	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
+
+	// Set Global setting on the default deployment
+	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterChecksRunnerComponentName)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageClusterChecksRunner(podManagers); errFeat != nil {
+			return nil, newFeatureError(feat.ID(), manageClusterChecksRunnerStep, errFeat)
+		}
+	}
+
+	// If Override is defined for the CCR component, apply the override on the PodTemplateSpec, it will cascade to container.
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok && !apiutils.BoolValue(componentOverride.Disabled) {
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterChecksRunnerComponentName, dda.Name)
+		override.Deployment(deployment, componentOverride)
+	}
+
+	return deployment, nil
+}
+
+// isV2ClusterChecksRunnerEnabled returns true if the Cluster Checks Runner should be deployed.
+// It requires the Cluster Agent, and the component override, if defined, supersedes the components required by the features.
+func isV2ClusterChecksRunnerEnabled(requiredComponents feature.RequiredComponents, dda *datadoghqv2alpha1.DatadogAgent) bool {
+	if !isV2ClusterAgentEnabled(requiredComponents, dda) {
+		return false
+	}
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return requiredComponents.ClusterChecksRunner.IsEnabled()
 }
 
 func updateStatusV2WithClusterChecksRunner(deployment *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
@@ -27,18 +27,9 @@ import (
 func (r *Reconciler) reconcileV2ClusterAgent(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
 	var result reconcile.Result
 
-	// Start by creating the Default Cluster-Agent deployment
-	deployment := componentdca.NewDefaultClusterAgentDeployment(dda)
-	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
-
-	// Set Global setting on the default deployment
-	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterAgentComponentName)
-
-	// Apply features changes on the Deployment.Spec.Template
-	for _, feat := range features {
-		if errFeat := feat.ManageClusterAgent(podManagers); errFeat != nil {
-			return result, errFeat
-		}
+	deployment, err := buildV2ClusterAgentDeployment(logger, features, dda, resourcesManager)
+	if err != nil {
+		return result, err
 	}
 
 	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterAgentComponentName)
@@ -46,7 +37,6 @@ func (r *Reconciler) reconcileV2ClusterAgent(logger logr.Logger, requiredCompone
 	// The requiredComponents can change depending on if updates to features result in disabled components
 	dcaEnabled := requiredComponents.ClusterAgent.IsEnabled()
 
-	// If Override is defined for the clusterAgent component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok {
 		if apiutils.BoolValue(componentOverride.Disabled) {
 			if dcaEnabled {
@@ -63,13 +53,57 @@ func (r *Reconciler) reconcileV2ClusterAgent(logger logr.Logger, requiredCompone
 			}
 			return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
 		}
-		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterAgentComponentName, dda.Name)
-		override.Deployment(deployment, componentOverride)
 	} else if !dcaEnabled {
 		// If the override is not defined, then disable based on dcaEnabled value
 		return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
 	}
-	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterAgent)
+	componentOverride := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]
+	if err = override.PodDisruptionBudget(resourcesManager, deployment, componentOverride); err != nil {
+		return result, err
+	}
+	if err = override.HorizontalPodAutoscaler(resourcesManager, deployment, componentOverride); err != nil {
+		return result, err
+	}
+	if err = r.setV2ReferencedConfigsChecksum(deploymentLogger, dda, deployment); err != nil {
+		return result, err
+	}
+
+	return r.createOrUpdateDeployment(deploymentLogger, dda, datadoghqv2alpha1.ClusterAgentComponentName, deployment, override.IsAutoscalingEnabled(componentOverride), newStatus, updateStatusV2WithClusterAgent)
+}
+
+// buildV2ClusterAgentDeployment builds the Cluster Agent Deployment from the default one, the global settings,
+// the enabled features and the component override.
+func buildV2ClusterAgentDeployment(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers) (*appsv1.Deployment, error) {
+	// Start by creating the Default Cluster-Agent deployment
+	deployment := componentdca.NewDefaultClusterAgentDeployment(dda)
+	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
+
+	// Set Global setting on the default deployment
+	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterAgentComponentName)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageClusterAgent(podManagers); errFeat != nil {
+			return nil, newFeatureError(feat.ID(), manageClusterAgentStep, errFeat)
+		}
+	}
+
+	// If Override is defined for the clusterAgent component, apply the override on the PodTemplateSpec, it will cascade to container.
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok && !apiutils.BoolValue(componentOverride.Disabled) {
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterAgentComponentName, dda.Name)
+		override.Deployment(deployment, componentOverride)
+	}
+
+	return deployment, nil
+}
+
+// isV2ClusterAgentEnabled returns true if the Cluster Agent should be deployed.
+// The component override, if defined, supersedes the components required by the features.
+func isV2ClusterAgentEnabled(requiredComponents feature.RequiredComponents, dda *datadoghqv2alpha1.DatadogAgent) bool {
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return requiredComponents.ClusterAgent.IsEnabled()
 }
 
 func updateStatusV2WithClusterAgent(dca *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
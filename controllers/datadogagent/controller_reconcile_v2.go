@@ -16,13 +16,16 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature/extension"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -75,48 +78,63 @@ func (r *Reconciler) internalReconcileV2(ctx context.Context, request reconcile.
 		return result, err
 	}
 
-	// TODO check if IsValideDatadogAgent function is needed for v2
-	/*
-		if err = datadoghqv2alpha1.IsValidDatadogAgent(&instance.Spec); err != nil {
-			reqLogger.V(1).Info("Invalid spec", "error", err)
-			return r.updateStatusIfNeeded(reqLogger, instance, &instance.Status, result, err)
-		}
-	*/
+	// The validating webhook is optional: check again the spec, and report the errors in the status.
+	// The DatadogAgent is still reconciled, to not break the DatadogAgents created before a validation rule.
+	validationErr := datadoghqv2alpha1.ValidateDatadogAgent(instance)
+	if validationErr != nil {
+		reqLogger.Info("Invalid spec", "error", validationErr)
+	}
 
 	// Set default values for GlobalConfig and Features
 	instanceCopy := instance.DeepCopy()
 	datadoghqv2alpha1.DefaultDatadogAgent(instanceCopy)
 
-	return r.reconcileInstanceV2(ctx, reqLogger, instanceCopy)
+	return r.reconcileInstanceV2(ctx, reqLogger, instanceCopy, validationErr)
 }
 
-func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent) (reconcile.Result, error) {
+func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent, validationErr error) (reconcile.Result, error) {
 	var result reconcile.Result
 	newStatus := instance.Status.DeepCopy()
+	setInvalidSpecCondition(newStatus, validationErr)
+
+	extensions, err := r.listDatadogAgentExtensions(ctx, instance.Namespace)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
 
-	features, requiredComponents := feature.BuildFeatures(instance, reconcilerOptionsToFeatureOptions(&r.options, logger))
+	features, featuresRequiredComponents, requiredComponents, errs := buildFeatures(instance, extensions, reconcilerOptionsToFeatureOptions(&r.options, logger))
 	// update list of enabled features for metrics forwarder
 	r.updateMetricsForwardersFeatures(instance, features)
+	// keep track of the features errors to report them in the status
+	featStatuses := newFeatureStatuses(features, featuresRequiredComponents)
 
 	// -----------------------
 	// Manage dependencies
 	// -----------------------
+	keepAnnotationsFilter, keepLabelsFilter := globalKeepAnnotationsLabelsFilters(instance)
 	storeOptions := &dependencies.StoreOptions{
-		SupportCilium: r.options.SupportCilium,
-		VersionInfo:   r.versionInfo,
-		PlatformInfo:  r.platformInfo,
-		Logger:        logger,
-		Scheme:        r.scheme,
+		SupportCilium:          r.options.SupportCilium,
+		VersionInfo:            r.versionInfo,
+		PlatformInfo:           r.platformInfo,
+		Logger:                 logger,
+		Scheme:                 r.scheme,
+		ServerSideApplyEnabled: r.options.ServerSideApplyEnabled,
+		UpdateFieldManager:     r.options.UpdateFieldManager,
+		DriftHandler: func(drift dependencies.Drift) {
+			r.recordDriftCorrected(instance, drift)
+		},
+		KeepAnnotationsFilter: keepAnnotationsFilter,
+		KeepLabelsFilter:      keepLabelsFilter,
 	}
 	depsStore := dependencies.NewStore(instance, storeOptions)
 	resourceManagers := feature.NewResourceManagers(depsStore)
 
-	var errs []error
-
 	// Set up dependencies required by enabled features
 	for _, feat := range features {
 		logger.Info("Dependency ManageDependencies", "featureID", feat.ID())
 		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
+			featErr = newFeatureError(feat.ID(), manageDependenciesStep, featErr)
+			featStatuses.recordError(featErr)
 			errs = append(errs, featErr)
 		}
 	}
@@ -124,6 +142,13 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// Examine user configuration to override any external dependencies (e.g. RBACs)
 	errs = append(errs, override.Dependencies(logger, resourceManagers, instance)...)
 
+	// Detect the resources shared with other DatadogAgents before deploying the components
+	conflicts, err := r.detectV2Conflicts(ctx, instance, depsStore)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+	preventNodeAgent := r.updateV2ConflictStatus(instance, newStatus, conflicts)
+
 	userSpecifiedClusterAgentToken := instance.Spec.Global.ClusterAgentToken != nil || instance.Spec.Global.ClusterAgentTokenSecret != nil
 	if !userSpecifiedClusterAgentToken {
 		ensureAutoGeneratedTokenInStatus(instance, newStatus, resourceManagers, logger)
@@ -133,21 +158,29 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// Start reconcile Components
 	// -----------------------------
 
-	var err error
-
 	result, err = r.reconcileV2ClusterAgent(logger, requiredComponents, features, instance, resourceManagers, newStatus)
 	if utils.ShouldReturn(result, err) {
+		featStatuses.recordError(err)
+		featStatuses.updateStatus(newStatus, metav1.NewTime(time.Now()))
 		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
-	requiredContainers := requiredComponents.Agent.Containers
-	result, err = r.reconcileV2Agent(logger, requiredComponents, features, instance, resourceManagers, newStatus, requiredContainers)
-	if utils.ShouldReturn(result, err) {
-		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	if preventNodeAgent {
+		logger.Info("The node Agent is not deployed: its nodes already run the node Agent of another DatadogAgent")
+	} else {
+		requiredContainers := requiredComponents.Agent.Containers
+		result, err = r.reconcileV2Agent(logger, requiredComponents, features, instance, resourceManagers, newStatus, requiredContainers)
+		if utils.ShouldReturn(result, err) {
+			featStatuses.recordError(err)
+			featStatuses.updateStatus(newStatus, metav1.NewTime(time.Now()))
+			return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+		}
 	}
 
 	result, err = r.reconcileV2ClusterChecksRunner(logger, requiredComponents, features, instance, resourceManagers, newStatus)
 	if utils.ShouldReturn(result, err) {
+		featStatuses.recordError(err)
+		featStatuses.updateStatus(newStatus, metav1.NewTime(time.Now()))
 		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
@@ -155,9 +188,10 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// Create and update dependencies
 	// ------------------------------
 	errs = append(errs, depsStore.Apply(ctx, r.client)...)
+	featStatuses.updateStatus(newStatus, metav1.NewTime(time.Now()))
 	if len(errs) > 0 {
 		logger.V(2).Info("Dependencies apply error", "errs", errs)
-		return result, errors.NewAggregate(errs)
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
 	}
 
 	// -----------------------------
@@ -165,7 +199,7 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// -----------------------------
 	// Run it after the deployments reconcile
 	if errs = depsStore.Cleanup(ctx, r.client); len(errs) > 0 {
-		return result, errors.NewAggregate(errs)
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
 	}
 
 	// Always requeue
@@ -175,6 +209,16 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 }
 
+// setInvalidSpecCondition reports the validation errors of the DatadogAgent spec in the InvalidSpec condition
+func setInvalidSpecCondition(status *datadoghqv2alpha1.DatadogAgentStatus, validationErr error) {
+	now := metav1.NewTime(time.Now())
+	if validationErr != nil {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(status, now, datadoghqv2alpha1.InvalidSpecConditionType, metav1.ConditionTrue, "InvalidSpec", validationErr.Error(), false)
+	} else {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(status, now, datadoghqv2alpha1.InvalidSpecConditionType, metav1.ConditionFalse, "ValidSpec", "", false)
+	}
+}
+
 func (r *Reconciler) updateStatusIfNeededV2(logger logr.Logger, agentdeployment *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus, result reconcile.Result, currentError error) (reconcile.Result, error) {
 	now := metav1.NewTime(time.Now())
 	if currentError == nil {
@@ -246,6 +290,36 @@ func ensureAutoGeneratedTokenInStatus(instance *datadoghqv2alpha1.DatadogAgent,
 	newStatus.ClusterAgent.GeneratedToken = string(generatedToken)
 }
 
+// listDatadogAgentExtensions returns the DatadogAgentExtensions of a namespace.
+// It returns nothing if the DatadogAgentExtensions are not enabled.
+func (r *Reconciler) listDatadogAgentExtensions(ctx context.Context, namespace string) ([]datadoghqv1alpha1.DatadogAgentExtension, error) {
+	if !r.options.DatadogAgentExtensionEnabled {
+		return nil, nil
+	}
+
+	extensionList := &datadoghqv1alpha1.DatadogAgentExtensionList{}
+	if err := r.client.List(ctx, extensionList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list the DatadogAgentExtensions: %w", err)
+	}
+	return extensionList.Items, nil
+}
+
+// buildFeatures returns the features configured in the DatadogAgent, followed by the features
+// of the DatadogAgentExtensions selecting it, and their RequiredComponents like feature.BuildFeaturesWithRequirements.
+// The returned errors concern the DatadogAgentExtensions that couldn't be applied.
+func buildFeatures(dda *datadoghqv2alpha1.DatadogAgent, extensions []datadoghqv1alpha1.DatadogAgentExtension, options *feature.Options) ([]feature.Feature, map[feature.IDType]feature.RequiredComponents, feature.RequiredComponents, []error) {
+	features, featuresRequiredComponents, requiredComponents := feature.BuildFeaturesWithRequirements(dda, options)
+
+	extFeatures, extFeaturesRequiredComponents, extRequiredComponents, errs := extension.BuildFeatures(dda, extensions, options)
+	features = append(features, extFeatures...)
+	for id, reqComponents := range extFeaturesRequiredComponents {
+		featuresRequiredComponents[id] = reqComponents
+	}
+	requiredComponents.Merge(&extRequiredComponents)
+
+	return features, featuresRequiredComponents, requiredComponents, errs
+}
+
 func (r *Reconciler) updateMetricsForwardersFeatures(dda *datadoghqv2alpha1.DatadogAgent, features []feature.Feature) {
 	if r.forwarders != nil {
 		r.forwarders.SetEnabledFeatures(dda, features)
@@ -19,6 +19,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -34,7 +35,7 @@ type updateDepStatusComponentFunc func(deployment *appsv1.Deployment, newStatus
 type updateDSStatusComponentFunc func(daemonset *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string)
 type updateEDSStatusComponentFunc func(eds *edsv1alpha1.ExtendedDaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string)
 
-func (r *Reconciler) createOrUpdateDeployment(parentLogger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, deployment *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateStatusFunc updateDepStatusComponentFunc) (reconcile.Result, error) {
+func (r *Reconciler) createOrUpdateDeployment(parentLogger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName, deployment *appsv1.Deployment, autoscaled bool, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateStatusFunc updateDepStatusComponentFunc) (reconcile.Result, error) {
 	logger := parentLogger.WithValues("deployment.Namespace", deployment.Namespace, "deployment.Name", deployment.Name)
 
 	var result reconcile.Result
@@ -83,19 +84,23 @@ func (r *Reconciler) createOrUpdateDeployment(parentLogger logr.Logger, dda *dat
 
 		logger.Info("Updating Deployment")
 
-		// TODO: these parameters can be added to the override.PodTemplateSpec. (It exists in v1alpha1)
-		keepAnnotationsFilter := ""
-		keepLabelsFilter := ""
+		keepAnnotationsFilter, keepLabelsFilter := keepAnnotationsLabelsFilters(dda, componentName)
 
 		// Copy possibly changed fields
 		updateDeployment := deployment.DeepCopy()
 		updateDeployment.Spec = *deployment.Spec.DeepCopy()
-		updateDeployment.Spec.Replicas = getReplicas(currentDeployment.Spec.Replicas, updateDeployment.Spec.Replicas)
-		updateDeployment.Annotations = mergeAnnotationsLabels(logger, currentDeployment.GetAnnotations(), deployment.GetAnnotations(), keepAnnotationsFilter)
-		updateDeployment.Labels = mergeAnnotationsLabels(logger, currentDeployment.GetLabels(), deployment.GetLabels(), keepLabelsFilter)
+		updateDeployment.Spec.Replicas = getReplicas(currentDeployment.Spec.Replicas, updateDeployment.Spec.Replicas, autoscaled)
+		updateDeployment.Annotations = kubernetes.MergeAnnotationsLabels(logger, currentDeployment.GetAnnotations(), deployment.GetAnnotations(), keepAnnotationsFilter)
+		updateDeployment.Labels = kubernetes.MergeAnnotationsLabels(logger, currentDeployment.GetLabels(), deployment.GetLabels(), keepLabelsFilter)
 
 		now := metav1.NewTime(time.Now())
-		err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateDeployment, currentDeployment.ObjectMeta)
+		if r.options.ServerSideApplyEnabled {
+			// Only the fields set by the operator are applied: the annotations and labels set by other
+			// controllers are kept.
+			err = kubernetes.ApplyObject(context.TODO(), r.client, deploymentToApply(dda, componentName, deployment, autoscaled), currentDeployment, r.options.UpdateFieldManager)
+		} else {
+			err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateDeployment, currentDeployment.ObjectMeta)
+		}
 		if err != nil {
 			updateStatusFunc(nil, newStatus, now, metav1.ConditionFalse, updateSucceeded, "Unable to update Deployment")
 			return reconcile.Result{}, err
@@ -106,7 +111,7 @@ func (r *Reconciler) createOrUpdateDeployment(parentLogger logr.Logger, dda *dat
 	} else {
 		now := metav1.NewTime(time.Now())
 
-		err = r.client.Create(context.TODO(), deployment)
+		err = r.createObject(deployment)
 		if err != nil {
 			updateStatusFunc(nil, newStatus, now, metav1.ConditionFalse, createSucceeded, "Unable to create Deployment")
 			return reconcile.Result{}, err
@@ -121,6 +126,17 @@ func (r *Reconciler) createOrUpdateDeployment(parentLogger logr.Logger, dda *dat
 	return result, err
 }
 
+// deploymentToApply returns the Deployment to update with server-side apply. The replicas are only applied
+// when they are set in the component override, to not take them over from an HPA, whether it's created by
+// the operator or not.
+func deploymentToApply(dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName, deployment *appsv1.Deployment, autoscaled bool) *appsv1.Deployment {
+	applyDeployment := deployment.DeepCopy()
+	if componentOverride, ok := dda.Spec.Override[componentName]; !ok || componentOverride == nil || componentOverride.Replicas == nil || autoscaled {
+		applyDeployment.Spec.Replicas = nil
+	}
+	return applyDeployment
+}
+
 func (r *Reconciler) createOrUpdateDaemonset(parentLogger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, daemonset *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateStatusFunc updateDSStatusComponentFunc) (reconcile.Result, error) {
 	logger := parentLogger.WithValues("daemonset.Namespace", daemonset.Namespace, "daemonset.Name", daemonset.Name)
 
@@ -174,15 +190,13 @@ func (r *Reconciler) createOrUpdateDaemonset(parentLogger logr.Logger, dda *data
 
 		logger.Info("Updating Daemonset")
 
-		// TODO: these parameters can be added to the override.PodTemplateSpec. (It exists in v1alpha1)
-		keepAnnotationsFilter := ""
-		keepLabelsFilter := ""
+		keepAnnotationsFilter, keepLabelsFilter := keepAnnotationsLabelsFilters(dda, datadoghqv2alpha1.NodeAgentComponentName)
 
 		// Copy possibly changed fields
 		updateDaemonset := daemonset.DeepCopy()
 		updateDaemonset.Spec = *daemonset.Spec.DeepCopy()
-		updateDaemonset.Annotations = mergeAnnotationsLabels(logger, currentDaemonset.GetAnnotations(), daemonset.GetAnnotations(), keepAnnotationsFilter)
-		updateDaemonset.Labels = mergeAnnotationsLabels(logger, currentDaemonset.GetLabels(), daemonset.GetLabels(), keepLabelsFilter)
+		updateDaemonset.Annotations = kubernetes.MergeAnnotationsLabels(logger, currentDaemonset.GetAnnotations(), daemonset.GetAnnotations(), keepAnnotationsFilter)
+		updateDaemonset.Labels = kubernetes.MergeAnnotationsLabels(logger, currentDaemonset.GetLabels(), daemonset.GetLabels(), keepLabelsFilter)
 
 		// Spec.Selector is an immutable field and can't be change changing it leads to an error.
 		// Template.Labels must match Spec.Selector, otherwise they become orphaned. Hence we keep both fields from the current DS.
@@ -191,7 +205,14 @@ func (r *Reconciler) createOrUpdateDaemonset(parentLogger logr.Logger, dda *data
 		updateDaemonset.Spec.Template.Labels = currentDaemonset.Spec.Template.Labels
 
 		now := metav1.NewTime(time.Now())
-		err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateDaemonset, currentDaemonset.ObjectMeta)
+		if r.options.ServerSideApplyEnabled {
+			applyDaemonset := daemonset.DeepCopy()
+			applyDaemonset.Spec.Selector = currentDaemonset.Spec.Selector
+			applyDaemonset.Spec.Template.Labels = currentDaemonset.Spec.Template.Labels
+			err = kubernetes.ApplyObject(context.TODO(), r.client, applyDaemonset, currentDaemonset, r.options.UpdateFieldManager)
+		} else {
+			err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateDaemonset, currentDaemonset.ObjectMeta)
+		}
 		if err != nil {
 			updateStatusFunc(updateDaemonset, newStatus, now, metav1.ConditionFalse, updateSucceeded, "Unable to update Daemonset")
 			return reconcile.Result{}, err
@@ -202,7 +223,7 @@ func (r *Reconciler) createOrUpdateDaemonset(parentLogger logr.Logger, dda *data
 	} else {
 		now := metav1.NewTime(time.Now())
 
-		err = r.client.Create(context.TODO(), daemonset)
+		err = r.createObject(daemonset)
 		if err != nil {
 			updateStatusFunc(nil, newStatus, now, metav1.ConditionFalse, createSucceeded, "Unable to create Daemonset")
 			return reconcile.Result{}, err
@@ -270,18 +291,20 @@ func (r *Reconciler) createOrUpdateExtendedDaemonset(parentLogger logr.Logger, d
 
 		logger.Info("Updating ExtendedDaemonSet")
 
-		// TODO: these parameters can be added to the override.PodTemplateSpec. (It exists in v1alpha1)
-		keepAnnotationsFilter := ""
-		keepLabelsFilter := ""
+		keepAnnotationsFilter, keepLabelsFilter := keepAnnotationsLabelsFilters(dda, datadoghqv2alpha1.NodeAgentComponentName)
 
 		// Copy possibly changed fields
 		updateEDS := eds.DeepCopy()
 		updateEDS.Spec = *eds.Spec.DeepCopy()
-		updateEDS.Annotations = mergeAnnotationsLabels(logger, currentEDS.GetAnnotations(), eds.GetAnnotations(), keepAnnotationsFilter)
-		updateEDS.Labels = mergeAnnotationsLabels(logger, currentEDS.GetLabels(), eds.GetLabels(), keepLabelsFilter)
+		updateEDS.Annotations = kubernetes.MergeAnnotationsLabels(logger, currentEDS.GetAnnotations(), eds.GetAnnotations(), keepAnnotationsFilter)
+		updateEDS.Labels = kubernetes.MergeAnnotationsLabels(logger, currentEDS.GetLabels(), eds.GetLabels(), keepLabelsFilter)
 
 		now := metav1.NewTime(time.Now())
-		err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateEDS, currentEDS.ObjectMeta)
+		if r.options.ServerSideApplyEnabled {
+			err = kubernetes.ApplyObject(context.TODO(), r.client, eds, currentEDS, r.options.UpdateFieldManager)
+		} else {
+			err = kubernetes.UpdateFromObject(context.TODO(), r.client, updateEDS, currentEDS.ObjectMeta)
+		}
 		if err != nil {
 			updateStatusFunc(updateEDS, newStatus, now, metav1.ConditionFalse, updateSucceeded, "Unable to update ExtendedDaemonSet")
 			return reconcile.Result{}, err
@@ -292,7 +315,7 @@ func (r *Reconciler) createOrUpdateExtendedDaemonset(parentLogger logr.Logger, d
 	} else {
 		now := metav1.NewTime(time.Now())
 
-		err = r.client.Create(context.TODO(), eds)
+		err = r.createObject(eds)
 		if err != nil {
 			updateStatusFunc(nil, newStatus, now, metav1.ConditionFalse, createSucceeded, "Unable to create ExtendedDaemonSet")
 			return reconcile.Result{}, err
@@ -306,3 +329,40 @@ func (r *Reconciler) createOrUpdateExtendedDaemonset(parentLogger logr.Logger, d
 
 	return result, err
 }
+
+// createObject creates a component workload, with server-side apply if it is enabled.
+func (r *Reconciler) createObject(obj client.Object) error {
+	if r.options.ServerSideApplyEnabled {
+		return kubernetes.ApplyObject(context.TODO(), r.client, obj, nil, r.options.UpdateFieldManager)
+	}
+	return r.client.Create(context.TODO(), obj)
+}
+
+// keepAnnotationsLabelsFilters returns the glob filters of the annotations and labels, not managed by the operator,
+// to keep on the workload of a component. The component override has priority over the global configuration.
+func keepAnnotationsLabelsFilters(dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName) (annotationsFilter, labelsFilter string) {
+	annotationsFilter, labelsFilter = globalKeepAnnotationsLabelsFilters(dda)
+	if componentOverride, ok := dda.Spec.Override[componentName]; ok && componentOverride != nil {
+		if componentOverride.KeepAnnotations != nil {
+			annotationsFilter = *componentOverride.KeepAnnotations
+		}
+		if componentOverride.KeepLabels != nil {
+			labelsFilter = *componentOverride.KeepLabels
+		}
+	}
+	return annotationsFilter, labelsFilter
+}
+
+// globalKeepAnnotationsLabelsFilters returns the glob filters of the annotations and labels, not managed by the operator,
+// to keep on all the resources of a DatadogAgent.
+func globalKeepAnnotationsLabelsFilters(dda *datadoghqv2alpha1.DatadogAgent) (annotationsFilter, labelsFilter string) {
+	if global := dda.Spec.Global; global != nil {
+		if global.KeepAnnotations != nil {
+			annotationsFilter = *global.KeepAnnotations
+		}
+		if global.KeepLabels != nil {
+			labelsFilter = *global.KeepLabels
+		}
+	}
+	return annotationsFilter, labelsFilter
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+)
+
+func Test_keepAnnotationsLabelsFilters(t *testing.T) {
+	tests := []struct {
+		name            string
+		global          *datadoghqv2alpha1.GlobalConfig
+		override        *datadoghqv2alpha1.DatadogAgentComponentOverride
+		wantAnnotations string
+		wantLabels      string
+	}{
+		{
+			name: "no filters",
+		},
+		{
+			name:            "global filters",
+			global:          &datadoghqv2alpha1.GlobalConfig{KeepAnnotations: apiutils.NewStringPointer("istio.io/*"), KeepLabels: apiutils.NewStringPointer("team")},
+			wantAnnotations: "istio.io/*",
+			wantLabels:      "team",
+		},
+		{
+			name:            "the component override has priority",
+			global:          &datadoghqv2alpha1.GlobalConfig{KeepAnnotations: apiutils.NewStringPointer("istio.io/*"), KeepLabels: apiutils.NewStringPointer("team")},
+			override:        &datadoghqv2alpha1.DatadogAgentComponentOverride{KeepLabels: apiutils.NewStringPointer("{team,env}")},
+			wantAnnotations: "istio.io/*",
+			wantLabels:      "{team,env}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &datadoghqv2alpha1.DatadogAgent{
+				Spec: datadoghqv2alpha1.DatadogAgentSpec{
+					Global:   tt.global,
+					Override: map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{},
+				},
+			}
+			if tt.override != nil {
+				dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName] = tt.override
+			}
+
+			annotations, labels := keepAnnotationsLabelsFilters(dda, datadoghqv2alpha1.NodeAgentComponentName)
+			assert.Equal(t, tt.wantAnnotations, annotations)
+			assert.Equal(t, tt.wantLabels, labels)
+		})
+	}
+}
+
+func TestReconciler_createOrUpdateDaemonset_keepLabels(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", &datadoghqv2alpha1.GlobalConfig{KeepLabels: apiutils.NewStringPointer("argocd.argoproj.io/*")})
+	s := testutils.TestScheme(true)
+	r := &Reconciler{
+		client:   fake.NewClientBuilder().WithScheme(s).Build(),
+		scheme:   s,
+		recorder: record.NewBroadcaster().NewRecorder(s, corev1.EventSource{}),
+		options:  ReconcilerOptions{V2Enabled: true},
+	}
+	key := client.ObjectKey{Namespace: "bar", Name: "foo-agent"}
+
+	_, err := r.createOrUpdateDaemonset(logr.Discard(), dda, newCanaryTestDaemonSet("agent:7.40"), &datadoghqv2alpha1.DatadogAgentStatus{}, updateDSStatusV2WithAgent)
+	require.NoError(t, err)
+
+	// Labels are added by other tools
+	daemonset := &appsv1.DaemonSet{}
+	require.NoError(t, r.client.Get(context.TODO(), key, daemonset))
+	daemonset.Labels = map[string]string{"argocd.argoproj.io/instance": "datadog", "other": "value"}
+	require.NoError(t, r.client.Update(context.TODO(), daemonset))
+
+	_, err = r.createOrUpdateDaemonset(logr.Discard(), dda, newCanaryTestDaemonSet("agent:7.41"), &datadoghqv2alpha1.DatadogAgentStatus{}, updateDSStatusV2WithAgent)
+	require.NoError(t, err)
+
+	require.NoError(t, r.client.Get(context.TODO(), key, daemonset))
+	assert.Equal(t, "agent:7.41", daemonset.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, map[string]string{"argocd.argoproj.io/instance": "datadog"}, daemonset.Labels)
+}
+
+func Test_deploymentToApply(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{Replicas: apiutils.NewInt32Pointer(1)},
+	}
+
+	tests := []struct {
+		name         string
+		override     *datadoghqv2alpha1.DatadogAgentComponentOverride
+		autoscaled   bool
+		wantReplicas *int32
+	}{
+		{
+			name: "no override, the replicas can be managed by an external HPA",
+		},
+		{
+			name:     "override without replicas",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Name: apiutils.NewStringPointer("cluster-agent")},
+		},
+		{
+			name:         "replicas override",
+			override:     &datadoghqv2alpha1.DatadogAgentComponentOverride{Replicas: apiutils.NewInt32Pointer(3)},
+			wantReplicas: apiutils.NewInt32Pointer(1),
+		},
+		{
+			name:       "replicas managed by the operator HPA",
+			override:   &datadoghqv2alpha1.DatadogAgentComponentOverride{Replicas: apiutils.NewInt32Pointer(3)},
+			autoscaled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			if tt.override != nil {
+				dda.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.ClusterAgentComponentName: tt.override,
+				}
+			}
+
+			got := deploymentToApply(dda, datadoghqv2alpha1.ClusterAgentComponentName, deployment, tt.autoscaled)
+			assert.Equal(t, tt.wantReplicas, got.Spec.Replicas)
+			// The built Deployment is left untouched, it's used for the status
+			assert.Equal(t, apiutils.NewInt32Pointer(1), deployment.Spec.Replicas)
+		})
+	}
+}
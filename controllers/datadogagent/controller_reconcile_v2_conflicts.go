@@ -0,0 +1,311 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+// admissionControllerWebhookName is the name of the MutatingWebhookConfiguration created by the Cluster Agent.
+const admissionControllerWebhookName = "datadog-webhook"
+
+// daemonSetTolerations are the taints tolerated by the pods of any DaemonSet, added by the DaemonSet controller.
+var daemonSetTolerations = map[string]bool{
+	corev1.TaintNodeNotReady:           true,
+	corev1.TaintNodeUnreachable:        true,
+	corev1.TaintNodeUnschedulable:      true,
+	corev1.TaintNodeMemoryPressure:     true,
+	corev1.TaintNodeDiskPressure:       true,
+	corev1.TaintNodeNetworkUnavailable: true,
+	corev1.TaintNodePIDPressure:        true,
+}
+
+// datadogAgentConflict describes the resources that a DatadogAgent shares with another DatadogAgent.
+type datadogAgentConflict struct {
+	// other is the DatadogAgent sharing the resources.
+	other types.NamespacedName
+	// otherIsOlder is true if the other DatadogAgent was created first.
+	otherIsOlder bool
+	// nodes is the number of nodes that run the node Agent of both DatadogAgents.
+	nodes int
+	// objects are the cluster-scoped objects claimed by both DatadogAgents, as `<Kind> <name>`.
+	objects []string
+}
+
+func (c *datadogAgentConflict) String() string {
+	var resources []string
+	if c.nodes > 0 {
+		resources = append(resources, fmt.Sprintf("node Agent on %d node(s)", c.nodes))
+	}
+	resources = append(resources, c.objects...)
+	return fmt.Sprintf("DatadogAgent %s: %s", c.other, strings.Join(resources, ", "))
+}
+
+// updateV2ConflictStatus reports the conflicts in the Conflict condition, and records an event when they change.
+// It returns true if the node Agent should not be deployed: its nodes already run the node Agent of an older
+// DatadogAgent, and the operator prevents the conflicts.
+func (r *Reconciler) updateV2ConflictStatus(dda *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus, conflicts []datadogAgentConflict) bool {
+	now := metav1.NewTime(time.Now())
+	if len(conflicts) == 0 {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.ConflictConditionType, metav1.ConditionFalse, "NoConflict", "", false)
+		return false
+	}
+
+	preventNodeAgent := false
+	descriptions := make([]string, 0, len(conflicts))
+	for i := range conflicts {
+		descriptions = append(descriptions, conflicts[i].String())
+		preventNodeAgent = preventNodeAgent || (r.options.AgentConflictPreventionEnabled && conflicts[i].nodes > 0 && conflicts[i].otherIsOlder)
+	}
+	message := "Resources shared with " + strings.Join(descriptions, "; ")
+	if preventNodeAgent {
+		message += "; the node Agent is not deployed"
+	}
+
+	var previousMessage string
+	for _, condition := range newStatus.Conditions {
+		if condition.Type == datadoghqv2alpha1.ConflictConditionType && condition.Status == metav1.ConditionTrue {
+			previousMessage = condition.Message
+		}
+	}
+	if message != previousMessage {
+		r.recordConflict(dda, message)
+	}
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.ConflictConditionType, metav1.ConditionTrue, "ConflictingDatadogAgent", message, true)
+
+	return preventNodeAgent
+}
+
+// detectV2Conflicts returns the resources that a defaulted DatadogAgent shares with the other DatadogAgents of the cluster:
+// the nodes where both would run the node Agent, and the cluster-scoped objects of the dependencies store
+// already created by another DatadogAgent. The conflicts are sorted by DatadogAgent namespace and name.
+func (r *Reconciler) detectV2Conflicts(ctx context.Context, dda *datadoghqv2alpha1.DatadogAgent, depsStore *dependencies.Store) ([]datadogAgentConflict, error) {
+	ddaList := &datadoghqv2alpha1.DatadogAgentList{}
+	if err := r.client.List(ctx, ddaList); err != nil {
+		return nil, err
+	}
+	nodeList := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodeList); err != nil {
+		return nil, err
+	}
+
+	self := types.NamespacedName{Namespace: dda.Namespace, Name: dda.Name}
+	conflicts := map[types.NamespacedName]*datadogAgentConflict{}
+	getConflict := func(other *datadoghqv2alpha1.DatadogAgent, nsName types.NamespacedName) *datadogAgentConflict {
+		if conflicts[nsName] == nil {
+			conflicts[nsName] = &datadogAgentConflict{other: nsName, otherIsOlder: isOlderDatadogAgent(other, dda)}
+		}
+		return conflicts[nsName]
+	}
+
+	others := map[types.NamespacedName]*datadoghqv2alpha1.DatadogAgent{}
+	for i := range ddaList.Items {
+		other := &ddaList.Items[i]
+		nsName := types.NamespacedName{Namespace: other.Namespace, Name: other.Name}
+		if nsName == self || other.DeletionTimestamp != nil {
+			continue
+		}
+		datadoghqv2alpha1.DefaultDatadogAgent(other)
+		others[nsName] = other
+
+		if nodes := countSharedNodes(nodeList.Items, dda, other); nodes > 0 {
+			getConflict(other, nsName).nodes = nodes
+		}
+		if isAdmissionControllerWebhookEnabled(dda) && isAdmissionControllerWebhookEnabled(other) {
+			conflict := getConflict(other, nsName)
+			conflict.objects = append(conflict.objects, "MutatingWebhookConfiguration "+admissionControllerWebhookName)
+		}
+	}
+
+	for _, obj := range depsStore.GetAll() {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+		owner, err := r.getClusterScopedObjectOwner(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		// The objects of a deleted DatadogAgent are taken over.
+		other, found := others[owner]
+		if !found {
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.scheme)
+		if err != nil {
+			return nil, err
+		}
+		conflict := getConflict(other, owner)
+		conflict.objects = append(conflict.objects, gvk.Kind+" "+obj.GetName())
+	}
+
+	result := make([]datadogAgentConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		result = append(result, *conflict)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].other.String() < result[j].other.String()
+	})
+	return result, nil
+}
+
+// getClusterScopedObjectOwner returns the DatadogAgent that created a cluster-scoped object of the dependencies store,
+// from its part-of label, or an empty NamespacedName if the object doesn't exist or wasn't created by the operator.
+func (r *Reconciler) getClusterScopedObjectOwner(ctx context.Context, obj client.Object) (types.NamespacedName, error) {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return types.NamespacedName{}, nil
+	}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return types.NamespacedName{}, nil
+		}
+		return types.NamespacedName{}, err
+	}
+	partOf, found := current.GetLabels()[kubernetes.AppKubernetesPartOfLabelKey]
+	if !found {
+		return types.NamespacedName{}, nil
+	}
+	return (&object.PartOfLabelValue{Value: partOf}).NamespacedName(), nil
+}
+
+// isOlderDatadogAgent returns true if a DatadogAgent was created before another one,
+// using the namespace and name to order the DatadogAgents created at the same time.
+func isOlderDatadogAgent(dda, other *datadoghqv2alpha1.DatadogAgent) bool {
+	if !dda.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return dda.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	return dda.Namespace+"/"+dda.Name < other.Namespace+"/"+other.Name
+}
+
+// isAdmissionControllerWebhookEnabled returns true if the Cluster Agent of a defaulted DatadogAgent creates the admission controller webhook.
+func isAdmissionControllerWebhookEnabled(dda *datadoghqv2alpha1.DatadogAgent) bool {
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok && apiutils.BoolValue(componentOverride.Disabled) {
+		return false
+	}
+	return dda.Spec.Features != nil && dda.Spec.Features.AdmissionController != nil && apiutils.BoolValue(dda.Spec.Features.AdmissionController.Enabled)
+}
+
+// countSharedNodes returns the number of nodes where both DatadogAgents run the node Agent.
+func countSharedNodes(nodes []corev1.Node, dda, other *datadoghqv2alpha1.DatadogAgent) int {
+	var count int
+	for i := range nodes {
+		if runsNodeAgent(dda, &nodes[i]) && runsNodeAgent(other, &nodes[i]) {
+			count++
+		}
+	}
+	return count
+}
+
+// runsNodeAgent returns true if the node Agent of a DatadogAgent can be scheduled on a node, following the node selector,
+// the required node affinity and the tolerations of the node Agent override. The profiles only change the node Agent
+// running on their nodes, except the disabled profiles, whose nodes don't run the node Agent.
+func runsNodeAgent(dda *datadoghqv2alpha1.DatadogAgent, node *corev1.Node) bool {
+	if !isV2AgentEnabled(dda) {
+		return false
+	}
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		if !labels.SelectorFromSet(componentOverride.NodeSelector).Matches(labels.Set(node.Labels)) {
+			return false
+		}
+		if !matchesRequiredNodeAffinity(componentOverride.Affinity, node) {
+			return false
+		}
+		if !toleratesNodeTaints(componentOverride.Tolerations, node) {
+			return false
+		}
+	} else if !toleratesNodeTaints(nil, node) {
+		return false
+	}
+
+	for i := range dda.Spec.Profiles {
+		if labels.SelectorFromSet(dda.Spec.Profiles[i].NodeSelector).Matches(labels.Set(node.Labels)) {
+			return isV2AgentProfileEnabled(&dda.Spec.Profiles[i])
+		}
+	}
+	return true
+}
+
+// matchesRequiredNodeAffinity returns true if a node matches one of the required node selector terms of an affinity.
+func matchesRequiredNodeAffinity(affinity *corev1.Affinity, node *corev1.Node) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		// An empty term matches no node.
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if matchesNodeSelectorRequirements(term.MatchExpressions, labels.Set(node.Labels)) &&
+			matchesNodeSelectorRequirements(term.MatchFields, labels.Set{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+	return false
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func matchesNodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement, fields labels.Set) bool {
+	for _, requirement := range requirements {
+		operator, found := nodeSelectorOperators[requirement.Operator]
+		if !found {
+			return false
+		}
+		labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !labelRequirement.Matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// toleratesNodeTaints returns true if the tolerations, and the tolerations added to the pods of any DaemonSet,
+// tolerate the taints of a node preventing scheduling.
+func toleratesNodeTaints(tolerations []corev1.Toleration, node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || daemonSetTolerations[taint.Key] {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func Test_runsNodeAgent(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "gpu", "zone": "a"}},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: corev1.TaintNodeNotReady, Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+	}
+	gpuToleration := []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+
+	tests := []struct {
+		name     string
+		override *datadoghqv2alpha1.DatadogAgentComponentOverride
+		profiles []datadoghqv2alpha1.AgentProfile
+		want     bool
+	}{
+		{
+			name: "taint not tolerated",
+			want: false,
+		},
+		{
+			name:     "taint tolerated",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Tolerations: gpuToleration},
+			want:     true,
+		},
+		{
+			name:     "disabled node Agent",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Tolerations: gpuToleration, Disabled: apiutils.NewBoolPointer(true)},
+			want:     false,
+		},
+		{
+			name:     "node selector not matching",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Tolerations: gpuToleration, NodeSelector: map[string]string{"pool": "highmem"}},
+			want:     false,
+		},
+		{
+			name: "required node affinity not matching",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{
+				Tolerations: gpuToleration,
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+						{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-2"}}}},
+					}},
+				}},
+			},
+			want: false,
+		},
+		{
+			name: "required node affinity matching a term",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{
+				Tolerations: gpuToleration,
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+						{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}}}},
+					}},
+				}},
+			},
+			want: true,
+		},
+		{
+			name:     "node of a disabled profile",
+			override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Tolerations: gpuToleration},
+			profiles: []datadoghqv2alpha1.AgentProfile{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}, Override: &datadoghqv2alpha1.DatadogAgentComponentOverride{Disabled: apiutils.NewBoolPointer(true)}},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			if tt.override != nil {
+				dda.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.NodeAgentComponentName: tt.override,
+				}
+			}
+			dda.Spec.Profiles = tt.profiles
+			assert.Equal(t, tt.want, runsNodeAgent(dda, node))
+		})
+	}
+}
+
+func TestReconciler_detectV2Conflicts(t *testing.T) {
+	s := testutils.TestScheme(true)
+	now := time.Now()
+
+	older := v2alpha1test.NewDatadogAgent("other", "datadog", nil)
+	older.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.CreationTimestamp = metav1.NewTime(now)
+	// Only runs on the nodes of the highmem pool
+	isolated := v2alpha1test.NewDatadogAgent("isolated", "datadog", nil)
+	isolated.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+		datadoghqv2alpha1.NodeAgentComponentName: {NodeSelector: map[string]string{"pool": "highmem"}},
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "datadog-agent",
+			Labels: map[string]string{kubernetes.AppKubernetesPartOfLabelKey: object.NewPartOfLabelValue(older).String()},
+		},
+	}
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			older, dda, isolated, clusterRole,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "default"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "default"}}},
+		).Build(),
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		options:  ReconcilerOptions{V2Enabled: true},
+	}
+
+	depsStore := dependencies.NewStore(dda, &dependencies.StoreOptions{Logger: logr.Discard(), Scheme: s})
+	require.NoError(t, depsStore.AddOrUpdate(kubernetes.ClusterRolesKind, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "datadog-agent"}}))
+
+	conflicts, err := r.detectV2Conflicts(context.TODO(), dda, depsStore)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, types.NamespacedName{Namespace: "other", Name: "datadog"}, conflicts[0].other)
+	assert.True(t, conflicts[0].otherIsOlder)
+	assert.Equal(t, 2, conflicts[0].nodes)
+	assert.Equal(t, "DatadogAgent other/datadog: node Agent on 2 node(s), ClusterRole datadog-agent", conflicts[0].String())
+
+	// The conflict is reported in the status, and the node Agent is deployed unless the conflicts are prevented
+	newStatus := &datadoghqv2alpha1.DatadogAgentStatus{}
+	assert.False(t, r.updateV2ConflictStatus(dda, newStatus, conflicts))
+	require.Len(t, newStatus.Conditions, 1)
+	assert.Equal(t, datadoghqv2alpha1.ConflictConditionType, newStatus.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, newStatus.Conditions[0].Status)
+	assert.Len(t, r.recorder.(*record.FakeRecorder).Events, 1)
+
+	// The event is only recorded when the conflicts change
+	r.options.AgentConflictPreventionEnabled = true
+	assert.True(t, r.updateV2ConflictStatus(dda, newStatus, conflicts))
+	assert.Contains(t, newStatus.Conditions[0].Message, "the node Agent is not deployed")
+	assert.False(t, r.updateV2ConflictStatus(dda, newStatus, nil))
+	assert.Equal(t, metav1.ConditionFalse, newStatus.Conditions[0].Status)
+	assert.Len(t, r.recorder.(*record.FakeRecorder).Events, 2)
+}
@@ -0,0 +1,244 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+const (
+	// referencedConfigsAnnotationKey is the pod template annotation containing the checksums of the Secrets and ConfigMaps
+	// referenced by the pods, so that the pods are rolled out when their content changes.
+	referencedConfigsAnnotationKey = "checksum/referenced-configs"
+	// ReferencedConfigsIndexField is the field index of the workloads on the Secrets and ConfigMaps referenced by their pods,
+	// see ReferencedConfigsIndexer and ReferencedConfigIndexValue.
+	ReferencedConfigsIndexField = "spec.template.referencedConfigs"
+)
+
+// ReferencedConfigsIndexer indexes a Deployment, DaemonSet or ExtendedDaemonSet on the Secrets and ConfigMaps referenced by its pods.
+func ReferencedConfigsIndexer(workload client.Object) []string {
+	template := podTemplate(workload)
+	if template == nil {
+		return nil
+	}
+	refs := referencedConfigs(&template.Spec)
+	values := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		values = append(values, ref.String())
+	}
+	return values
+}
+
+// ReferencedConfigIndexValue returns the value of ReferencedConfigsIndexField matching the workloads referencing a Secret or ConfigMap.
+func ReferencedConfigIndexValue(kind kubernetes.ObjectKind, name string) string {
+	return configReference{kind: kind, name: name}.String()
+}
+
+// setV2ReferencedConfigsChecksum sets the checksums of the Secrets and ConfigMaps referenced by the pod template
+// of a workload, and records an event naming the ones that changed since the current workload was deployed.
+// It must be called before the spec hash of the workload is computed.
+func (r *Reconciler) setV2ReferencedConfigsChecksum(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, workload client.Object) error {
+	if err := setReferencedConfigsChecksum(context.TODO(), r.client, workload); err != nil {
+		return err
+	}
+
+	current, ok := workload.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	if err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(workload), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	currentChecksums := parseReferencedConfigsChecksums(podTemplate(current).Annotations[referencedConfigsAnnotationKey])
+	var changed []string
+	for ref, checksum := range parseReferencedConfigsChecksums(podTemplate(workload).Annotations[referencedConfigsAnnotationKey]) {
+		if currentChecksum, found := currentChecksums[ref]; found && currentChecksum != checksum {
+			changed = append(changed, ref)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+	logger.Info("Referenced configuration changed, rolling out the pods", "workload", workload.GetName(), "changed", changed)
+	r.recordReferencedConfigsChanged(dda, workloadKind(workload), workload.GetNamespace(), workload.GetName(), changed)
+	return nil
+}
+
+// setReferencedConfigsChecksum sets the referenced configs annotation on the pod template of a workload.
+// The Secrets and ConfigMaps managed by the dependencies store are ignored: their content only depends
+// on the DatadogAgent, which is already part of the spec hash. The missing ones are ignored as well.
+func setReferencedConfigsChecksum(ctx context.Context, k8sClient client.Reader, workload client.Object) error {
+	template := podTemplate(workload)
+	if template == nil {
+		return fmt.Errorf("unsupported workload type %T", workload)
+	}
+
+	var checksums []string
+	for _, ref := range referencedConfigs(&template.Spec) {
+		var obj client.Object
+		switch ref.kind {
+		case kubernetes.SecretsKind:
+			obj = &corev1.Secret{}
+		case kubernetes.ConfigMapKind:
+			obj = &corev1.ConfigMap{}
+		}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: workload.GetNamespace(), Name: ref.name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if dependencies.IsManagedByStore(obj) {
+			continue
+		}
+
+		var checksum string
+		var err error
+		switch config := obj.(type) {
+		case *corev1.Secret:
+			checksum, err = comparison.GenerateMD5ForSpec(config.Data)
+		case *corev1.ConfigMap:
+			checksum, err = comparison.GenerateMD5ForSpec([]interface{}{config.Data, config.BinaryData})
+		}
+		if err != nil {
+			return err
+		}
+		checksums = append(checksums, fmt.Sprintf("%s=%s", ref, checksum))
+	}
+
+	if len(checksums) == 0 {
+		delete(template.Annotations, referencedConfigsAnnotationKey)
+		return nil
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[referencedConfigsAnnotationKey] = strings.Join(checksums, ",")
+	return nil
+}
+
+func workloadKind(workload client.Object) string {
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		return deploymentKind
+	case *appsv1.DaemonSet:
+		return daemonSetKind
+	case *edsv1alpha1.ExtendedDaemonSet:
+		return extendedDaemonSetKind
+	}
+	return fmt.Sprintf("%T", workload)
+}
+
+type configReference struct {
+	kind kubernetes.ObjectKind
+	name string
+}
+
+func (ref configReference) String() string {
+	if ref.kind == kubernetes.SecretsKind {
+		return "Secret/" + ref.name
+	}
+	return "ConfigMap/" + ref.name
+}
+
+// referencedConfigs returns the Secrets and ConfigMaps referenced by the volumes and the environment
+// of the containers of a pod, sorted by kind and name.
+func referencedConfigs(podSpec *corev1.PodSpec) []configReference {
+	refs := map[configReference]struct{}{}
+	addSecret := func(name string) {
+		if name != "" {
+			refs[configReference{kind: kubernetes.SecretsKind, name: name}] = struct{}{}
+		}
+	}
+	addConfigMap := func(name string) {
+		if name != "" {
+			refs[configReference{kind: kubernetes.ConfigMapKind, name: name}] = struct{}{}
+		}
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			addSecret(volume.Secret.SecretName)
+		}
+		if volume.ConfigMap != nil {
+			addConfigMap(volume.ConfigMap.Name)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					addSecret(source.Secret.Name)
+				}
+				if source.ConfigMap != nil {
+					addConfigMap(source.ConfigMap.Name)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				addSecret(env.ValueFrom.SecretKeyRef.Name)
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				addConfigMap(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				addSecret(envFrom.SecretRef.Name)
+			}
+			if envFrom.ConfigMapRef != nil {
+				addConfigMap(envFrom.ConfigMapRef.Name)
+			}
+		}
+	}
+
+	sorted := make([]configReference, 0, len(refs))
+	for ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
+
+// parseReferencedConfigsChecksums returns the checksum of each Secret and ConfigMap of a referenced configs annotation.
+func parseReferencedConfigsChecksums(annotation string) map[string]string {
+	checksums := map[string]string{}
+	for _, entry := range strings.Split(annotation, ",") {
+		if ref, checksum, found := strings.Cut(entry, "="); found {
+			checksums[ref] = checksum
+		}
+	}
+	return checksums
+}
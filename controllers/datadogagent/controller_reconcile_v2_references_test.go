@@ -0,0 +1,163 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func Test_referencedConfigs(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "confd", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "custom-confd"}}}},
+			{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "certs"}}},
+			{Name: "projected", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-yaml"}}},
+			}}}},
+		},
+		InitContainers: []corev1.Container{
+			{Name: "init", EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "extra-env"}}}}},
+		},
+		Containers: []corev1.Container{
+			{
+				Name: "agent",
+				Env: []corev1.EnvVar{
+					{Name: "DD_API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-secret"}, Key: "api-key"}}},
+					{Name: "DD_APP_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-secret"}, Key: "app-key"}}},
+					{Name: "DD_SITE", Value: "datadoghq.eu"},
+				},
+			},
+		},
+	}
+
+	var got []string
+	for _, ref := range referencedConfigs(podSpec) {
+		got = append(got, ref.String())
+	}
+	assert.Equal(t, []string{"ConfigMap/custom-confd", "ConfigMap/datadog-yaml", "Secret/certs", "Secret/datadog-secret", "Secret/extra-env"}, got)
+}
+
+func TestReconciler_setV2ReferencedConfigsChecksum(t *testing.T) {
+	s := testutils.TestScheme(true)
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+
+	newDaemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "confd", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "custom-confd"}}}},
+					// Created by the dependencies store, ignored
+					{Name: "token", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "foo-token"}}},
+					// Missing, ignored
+					{Name: "missing", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "missing"}}},
+				},
+				Containers: []corev1.Container{{
+					Name: "agent",
+					Env: []corev1.EnvVar{
+						{Name: "DD_API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-secret"}, Key: "api-key"}}},
+					},
+				}},
+			}}},
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "datadog-secret"},
+		Data:       map[string][]byte{"api-key": []byte("key-1")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "custom-confd"},
+		Data:       map[string]string{"http_check.yaml": "instances: []"},
+	}
+	storeSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-token", Labels: map[string]string{"operator.datadoghq.com/managed-by-store": "true"}},
+		Data:       map[string][]byte{"token": []byte("token")},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		client:   fake.NewClientBuilder().WithScheme(s).WithObjects(dda, secret, configMap, storeSecret).Build(),
+		scheme:   s,
+		recorder: recorder,
+	}
+
+	// The workload doesn't exist yet: no event
+	daemonset := newDaemonSet()
+	require.NoError(t, r.setV2ReferencedConfigsChecksum(logr.Discard(), dda, daemonset))
+	checksums := parseReferencedConfigsChecksums(daemonset.Spec.Template.Annotations[referencedConfigsAnnotationKey])
+	assert.Len(t, checksums, 2)
+	assert.Contains(t, checksums, "ConfigMap/custom-confd")
+	assert.Contains(t, checksums, "Secret/datadog-secret")
+	require.NoError(t, r.client.Create(context.TODO(), daemonset))
+	assert.Len(t, recorder.Events, 0)
+
+	// Nothing changed: same annotation, no event
+	daemonset = newDaemonSet()
+	require.NoError(t, r.setV2ReferencedConfigsChecksum(logr.Discard(), dda, daemonset))
+	assert.Equal(t, checksums, parseReferencedConfigsChecksums(daemonset.Spec.Template.Annotations[referencedConfigsAnnotationKey]))
+	assert.Len(t, recorder.Events, 0)
+
+	// The API key is rotated: the checksum changes and an event is recorded
+	secret.Data["api-key"] = []byte("key-2")
+	require.NoError(t, r.client.Update(context.TODO(), secret))
+	storeSecret.Data["token"] = []byte("new-token")
+	require.NoError(t, r.client.Update(context.TODO(), storeSecret))
+	daemonset = newDaemonSet()
+	require.NoError(t, r.setV2ReferencedConfigsChecksum(logr.Discard(), dda, daemonset))
+	newChecksums := parseReferencedConfigsChecksums(daemonset.Spec.Template.Annotations[referencedConfigsAnnotationKey])
+	assert.NotEqual(t, checksums["Secret/datadog-secret"], newChecksums["Secret/datadog-secret"])
+	assert.Equal(t, checksums["ConfigMap/custom-confd"], newChecksums["ConfigMap/custom-confd"])
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal ReferencedConfigsChanged Rolling out DaemonSet bar/foo-agent, referenced configuration changed: Secret/datadog-secret", <-recorder.Events)
+}
+
+func Test_setReferencedConfigsChecksum_noReference(t *testing.T) {
+	daemonset := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"}}
+	daemonset.Spec.Template.Annotations = map[string]string{referencedConfigsAnnotationKey: "Secret/removed=1234"}
+
+	var k8sClient client.Reader = fake.NewClientBuilder().WithScheme(testutils.TestScheme(true)).Build()
+	require.NoError(t, setReferencedConfigsChecksum(context.TODO(), k8sClient, daemonset))
+	assert.NotContains(t, daemonset.Spec.Template.Annotations, referencedConfigsAnnotationKey)
+}
+
+func Test_ReferencedConfigsIndexer(t *testing.T) {
+	daemonset := &appsv1.DaemonSet{}
+	daemonset.Spec.Template.Spec = corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "confd", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "custom-confd"}}}},
+		},
+		Containers: []corev1.Container{
+			{
+				Name: "agent",
+				Env: []corev1.EnvVar{
+					{Name: "DD_API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-secret"}, Key: "api-key"}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		ReferencedConfigIndexValue(kubernetes.ConfigMapKind, "custom-confd"),
+		ReferencedConfigIndexValue(kubernetes.SecretsKind, "datadog-secret"),
+	}, ReferencedConfigsIndexer(daemonset))
+	assert.Empty(t, ReferencedConfigsIndexer(&corev1.Secret{}))
+}
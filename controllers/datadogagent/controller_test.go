@@ -3090,6 +3090,9 @@ func (dummyManager) ProcessError(datadog.MonitoredObject, error) {
 func (dummyManager) ProcessEvent(datadog.MonitoredObject, datadog.Event) {
 }
 
+func (dummyManager) ProcessDriftCorrection(datadog.MonitoredObject, datadog.DriftCorrection) {
+}
+
 func (dummyManager) MetricsForwarderStatusForObj(obj datadog.MonitoredObject) *datadog.ConditionCommon {
 	return nil
 }
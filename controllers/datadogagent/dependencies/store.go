@@ -8,11 +8,13 @@ package dependencies
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/component"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/equality"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 	"github.com/go-logr/logr"
@@ -32,8 +34,64 @@ import (
 const (
 	// operatorStoreLabelKey used to identified which resource is managed by the store.
 	operatorStoreLabelKey = "operator.datadoghq.com/managed-by-store"
+	// operatorStoreHashAnnotationKey used to store the hash of the resource applied by the store.
+	// It allows to differentiate a change of the resource done outside of the operator (drift)
+	// from a change of the resource done by the operator.
+	operatorStoreHashAnnotationKey = "operator.datadoghq.com/managed-by-store-hash"
 )
 
+// IsManagedByStore returns true if the object was created by a Store.
+func IsManagedByStore(obj metav1.Object) bool {
+	_, found := obj.GetLabels()[operatorStoreLabelKey]
+	return found
+}
+
+// Drift describes a resource managed by the Store that was modified outside of the operator,
+// and reverted by the Store.
+type Drift struct {
+	Kind      kubernetes.ObjectKind
+	Namespace string
+	Name      string
+	// Field is the path of the first field that drifted, for instance "spec.ports" or "rules".
+	Field string
+}
+
+// DriftHandler is called each time a drift is corrected by the Store.
+type DriftHandler func(drift Drift)
+
+// ChangeType is the type of a change that Apply or Cleanup would do in the api-server.
+type ChangeType string
+
+const (
+	// CreateChange is used when the object of the Store doesn't exist in the api-server.
+	CreateChange ChangeType = "create"
+	// UpdateChange is used when the object in the api-server differs from the object of the Store.
+	UpdateChange ChangeType = "update"
+	// DeleteChange is used when the object in the api-server is not in the Store anymore.
+	DeleteChange ChangeType = "delete"
+)
+
+// Change describes a change that Apply or Cleanup would do in the api-server.
+type Change struct {
+	Type ChangeType
+	Kind kubernetes.ObjectKind
+	// Desired is the object of the Store. It is nil for a DeleteChange.
+	Desired client.Object
+	// Current is the object in the api-server. It is nil for a CreateChange,
+	// and only contains the object metadata for a DeleteChange.
+	Current client.Object
+	// Field is the path of the first field that differs for an UpdateChange, for instance "spec.ports" or "rules".
+	Field string
+}
+
+// Object returns the desired object, or the current object for a DeleteChange.
+func (c *Change) Object() client.Object {
+	if c.Desired != nil {
+		return c.Desired
+	}
+	return c.Current
+}
+
 // StoreClient dependencies store client interface
 type StoreClient interface {
 	AddOrUpdate(kind kubernetes.ObjectKind, obj client.Object) error
@@ -58,6 +116,11 @@ func NewStore(owner metav1.Object, options *StoreOptions) *Store {
 		store.platformInfo = options.PlatformInfo
 		store.logger = options.Logger
 		store.scheme = options.Scheme
+		store.driftHandler = options.DriftHandler
+		store.serverSideApply = options.ServerSideApplyEnabled
+		store.updateFieldManager = options.UpdateFieldManager
+		store.keepAnnotationsFilter = options.KeepAnnotationsFilter
+		store.keepLabelsFilter = options.KeepLabelsFilter
 	}
 
 	return store
@@ -69,13 +132,19 @@ type Store struct {
 	deps  map[kubernetes.ObjectKind]map[string]client.Object
 	mutex sync.RWMutex
 
-	supportCilium bool
-	versionInfo   *version.Info
-	platformInfo  kubernetes.PlatformInfo
+	supportCilium      bool
+	serverSideApply    bool
+	updateFieldManager string
+	versionInfo        *version.Info
+	platformInfo       kubernetes.PlatformInfo
 
-	scheme *runtime.Scheme
-	logger logr.Logger
-	owner  metav1.Object
+	keepAnnotationsFilter string
+	keepLabelsFilter      string
+
+	scheme       *runtime.Scheme
+	logger       logr.Logger
+	owner        metav1.Object
+	driftHandler DriftHandler
 }
 
 // StoreOptions use to provide to NewStore() function some Store creation options.
@@ -86,6 +155,17 @@ type StoreOptions struct {
 
 	Scheme *runtime.Scheme
 	Logger logr.Logger
+	// DriftHandler is optional, it is called when a resource modified outside of the operator is reverted by Apply.
+	DriftHandler DriftHandler
+	// ServerSideApplyEnabled is used to create and update the resources with server-side apply in Apply.
+	ServerSideApplyEnabled bool
+	// UpdateFieldManager is the field manager of the regular updates done by the operator, whose fields
+	// are transferred to the server-side apply field manager, see kubernetes.UpdateFieldManager.
+	UpdateFieldManager string
+	// KeepAnnotationsFilter and KeepLabelsFilter are glob filters of the annotations and labels, not managed
+	// by the operator, that Apply keeps when it updates a resource.
+	KeepAnnotationsFilter string
+	KeepLabelsFilter      string
 }
 
 // AddOrUpdate used to add or update an object in the Store
@@ -180,6 +260,34 @@ func (ds *Store) GetOrCreate(kind kubernetes.ObjectKind, namespace, name string)
 	return obj, found
 }
 
+// GetAll returns every object previously added in the Store.
+// Objects are sorted by kind, then by `namespace/name` identifier, to always return them in the same order.
+func (ds *Store) GetAll() []client.Object {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	kinds := make([]kubernetes.ObjectKind, 0, len(ds.deps))
+	for kind := range ds.deps {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	var objs []client.Object
+	for _, kind := range kinds {
+		ids := make([]string, 0, len(ds.deps[kind]))
+		for id := range ds.deps[kind] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			objs = append(objs, ds.deps[kind][id])
+		}
+	}
+	return objs
+}
+
 // Delete deletes an item from the store by kind, namespace and name.
 func (ds *Store) Delete(kind kubernetes.ObjectKind, namespace string, name string) bool {
 	ds.mutex.RLock()
@@ -204,11 +312,19 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 	var errs []error
 	var objsToCreate []client.Object
 	var objsToUpdate []client.Object
+	currentObjs := map[client.Object]client.Object{}
+	drifts := map[client.Object]Drift{}
 	for kind := range ds.deps {
 		for objID, objStore := range ds.deps[kind] {
+			hash, err := setStoreHashAnnotation(objStore)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
 			objNSName := buildObjectKey(objID)
 			objAPIServer := kubernetes.ObjectFromKind(kind, ds.platformInfo)
-			err := k8sClient.Get(ctx, objNSName, objAPIServer)
+			err = k8sClient.Get(ctx, objNSName, objAPIServer)
 			if err != nil && apierrors.IsNotFound(err) {
 				ds.logger.V(2).Info("dependencies.store Add object to create", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
 				objsToCreate = append(objsToCreate, objStore)
@@ -218,20 +334,17 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 				continue
 			}
 
-			// ServicesKind is a special case; the cluster IPs are immutable and resource version must be set.
-			if kind == kubernetes.ServicesKind {
-				objStore.(*v1.Service).Spec.ClusterIP = objAPIServer.(*v1.Service).Spec.ClusterIP
-				objStore.(*v1.Service).Spec.ClusterIPs = objAPIServer.(*v1.Service).Spec.ClusterIPs
-				objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
-			}
-			// The APIServiceKind resource version must be set.
-			if kind == kubernetes.APIServiceKind {
-				objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
-			}
+			ds.setAPIServerManagedFields(kind, objStore, objAPIServer)
 
-			if !equality.IsEqualObject(kind, objStore, objAPIServer) {
-				ds.logger.V(2).Info("dependencies.store Add object to update", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
+			if field := equality.DriftedField(kind, objStore, objAPIServer); field != "" {
+				ds.logger.V(2).Info("dependencies.store Add object to update", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind, "field", field)
 				objsToUpdate = append(objsToUpdate, objStore)
+				currentObjs[objStore] = objAPIServer
+				// The object applied previously is the same as the current one: the difference
+				// comes from a change done outside of the operator.
+				if objAPIServer.GetAnnotations()[operatorStoreHashAnnotationKey] == hash {
+					drifts[objStore] = Drift{Kind: kind, Namespace: objStore.GetNamespace(), Name: objStore.GetName(), Field: field}
+				}
 				continue
 			}
 		}
@@ -239,7 +352,7 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 
 	ds.logger.V(2).Info("dependencies.store objsToCreate", "nb", len(objsToCreate))
 	for _, obj := range objsToCreate {
-		if err := k8sClient.Create(ctx, obj); err != nil {
+		if err := ds.create(ctx, k8sClient, obj); err != nil {
 			ds.logger.Error(err, "dependencies.store Create", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
 			errs = append(errs, err)
 		}
@@ -247,20 +360,123 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 
 	ds.logger.V(2).Info("dependencies.store objsToUpdate", "nb", len(objsToUpdate))
 	for _, obj := range objsToUpdate {
-		if err := k8sClient.Update(ctx, obj); err != nil {
+		if err := ds.update(ctx, k8sClient, obj, currentObjs[obj]); err != nil {
 			ds.logger.Error(err, "dependencies.store Update", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
 			errs = append(errs, err)
+			continue
+		}
+		if drift, found := drifts[obj]; found {
+			ds.logger.Info("dependencies.store Drift corrected", "obj.namespace", drift.Namespace, "obj.name", drift.Name, "obj.kind", drift.Kind, "field", drift.Field)
+			if ds.driftHandler != nil {
+				ds.driftHandler(drift)
+			}
 		}
 	}
 	return errs
 }
 
+// Plan returns the changes that Apply and Cleanup would do in the api-server, without doing them.
+// The changes are sorted by kind, namespace and name. The objects in the Store are not modified.
+func (ds *Store) Plan(ctx context.Context, k8sClient client.Client) ([]Change, []error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	var errs []error
+	var changes []Change
+	for kind := range ds.deps {
+		for objID, objStore := range ds.deps[kind] {
+			desired := objStore.DeepCopyObject().(client.Object)
+			objAPIServer := kubernetes.ObjectFromKind(kind, ds.platformInfo)
+			err := k8sClient.Get(ctx, buildObjectKey(objID), objAPIServer)
+			if err != nil && apierrors.IsNotFound(err) {
+				changes = append(changes, Change{Type: CreateChange, Kind: kind, Desired: desired})
+				continue
+			} else if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			ds.setAPIServerManagedFields(kind, desired, objAPIServer)
+			if field := equality.DriftedField(kind, desired, objAPIServer); field != "" {
+				changes = append(changes, Change{Type: UpdateChange, Kind: kind, Desired: desired, Current: objAPIServer, Field: field})
+			}
+		}
+	}
+
+	deletions, cleanupErrs := ds.cleanupChanges(ctx, k8sClient)
+	changes = append(changes, deletions...)
+	errs = append(errs, cleanupErrs...)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		objI, objJ := changes[i].Object(), changes[j].Object()
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		if objI.GetNamespace() != objJ.GetNamespace() {
+			return objI.GetNamespace() < objJ.GetNamespace()
+		}
+		return objI.GetName() < objJ.GetName()
+	})
+
+	return changes, errs
+}
+
+// setAPIServerManagedFields copies to the desired object the fields that must be kept from the api-server object.
+func (ds *Store) setAPIServerManagedFields(kind kubernetes.ObjectKind, objStore, objAPIServer client.Object) {
+	// The annotations and labels set outside of the operator are kept if a keep filter is set, like on the workloads.
+	// With server-side apply, the fields that are not applied by the operator are kept anyway.
+	if !ds.serverSideApply {
+		if ds.keepAnnotationsFilter != "" {
+			objStore.SetAnnotations(kubernetes.MergeAnnotationsLabels(ds.logger, objAPIServer.GetAnnotations(), objStore.GetAnnotations(), ds.keepAnnotationsFilter))
+		}
+		if ds.keepLabelsFilter != "" {
+			objStore.SetLabels(kubernetes.MergeAnnotationsLabels(ds.logger, objAPIServer.GetLabels(), objStore.GetLabels(), ds.keepLabelsFilter))
+		}
+	}
+	// ServicesKind is a special case; the cluster IPs are immutable and resource version must be set.
+	if kind == kubernetes.ServicesKind {
+		objStore.(*v1.Service).Spec.ClusterIP = objAPIServer.(*v1.Service).Spec.ClusterIP
+		objStore.(*v1.Service).Spec.ClusterIPs = objAPIServer.(*v1.Service).Spec.ClusterIPs
+		objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
+	}
+	// The APIServiceKind resource version must be set.
+	if kind == kubernetes.APIServiceKind {
+		objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
+	}
+}
+
+func (ds *Store) create(ctx context.Context, k8sClient client.Client, obj client.Object) error {
+	if ds.serverSideApply {
+		return kubernetes.ApplyObject(ctx, k8sClient, obj, nil, ds.updateFieldManager)
+	}
+	return k8sClient.Create(ctx, obj)
+}
+
+func (ds *Store) update(ctx context.Context, k8sClient client.Client, obj, current client.Object) error {
+	if ds.serverSideApply {
+		return kubernetes.ApplyObject(ctx, k8sClient, obj, current, ds.updateFieldManager)
+	}
+	return k8sClient.Update(ctx, obj)
+}
+
 // Cleanup use to cleanup resources that are not needed anymore
 func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client) []error {
 	ds.mutex.RLock()
 	defer ds.mutex.RUnlock()
 
+	deletions, errs := ds.cleanupChanges(ctx, k8sClient)
+	objsToDelete := make([]client.Object, 0, len(deletions))
+	for _, deletion := range deletions {
+		objsToDelete = append(objsToDelete, deletion.Current)
+	}
+	return append(errs, deleteObjects(ctx, k8sClient, objsToDelete)...)
+}
+
+// cleanupChanges returns the objects of the api-server managed by the Store for the owner
+// that are not in the Store anymore, as DeleteChanges.
+func (ds *Store) cleanupChanges(ctx context.Context, k8sClient client.Client) ([]Change, []error) {
 	var errs []error
+	var changes []Change
 
 	requirementLabel, _ := labels.NewRequirement(operatorStoreLabelKey, selection.Exists, nil)
 	listOptions := &client.ListOptions{
@@ -278,10 +494,12 @@ func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client) []error {
 			errs = append(errs, err)
 			continue
 		}
-		errs = append(errs, deleteObjects(ctx, k8sClient, objsToDelete)...)
+		for _, obj := range objsToDelete {
+			changes = append(changes, Change{Type: DeleteChange, Kind: kind, Current: obj})
+		}
 	}
 
-	return errs
+	return changes, errs
 }
 
 // GetVersionInfo returns the Kubernetes version
@@ -392,6 +610,27 @@ func deleteObjects(ctx context.Context, k8sClient client.Client, objsToDelete []
 	return errs
 }
 
+// setStoreHashAnnotation sets the hash of the desired object in its annotations, and returns it.
+// The resource version, set from the api-server object for some kinds, is not part of the hash.
+func setStoreHashAnnotation(obj client.Object) (string, error) {
+	objCopy := obj.DeepCopyObject().(client.Object)
+	objCopy.SetResourceVersion("")
+	annotations := objCopy.GetAnnotations()
+	delete(annotations, operatorStoreHashAnnotationKey)
+	objCopy.SetAnnotations(annotations)
+
+	hash, err := comparison.GenerateMD5ForSpec(objCopy)
+	if err != nil {
+		return "", fmt.Errorf("store.Apply, unable to generate the object hash, %w", err)
+	}
+
+	if obj.GetAnnotations() == nil {
+		obj.SetAnnotations(map[string]string{})
+	}
+	obj.GetAnnotations()[operatorStoreHashAnnotationKey] = hash
+	return hash, nil
+}
+
 func buildID(ns, name string) string {
 	if ns == "" {
 		return name
@@ -307,6 +307,61 @@ func TestStore_Get(t *testing.T) {
 	}
 }
 
+func TestStore_GetAll(t *testing.T) {
+	dummyConfigMap1 := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+	}
+	dummyConfigMap2 := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "abc",
+		},
+	}
+	dummySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+	}
+
+	tests := []struct {
+		name string
+		deps map[kubernetes.ObjectKind]map[string]client.Object
+		want []client.Object
+	}{
+		{
+			name: "empty store",
+			deps: map[kubernetes.ObjectKind]map[string]client.Object{},
+			want: nil,
+		},
+		{
+			name: "sorted by kind then by id",
+			deps: map[kubernetes.ObjectKind]map[string]client.Object{
+				kubernetes.SecretsKind: {
+					"bar/foo": dummySecret,
+				},
+				kubernetes.ConfigMapKind: {
+					"bar/foo": dummyConfigMap1,
+					"bar/abc": dummyConfigMap2,
+				},
+			},
+			want: []client.Object{dummyConfigMap2, dummyConfigMap1, dummySecret},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &Store{
+				deps:   tt.deps,
+				logger: logf.Log.WithName(t.Name()),
+			}
+			assert.Equal(t, tt.want, ds.GetAll())
+		})
+	}
+}
+
 func TestStore_Apply(t *testing.T) {
 	dummyConfigMap1 := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -391,6 +446,154 @@ func TestStore_Apply(t *testing.T) {
 	}
 }
 
+func TestStore_Apply_drift(t *testing.T) {
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "bar",
+				Name:      "foo",
+			},
+			Data: map[string]string{"key": value},
+		}
+	}
+	newStore := func(cm *corev1.ConfigMap, drifts *[]Drift) *Store {
+		ds := NewStore(nil, &StoreOptions{
+			Logger: logf.Log.WithName(t.Name()),
+			DriftHandler: func(drift Drift) {
+				*drifts = append(*drifts, drift)
+			},
+		})
+		assert.NoError(t, ds.AddOrUpdate(kubernetes.ConfigMapKind, cm))
+		return ds
+	}
+	k8sClient := fake.NewClientBuilder().Build()
+	nsName := types.NamespacedName{Namespace: "bar", Name: "foo"}
+	var drifts []Drift
+
+	// initial creation
+	assert.Empty(t, newStore(newConfigMap("v1"), &drifts).Apply(context.TODO(), k8sClient))
+	assert.Empty(t, drifts)
+
+	// the desired state changes: this is not a drift
+	assert.Empty(t, newStore(newConfigMap("v2"), &drifts).Apply(context.TODO(), k8sClient))
+	assert.Empty(t, drifts)
+
+	// the ConfigMap is modified outside of the operator
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), nsName, cm))
+	cm.Data["key"] = "manual"
+	assert.NoError(t, k8sClient.Update(context.TODO(), cm))
+
+	assert.Empty(t, newStore(newConfigMap("v2"), &drifts).Apply(context.TODO(), k8sClient))
+	assert.Equal(t, []Drift{{Kind: kubernetes.ConfigMapKind, Namespace: "bar", Name: "foo", Field: "data"}}, drifts)
+
+	assert.NoError(t, k8sClient.Get(context.TODO(), nsName, cm))
+	assert.Equal(t, "v2", cm.Data["key"], "the drift should be reverted")
+
+	// nothing changed since the drift was reverted
+	assert.Empty(t, newStore(newConfigMap("v2"), &drifts).Apply(context.TODO(), k8sClient))
+	assert.Len(t, drifts, 1)
+}
+
+func TestStore_Apply_keepAnnotationsLabels(t *testing.T) {
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "bar",
+				Name:      "foo",
+				Labels:    map[string]string{"app": "agent"},
+			},
+			Data: map[string]string{"key": value},
+		}
+	}
+	newStore := func(cm *corev1.ConfigMap) *Store {
+		ds := NewStore(nil, &StoreOptions{
+			Logger:                logf.Log.WithName(t.Name()),
+			KeepAnnotationsFilter: "argocd.argoproj.io/*",
+			KeepLabelsFilter:      "{team,env}",
+		})
+		assert.NoError(t, ds.AddOrUpdate(kubernetes.ConfigMapKind, cm))
+		return ds
+	}
+	k8sClient := fake.NewClientBuilder().Build()
+	nsName := types.NamespacedName{Namespace: "bar", Name: "foo"}
+	assert.Empty(t, newStore(newConfigMap("v1")).Apply(context.TODO(), k8sClient))
+
+	// annotations and labels are added by other tools
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), nsName, cm))
+	cm.Annotations = map[string]string{"argocd.argoproj.io/sync-wave": "1", "other": "value"}
+	cm.Labels["team"] = "containers"
+	cm.Labels["other"] = "value"
+	assert.NoError(t, k8sClient.Update(context.TODO(), cm))
+
+	assert.Empty(t, newStore(newConfigMap("v2")).Apply(context.TODO(), k8sClient))
+	assert.NoError(t, k8sClient.Get(context.TODO(), nsName, cm))
+	assert.Equal(t, "v2", cm.Data["key"])
+	assert.Equal(t, "1", cm.Annotations["argocd.argoproj.io/sync-wave"])
+	assert.NotContains(t, cm.Annotations, "other")
+	assert.Equal(t, "containers", cm.Labels["team"])
+	assert.NotContains(t, cm.Labels, "other")
+}
+
+func TestStore_Plan(t *testing.T) {
+	newConfigMap := func(name, value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "bar",
+				Name:      name,
+				Labels: map[string]string{
+					operatorStoreLabelKey:                  "true",
+					kubernetes.AppKubernetesPartOfLabelKey: "namespace--test-dda--test",
+				},
+			},
+			Data: map[string]string{"key": value},
+		}
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(apiregistrationv1.SchemeGroupVersion, &apiregistrationv1.APIService{})
+	s.AddKnownTypes(apiregistrationv1.SchemeGroupVersion, &apiregistrationv1.APIServiceList{})
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		newConfigMap("unchanged", "v1"),
+		newConfigMap("changed", "v1"),
+		newConfigMap("stale", "v1"),
+	).Build()
+
+	ds := NewStore(&metav1.ObjectMeta{Namespace: "namespace-test", Name: "dda-test"}, &StoreOptions{
+		Logger: logf.Log.WithName(t.Name()),
+	})
+	assert.NoError(t, ds.AddOrUpdate(kubernetes.ConfigMapKind, newConfigMap("unchanged", "v1")))
+	assert.NoError(t, ds.AddOrUpdate(kubernetes.ConfigMapKind, newConfigMap("changed", "v2")))
+	assert.NoError(t, ds.AddOrUpdate(kubernetes.ConfigMapKind, newConfigMap("new", "v1")))
+
+	changes, errs := ds.Plan(context.TODO(), k8sClient)
+	assert.Empty(t, errs)
+	assert.Len(t, changes, 3)
+
+	assert.Equal(t, UpdateChange, changes[0].Type)
+	assert.Equal(t, "changed", changes[0].Object().GetName())
+	assert.Equal(t, "data", changes[0].Field)
+	assert.Equal(t, "v1", changes[0].Current.(*corev1.ConfigMap).Data["key"])
+	assert.Equal(t, "v2", changes[0].Desired.(*corev1.ConfigMap).Data["key"])
+
+	assert.Equal(t, CreateChange, changes[1].Type)
+	assert.Equal(t, "new", changes[1].Object().GetName())
+	assert.Nil(t, changes[1].Current)
+
+	assert.Equal(t, DeleteChange, changes[2].Type)
+	assert.Equal(t, kubernetes.ConfigMapKind, changes[2].Kind)
+	assert.Equal(t, "stale", changes[2].Object().GetName())
+	assert.Nil(t, changes[2].Desired)
+
+	// nothing was applied
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "changed"}, cm))
+	assert.Equal(t, "v1", cm.Data["key"])
+	assert.True(t, errors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "new"}, cm)))
+	storeCM, _ := ds.Get(kubernetes.ConfigMapKind, "bar", "changed")
+	assert.NotContains(t, storeCM.GetAnnotations(), operatorStoreHashAnnotationKey, "the objects of the store should not be modified")
+}
+
 func TestStore_Cleanup(t *testing.T) {
 	dummyConfigMap1 := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/equality"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+// ObjectChange is a change that the v2 reconciler would do in the api-server.
+type ObjectChange struct {
+	dependencies.Change
+	// RollsPods is true when the pod template of a workload changes, which recreates all its pods.
+	RollsPods bool
+}
+
+// DiffV2 returns the changes that the v2 reconciler would do in the api-server to reconcile a DatadogAgent.
+// The workload changes come first, in the RenderV2 order, followed by the dependency changes sorted by kind,
+// namespace and name. The api-server is only read.
+// The generated Cluster Agent token is read from the DatadogAgent status, which should be copied from the api-server.
+func DiffV2(ctx context.Context, k8sClient client.Client, dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions) ([]ObjectChange, error) {
+	workloads, depsStore, err := renderV2(dda, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ObjectChange
+	for _, workload := range workloads {
+		// The checksums of the referenced Secrets and ConfigMaps are part of the spec hash,
+		// like in createOrUpdateDeployment and createOrUpdateDaemonset.
+		if err = setReferencedConfigsChecksum(ctx, k8sClient, workload); err != nil {
+			return nil, err
+		}
+		if err = setMD5WorkloadAnnotation(workload); err != nil {
+			return nil, err
+		}
+		change, workloadErr := diffWorkload(ctx, k8sClient, workload)
+		if workloadErr != nil {
+			return nil, workloadErr
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	depsChanges, errs := depsStore.Plan(ctx, k8sClient)
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+	for _, change := range depsChanges {
+		changes = append(changes, ObjectChange{Change: change})
+	}
+
+	return changes, nil
+}
+
+// diffWorkload compares a workload with the one in the api-server using the spec hash annotation,
+// like createOrUpdateDeployment and createOrUpdateDaemonset do. It returns nil if the workload is up-to-date.
+func diffWorkload(ctx context.Context, k8sClient client.Client, workload client.Object) (*ObjectChange, error) {
+	var kind kubernetes.ObjectKind
+	var current client.Object
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		kind, current = kubernetes.DeploymentsKind, &appsv1.Deployment{}
+	case *appsv1.DaemonSet:
+		kind, current = kubernetes.DaemonSetsKind, &appsv1.DaemonSet{}
+	case *edsv1alpha1.ExtendedDaemonSet:
+		kind, current = kubernetes.ExtendedDaemonSetsKind, &edsv1alpha1.ExtendedDaemonSet{}
+	default:
+		return nil, fmt.Errorf("unsupported workload type %T", workload)
+	}
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(workload), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &ObjectChange{Change: dependencies.Change{Type: dependencies.CreateChange, Kind: kind, Desired: workload}}, nil
+		}
+		return nil, err
+	}
+
+	hash := workload.GetAnnotations()[common.MD5AgentDeploymentAnnotationKey]
+	if comparison.IsSameSpecMD5Hash(hash, current.GetAnnotations()) {
+		return nil, nil
+	}
+
+	change := &ObjectChange{
+		Change: dependencies.Change{Type: dependencies.UpdateChange, Kind: kind, Desired: workload, Current: current, Field: "spec"},
+	}
+	// The fields defaulted by the api-server are ignored, the fields removed or added are compared.
+	if !equality.IsEqualPodTemplate(podTemplate(workload), podTemplate(current)) {
+		change.Field = "spec.template"
+		change.RollsPods = true
+	}
+	return change, nil
+}
+
+// podTemplate returns the pod template of a workload, or nil if the workload type isn't supported.
+func podTemplate(workload client.Object) *corev1.PodTemplateSpec {
+	switch obj := workload.(type) {
+	case *appsv1.Deployment:
+		return &obj.Spec.Template
+	case *appsv1.DaemonSet:
+		return &obj.Spec.Template
+	case *edsv1alpha1.ExtendedDaemonSet:
+		return &obj.Spec.Template
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestDiffV2(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	// The Cluster Agent token is read from the status; otherwise a new one is generated for each rendering.
+	dda.Status.ClusterAgent = &apicommonv1.DeploymentStatus{GeneratedToken: "token"}
+	options := &RenderOptions{
+		VersionInfo:  &version.Info{GitVersion: "v1.25.0"},
+		PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{}, map[string]string{}),
+		Scheme:       testutils.TestScheme(true),
+		Logger:       logf.Log.WithName(t.Name()),
+	}
+	objs, err := RenderV2(dda, options)
+	require.NoError(t, err)
+
+	// Nothing exists yet: every object is created.
+	emptyClient := fake.NewClientBuilder().WithScheme(options.Scheme).Build()
+	changes, err := DiffV2(context.TODO(), emptyClient, dda, options)
+	require.NoError(t, err)
+	require.Len(t, changes, len(objs))
+	for _, change := range changes {
+		assert.Equal(t, dependencies.CreateChange, change.Type, "%s %s", change.Kind, change.Object().GetName())
+	}
+
+	// Everything is up-to-date.
+	k8sClient := fake.NewClientBuilder().WithScheme(options.Scheme).WithObjects(withAPIServerDefaults(objs)...).Build()
+	changes, err = DiffV2(context.TODO(), k8sClient, dda, options)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// A new tag changes the pod template of every workload.
+	dda.Spec.Global.Tags = []string{"team:platform"}
+	changes, err = DiffV2(context.TODO(), k8sClient, dda, options)
+	require.NoError(t, err)
+
+	var workloads []string
+	for _, change := range changes {
+		if change.Kind == kubernetes.DeploymentsKind || change.Kind == kubernetes.DaemonSetsKind {
+			assert.Equal(t, dependencies.UpdateChange, change.Type)
+			assert.True(t, change.RollsPods, "%s %s", change.Kind, change.Object().GetName())
+			workloads = append(workloads, change.Object().GetName())
+		}
+	}
+	assert.Equal(t, []string{"foo-cluster-agent", "foo-agent"}, workloads)
+	assertNoPodRoll(t, changes, func(obj client.Object) bool { return obj.GetName() == "foo-token" })
+}
+
+func Test_diffWorkload(t *testing.T) {
+	newDeployment := func(hash string, env ...corev1.EnvVar) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "bar",
+				Name:        "foo-cluster-agent",
+				Annotations: map[string]string{common.MD5AgentDeploymentAnnotationKey: hash},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "cluster-agent", Image: "gcr.io/datadoghq/cluster-agent:1.23.0", Env: env}},
+					},
+				},
+			},
+		}
+	}
+	// apiServerDeployment returns the Deployment as defaulted by the api-server
+	apiServerDeployment := func(hash string, env ...corev1.EnvVar) *appsv1.Deployment {
+		deployment := newDeployment(hash, env...)
+		spec := &deployment.Spec.Template.Spec
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+		spec.DNSPolicy = corev1.DNSClusterFirst
+		spec.TerminationGracePeriodSeconds = apiutils.NewInt64Pointer(30)
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+		spec.SchedulerName = corev1.DefaultSchedulerName
+		spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+		spec.Containers[0].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+		spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		return deployment
+	}
+	logLevel := corev1.EnvVar{Name: "DD_LOG_LEVEL", Value: "debug"}
+	clusterName := corev1.EnvVar{Name: "DD_CLUSTER_NAME", Value: "test"}
+
+	tests := []struct {
+		name          string
+		desired       *appsv1.Deployment
+		current       *appsv1.Deployment
+		wantChange    bool
+		wantRollsPods bool
+	}{
+		{
+			name:    "same hash",
+			desired: newDeployment("new", logLevel),
+			current: apiServerDeployment("new", logLevel),
+		},
+		{
+			name:       "the pod template is the same once defaulted",
+			desired:    newDeployment("new", logLevel),
+			current:    apiServerDeployment("old", logLevel),
+			wantChange: true,
+		},
+		{
+			name:          "an env var is removed",
+			desired:       newDeployment("new", logLevel),
+			current:       apiServerDeployment("old", logLevel, clusterName),
+			wantChange:    true,
+			wantRollsPods: true,
+		},
+		{
+			name:          "an env var is added",
+			desired:       newDeployment("new", logLevel, clusterName),
+			current:       apiServerDeployment("old", logLevel),
+			wantChange:    true,
+			wantRollsPods: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithObjects(tt.current).Build()
+			change, err := diffWorkload(context.TODO(), k8sClient, tt.desired)
+			require.NoError(t, err)
+			if !tt.wantChange {
+				assert.Nil(t, change)
+				return
+			}
+			require.NotNil(t, change)
+			assert.Equal(t, dependencies.UpdateChange, change.Type)
+			assert.Equal(t, tt.wantRollsPods, change.RollsPods)
+			if tt.wantRollsPods {
+				assert.Equal(t, "spec.template", change.Field)
+			} else {
+				assert.Equal(t, "spec", change.Field)
+			}
+		})
+	}
+}
+
+// withAPIServerDefaults returns copies of the objects with the fields defaulted by the api-server,
+// which the fake client doesn't default.
+func withAPIServerDefaults(objs []client.Object) []client.Object {
+	defaulted := make([]client.Object, 0, len(objs))
+	for _, obj := range objs {
+		obj = obj.DeepCopyObject().(client.Object)
+		if service, ok := obj.(*corev1.Service); ok {
+			if service.Spec.Type == "" {
+				service.Spec.Type = corev1.ServiceTypeClusterIP
+			}
+			if service.Spec.SessionAffinity == "" {
+				service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+			}
+			for i := range service.Spec.Ports {
+				if service.Spec.Ports[i].Protocol == "" {
+					service.Spec.Ports[i].Protocol = corev1.ProtocolTCP
+				}
+			}
+		}
+		defaulted = append(defaulted, obj)
+	}
+	return defaulted
+}
+
+func assertNoPodRoll(t *testing.T, changes []ObjectChange, match func(client.Object) bool) {
+	for _, change := range changes {
+		if match(change.Object()) {
+			assert.False(t, change.RollsPods)
+		}
+	}
+}
@@ -6,13 +6,23 @@
 package datadogagent
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
 	"github.com/DataDog/datadog-operator/controllers/utils"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 )
 
+const (
+	driftCorrectedEventReason = "DriftCorrected"
+	conflictEventReason       = "Conflict"
+
+	referencedConfigsChangedEventReason = "ReferencedConfigsChanged"
+)
+
 // buildEventInfo creates a new EventInfo instance
 func buildEventInfo(name, ns, kind string, eventType datadog.EventType) utils.EventInfo {
 	return utils.BuildEventInfo(name, ns, kind, eventType)
@@ -26,3 +36,27 @@ func (r *Reconciler) recordEvent(dda client.Object, info utils.EventInfo) {
 		r.forwarders.ProcessEvent(dda, info.GetDDEvent())
 	}
 }
+
+// recordDriftCorrected records an event when a dependency modified outside of the operator has been reverted
+// recordDriftCorrected calls the metric forwarders to send the drift corrected metric
+func (r *Reconciler) recordDriftCorrected(dda client.Object, drift dependencies.Drift) {
+	objName := drift.Name
+	if drift.Namespace != "" {
+		objName = drift.Namespace + "/" + drift.Name
+	}
+	r.recorder.Eventf(dda, corev1.EventTypeWarning, driftCorrectedEventReason, "%s %s modified outside of the operator has been reverted, drifted field: %s", drift.Kind, objName, drift.Field)
+	if r.options.OperatorMetricsEnabled {
+		r.forwarders.ProcessDriftCorrection(dda, datadog.DriftCorrection{Kind: string(drift.Kind), Field: drift.Field})
+	}
+}
+
+// recordConflict records an event when the resources shared with other DatadogAgents change
+func (r *Reconciler) recordConflict(dda client.Object, message string) {
+	r.recorder.Event(dda, corev1.EventTypeWarning, conflictEventReason, message)
+}
+
+// recordReferencedConfigsChanged records an event on the DatadogAgent when the pods of a workload
+// are rolled out because Secrets or ConfigMaps that they reference changed.
+func (r *Reconciler) recordReferencedConfigsChanged(dda client.Object, kind, ns, name string, changed []string) {
+	r.recorder.Eventf(dda, corev1.EventTypeNormal, referencedConfigsChangedEventReason, "Rolling out %s %s/%s, referenced configuration changed: %s", kind, ns, name, strings.Join(changed, ", "))
+}
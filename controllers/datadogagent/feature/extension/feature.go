@@ -0,0 +1,320 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package extension
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/merger"
+)
+
+// BuildFeatures returns one Feature per DatadogAgentExtension selecting the DatadogAgent,
+// sorted by increasing order, then by name.
+// Like feature.BuildFeaturesWithRequirements, it also returns the RequiredComponents of each Feature
+// indexed by the Feature ID, and the merged RequiredComponents.
+// The extensions with an invalid DatadogAgent selector are ignored and reported in the returned errors.
+func BuildFeatures(dda *v2alpha1.DatadogAgent, extensions []v1alpha1.DatadogAgentExtension, options *feature.Options) ([]feature.Feature, map[feature.IDType]feature.RequiredComponents, feature.RequiredComponents, []error) {
+	var output []feature.Feature
+	var requiredComponents feature.RequiredComponents
+	featuresRequiredComponents := map[feature.IDType]feature.RequiredComponents{}
+	var errs []error
+
+	sorted := make([]v1alpha1.DatadogAgentExtension, len(extensions))
+	copy(sorted, extensions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Spec.Order != sorted[j].Spec.Order {
+			return sorted[i].Spec.Order < sorted[j].Spec.Order
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for i := range sorted {
+		ext := &sorted[i]
+		if ext.Namespace != dda.Namespace {
+			continue
+		}
+		selected, err := selectsDatadogAgent(ext, dda)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.datadogAgentSelector in DatadogAgentExtension %s/%s: %w", ext.Namespace, ext.Name, err))
+			continue
+		}
+		if !selected {
+			continue
+		}
+
+		feat := newExtensionFeature(ext, options)
+		reqComponents := feat.Configure(dda)
+		output = append(output, feat)
+		featuresRequiredComponents[feat.ID()] = reqComponents
+		requiredComponents.Merge(&reqComponents)
+	}
+
+	return output, featuresRequiredComponents, requiredComponents, errs
+}
+
+func selectsDatadogAgent(ext *v1alpha1.DatadogAgentExtension, dda *v2alpha1.DatadogAgent) (bool, error) {
+	if ext.Spec.DatadogAgentSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ext.Spec.DatadogAgentSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(dda.GetLabels())), nil
+}
+
+// GetID returns the ID of the Feature built from a DatadogAgentExtension.
+func GetID(extensionName string) feature.IDType {
+	return feature.IDType(feature.ExtensionIDTypePrefix + extensionName)
+}
+
+func newExtensionFeature(ext *v1alpha1.DatadogAgentExtension, options *feature.Options) *extensionFeature {
+	extFeat := &extensionFeature{
+		name: ext.Name,
+		spec: *ext.Spec.DeepCopy(),
+	}
+
+	if options != nil {
+		extFeat.logger = options.Logger
+		extFeat.clusterPolicyRulesEnabled = options.ExtensionClusterPolicyRulesEnabled
+	}
+
+	return extFeat
+}
+
+type extensionFeature struct {
+	name string
+	spec v1alpha1.DatadogAgentExtensionSpec
+
+	owner                             metav1.Object
+	nodeAgentServiceAccount           string
+	clusterAgentServiceAccount        string
+	clusterChecksRunnerServiceAccount string
+
+	clusterPolicyRulesEnabled bool
+
+	conflicts []string
+	logger    logr.Logger
+}
+
+// ID returns the ID of the Feature
+func (f *extensionFeature) ID() feature.IDType {
+	return GetID(f.name)
+}
+
+// Configure is used to configure the feature from a v2alpha1.DatadogAgent instance.
+func (f *extensionFeature) Configure(dda *v2alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
+	f.owner = dda
+	f.nodeAgentServiceAccount = v2alpha1.GetAgentServiceAccount(dda)
+	f.clusterAgentServiceAccount = v2alpha1.GetClusterAgentServiceAccount(dda)
+	f.clusterChecksRunnerServiceAccount = v2alpha1.GetClusterChecksRunnerServiceAccount(dda)
+
+	return feature.RequiredComponents{
+		Agent:               requiredComponent(f.spec.NodeAgent),
+		ClusterAgent:        requiredComponent(f.spec.ClusterAgent),
+		ClusterChecksRunner: requiredComponent(f.spec.ClusterChecksRunner),
+	}
+}
+
+// ConfigureV1 use to configure the feature from a v1alpha1.DatadogAgent instance.
+// DatadogAgentExtensions are only supported by the v2alpha1 reconciler.
+func (f *extensionFeature) ConfigureV1(dda *v1alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
+	return reqComp
+}
+
+// ManageDependencies allows a feature to manage its dependencies.
+// Feature's dependencies should be added in the store.
+func (f *extensionFeature) ManageDependencies(managers feature.ResourceManagers, components feature.RequiredComponents) error {
+	rbacComponents := []struct {
+		name           v2alpha1.ComponentName
+		suffix         string
+		serviceAccount string
+		config         *v1alpha1.DatadogAgentExtensionComponent
+	}{
+		{name: v2alpha1.NodeAgentComponentName, suffix: apicommon.DefaultAgentResourceSuffix, serviceAccount: f.nodeAgentServiceAccount, config: f.spec.NodeAgent},
+		{name: v2alpha1.ClusterAgentComponentName, suffix: apicommon.DefaultClusterAgentResourceSuffix, serviceAccount: f.clusterAgentServiceAccount, config: f.spec.ClusterAgent},
+		{name: v2alpha1.ClusterChecksRunnerComponentName, suffix: apicommon.DefaultClusterChecksRunnerResourceSuffix, serviceAccount: f.clusterChecksRunnerServiceAccount, config: f.spec.ClusterChecksRunner},
+	}
+
+	for _, c := range rbacComponents {
+		if c.config == nil {
+			continue
+		}
+		rbacName := getRBACResourceName(f.owner, f.name, c.suffix)
+		if len(c.config.PolicyRules) > 0 {
+			if err := managers.RBACManager().AddPolicyRules(f.owner.GetNamespace(), rbacName, c.serviceAccount, c.config.PolicyRules); err != nil {
+				return err
+			}
+		}
+		if len(c.config.ClusterPolicyRules) > 0 {
+			// The operator can bind any ClusterRole: granting cluster-wide RBAC from a namespaced
+			// resource must be allowed explicitly.
+			if !f.clusterPolicyRulesEnabled {
+				f.addConflict(c.name, "clusterPolicyRules are ignored, they require the -datadogAgentExtensionClusterPolicyRulesEnabled operator flag")
+				continue
+			}
+			if err := managers.RBACManager().AddClusterPolicyRules(f.owner.GetNamespace(), rbacName, c.serviceAccount, c.config.ClusterPolicyRules); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ManageClusterAgent allows a feature to configure the ClusterAgent's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *extensionFeature) ManageClusterAgent(managers feature.PodTemplateManagers) error {
+	f.manageComponent(managers, v2alpha1.ClusterAgentComponentName, f.spec.ClusterAgent)
+	return nil
+}
+
+// ManageNodeAgent allows a feature to configure the Node Agent's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *extensionFeature) ManageNodeAgent(managers feature.PodTemplateManagers) error {
+	f.manageComponent(managers, v2alpha1.NodeAgentComponentName, f.spec.NodeAgent)
+	return nil
+}
+
+// ManageClusterChecksRunner allows a feature to configure the ClusterChecksRunner's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *extensionFeature) ManageClusterChecksRunner(managers feature.PodTemplateManagers) error {
+	f.manageComponent(managers, v2alpha1.ClusterChecksRunnerComponentName, f.spec.ClusterChecksRunner)
+	return nil
+}
+
+// Conflicts returns the parts of the extension that were not applied because
+// they conflict with the configuration of the operator or of a previous extension.
+func (f *extensionFeature) Conflicts() []string {
+	return f.conflicts
+}
+
+// manageComponent adds the extension configuration to a PodTemplateSpec. The settings already defined
+// by the operator or by a previous extension are never replaced: they are recorded as conflicts instead.
+func (f *extensionFeature) manageComponent(managers feature.PodTemplateManagers, componentName v2alpha1.ComponentName, config *v1alpha1.DatadogAgentExtensionComponent) {
+	if config == nil {
+		return
+	}
+
+	// The target containers are resolved before adding the sidecar containers,
+	// which are added as they are defined in the extension.
+	containers := targetContainers(managers.PodTemplateSpec(), config.Containers)
+
+	for i := range config.Env {
+		envVar := &config.Env[i]
+		for _, container := range containers {
+			if err := managers.EnvVar().AddEnvVarToContainerWithMergeFunc(container, envVar, errorOnDifferentEnvVarMergeFunction); err != nil {
+				f.addConflict(componentName, "env var %s of container %s is already set with a different value", envVar.Name, container)
+			}
+		}
+	}
+
+	for i := range config.Volumes {
+		vol := &config.Volumes[i]
+		if err := managers.Volume().AddVolumeWithMergeFunc(vol, errorOnDifferentVolumeMergeFunction); err != nil {
+			f.addConflict(componentName, "volume %s is already defined with a different source", vol.Name)
+		}
+	}
+
+	for i := range config.VolumeMounts {
+		volMount := &config.VolumeMounts[i]
+		for _, container := range containers {
+			if err := managers.VolumeMount().AddVolumeMountToContainerWithMergeFunc(volMount, container, errorOnDifferentVolumeMountMergeFunction); err != nil {
+				f.addConflict(componentName, "volume mount %s of container %s conflicts with an existing volume mount", volMount.Name, container)
+			}
+		}
+	}
+
+	podSpec := &managers.PodTemplateSpec().Spec
+	for _, sidecar := range config.SidecarContainers {
+		if hasContainer(podSpec, sidecar.Name) {
+			f.addConflict(componentName, "container %s already exists", sidecar.Name)
+			continue
+		}
+		podSpec.Containers = append(podSpec.Containers, *sidecar.DeepCopy())
+	}
+}
+
+// addConflict records a conflict once: with profiles, the node Agent is configured once per DaemonSet.
+func (f *extensionFeature) addConflict(componentName v2alpha1.ComponentName, format string, args ...interface{}) {
+	conflict := fmt.Sprintf("%s: %s", componentName, fmt.Sprintf(format, args...))
+	for _, c := range f.conflicts {
+		if c == conflict {
+			return
+		}
+	}
+	f.conflicts = append(f.conflicts, conflict)
+	f.logger.V(1).Info("Skipping DatadogAgentExtension configuration", "extension", f.name, "conflict", conflict)
+}
+
+func requiredComponent(config *v1alpha1.DatadogAgentExtensionComponent) feature.RequiredComponent {
+	if config == nil {
+		return feature.RequiredComponent{}
+	}
+	return feature.RequiredComponent{
+		IsRequired: config.Required,
+		Containers: config.Containers,
+	}
+}
+
+// targetContainers returns the requested containers, or all the containers of the PodTemplateSpec if none is requested.
+func targetContainers(podTmpl *corev1.PodTemplateSpec, requested []apicommonv1.AgentContainerName) []apicommonv1.AgentContainerName {
+	if len(requested) > 0 {
+		return requested
+	}
+	containers := make([]apicommonv1.AgentContainerName, 0, len(podTmpl.Spec.Containers))
+	for _, container := range podTmpl.Spec.Containers {
+		containers = append(containers, apicommonv1.AgentContainerName(container.Name))
+	}
+	return containers
+}
+
+func hasContainer(podSpec *corev1.PodSpec, name string) bool {
+	for _, container := range podSpec.InitContainers {
+		if container.Name == name {
+			return true
+		}
+	}
+	for _, container := range podSpec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func errorOnDifferentEnvVarMergeFunction(current, newEnv *corev1.EnvVar) (*corev1.EnvVar, error) {
+	if apiequality.Semantic.DeepEqual(current, newEnv) {
+		return current.DeepCopy(), nil
+	}
+	return merger.ErrorOnMergeAttemptdEnvVarMergeFunction(current, newEnv)
+}
+
+func errorOnDifferentVolumeMergeFunction(current, newVolume *corev1.Volume) (*corev1.Volume, error) {
+	if apiequality.Semantic.DeepEqual(current, newVolume) {
+		return current.DeepCopy(), nil
+	}
+	return merger.ErrorOnMergeAttemptdVolumeMergeFunction(current, newVolume)
+}
+
+func errorOnDifferentVolumeMountMergeFunction(current, newVolumeMount *corev1.VolumeMount) (*corev1.VolumeMount, error) {
+	if apiequality.Semantic.DeepEqual(current, newVolumeMount) {
+		return current.DeepCopy(), nil
+	}
+	return merger.ErrorOnMergeAttemptdVolumeMountMergeFunction(current, newVolumeMount)
+}
@@ -0,0 +1,216 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func newExtension(name string, order int32, spec v1alpha1.DatadogAgentExtensionSpec) v1alpha1.DatadogAgentExtension {
+	spec.Order = order
+	return v1alpha1.DatadogAgentExtension{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      name,
+		},
+		Spec: spec,
+	}
+}
+
+func TestBuildFeatures(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.Labels = map[string]string{"team": "platform"}
+
+	otherNamespace := newExtension("other-namespace", 0, v1alpha1.DatadogAgentExtensionSpec{})
+	otherNamespace.Namespace = "other"
+
+	extensions := []v1alpha1.DatadogAgentExtension{
+		newExtension("sidecar", 10, v1alpha1.DatadogAgentExtensionSpec{
+			ClusterAgent: &v1alpha1.DatadogAgentExtensionComponent{Required: apiutils.NewBoolPointer(true)},
+		}),
+		newExtension("env", 0, v1alpha1.DatadogAgentExtensionSpec{
+			NodeAgent: &v1alpha1.DatadogAgentExtensionComponent{
+				Containers: []apicommonv1.AgentContainerName{apicommonv1.TraceAgentContainerName},
+			},
+		}),
+		newExtension("another-env", 0, v1alpha1.DatadogAgentExtensionSpec{
+			DatadogAgentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+		}),
+		newExtension("not-selected", 0, v1alpha1.DatadogAgentExtensionSpec{
+			DatadogAgentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "security"}},
+		}),
+		newExtension("invalid-selector", 0, v1alpha1.DatadogAgentExtensionSpec{
+			DatadogAgentSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+			},
+		}),
+		otherNamespace,
+	}
+
+	features, featuresRequiredComponents, requiredComponents, errs := BuildFeatures(dda, extensions, &feature.Options{Logger: logr.Discard()})
+
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "DatadogAgentExtension bar/invalid-selector")
+
+	ids := make([]feature.IDType, 0, len(features))
+	for _, feat := range features {
+		ids = append(ids, feat.ID())
+	}
+	assert.Equal(t, []feature.IDType{"extension_another-env", "extension_env", "extension_sidecar"}, ids, "features should be sorted by order then by name")
+
+	assert.Len(t, featuresRequiredComponents, 3)
+	anotherEnvRequiredComponents := featuresRequiredComponents["extension_another-env"]
+	assert.False(t, anotherEnvRequiredComponents.IsConfigured())
+	assert.Equal(t, []apicommonv1.AgentContainerName{apicommonv1.TraceAgentContainerName}, requiredComponents.Agent.Containers)
+	assert.True(t, requiredComponents.ClusterAgent.IsEnabled())
+	assert.Nil(t, requiredComponents.ClusterChecksRunner.IsRequired)
+}
+
+func Test_extensionFeature_ManageNodeAgent(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	extensions := []v1alpha1.DatadogAgentExtension{
+		newExtension("first", 0, v1alpha1.DatadogAgentExtensionSpec{
+			NodeAgent: &v1alpha1.DatadogAgentExtensionComponent{
+				Env: []corev1.EnvVar{
+					{Name: "DD_SITE", Value: "datadoghq.com"},
+					{Name: "DD_ORG_TEAM", Value: "platform"},
+				},
+				Volumes: []corev1.Volume{
+					{Name: "config", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/org"}}},
+					{Name: "org-certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "org-certs"}}},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "org-certs", MountPath: "/etc/org/certs", ReadOnly: true},
+				},
+				SidecarContainers: []corev1.Container{
+					{Name: string(apicommonv1.CoreAgentContainerName), Image: "org/agent"},
+					{Name: "org-proxy", Image: "org/proxy"},
+				},
+			},
+		}),
+		newExtension("second", 1, v1alpha1.DatadogAgentExtensionSpec{
+			NodeAgent: &v1alpha1.DatadogAgentExtensionComponent{
+				Containers: []apicommonv1.AgentContainerName{apicommonv1.TraceAgentContainerName},
+				Env: []corev1.EnvVar{
+					{Name: "DD_ORG_TEAM", Value: "security"},
+				},
+			},
+		}),
+	}
+	features, _, _, errs := BuildFeatures(dda, extensions, &feature.Options{Logger: logr.Discard()})
+	require.Empty(t, errs)
+
+	podTmpl := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: string(apicommonv1.CoreAgentContainerName), Env: []corev1.EnvVar{{Name: "DD_SITE", Value: "datadoghq.com"}}},
+				{Name: string(apicommonv1.TraceAgentContainerName)},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+	}
+	// With profiles, the node Agent is configured once per DaemonSet: the conflicts are only reported once
+	profilePodTmpl := podTmpl.DeepCopy()
+	managers := feature.NewPodTemplateManagers(podTmpl)
+	for _, feat := range features {
+		require.NoError(t, feat.ManageNodeAgent(managers))
+		require.NoError(t, feat.ManageNodeAgent(feature.NewPodTemplateManagers(profilePodTmpl)))
+	}
+
+	assert.Equal(t, []string{
+		"nodeAgent: volume config is already defined with a different source",
+		"nodeAgent: container agent already exists",
+	}, features[0].(feature.ConflictReporter).Conflicts())
+	assert.Equal(t, []string{
+		"nodeAgent: env var DD_ORG_TEAM of container trace-agent is already set with a different value",
+	}, features[1].(feature.ConflictReporter).Conflicts())
+
+	// the configuration defined by the operator and by the first extension is kept
+	require.Len(t, podTmpl.Spec.Containers, 3)
+	for _, container := range podTmpl.Spec.Containers[:2] {
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "DD_SITE", Value: "datadoghq.com"},
+			{Name: "DD_ORG_TEAM", Value: "platform"},
+		}, container.Env, "container %s", container.Name)
+		assert.Equal(t, []corev1.VolumeMount{
+			{Name: "org-certs", MountPath: "/etc/org/certs", ReadOnly: true},
+		}, container.VolumeMounts, "container %s", container.Name)
+	}
+	assert.Equal(t, corev1.Container{Name: "org-proxy", Image: "org/proxy"}, podTmpl.Spec.Containers[2])
+	require.Len(t, podTmpl.Spec.Volumes, 2)
+	assert.NotNil(t, podTmpl.Spec.Volumes[0].EmptyDir)
+	assert.Equal(t, "org-certs", podTmpl.Spec.Volumes[1].Name)
+}
+
+func Test_extensionFeature_ManageDependencies(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+	}
+	extensions := []v1alpha1.DatadogAgentExtension{
+		newExtension("org", 0, v1alpha1.DatadogAgentExtensionSpec{
+			NodeAgent:    &v1alpha1.DatadogAgentExtensionComponent{ClusterPolicyRules: rules},
+			ClusterAgent: &v1alpha1.DatadogAgentExtensionComponent{PolicyRules: rules},
+		}),
+	}
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+
+	// The cluster policy rules are ignored unless they are enabled in the operator
+	features, _, requiredComponents, errs := BuildFeatures(dda, extensions, &feature.Options{Logger: logr.Discard()})
+	require.Empty(t, errs)
+	require.Len(t, features, 1)
+	store := dependencies.NewStore(dda, &dependencies.StoreOptions{Scheme: testScheme})
+	require.NoError(t, features[0].ManageDependencies(feature.NewResourceManagers(store), requiredComponents))
+	_, found := store.Get(kubernetes.ClusterRolesKind, "", "bar-foo-org-agent")
+	assert.False(t, found, "the ClusterRole of the node agent requires the cluster policy rules to be enabled")
+	assert.Equal(t, []string{
+		"nodeAgent: clusterPolicyRules are ignored, they require the -datadogAgentExtensionClusterPolicyRulesEnabled operator flag",
+	}, features[0].(feature.ConflictReporter).Conflicts())
+	_, found = store.Get(kubernetes.RolesKind, "bar", "bar-foo-org-cluster-agent")
+	assert.True(t, found, "the namespaced policy rules are always applied")
+
+	features, _, requiredComponents, errs = BuildFeatures(dda, extensions, &feature.Options{Logger: logr.Discard(), ExtensionClusterPolicyRulesEnabled: true})
+	require.Empty(t, errs)
+	require.Len(t, features, 1)
+	store = dependencies.NewStore(dda, &dependencies.StoreOptions{Scheme: testScheme})
+	require.NoError(t, features[0].ManageDependencies(feature.NewResourceManagers(store), requiredComponents))
+	assert.Empty(t, features[0].(feature.ConflictReporter).Conflicts())
+
+	obj, found := store.Get(kubernetes.ClusterRolesKind, "", "bar-foo-org-agent")
+	require.True(t, found, "missing ClusterRole of the node agent")
+	assert.Equal(t, rules, obj.(*rbacv1.ClusterRole).Rules)
+	_, found = store.Get(kubernetes.ClusterRoleBindingKind, "", "bar-foo-org-agent")
+	assert.True(t, found, "missing ClusterRoleBinding of the node agent")
+
+	obj, found = store.Get(kubernetes.RolesKind, "bar", "bar-foo-org-cluster-agent")
+	require.True(t, found, "missing Role of the cluster agent")
+	assert.Equal(t, rules, obj.(*rbacv1.Role).Rules)
+	_, found = store.Get(kubernetes.RoleBindingKind, "bar", "bar-foo-org-cluster-agent")
+	assert.True(t, found, "missing RoleBinding of the cluster agent")
+
+	_, found = store.Get(kubernetes.RolesKind, "bar", "bar-foo-org-agent")
+	assert.False(t, found, "no Role should be created without policy rules")
+}
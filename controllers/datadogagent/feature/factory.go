@@ -32,11 +32,19 @@ func Register(id IDType, buildFunc BuildFunc) error {
 
 // BuildFeatures use to build a list features depending of the v2alpha1.DatadogAgent instance
 func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, RequiredComponents) {
+	output, _, requiredComponents := BuildFeaturesWithRequirements(dda, options)
+	return output, requiredComponents
+}
+
+// BuildFeaturesWithRequirements is like BuildFeatures, but it also returns the RequiredComponents of each returned Feature,
+// indexed by the Feature ID.
+func BuildFeaturesWithRequirements(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, map[IDType]RequiredComponents, RequiredComponents) {
 	builderMutex.RLock()
 	defer builderMutex.RUnlock()
 
 	var output []Feature
 	var requiredComponents RequiredComponents
+	featuresRequiredComponents := map[IDType]RequiredComponents{}
 
 	// to always return in feature in the same order we need to sort the map keys
 	sortedkeys := make([]IDType, 0, len(featureBuilders))
@@ -53,11 +61,12 @@ func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, Req
 		// only add feature to the output if one of the components is configured (but not necessarily required)
 		if reqComponents.IsConfigured() {
 			output = append(output, feat)
+			featuresRequiredComponents[feat.ID()] = reqComponents
 		}
 		requiredComponents.Merge(&reqComponents)
 	}
 
-	return output, requiredComponents
+	return output, featuresRequiredComponents, requiredComponents
 }
 
 // BuildFeaturesV1 use to build a list features depending of the v1alpha1.DatadogAgent instance
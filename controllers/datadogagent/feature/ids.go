@@ -53,4 +53,7 @@ const (
 	RemoteConfigurationIDType = "remote_config"
 	// DummyIDType Dummy feature.
 	DummyIDType = "dummy"
+
+	// ExtensionIDTypePrefix prefix of the ID of the features built from DatadogAgentExtensions.
+	ExtensionIDTypePrefix = "extension_"
 )
@@ -7,9 +7,6 @@ package otlp
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,10 +23,6 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
 )
 
-var (
-	portRegexp = regexp.MustCompile(":([0-9]+)$")
-)
-
 func init() {
 	err := feature.Register(feature.OTLPIDType, buildOTLPFeature)
 	if err != nil {
@@ -159,7 +152,7 @@ func (f *otlpFeature) ConfigureV1(dda *v1alpha1.DatadogAgent) (reqComp feature.R
 func (f *otlpFeature) ManageDependencies(managers feature.ResourceManagers, components feature.RequiredComponents) error {
 	if f.grpcEnabled {
 		if component.ShouldCreateAgentLocalService(managers.Store().GetVersionInfo(), f.forceEnableLocalService) {
-			port, err := extractPortEndpoint(f.grpcEndpoint)
+			port, err := v2alpha1.ExtractOTLPEndpointPort(f.grpcEndpoint)
 			if err != nil {
 				f.logger.Error(err, "failed to extract port from OTLP/gRPC endpoint")
 				return fmt.Errorf("failed to extract port from OTLP/gRPC endpoint: %w", err)
@@ -180,7 +173,7 @@ func (f *otlpFeature) ManageDependencies(managers feature.ResourceManagers, comp
 	}
 	if f.httpEnabled {
 		if component.ShouldCreateAgentLocalService(managers.Store().GetVersionInfo(), f.forceEnableLocalService) {
-			port, err := extractPortEndpoint(f.httpEndpoint)
+			port, err := v2alpha1.ExtractOTLPEndpointPort(f.httpEndpoint)
 			if err != nil {
 				f.logger.Error(err, "failed to extract port from OTLP/HTTP endpoint")
 				return fmt.Errorf("failed to extract port from OTLP/HTTP endpoint: %w", err)
@@ -208,47 +201,16 @@ func (f *otlpFeature) ManageClusterAgent(managers feature.PodTemplateManagers) e
 	return nil
 }
 
-func validateOTLPGRPCEndpoint(endpoint string) error {
-	for _, protocol := range []string{"unix", "unix-abstract"} {
-		if strings.HasPrefix(endpoint, protocol+":") {
-			return fmt.Errorf("%q protocol is not currently supported", protocol)
-		}
-	}
-
-	return nil
-}
-
-func extractPortEndpoint(endpoint string) (int32, error) {
-	if match := portRegexp.FindStringSubmatch(endpoint); match != nil {
-		if len(match) < 2 {
-			return 0, fmt.Errorf("no match for port on %q", endpoint)
-		}
-		portStr := match[1]
-
-		port, err := strconv.Atoi(portStr)
-		if err != nil {
-			return 0, fmt.Errorf("could not cast port %q from endpoint %q to int: %w", portStr, endpoint, err)
-		}
-
-		if port < 0 || port > 65535 {
-			return 0, fmt.Errorf("port is outside valid range: %d", port)
-		}
-
-		return int32(port), nil
-	}
-	return 0, fmt.Errorf("%q does not have a port explicitly set", endpoint)
-}
-
 // ManageNodeAgent allows a feature to configure the Node Agent's corev1.PodTemplateSpec
 // It should do nothing if the feature doesn't need to configure it.
 func (f *otlpFeature) ManageNodeAgent(managers feature.PodTemplateManagers) error {
 	if f.grpcEnabled {
-		if err := validateOTLPGRPCEndpoint(f.grpcEndpoint); err != nil {
+		if err := v2alpha1.ValidateOTLPGRPCEndpoint(f.grpcEndpoint); err != nil {
 			f.logger.Error(err, "invalid OTLP/gRPC endpoint")
 			return fmt.Errorf("invalid OTLP/gRPC endpoint: %w", err)
 		}
 
-		port, err := extractPortEndpoint(f.grpcEndpoint)
+		port, err := v2alpha1.ExtractOTLPEndpointPort(f.grpcEndpoint)
 		if err != nil {
 			f.logger.Error(err, "failed to extract port from OTLP/gRPC endpoint")
 			return fmt.Errorf("failed to extract port from OTLP/gRPC endpoint: %w", err)
@@ -271,7 +233,7 @@ func (f *otlpFeature) ManageNodeAgent(managers feature.PodTemplateManagers) erro
 	}
 
 	if f.httpEnabled {
-		port, err := extractPortEndpoint(f.httpEndpoint)
+		port, err := v2alpha1.ExtractOTLPEndpointPort(f.httpEndpoint)
 		if err != nil {
 			f.logger.Error(err, "failed to extract port from OTLP/HTTP endpoint")
 			return fmt.Errorf("failed to extract port from OTLP/HTTP endpoint: %w", err)
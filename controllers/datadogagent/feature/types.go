@@ -131,9 +131,19 @@ type Feature interface {
 	ManageClusterChecksRunner(managers PodTemplateManagers) error
 }
 
+// ConflictReporter can be implemented by a Feature that doesn't apply the parts of its configuration
+// conflicting with the configuration of the operator or of another Feature.
+type ConflictReporter interface {
+	// Conflicts returns a description of each part of the configuration that was not applied.
+	Conflicts() []string
+}
+
 // Options option that can be pass to the Interface.Configure function
 type Options struct {
 	SupportExtendedDaemonset bool
+	// ExtensionClusterPolicyRulesEnabled allows the DatadogAgentExtensions to grant cluster-wide RBAC
+	// to the service accounts of the components with clusterPolicyRules.
+	ExtensionClusterPolicyRulesEnabled bool
 
 	Logger logr.Logger
 }
@@ -153,6 +163,8 @@ type ResourceManagers interface {
 	CiliumPolicyManager() merger.CiliumPolicyManager
 	ConfigMapManager() merger.ConfigMapManager
 	APIServiceManager() merger.APIServiceManager
+	PodDisruptionBudgetManager() merger.PodDisruptionBudgetManager
+	HorizontalPodAutoscalerManager() merger.HorizontalPodAutoscalerManager
 }
 
 // NewResourceManagers return new instance of the ResourceManagers interface
@@ -167,6 +179,8 @@ func NewResourceManagers(store dependencies.StoreClient) ResourceManagers {
 		cilium:        merger.NewCiliumPolicyManager(store),
 		configMap:     merger.NewConfigMapManager(store),
 		apiService:    merger.NewAPIServiceManager(store),
+		pdb:           merger.NewPodDisruptionBudgetManager(store),
+		hpa:           merger.NewHorizontalPodAutoscalerManager(store),
 	}
 }
 
@@ -180,6 +194,8 @@ type resourceManagersImpl struct {
 	cilium        merger.CiliumPolicyManager
 	configMap     merger.ConfigMapManager
 	apiService    merger.APIServiceManager
+	pdb           merger.PodDisruptionBudgetManager
+	hpa           merger.HorizontalPodAutoscalerManager
 }
 
 func (impl *resourceManagersImpl) Store() dependencies.StoreClient {
@@ -218,6 +234,14 @@ func (impl *resourceManagersImpl) APIServiceManager() merger.APIServiceManager {
 	return impl.apiService
 }
 
+func (impl *resourceManagersImpl) PodDisruptionBudgetManager() merger.PodDisruptionBudgetManager {
+	return impl.pdb
+}
+
+func (impl *resourceManagersImpl) HorizontalPodAutoscalerManager() merger.HorizontalPodAutoscalerManager {
+	return impl.hpa
+}
+
 // PodTemplateManagers used to access the different PodTemplateSpec manager.
 type PodTemplateManagers interface {
 	// PodTemplateSpec used to access directly the PodTemplateSpec.
@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+)
+
+const (
+	manageDependenciesStep        = "ManageDependencies"
+	manageNodeAgentStep           = "ManageNodeAgent"
+	manageClusterAgentStep        = "ManageClusterAgent"
+	manageClusterChecksRunnerStep = "ManageClusterChecksRunner"
+
+	featureReconcileSucceeded    = "FeatureReconcileSucceeded"
+	featureConfigurationConflict = "FeatureConfigurationConflict"
+)
+
+// featureError is returned when a Feature fails to manage its dependencies or a component,
+// so that the error can be reported in the feature's status condition.
+type featureError struct {
+	id   feature.IDType
+	step string
+	err  error
+}
+
+func newFeatureError(id feature.IDType, step string, err error) error {
+	return &featureError{id: id, step: step, err: err}
+}
+
+func (e *featureError) Error() string {
+	return fmt.Sprintf("feature %s: %s: %v", e.id, e.step, e.err)
+}
+
+func (e *featureError) Unwrap() error {
+	return e.err
+}
+
+// featureStatuses keeps track of the reconcile state of the active features.
+type featureStatuses struct {
+	features           []feature.Feature
+	requiredComponents map[feature.IDType]feature.RequiredComponents
+	errors             map[feature.IDType]*featureError
+}
+
+func newFeatureStatuses(features []feature.Feature, requiredComponents map[feature.IDType]feature.RequiredComponents) *featureStatuses {
+	return &featureStatuses{
+		features:           features,
+		requiredComponents: requiredComponents,
+		errors:             map[feature.IDType]*featureError{},
+	}
+}
+
+// recordError keeps the last error returned by a Feature. Other errors are ignored.
+func (fs *featureStatuses) recordError(err error) {
+	var featErr *featureError
+	if errors.As(err, &featErr) {
+		fs.errors[featErr.id] = featErr
+	}
+}
+
+// updateStatus sets the list of active features and one condition per active feature in the status.
+// The conditions of the features that are no longer active are removed.
+// A Feature that reports conflicts has a False condition listing the parts of its configuration that were not applied.
+func (fs *featureStatuses) updateStatus(status *datadoghqv2alpha1.DatadogAgentStatus, now metav1.Time) {
+	activeFeatures := make([]string, 0, len(fs.features))
+	for _, feat := range fs.features {
+		id := string(feat.ID())
+		activeFeatures = append(activeFeatures, id)
+
+		conditionType := datadoghqv2alpha1.GetFeatureReconcileConditionType(id)
+		if featErr, found := fs.errors[feat.ID()]; found {
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(status, now, conditionType, metav1.ConditionFalse, featErr.step+"Error", featErr.err.Error(), true)
+			continue
+		}
+		if reporter, ok := feat.(feature.ConflictReporter); ok && len(reporter.Conflicts()) > 0 {
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(status, now, conditionType, metav1.ConditionFalse, featureConfigurationConflict, strings.Join(reporter.Conflicts(), "; "), true)
+			continue
+		}
+		reqComponents := fs.requiredComponents[feat.ID()]
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(status, now, conditionType, metav1.ConditionTrue, featureReconcileSucceeded, requiredComponentsMessage(&reqComponents), true)
+	}
+
+	for _, id := range status.ActiveFeatures {
+		if !utils.ContainsString(activeFeatures, id) {
+			datadoghqv2alpha1.DeleteDatadogAgentStatusCondition(status, datadoghqv2alpha1.GetFeatureReconcileConditionType(id))
+		}
+	}
+	status.ActiveFeatures = activeFeatures
+}
+
+// requiredComponentsMessage describes if a feature is enabled or only configured, and the components it requires.
+// For instance: "Enabled, required components: nodeAgent [agent trace-agent], clusterAgent".
+func requiredComponentsMessage(reqComponents *feature.RequiredComponents) string {
+	state := "Configured"
+	if reqComponents.IsEnabled() {
+		state = "Enabled"
+	}
+
+	components := []struct {
+		name      datadoghqv2alpha1.ComponentName
+		component feature.RequiredComponent
+	}{
+		{name: datadoghqv2alpha1.NodeAgentComponentName, component: reqComponents.Agent},
+		{name: datadoghqv2alpha1.ClusterAgentComponentName, component: reqComponents.ClusterAgent},
+		{name: datadoghqv2alpha1.ClusterChecksRunnerComponentName, component: reqComponents.ClusterChecksRunner},
+	}
+	var required []string
+	for _, c := range components {
+		if !c.component.IsEnabled() {
+			continue
+		}
+		if len(c.component.Containers) == 0 {
+			required = append(required, string(c.name))
+			continue
+		}
+		containers := make([]string, 0, len(c.component.Containers))
+		for _, container := range c.component.Containers {
+			containers = append(containers, string(container))
+		}
+		required = append(required, fmt.Sprintf("%s [%s]", c.name, strings.Join(containers, " ")))
+	}
+
+	if len(required) == 0 {
+		return fmt.Sprintf("%s, no required component", state)
+	}
+	return fmt.Sprintf("%s, required components: %s", state, strings.Join(required, ", "))
+}
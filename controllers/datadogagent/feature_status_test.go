@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+func Test_featureStatuses_updateStatus(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.Spec.Features = &v2alpha1.DatadogFeatures{
+		APM: &v2alpha1.APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+		CWS: &v2alpha1.CWSFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+	}
+	v2alpha1.DefaultDatadogAgent(dda)
+	features, featuresRequiredComponents, _ := feature.BuildFeaturesWithRequirements(dda, &feature.Options{Logger: logr.Discard()})
+
+	now := metav1.Now()
+	status := &v2alpha1.DatadogAgentStatus{
+		// "npm" was active during a previous reconcile
+		ActiveFeatures: []string{"npm"},
+		Conditions: []metav1.Condition{
+			v2alpha1.NewDatadogAgentStatusCondition("NpmFeatureReconcile", metav1.ConditionTrue, now, featureReconcileSucceeded, ""),
+		},
+	}
+
+	featStatuses := newFeatureStatuses(features, featuresRequiredComponents)
+	// errors that are not returned by a feature are ignored
+	featStatuses.recordError(errors.New("not a feature error"))
+	featStatuses.recordError(nil)
+	// errors returned by a feature are found even when wrapped
+	featStatuses.recordError(fmt.Errorf("reconcile error: %w", newFeatureError(feature.CWSIDType, manageNodeAgentStep, errors.New("invalid cws config"))))
+	featStatuses.updateStatus(status, now)
+
+	assert.Contains(t, status.ActiveFeatures, "apm")
+	assert.Contains(t, status.ActiveFeatures, "cws")
+	assert.NotContains(t, status.ActiveFeatures, "npm")
+	assert.Len(t, status.Conditions, len(status.ActiveFeatures))
+
+	conditions := map[string]metav1.Condition{}
+	for _, condition := range status.Conditions {
+		conditions[condition.Type] = condition
+	}
+	assert.NotContains(t, conditions, "NpmFeatureReconcile", "the condition of an inactive feature should be removed")
+
+	require.Contains(t, conditions, "CwsFeatureReconcile")
+	assert.Equal(t, metav1.ConditionFalse, conditions["CwsFeatureReconcile"].Status)
+	assert.Equal(t, "ManageNodeAgentError", conditions["CwsFeatureReconcile"].Reason)
+	assert.Equal(t, "invalid cws config", conditions["CwsFeatureReconcile"].Message)
+
+	require.Contains(t, conditions, "ApmFeatureReconcile")
+	assert.Equal(t, metav1.ConditionTrue, conditions["ApmFeatureReconcile"].Status)
+	assert.Equal(t, featureReconcileSucceeded, conditions["ApmFeatureReconcile"].Reason)
+	assert.Equal(t, "Enabled, required components: nodeAgent [agent trace-agent]", conditions["ApmFeatureReconcile"].Message)
+}
+
+// conflictingFeature is a Feature that reports conflicts
+type conflictingFeature struct {
+	feature.Feature
+	conflicts []string
+}
+
+func (f *conflictingFeature) ID() feature.IDType {
+	return feature.ExtensionIDTypePrefix + "foo"
+}
+
+func (f *conflictingFeature) Conflicts() []string {
+	return f.conflicts
+}
+
+func Test_featureStatuses_updateStatusConflicts(t *testing.T) {
+	now := metav1.Now()
+	status := &v2alpha1.DatadogAgentStatus{}
+	features := []feature.Feature{
+		&conflictingFeature{conflicts: []string{"nodeAgent: container foo already exists", "clusterAgent: volume bar is already defined with a different source"}},
+	}
+	featuresRequiredComponents := map[feature.IDType]feature.RequiredComponents{
+		feature.ExtensionIDTypePrefix + "foo": {},
+	}
+
+	featStatuses := newFeatureStatuses(features, featuresRequiredComponents)
+	featStatuses.updateStatus(status, now)
+
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, "ExtensionFooFeatureReconcile", status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, status.Conditions[0].Status)
+	assert.Equal(t, featureConfigurationConflict, status.Conditions[0].Reason)
+	assert.Equal(t, "nodeAgent: container foo already exists; clusterAgent: volume bar is already defined with a different source", status.Conditions[0].Message)
+
+	// without conflict the condition is updated back to True
+	features[0].(*conflictingFeature).conflicts = nil
+	featStatuses.updateStatus(status, now)
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, status.Conditions[0].Status)
+	assert.Equal(t, featureReconcileSucceeded, status.Conditions[0].Reason)
+}
+
+func Test_requiredComponentsMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		reqComponents feature.RequiredComponents
+		want          string
+	}{
+		{
+			name: "configured only",
+			reqComponents: feature.RequiredComponents{
+				ClusterAgent: feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(false)},
+			},
+			want: "Configured, no required component",
+		},
+		{
+			name: "several components",
+			reqComponents: feature.RequiredComponents{
+				Agent: feature.RequiredComponent{
+					IsRequired: apiutils.NewBoolPointer(true),
+					Containers: []commonv1.AgentContainerName{commonv1.CoreAgentContainerName, commonv1.SystemProbeContainerName},
+				},
+				ClusterAgent:        feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+				ClusterChecksRunner: feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+			},
+			want: "Enabled, required components: nodeAgent [agent system-probe], clusterAgent, clusterChecksRunner",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, requiredComponentsMessage(&tt.reqComponents))
+		})
+	}
+}
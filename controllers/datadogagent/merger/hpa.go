@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package merger
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// HorizontalPodAutoscalerManager is used to manage HorizontalPodAutoscaler resources.
+type HorizontalPodAutoscalerManager interface {
+	AddHorizontalPodAutoscaler(name, namespace string, spec autoscalingv2.HorizontalPodAutoscalerSpec) error
+}
+
+// NewHorizontalPodAutoscalerManager returns a new HorizontalPodAutoscalerManager instance
+func NewHorizontalPodAutoscalerManager(store dependencies.StoreClient) HorizontalPodAutoscalerManager {
+	manager := &horizontalPodAutoscalerManagerImpl{
+		store: store,
+	}
+	return manager
+}
+
+// horizontalPodAutoscalerManagerImpl is used to manage HorizontalPodAutoscaler resources.
+type horizontalPodAutoscalerManagerImpl struct {
+	store dependencies.StoreClient
+}
+
+// AddHorizontalPodAutoscaler creates or updates an autoscaling/v2 HorizontalPodAutoscaler.
+// It returns an error if the autoscaling/v2 API is not served by the cluster.
+func (m *horizontalPodAutoscalerManagerImpl) AddHorizontalPodAutoscaler(name, namespace string, spec autoscalingv2.HorizontalPodAutoscalerSpec) error {
+	platformInfo := m.store.GetPlatformInfo()
+	if !platformInfo.SupportsHPAV2() {
+		return fmt.Errorf("unable to add the HorizontalPodAutoscaler %s: the autoscaling/v2 API is not supported by the cluster", name)
+	}
+
+	obj, _ := m.store.GetOrCreate(kubernetes.HorizontalPodAutoscalersKind, namespace, name)
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return fmt.Errorf("unable to get from the store the HorizontalPodAutoscaler %s", name)
+	}
+
+	hpa.Spec = spec
+
+	return m.store.AddOrUpdate(kubernetes.HorizontalPodAutoscalersKind, hpa)
+}
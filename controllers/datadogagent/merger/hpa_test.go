@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package merger
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestHorizontalPodAutoscalerManager_AddHorizontalPodAutoscaler(t *testing.T) {
+	ns := "bar"
+	name := "foo-cluster-checks-runner"
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: name},
+		MinReplicas:    apiutils.NewInt32Pointer(2),
+		MaxReplicas:    5,
+	}
+
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	owner := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      "foo",
+		},
+	}
+	newStore := func(gitVersion string) *dependencies.Store {
+		return dependencies.NewStore(owner, &dependencies.StoreOptions{
+			Scheme:       testScheme,
+			PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(&version.Info{GitVersion: gitVersion}, map[string]string{}, map[string]string{}),
+		})
+	}
+
+	tests := []struct {
+		name    string
+		store   *dependencies.Store
+		wantErr bool
+		wantHPA bool
+	}{
+		{
+			name:    "autoscaling/v2 supported",
+			store:   newStore("v1.23.4"),
+			wantHPA: true,
+		},
+		{
+			name:    "autoscaling/v2 not supported",
+			store:   newStore("v1.21.1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &horizontalPodAutoscalerManagerImpl{
+				store: tt.store,
+			}
+			if err := m.AddHorizontalPodAutoscaler(name, ns, spec); (err != nil) != tt.wantErr {
+				t.Errorf("HorizontalPodAutoscalerManager.AddHorizontalPodAutoscaler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			obj, found := tt.store.Get(kubernetes.HorizontalPodAutoscalersKind, ns, name)
+			if found != tt.wantHPA {
+				t.Fatalf("HorizontalPodAutoscaler %s/%s found = %v, want %v", ns, name, found, tt.wantHPA)
+			}
+			if found && obj.(*autoscalingv2.HorizontalPodAutoscaler).Spec.MaxReplicas != 5 {
+				t.Errorf("wrong spec in HorizontalPodAutoscaler %s/%s", ns, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package merger
+
+import (
+	"testing"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodDisruptionBudgetManager_AddPodDisruptionBudget(t *testing.T) {
+	ns := "bar"
+	name := "foo-cluster-agent"
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			apicommon.AgentDeploymentNameLabelKey:      "foo",
+			apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultClusterAgentResourceSuffix,
+		},
+	}
+	minAvailable := intstr.FromInt(1)
+	maxUnavailable := intstr.FromString("50%")
+
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	owner := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      "foo",
+		},
+	}
+	newStore := func(pdbVersion string) *dependencies.Store {
+		return dependencies.NewStore(owner, &dependencies.StoreOptions{
+			Scheme:       testScheme,
+			PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"PodDisruptionBudget": pdbVersion}, nil),
+		})
+	}
+	existingPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+		},
+	}
+
+	type args struct {
+		minAvailable   *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+	}
+	tests := []struct {
+		name         string
+		store        *dependencies.Store
+		args         args
+		wantErr      bool
+		validateFunc func(*testing.T, *dependencies.Store)
+	}{
+		{
+			name:  "policy/v1",
+			store: newStore("policy/v1"),
+			args: args{
+				minAvailable: &minAvailable,
+			},
+			validateFunc: func(t *testing.T, store *dependencies.Store) {
+				obj, found := store.Get(kubernetes.PodDisruptionBudgetsKind, ns, name)
+				if !found {
+					t.Fatalf("missing PodDisruptionBudget %s/%s", ns, name)
+				}
+				pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+				if !ok {
+					t.Fatalf("PodDisruptionBudget %s/%s is a %T, want a policy/v1 PodDisruptionBudget", ns, name, obj)
+				}
+				if pdb.Spec.MinAvailable.IntValue() != 1 || pdb.Spec.Selector != selector {
+					t.Errorf("wrong spec in PodDisruptionBudget %s/%s: %v", ns, name, pdb.Spec)
+				}
+			},
+		},
+		{
+			name:  "policy/v1beta1",
+			store: newStore("policy/v1beta1"),
+			args: args{
+				maxUnavailable: &maxUnavailable,
+			},
+			validateFunc: func(t *testing.T, store *dependencies.Store) {
+				obj, found := store.Get(kubernetes.PodDisruptionBudgetsKind, ns, name)
+				if !found {
+					t.Fatalf("missing PodDisruptionBudget %s/%s", ns, name)
+				}
+				pdb, ok := obj.(*policyv1beta1.PodDisruptionBudget)
+				if !ok {
+					t.Fatalf("PodDisruptionBudget %s/%s is a %T, want a policy/v1beta1 PodDisruptionBudget", ns, name, obj)
+				}
+				if pdb.Spec.MinAvailable != nil || pdb.Spec.MaxUnavailable.String() != "50%" {
+					t.Errorf("wrong spec in PodDisruptionBudget %s/%s: %v", ns, name, pdb.Spec)
+				}
+			},
+		},
+		{
+			name:  "update existing PodDisruptionBudget",
+			store: newStore("policy/v1").AddOrUpdateStore(kubernetes.PodDisruptionBudgetsKind, existingPDB),
+			args: args{
+				maxUnavailable: &maxUnavailable,
+			},
+			validateFunc: func(t *testing.T, store *dependencies.Store) {
+				obj, _ := store.Get(kubernetes.PodDisruptionBudgetsKind, ns, name)
+				pdb := obj.(*policyv1.PodDisruptionBudget)
+				if pdb.Spec.MinAvailable != nil || pdb.Spec.MaxUnavailable.String() != "50%" {
+					t.Errorf("wrong spec in PodDisruptionBudget %s/%s: %v", ns, name, pdb.Spec)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &podDisruptionBudgetManagerImpl{
+				store: tt.store,
+			}
+			if err := m.AddPodDisruptionBudget(name, ns, selector, tt.args.minAvailable, tt.args.maxUnavailable); (err != nil) != tt.wantErr {
+				t.Errorf("PodDisruptionBudgetManager.AddPodDisruptionBudget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.validateFunc != nil {
+				tt.validateFunc(t, tt.store)
+			}
+		})
+	}
+}
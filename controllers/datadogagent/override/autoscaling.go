@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+const (
+	defaultAutoscalingMinReplicas                    int32 = 1
+	defaultAutoscalingTargetCPUUtilizationPercentage int32 = 80
+)
+
+// IsAutoscalingEnabled returns true if the HorizontalPodAutoscaler of a component is enabled in its override.
+func IsAutoscalingEnabled(override *v2alpha1.DatadogAgentComponentOverride) bool {
+	return override != nil && override.Autoscaling != nil && apiutils.BoolValue(override.Autoscaling.Enabled)
+}
+
+// HorizontalPodAutoscaler adds to the store the HorizontalPodAutoscaler of a component Deployment,
+// if it is enabled in the component override.
+// When it is not added to the store, an existing HorizontalPodAutoscaler is removed by the store cleanup.
+func HorizontalPodAutoscaler(manager feature.ResourceManagers, deployment *appsv1.Deployment, override *v2alpha1.DatadogAgentComponentOverride) error {
+	if !IsAutoscalingEnabled(override) {
+		return nil
+	}
+	config := override.Autoscaling
+	if config.MaxReplicas == nil {
+		return fmt.Errorf("autoscaling.maxReplicas must be set to autoscale the Deployment %s", deployment.Name)
+	}
+
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       deployment.Name,
+		},
+		MinReplicas: apiutils.NewInt32Pointer(autoscalingMinReplicas(config)),
+		MaxReplicas: *config.MaxReplicas,
+		Metrics:     autoscalingMetrics(config),
+	}
+
+	return manager.HorizontalPodAutoscalerManager().AddHorizontalPodAutoscaler(deployment.Name, deployment.Namespace, spec)
+}
+
+func autoscalingMinReplicas(config *v2alpha1.AutoscalingConfig) int32 {
+	if config.MinReplicas != nil {
+		return *config.MinReplicas
+	}
+	return defaultAutoscalingMinReplicas
+}
+
+// autoscalingMetrics returns the metrics targets of the HorizontalPodAutoscaler.
+// The CPU utilization is targeted by default, like the HorizontalPodAutoscaler does when no metric is set.
+func autoscalingMetrics(config *v2alpha1.AutoscalingConfig) []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+
+	targetCPU := config.TargetCPUUtilizationPercentage
+	if targetCPU == nil && config.TargetMemoryUtilizationPercentage == nil && config.ExternalMetric == nil {
+		targetCPU = apiutils.NewInt32Pointer(defaultAutoscalingTargetCPUUtilizationPercentage)
+	}
+	if targetCPU != nil {
+		metrics = append(metrics, resourceUtilizationMetric(corev1.ResourceCPU, *targetCPU))
+	}
+	if config.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceUtilizationMetric(corev1.ResourceMemory, *config.TargetMemoryUtilizationPercentage))
+	}
+	if externalMetric := config.ExternalMetric; externalMetric != nil {
+		targetAverageValue := externalMetric.TargetAverageValue.DeepCopy()
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name:     externalMetric.Name,
+					Selector: externalMetric.Selector.DeepCopy(),
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &targetAverageValue,
+				},
+			},
+		})
+	}
+
+	return metrics
+}
+
+func resourceUtilizationMetric(name corev1.ResourceName, targetPercentage int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: apiutils.NewInt32Pointer(targetPercentage),
+			},
+		},
+	}
+}
@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	componentccr "github.com/DataDog/datadog-operator/controllers/datadogagent/component/clusterchecksrunner"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestHorizontalPodAutoscaler(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	targetAverageValue := resource.MustParse("20")
+	platformInfo := kubernetes.NewPlatformInfoFromVersionMaps(&version.Info{GitVersion: "v1.24.0"}, map[string]string{}, map[string]string{})
+
+	tests := []struct {
+		name        string
+		override    *v2alpha1.DatadogAgentComponentOverride
+		wantHPA     bool
+		wantMin     int32
+		wantMax     int32
+		wantMetrics []autoscalingv2.MetricSpec
+	}{
+		{
+			name:    "no override",
+			wantHPA: false,
+		},
+		{
+			name: "autoscaling disabled",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				Autoscaling: &v2alpha1.AutoscalingConfig{Enabled: apiutils.NewBoolPointer(false), MaxReplicas: apiutils.NewInt32Pointer(3)},
+			},
+			wantHPA: false,
+		},
+		{
+			name: "default CPU target",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				Autoscaling: &v2alpha1.AutoscalingConfig{Enabled: apiutils.NewBoolPointer(true), MaxReplicas: apiutils.NewInt32Pointer(3)},
+			},
+			wantHPA:     true,
+			wantMin:     1,
+			wantMax:     3,
+			wantMetrics: []autoscalingv2.MetricSpec{resourceUtilizationMetric(corev1.ResourceCPU, 80)},
+		},
+		{
+			name: "memory and external metric targets",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				Autoscaling: &v2alpha1.AutoscalingConfig{
+					Enabled:                           apiutils.NewBoolPointer(true),
+					MinReplicas:                       apiutils.NewInt32Pointer(2),
+					MaxReplicas:                       apiutils.NewInt32Pointer(10),
+					TargetMemoryUtilizationPercentage: apiutils.NewInt32Pointer(70),
+					ExternalMetric: &v2alpha1.AutoscalingExternalMetricConfig{
+						Name:               "datadogmetric@bar:checks",
+						TargetAverageValue: targetAverageValue,
+					},
+				},
+			},
+			wantHPA: true,
+			wantMin: 2,
+			wantMax: 10,
+			wantMetrics: []autoscalingv2.MetricSpec{
+				resourceUtilizationMetric(corev1.ResourceMemory, 70),
+				{
+					Type: autoscalingv2.ExternalMetricSourceType,
+					External: &autoscalingv2.ExternalMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{Name: "datadogmetric@bar:checks"},
+						Target: autoscalingv2.MetricTarget{
+							Type:         autoscalingv2.AverageValueMetricType,
+							AverageValue: &targetAverageValue,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := dependencies.NewStore(dda, &dependencies.StoreOptions{Scheme: testScheme, PlatformInfo: platformInfo})
+			manager := feature.NewResourceManagers(store)
+			deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
+
+			require.NoError(t, HorizontalPodAutoscaler(manager, deployment, tt.override))
+
+			obj, found := store.Get(kubernetes.HorizontalPodAutoscalersKind, deployment.Namespace, deployment.Name)
+			require.Equal(t, tt.wantHPA, found)
+			if !tt.wantHPA {
+				return
+			}
+			hpa := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+			assert.Equal(t, autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: deployment.Name}, hpa.Spec.ScaleTargetRef)
+			assert.Equal(t, tt.wantMin, *hpa.Spec.MinReplicas)
+			assert.Equal(t, tt.wantMax, hpa.Spec.MaxReplicas)
+			assert.Equal(t, tt.wantMetrics, hpa.Spec.Metrics)
+		})
+	}
+}
@@ -16,6 +16,10 @@ func DaemonSet(daemonSet *v1.DaemonSet, override *v2alpha1.DatadogAgentComponent
 	if override.Name != nil {
 		daemonSet.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		daemonSet.Spec.UpdateStrategy = daemonSetUpdateStrategy(override.UpdateStrategy)
+	}
 }
 
 // ExtendedDaemonSet overrides an ExtendedDaemonSet according to the given override options
@@ -23,4 +27,105 @@ func ExtendedDaemonSet(eds *edsv1alpha1.ExtendedDaemonSet, override *v2alpha1.Da
 	if override.Name != nil {
 		eds.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil && override.UpdateStrategy.RollingUpdate != nil {
+		extendedDaemonSetRollingUpdate(&eds.Spec.Strategy.RollingUpdate, override.UpdateStrategy.RollingUpdate)
+	}
+	if eds.Spec.Strategy.Canary != nil {
+		ExtendedDaemonSetCanary(eds.Spec.Strategy.Canary, override)
+	}
+}
+
+// ExtendedDaemonSetCanary overrides the fields of a canary strategy that are set in the override options.
+// The other fields keep their value, which comes from the operator options.
+func ExtendedDaemonSetCanary(canary *edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary, override *v2alpha1.DatadogAgentComponentOverride) {
+	if override.UpdateStrategy == nil || override.UpdateStrategy.Canary == nil {
+		return
+	}
+	overrideCanary := override.UpdateStrategy.Canary.DeepCopy()
+
+	if overrideCanary.Replicas != nil {
+		canary.Replicas = overrideCanary.Replicas
+	}
+	if overrideCanary.Duration != nil {
+		canary.Duration = overrideCanary.Duration
+	}
+	if overrideCanary.NoRestartsDuration != nil {
+		canary.NoRestartsDuration = overrideCanary.NoRestartsDuration
+	}
+	if overrideCanary.NodeSelector != nil {
+		canary.NodeSelector = overrideCanary.NodeSelector
+	}
+	if overrideCanary.NodeAntiAffinityKeys != nil {
+		canary.NodeAntiAffinityKeys = overrideCanary.NodeAntiAffinityKeys
+	}
+	if overrideCanary.ValidationMode != "" {
+		canary.ValidationMode = overrideCanary.ValidationMode
+	}
+
+	if overrideCanary.AutoPause != nil {
+		if canary.AutoPause == nil {
+			canary.AutoPause = &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryAutoPause{}
+		}
+		if overrideCanary.AutoPause.Enabled != nil {
+			canary.AutoPause.Enabled = overrideCanary.AutoPause.Enabled
+		}
+		if overrideCanary.AutoPause.MaxRestarts != nil {
+			canary.AutoPause.MaxRestarts = overrideCanary.AutoPause.MaxRestarts
+		}
+		if overrideCanary.AutoPause.MaxSlowStartDuration != nil {
+			canary.AutoPause.MaxSlowStartDuration = overrideCanary.AutoPause.MaxSlowStartDuration
+		}
+	}
+
+	if overrideCanary.AutoFail != nil {
+		if canary.AutoFail == nil {
+			canary.AutoFail = &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryAutoFail{}
+		}
+		if overrideCanary.AutoFail.Enabled != nil {
+			canary.AutoFail.Enabled = overrideCanary.AutoFail.Enabled
+		}
+		if overrideCanary.AutoFail.MaxRestarts != nil {
+			canary.AutoFail.MaxRestarts = overrideCanary.AutoFail.MaxRestarts
+		}
+		if overrideCanary.AutoFail.MaxRestartsDuration != nil {
+			canary.AutoFail.MaxRestartsDuration = overrideCanary.AutoFail.MaxRestartsDuration
+		}
+		if overrideCanary.AutoFail.CanaryTimeout != nil {
+			canary.AutoFail.CanaryTimeout = overrideCanary.AutoFail.CanaryTimeout
+		}
+	}
+}
+
+func daemonSetUpdateStrategy(strategy *v2alpha1.UpdateStrategy) v1.DaemonSetUpdateStrategy {
+	if strategy.Type == v2alpha1.OnDeleteStrategyType {
+		return v1.DaemonSetUpdateStrategy{Type: v1.OnDeleteDaemonSetStrategyType}
+	}
+
+	updateStrategy := v1.DaemonSetUpdateStrategy{Type: v1.RollingUpdateDaemonSetStrategyType}
+	if strategy.RollingUpdate != nil {
+		updateStrategy.RollingUpdate = &v1.RollingUpdateDaemonSet{
+			MaxUnavailable: strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       strategy.RollingUpdate.MaxSurge,
+		}
+	}
+	return updateStrategy
+}
+
+func extendedDaemonSetRollingUpdate(rollingUpdate *edsv1alpha1.ExtendedDaemonSetSpecStrategyRollingUpdate, override *v2alpha1.RollingUpdate) {
+	if override.MaxUnavailable != nil {
+		rollingUpdate.MaxUnavailable = override.MaxUnavailable
+	}
+	if override.MaxPodSchedulerFailure != nil {
+		rollingUpdate.MaxPodSchedulerFailure = override.MaxPodSchedulerFailure
+	}
+	if override.MaxParallelPodCreation != nil {
+		rollingUpdate.MaxParallelPodCreation = override.MaxParallelPodCreation
+	}
+	if override.SlowStartIntervalDuration != nil {
+		rollingUpdate.SlowStartIntervalDuration = override.SlowStartIntervalDuration
+	}
+	if override.SlowStartAdditiveIncrease != nil {
+		rollingUpdate.SlowStartAdditiveIncrease = override.SlowStartAdditiveIncrease
+	}
 }
@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDaemonSet(t *testing.T) {
+	daemonSet := v1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "current-name",
+		},
+	}
+	maxUnavailable := intstr.FromString("10%")
+	maxSurge := intstr.FromInt(1)
+
+	DaemonSet(&daemonSet, &v2alpha1.DatadogAgentComponentOverride{
+		Name: apiutils.NewStringPointer("new-name"),
+		UpdateStrategy: &v2alpha1.UpdateStrategy{
+			RollingUpdate: &v2alpha1.RollingUpdate{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge},
+		},
+	})
+	assert.Equal(t, "new-name", daemonSet.Name)
+	assert.Equal(t, v1.DaemonSetUpdateStrategy{
+		Type:          v1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &v1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge},
+	}, daemonSet.Spec.UpdateStrategy)
+
+	DaemonSet(&daemonSet, &v2alpha1.DatadogAgentComponentOverride{
+		UpdateStrategy: &v2alpha1.UpdateStrategy{Type: v2alpha1.OnDeleteStrategyType},
+	})
+	assert.Equal(t, v1.DaemonSetUpdateStrategy{Type: v1.OnDeleteDaemonSetStrategyType}, daemonSet.Spec.UpdateStrategy)
+}
+
+func TestExtendedDaemonSet(t *testing.T) {
+	defaultMaxUnavailable := intstr.FromInt(1)
+	defaultDuration := metav1.Duration{Duration: 10 * time.Minute}
+	eds := edsv1alpha1.ExtendedDaemonSet{
+		Spec: edsv1alpha1.ExtendedDaemonSetSpec{
+			Strategy: edsv1alpha1.ExtendedDaemonSetSpecStrategy{
+				RollingUpdate: edsv1alpha1.ExtendedDaemonSetSpecStrategyRollingUpdate{
+					MaxUnavailable:         &defaultMaxUnavailable,
+					MaxParallelPodCreation: apiutils.NewInt32Pointer(250),
+				},
+				Canary: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{
+					Duration: &defaultDuration,
+					AutoPause: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryAutoPause{
+						Enabled:     apiutils.NewBoolPointer(true),
+						MaxRestarts: apiutils.NewInt32Pointer(2),
+					},
+				},
+			},
+		},
+	}
+	slowStart := metav1.Duration{Duration: time.Minute}
+
+	ExtendedDaemonSet(&eds, &v2alpha1.DatadogAgentComponentOverride{
+		UpdateStrategy: &v2alpha1.UpdateStrategy{
+			RollingUpdate: &v2alpha1.RollingUpdate{
+				MaxParallelPodCreation:    apiutils.NewInt32Pointer(10),
+				SlowStartIntervalDuration: &slowStart,
+			},
+			Canary: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{
+				AutoPause: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryAutoPause{
+					MaxRestarts: apiutils.NewInt32Pointer(4),
+				},
+			},
+		},
+	})
+
+	// The fields that are not overridden keep the values of the operator options
+	assert.Equal(t, edsv1alpha1.ExtendedDaemonSetSpecStrategyRollingUpdate{
+		MaxUnavailable:            &defaultMaxUnavailable,
+		MaxParallelPodCreation:    apiutils.NewInt32Pointer(10),
+		SlowStartIntervalDuration: &slowStart,
+	}, eds.Spec.Strategy.RollingUpdate)
+	assert.Equal(t, &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{
+		Duration: &defaultDuration,
+		AutoPause: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryAutoPause{
+			Enabled:     apiutils.NewBoolPointer(true),
+			MaxRestarts: apiutils.NewInt32Pointer(4),
+		},
+	}, eds.Spec.Strategy.Canary)
+}
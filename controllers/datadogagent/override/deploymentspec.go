@@ -7,6 +7,7 @@ package override
 
 import (
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
 	v1 "k8s.io/api/apps/v1"
 )
 
@@ -16,7 +17,31 @@ func Deployment(deployment *v1.Deployment, override *v2alpha1.DatadogAgentCompon
 		deployment.Spec.Replicas = override.Replicas
 	}
 
+	// The replicas are managed by the HorizontalPodAutoscaler: they are only set when the Deployment is created.
+	if IsAutoscalingEnabled(override) {
+		deployment.Spec.Replicas = apiutils.NewInt32Pointer(autoscalingMinReplicas(override.Autoscaling))
+	}
+
 	if override.Name != nil {
 		deployment.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		deployment.Spec.Strategy = deploymentStrategy(override.UpdateStrategy)
+	}
+}
+
+func deploymentStrategy(strategy *v2alpha1.UpdateStrategy) v1.DeploymentStrategy {
+	if strategy.Type == v2alpha1.RecreateStrategyType {
+		return v1.DeploymentStrategy{Type: v1.RecreateDeploymentStrategyType}
+	}
+
+	deploymentStrategy := v1.DeploymentStrategy{Type: v1.RollingUpdateDeploymentStrategyType}
+	if strategy.RollingUpdate != nil {
+		deploymentStrategy.RollingUpdate = &v1.RollingUpdateDeployment{
+			MaxUnavailable: strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       strategy.RollingUpdate.MaxSurge,
+		}
+	}
+	return deploymentStrategy
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestDeployment(t *testing.T) {
@@ -35,3 +36,44 @@ func TestDeployment(t *testing.T) {
 	assert.Equal(t, "new-name", deployment.Name)
 	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
 }
+
+func TestDeploymentAutoscaling(t *testing.T) {
+	deployment := v1.Deployment{
+		Spec: v1.DeploymentSpec{
+			Replicas: apiutils.NewInt32Pointer(1),
+		},
+	}
+
+	override := v2alpha1.DatadogAgentComponentOverride{
+		Replicas: apiutils.NewInt32Pointer(5),
+		Autoscaling: &v2alpha1.AutoscalingConfig{
+			Enabled:     apiutils.NewBoolPointer(true),
+			MinReplicas: apiutils.NewInt32Pointer(2),
+			MaxReplicas: apiutils.NewInt32Pointer(10),
+		},
+	}
+
+	Deployment(&deployment, &override)
+
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas, "the initial replicas should be the autoscaling minReplicas")
+}
+
+func TestDeploymentUpdateStrategy(t *testing.T) {
+	deployment := v1.Deployment{}
+	maxSurge := intstr.FromString("50%")
+
+	Deployment(&deployment, &v2alpha1.DatadogAgentComponentOverride{
+		UpdateStrategy: &v2alpha1.UpdateStrategy{
+			RollingUpdate: &v2alpha1.RollingUpdate{MaxSurge: &maxSurge},
+		},
+	})
+	assert.Equal(t, v1.DeploymentStrategy{
+		Type:          v1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &v1.RollingUpdateDeployment{MaxSurge: &maxSurge},
+	}, deployment.Spec.Strategy)
+
+	Deployment(&deployment, &v2alpha1.DatadogAgentComponentOverride{
+		UpdateStrategy: &v2alpha1.UpdateStrategy{Type: v2alpha1.RecreateStrategyType},
+	})
+	assert.Equal(t, v1.DeploymentStrategy{Type: v1.RecreateDeploymentStrategyType}, deployment.Spec.Strategy)
+}
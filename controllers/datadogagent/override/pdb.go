@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+const defaultPDBMinAvailable = 1
+
+// PodDisruptionBudget adds to the store the PodDisruptionBudget of a component Deployment.
+// By default, it is only created if the Deployment has more than one replica, with a minAvailable of 1.
+// The component override can enable or disable it, and set its minAvailable or maxUnavailable.
+// When it is not added to the store, an existing PodDisruptionBudget is removed by the store cleanup.
+func PodDisruptionBudget(manager feature.ResourceManagers, deployment *appsv1.Deployment, override *v2alpha1.DatadogAgentComponentOverride) error {
+	var config *v2alpha1.PodDisruptionBudgetConfig
+	if override != nil {
+		config = override.PDB
+	}
+
+	enabled := deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 1
+	if config != nil && config.Enabled != nil {
+		enabled = apiutils.BoolValue(config.Enabled)
+	}
+	if !enabled {
+		return nil
+	}
+
+	var minAvailable, maxUnavailable *intstr.IntOrString
+	if config != nil {
+		minAvailable, maxUnavailable = config.MinAvailable, config.MaxUnavailable
+	}
+	if minAvailable == nil && maxUnavailable == nil {
+		defaultMinAvailable := intstr.FromInt(defaultPDBMinAvailable)
+		minAvailable = &defaultMinAvailable
+	}
+
+	return manager.PodDisruptionBudgetManager().AddPodDisruptionBudget(deployment.Name, deployment.Namespace, deployment.Spec.Selector.DeepCopy(), minAvailable, maxUnavailable)
+}
@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	componentdca "github.com/DataDog/datadog-operator/controllers/datadogagent/component/clusteragent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestPodDisruptionBudget(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	maxUnavailable := intstr.FromString("50%")
+
+	tests := []struct {
+		name               string
+		replicas           int32
+		override           *v2alpha1.DatadogAgentComponentOverride
+		wantPDB            bool
+		wantMinAvailable   *intstr.IntOrString
+		wantMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:     "single replica, no override",
+			replicas: 1,
+			wantPDB:  false,
+		},
+		{
+			name:             "several replicas, no override",
+			replicas:         2,
+			wantPDB:          true,
+			wantMinAvailable: &intstr.IntOrString{IntVal: 1},
+		},
+		{
+			name:     "several replicas, disabled by the override",
+			replicas: 2,
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				PDB: &v2alpha1.PodDisruptionBudgetConfig{Enabled: apiutils.NewBoolPointer(false)},
+			},
+			wantPDB: false,
+		},
+		{
+			name:     "single replica, enabled by the override with maxUnavailable",
+			replicas: 1,
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				PDB: &v2alpha1.PodDisruptionBudgetConfig{Enabled: apiutils.NewBoolPointer(true), MaxUnavailable: &maxUnavailable},
+			},
+			wantPDB:            true,
+			wantMaxUnavailable: &maxUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := dependencies.NewStore(dda, &dependencies.StoreOptions{Scheme: testScheme})
+			manager := feature.NewResourceManagers(store)
+			deployment := componentdca.NewDefaultClusterAgentDeployment(dda)
+			deployment.Spec.Replicas = apiutils.NewInt32Pointer(tt.replicas)
+
+			require.NoError(t, PodDisruptionBudget(manager, deployment, tt.override))
+
+			obj, found := store.Get(kubernetes.PodDisruptionBudgetsKind, deployment.Namespace, deployment.Name)
+			require.Equal(t, tt.wantPDB, found)
+			if !tt.wantPDB {
+				return
+			}
+			pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+			require.True(t, ok)
+			assert.Equal(t, deployment.Spec.Selector, pdb.Spec.Selector)
+			assert.Equal(t, tt.wantMinAvailable, pdb.Spec.MinAvailable)
+			assert.Equal(t, tt.wantMaxUnavailable, pdb.Spec.MaxUnavailable)
+		})
+	}
+}
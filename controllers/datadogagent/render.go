@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+// RenderOptions provides the information that the v2 reconciler gets from the
+// command line and from the api-server, so that objects can be rendered offline.
+type RenderOptions struct {
+	ExtendedDaemonsetOptions componentagent.ExtendedDaemonsetOptions
+	SupportCilium            bool
+	VersionInfo              *version.Info
+	PlatformInfo             kubernetes.PlatformInfo
+	Scheme                   *runtime.Scheme
+	Logger                   logr.Logger
+	// Extensions are the DatadogAgentExtensions of the DatadogAgent namespace
+	Extensions []datadoghqv1alpha1.DatadogAgentExtension
+}
+
+// RenderV2 returns every object that the v2 reconciler would create for a DatadogAgent, without any api-server access.
+// The workloads (Agent DaemonSet or ExtendedDaemonSet, Cluster Agent and Cluster Checks Runner Deployments) come first,
+// followed by the dependencies added to the store, sorted by kind, namespace and name.
+// The DatadogAgent is defaulted on a copy; the instance passed as argument is not modified.
+func RenderV2(dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions) ([]client.Object, error) {
+	workloads, depsStore, err := renderV2(dda, options)
+	if err != nil {
+		return nil, err
+	}
+	return append(workloads, depsStore.GetAll()...), nil
+}
+
+// renderV2 returns the workloads of a DatadogAgent and the store containing its dependencies.
+func renderV2(dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions) ([]client.Object, *dependencies.Store, error) {
+	if dda.Spec.Global == nil || dda.Spec.Global.Credentials == nil {
+		return nil, nil, fmt.Errorf("credentials not configured in the DatadogAgent, can't render")
+	}
+
+	instance := dda.DeepCopy()
+	datadoghqv2alpha1.DefaultDatadogAgent(instance)
+
+	logger := options.Logger
+	reconcilerOptions := &ReconcilerOptions{
+		ExtendedDaemonsetOptions: options.ExtendedDaemonsetOptions,
+		SupportCilium:            options.SupportCilium,
+		V2Enabled:                true,
+	}
+	features, _, requiredComponents, errs := buildFeatures(instance, options.Extensions, reconcilerOptionsToFeatureOptions(reconcilerOptions, logger))
+	if len(errs) > 0 {
+		return nil, nil, errors.NewAggregate(errs)
+	}
+
+	keepAnnotationsFilter, keepLabelsFilter := globalKeepAnnotationsLabelsFilters(instance)
+	storeOptions := &dependencies.StoreOptions{
+		SupportCilium:         options.SupportCilium,
+		VersionInfo:           options.VersionInfo,
+		PlatformInfo:          options.PlatformInfo,
+		Logger:                logger,
+		Scheme:                options.Scheme,
+		KeepAnnotationsFilter: keepAnnotationsFilter,
+		KeepLabelsFilter:      keepLabelsFilter,
+	}
+	depsStore := dependencies.NewStore(instance, storeOptions)
+	resourceManagers := feature.NewResourceManagers(depsStore)
+
+	for _, feat := range features {
+		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
+			errs = append(errs, featErr)
+		}
+	}
+	errs = append(errs, override.Dependencies(logger, resourceManagers, instance)...)
+	if len(errs) > 0 {
+		return nil, nil, errors.NewAggregate(errs)
+	}
+
+	// The components are built in the same order as in reconcileInstanceV2, since building
+	// a component can also add dependencies to the store.
+	var objs []client.Object
+	if isV2ClusterAgentEnabled(requiredComponents, instance) {
+		deployment, err := buildV2ClusterAgentDeployment(logger, features, instance, resourceManagers)
+		if err != nil {
+			return nil, nil, err
+		}
+		componentOverride := instance.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]
+		if err = override.PodDisruptionBudget(resourceManagers, deployment, componentOverride); err != nil {
+			return nil, nil, err
+		}
+		if err = override.HorizontalPodAutoscaler(resourceManagers, deployment, componentOverride); err != nil {
+			return nil, nil, err
+		}
+		objs = append(objs, deployment)
+	}
+
+	if isV2AgentEnabled(instance) {
+		if options.ExtendedDaemonsetOptions.Enabled {
+			eds, err := buildV2AgentExtendedDaemonSet(logger, features, instance, resourceManagers, &reconcilerOptions.ExtendedDaemonsetOptions, requiredComponents.Agent.Containers, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			objs = append(objs, eds)
+		} else {
+			daemonset, err := buildV2AgentDaemonSet(logger, features, instance, resourceManagers, requiredComponents.Agent.Containers, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			objs = append(objs, daemonset)
+		}
+		for i := range instance.Spec.Profiles {
+			profile := &instance.Spec.Profiles[i]
+			if !isV2AgentProfileEnabled(profile) {
+				continue
+			}
+			if options.ExtendedDaemonsetOptions.Enabled {
+				eds, err := buildV2AgentExtendedDaemonSet(logger, features, instance, resourceManagers, &reconcilerOptions.ExtendedDaemonsetOptions, requiredComponents.Agent.Containers, profile)
+				if err != nil {
+					return nil, nil, err
+				}
+				objs = append(objs, eds)
+			} else {
+				daemonset, err := buildV2AgentDaemonSet(logger, features, instance, resourceManagers, requiredComponents.Agent.Containers, profile)
+				if err != nil {
+					return nil, nil, err
+				}
+				objs = append(objs, daemonset)
+			}
+		}
+	}
+
+	if isV2ClusterChecksRunnerEnabled(requiredComponents, instance) {
+		deployment, err := buildV2ClusterChecksRunnerDeployment(logger, features, instance, resourceManagers)
+		if err != nil {
+			return nil, nil, err
+		}
+		componentOverride := instance.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]
+		if err = override.PodDisruptionBudget(resourceManagers, deployment, componentOverride); err != nil {
+			return nil, nil, err
+		}
+		if err = override.HorizontalPodAutoscaler(resourceManagers, deployment, componentOverride); err != nil {
+			return nil, nil, err
+		}
+		objs = append(objs, deployment)
+	}
+
+	// Set the owner reference and the spec hash annotation like createOrUpdateDeployment,
+	// createOrUpdateDaemonset and createOrUpdateExtendedDaemonset do.
+	for _, obj := range objs {
+		if err := controllerutil.SetControllerReference(instance, obj, options.Scheme); err != nil {
+			return nil, nil, err
+		}
+		if err := setMD5WorkloadAnnotation(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return objs, depsStore, nil
+}
+
+func setMD5WorkloadAnnotation(obj client.Object) error {
+	var err error
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	case *appsv1.DaemonSet:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	case *edsv1alpha1.ExtendedDaemonSet:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+func TestRenderV2(t *testing.T) {
+	const ns, name = "bar", "foo"
+
+	tests := []struct {
+		name        string
+		dda         func() *v2alpha1.DatadogAgent
+		edsEnabled  bool
+		wantErr     bool
+		wantWorkers []string
+	}{
+		{
+			name: "no credentials",
+			dda: func() *v2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent(ns, name, nil)
+				dda.Spec.Global.Credentials = nil
+				return dda
+			},
+			wantErr: true,
+		},
+		{
+			name: "default DatadogAgent",
+			dda: func() *v2alpha1.DatadogAgent {
+				return v2alpha1test.NewDatadogAgent(ns, name, nil)
+			},
+			wantWorkers: []string{"Deployment/foo-cluster-agent", "DaemonSet/foo-agent"},
+		},
+		{
+			name: "ExtendedDaemonSet enabled",
+			dda: func() *v2alpha1.DatadogAgent {
+				return v2alpha1test.NewDatadogAgent(ns, name, nil)
+			},
+			edsEnabled:  true,
+			wantWorkers: []string{"Deployment/foo-cluster-agent", "ExtendedDaemonSet/foo-agent"},
+		},
+		{
+			name: "cluster agent disabled by override",
+			dda: func() *v2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent(ns, name, nil)
+				dda.Spec.Override = map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+					v2alpha1.ClusterAgentComponentName: {Disabled: apiutils.NewBoolPointer(true)},
+				}
+				return dda
+			},
+			wantWorkers: []string{"DaemonSet/foo-agent"},
+		},
+		{
+			name: "cluster checks runner enabled",
+			dda: func() *v2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent(ns, name, nil)
+				dda.Spec.Features = &v2alpha1.DatadogFeatures{
+					ClusterChecks: &v2alpha1.ClusterChecksFeatureConfig{
+						Enabled:                 apiutils.NewBoolPointer(true),
+						UseClusterChecksRunners: apiutils.NewBoolPointer(true),
+					},
+				}
+				return dda
+			},
+			wantWorkers: []string{"Deployment/foo-cluster-agent", "DaemonSet/foo-agent", "Deployment/foo-cluster-checks-runner"},
+		},
+		{
+			name: "node Agent profiles",
+			dda: func() *v2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent(ns, name, nil)
+				dda.Spec.Profiles = []v2alpha1.AgentProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{Name: "windows", NodeSelector: map[string]string{"os": "windows"}, Override: &v2alpha1.DatadogAgentComponentOverride{Disabled: apiutils.NewBoolPointer(true)}},
+				}
+				return dda
+			},
+			edsEnabled:  true,
+			wantWorkers: []string{"Deployment/foo-cluster-agent", "ExtendedDaemonSet/foo-agent", "ExtendedDaemonSet/foo-agent-gpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := tt.dda()
+			original := dda.DeepCopy()
+			options := &RenderOptions{
+				ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+					Enabled: tt.edsEnabled,
+				},
+				VersionInfo:  &version.Info{GitVersion: "v1.25.0"},
+				PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{}, map[string]string{}),
+				Scheme:       testutils.TestScheme(true),
+				Logger:       logf.Log.WithName(t.Name()),
+			}
+
+			objs, err := RenderV2(dda, options)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The DatadogAgent passed as argument must not be defaulted
+			assert.Equal(t, original, dda)
+
+			var workers []string
+			var foundDCASecret bool
+			for _, obj := range objs {
+				switch obj.(type) {
+				case *appsv1.Deployment:
+					workers = append(workers, "Deployment/"+obj.GetName())
+				case *appsv1.DaemonSet:
+					workers = append(workers, "DaemonSet/"+obj.GetName())
+				case *edsv1alpha1.ExtendedDaemonSet:
+					workers = append(workers, "ExtendedDaemonSet/"+obj.GetName())
+				case *corev1.Secret:
+					foundDCASecret = foundDCASecret || obj.GetName() == name+"-token"
+					continue
+				default:
+					continue
+				}
+				assert.Contains(t, obj.GetAnnotations(), apicommon.MD5AgentDeploymentAnnotationKey)
+				assert.Len(t, obj.GetOwnerReferences(), 1)
+			}
+			assert.Equal(t, tt.wantWorkers, workers)
+			assert.True(t, foundDCASecret, "the token Secret created by the store should be rendered")
+			assertWorkloadsFirst(t, objs, len(tt.wantWorkers))
+		})
+	}
+}
+
+func assertWorkloadsFirst(t *testing.T, objs []client.Object, nbWorkloads int) {
+	for i, obj := range objs {
+		switch obj.(type) {
+		case *appsv1.Deployment, *appsv1.DaemonSet, *edsv1alpha1.ExtendedDaemonSet:
+			assert.Less(t, i, nbWorkloads, "workloads should be rendered before the dependencies")
+		}
+	}
+}
@@ -38,7 +38,7 @@ func TestScheme(isV2 bool) *runtime.Scheme {
 	s.AddKnownTypes(networkingv1.SchemeGroupVersion, &networkingv1.NetworkPolicy{})
 
 	if isV2 {
-		s.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+		s.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{}, &v2alpha1.DatadogAgentList{})
 	} else {
 		s.AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.DatadogAgent{})
 	}
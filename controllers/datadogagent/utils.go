@@ -28,7 +28,6 @@ import (
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	"github.com/go-logr/logr"
-	"github.com/gobwas/glob"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1905,33 +1904,6 @@ func getClusterChecksRunnerServiceAccount(dda *datadoghqv1alpha1.DatadogAgent) s
 	return saDefault
 }
 
-func mergeAnnotationsLabels(logger logr.Logger, previousVal map[string]string, newVal map[string]string, filter string) map[string]string {
-	var globFilter glob.Glob
-	var err error
-	if filter != "" {
-		globFilter, err = glob.Compile(filter)
-		if err != nil {
-			logger.Error(err, "Unable to parse glob filter for metadata/annotations - discarding everything", "filter", filter)
-		}
-	}
-
-	mergedMap := make(map[string]string, len(newVal))
-	for k, v := range newVal {
-		mergedMap[k] = v
-	}
-
-	// Copy from previous if not in new match and matches globfilter
-	for k, v := range previousVal {
-		if _, found := newVal[k]; !found {
-			if (globFilter != nil && globFilter.Match(k)) || strings.Contains(k, "datadoghq.com") {
-				mergedMap[k] = v
-			}
-		}
-	}
-
-	return mergedMap
-}
-
 // GetConfName get the name of the Configmap for a CustomConfigSpec
 func GetConfName(dca metav1.Object, conf *datadoghqv1alpha1.CustomConfigSpec, defaultName string) string {
 	// `configData` and `configMap` can't be set together.
@@ -2284,14 +2256,15 @@ func addBoolPointerEnVar(b *bool, varName string, varList []corev1.EnvVar) []cor
 
 // getReplicas returns the desired replicas of a
 // deployment based on the current and new replica values.
-func getReplicas(currentReplicas, newReplicas *int32) *int32 {
-	if newReplicas == nil {
-		if currentReplicas != nil {
-			// Do not overwrite the current value
-			// It's most likely managed by an autoscaler
-			return apiutils.NewInt32Pointer(*currentReplicas)
-		}
+// If the deployment is autoscaled, the new replicas are only used when the deployment is created.
+func getReplicas(currentReplicas, newReplicas *int32, autoscaled bool) *int32 {
+	if currentReplicas != nil && (newReplicas == nil || autoscaled) {
+		// Do not overwrite the current value
+		// It's most likely managed by an autoscaler
+		return apiutils.NewInt32Pointer(*currentReplicas)
+	}
 
+	if newReplicas == nil {
 		// Both new and current are nil
 		return nil
 	}
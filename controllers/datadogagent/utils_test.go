@@ -325,73 +325,13 @@ func Test_dsdMapperProfilesEnvVar(t *testing.T) {
 	}
 }
 
-func Test_mergeAnnotationsLabels(t *testing.T) {
-	type args struct {
-		previousVal map[string]string
-		newVal      map[string]string
-		filter      string
-	}
-	tests := []struct {
-		name string
-		args args
-		want map[string]string
-	}{
-		{
-			name: "basic test",
-			args: args{
-				previousVal: map[string]string{
-					"foo":               "bar",
-					"foo-datadoghq.com": "dog-bar",
-					"foo-removed":       "foo",
-					"foo.match":         "foomatch",
-				},
-				newVal: map[string]string{
-					"foo": "baz",
-				},
-				filter: "*.match",
-			},
-			want: map[string]string{
-				"foo":               "baz",
-				"foo-datadoghq.com": "dog-bar",
-				"foo.match":         "foomatch",
-			},
-		},
-		{
-			name: "no filter test",
-			args: args{
-				previousVal: map[string]string{
-					"foo":               "bar",
-					"foo-datadoghq.com": "dog-bar",
-					"foo-removed":       "foo",
-					"foo.match":         "foomatch",
-				},
-				newVal: map[string]string{
-					"foo": "baz",
-				},
-			},
-			want: map[string]string{
-				"foo":               "baz",
-				"foo-datadoghq.com": "dog-bar",
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			logger := logf.Log.WithName(t.Name())
-			got := mergeAnnotationsLabels(logger, tt.args.previousVal, tt.args.newVal, tt.args.filter)
-			diff := cmp.Diff(tt.want, got)
-			assert.Empty(t, diff)
-		})
-	}
-}
-
 func Test_getReplicas(t *testing.T) {
 	tests := []struct {
-		name    string
-		current *int32
-		new     *int32
-		want    *int32
+		name       string
+		current    *int32
+		new        *int32
+		autoscaled bool
+		want       *int32
 	}{
 		{
 			name:    "both not nil",
@@ -417,10 +357,24 @@ func Test_getReplicas(t *testing.T) {
 			new:     nil,
 			want:    nil,
 		},
+		{
+			name:       "autoscaled, both not nil",
+			current:    apiutils.NewInt32Pointer(5),
+			new:        apiutils.NewInt32Pointer(2),
+			autoscaled: true,
+			want:       apiutils.NewInt32Pointer(5),
+		},
+		{
+			name:       "autoscaled, current is nil",
+			current:    nil,
+			new:        apiutils.NewInt32Pointer(2),
+			autoscaled: true,
+			want:       apiutils.NewInt32Pointer(2),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getReplicas(tt.current, tt.new)
+			got := getReplicas(tt.current, tt.new, tt.autoscaled)
 			assert.Equal(t, tt.want, got)
 			if got != nil {
 				// Assert the result's address and
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+// IsValidV2RequiredComponents returns an error if a component override disables a component
+// that one of the enabled features requires. The reconciler only reports this case with
+// the OverrideReconcileConflict condition; the validating webhook rejects it.
+// The components required by the default features, like the node Agent and the Cluster Agent,
+// aren't checked: disabling them with an override is supported.
+// The DatadogAgent is expected to be defaulted.
+func IsValidV2RequiredComponents(dda *datadoghqv2alpha1.DatadogAgent) error {
+	featureOptions := &feature.Options{Logger: logr.Discard()}
+	_, requiredComponents := feature.BuildFeatures(dda, featureOptions)
+
+	defaultFeatures := dda.DeepCopy()
+	defaultFeatures.Spec.Features = nil
+	datadoghqv2alpha1.DefaultDatadogAgent(defaultFeatures)
+	_, defaultRequiredComponents := feature.BuildFeatures(defaultFeatures, featureOptions)
+
+	components := []struct {
+		name            datadoghqv2alpha1.ComponentName
+		required        feature.RequiredComponent
+		defaultRequired feature.RequiredComponent
+	}{
+		{name: datadoghqv2alpha1.NodeAgentComponentName, required: requiredComponents.Agent, defaultRequired: defaultRequiredComponents.Agent},
+		{name: datadoghqv2alpha1.ClusterAgentComponentName, required: requiredComponents.ClusterAgent, defaultRequired: defaultRequiredComponents.ClusterAgent},
+		{name: datadoghqv2alpha1.ClusterChecksRunnerComponentName, required: requiredComponents.ClusterChecksRunner, defaultRequired: defaultRequiredComponents.ClusterChecksRunner},
+	}
+
+	var errs []error
+	for _, component := range components {
+		componentOverride, ok := dda.Spec.Override[component.name]
+		if !ok || componentOverride == nil || !apiutils.BoolValue(componentOverride.Disabled) {
+			continue
+		}
+		if component.required.IsEnabled() && !component.defaultRequired.IsEnabled() {
+			errs = append(errs, fmt.Errorf("spec.override.%s.disabled can't be set: the component is required by the enabled features", component.name))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
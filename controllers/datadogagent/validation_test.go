@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestIsValidV2RequiredComponents(t *testing.T) {
+	disabled := &v2alpha1.DatadogAgentComponentOverride{Disabled: apiutils.NewBoolPointer(true)}
+
+	tests := []struct {
+		name     string
+		override map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride
+		features *v2alpha1.DatadogFeatures
+		wantErr  string
+	}{
+		{
+			name: "no override",
+		},
+		{
+			name: "override without disabled",
+			override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterAgentComponentName: {Replicas: apiutils.NewInt32Pointer(2)},
+			},
+		},
+		{
+			name: "node agent disabled with the default features",
+			override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.NodeAgentComponentName: disabled,
+			},
+		},
+		{
+			name: "cluster agent disabled with the default features",
+			override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterAgentComponentName: disabled,
+			},
+		},
+		{
+			name: "cluster checks runner not required",
+			override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterChecksRunnerComponentName: disabled,
+			},
+		},
+		{
+			name: "cluster checks runner required by the cluster checks feature",
+			override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterChecksRunnerComponentName: disabled,
+			},
+			features: &v2alpha1.DatadogFeatures{
+				ClusterChecks: &v2alpha1.ClusterChecksFeatureConfig{
+					Enabled:                 apiutils.NewBoolPointer(true),
+					UseClusterChecksRunners: apiutils.NewBoolPointer(true),
+				},
+			},
+			wantErr: "spec.override.clusterChecksRunner.disabled can't be set: the component is required by the enabled features",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			dda.Spec.Override = tt.override
+			dda.Spec.Features = tt.features
+			v2alpha1.DefaultDatadogAgent(dda)
+
+			err := IsValidV2RequiredComponents(dda)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_setInvalidSpecCondition(t *testing.T) {
+	status := &v2alpha1.DatadogAgentStatus{}
+
+	// No condition is added for a valid spec
+	setInvalidSpecCondition(status, nil)
+	assert.Empty(t, status.Conditions)
+
+	setInvalidSpecCondition(status, errors.New("spec.global.credentials must be set"))
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, v2alpha1.InvalidSpecConditionType, status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, status.Conditions[0].Status)
+	assert.Equal(t, "spec.global.credentials must be set", status.Conditions[0].Message)
+
+	// The condition is cleared once the spec is fixed
+	setInvalidSpecCondition(status, nil)
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, status.Conditions[0].Status)
+}
@@ -14,9 +14,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -31,6 +34,7 @@ import (
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -40,6 +44,8 @@ import (
 // DatadogAgentReconciler reconciles a DatadogAgent object.
 type DatadogAgentReconciler struct {
 	client.Client
+	// APIReader reads the objects that are not cached by the manager, like the pods
+	APIReader    client.Reader
 	VersionInfo  *version.Info
 	PlatformInfo kubernetes.PlatformInfo
 	Log          logr.Logger
@@ -52,6 +58,7 @@ type DatadogAgentReconciler struct {
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagentextensions,verbs=get;list;watch
 
 // RBAC Management
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
@@ -113,6 +120,7 @@ type DatadogAgentReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
@@ -197,6 +205,34 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRole{}}, handlerEnqueue)
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRoleBinding{}}, handlerEnqueue)
 
+	if r.Options.V2Enabled {
+		// Watch every kind managed by the dependencies store, so that the changes done outside
+		// of the operator are reverted right away instead of at the next periodic reconcile.
+		storePredicate := ctrlbuilder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return dependencies.IsManagedByStore(obj)
+		}))
+		for _, kind := range r.PlatformInfo.GetAgentResourcesKind(r.Options.SupportCilium) {
+			builder.Watches(&source.Kind{Type: kubernetes.ObjectFromKind(kind, r.PlatformInfo)}, handlerEnqueue, storePredicate)
+		}
+
+		// Watch the Secrets and ConfigMaps created by the users, such as the credentials or the custom configurations,
+		// so that the pods referencing them are rolled out when they change. Only the DatadogAgents whose workloads
+		// reference them are enqueued. These watches share the informers of the owned Secrets and ConfigMaps, whose
+		// content is read from the cache to compute the checksum of the referenced configurations.
+		if err := r.indexReferencedConfigs(mgr); err != nil {
+			return err
+		}
+		referencedPredicate := ctrlbuilder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return !dependencies.IsManagedByStore(obj)
+		}))
+		builder.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueDatadogAgentsReferencing(kubernetes.SecretsKind)), referencedPredicate)
+		builder.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueDatadogAgentsReferencing(kubernetes.ConfigMapKind)), referencedPredicate)
+
+		if r.Options.DatadogAgentExtensionEnabled {
+			builder.Watches(&source.Kind{Type: &datadoghqv1alpha1.DatadogAgentExtension{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueDatadogAgentsInNamespace))
+		}
+	}
+
 	if r.Options.ExtendedDaemonsetOptions.Enabled {
 		builder = builder.Owns(&edsdatadoghqv1alpha1.ExtendedDaemonSet{})
 	}
@@ -234,7 +270,7 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	}
 
-	internal, err := datadogagent.NewReconciler(r.Options, r.Client, r.VersionInfo, r.PlatformInfo, r.Scheme, r.Log, r.Recorder, metricForwarder)
+	internal, err := datadogagent.NewReconciler(r.Options, r.Client, r.APIReader, r.VersionInfo, r.PlatformInfo, r.Scheme, r.Log, r.Recorder, metricForwarder)
 	if err != nil {
 		return err
 	}
@@ -243,6 +279,75 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return nil
 }
 
+// indexReferencedConfigs indexes the workloads of the DatadogAgents on the Secrets and ConfigMaps referenced by their pods.
+func (r *DatadogAgentReconciler) indexReferencedConfigs(mgr ctrl.Manager) error {
+	workloads := []client.Object{&appsv1.DaemonSet{}, &appsv1.Deployment{}}
+	if r.Options.ExtendedDaemonsetOptions.Enabled {
+		workloads = append(workloads, &edsdatadoghqv1alpha1.ExtendedDaemonSet{})
+	}
+	for _, workload := range workloads {
+		if err := mgr.GetFieldIndexer().IndexField(context.TODO(), workload, datadogagent.ReferencedConfigsIndexField, datadogagent.ReferencedConfigsIndexer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueDatadogAgentsReferencing returns a map function enqueuing the DatadogAgents owning a workload
+// whose pods reference the Secret or ConfigMap, according to the index created by indexReferencedConfigs.
+func (r *DatadogAgentReconciler) enqueueDatadogAgentsReferencing(kind kubernetes.ObjectKind) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		lists := []client.ObjectList{&appsv1.DaemonSetList{}, &appsv1.DeploymentList{}}
+		if r.Options.ExtendedDaemonsetOptions.Enabled {
+			lists = append(lists, &edsdatadoghqv1alpha1.ExtendedDaemonSetList{})
+		}
+
+		ddas := map[types.NamespacedName]struct{}{}
+		for _, list := range lists {
+			if err := r.Client.List(context.TODO(), list, client.InNamespace(obj.GetNamespace()), client.MatchingFields{datadogagent.ReferencedConfigsIndexField: datadogagent.ReferencedConfigIndexValue(kind, obj.GetName())}); err != nil {
+				r.Log.Error(err, "Unable to list the workloads referencing the object", "namespace", obj.GetNamespace(), "name", obj.GetName())
+				return nil
+			}
+			workloads, err := meta.ExtractList(list)
+			if err != nil {
+				r.Log.Error(err, "Unable to list the workloads referencing the object", "namespace", obj.GetNamespace(), "name", obj.GetName())
+				return nil
+			}
+			for _, workload := range workloads {
+				workloadMeta, ok := workload.(metav1.Object)
+				if !ok {
+					continue
+				}
+				if owner := metav1.GetControllerOf(workloadMeta); owner != nil && owner.Kind == "DatadogAgent" {
+					ddas[types.NamespacedName{Namespace: workloadMeta.GetNamespace(), Name: owner.Name}] = struct{}{}
+				}
+			}
+		}
+
+		requests := make([]reconcile.Request, 0, len(ddas))
+		for dda := range ddas {
+			requests = append(requests, reconcile.Request{NamespacedName: dda})
+		}
+		return requests
+	}
+}
+
+// enqueueDatadogAgentsInNamespace enqueues all the DatadogAgents of the namespace of obj.
+// It is used for the DatadogAgentExtensions, which can apply to any DatadogAgent of their namespace.
+func (r *DatadogAgentReconciler) enqueueDatadogAgentsInNamespace(obj client.Object) []reconcile.Request {
+	ddaList := &datadoghqv2alpha1.DatadogAgentList{}
+	if err := r.Client.List(context.TODO(), ddaList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Unable to list the DatadogAgents", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(ddaList.Items))
+	for _, dda := range ddaList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dda.Namespace, Name: dda.Name}})
+	}
+	return requests
+}
+
 func enqueueIfOwnedByDatadogAgent(obj client.Object) []reconcile.Request {
 	labels := obj.GetLabels()
 
@@ -0,0 +1,237 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	ctrutils "github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	defaultRequeuePeriod    = 60 * time.Second
+	defaultErrRequeuePeriod = 5 * time.Second
+	defaultForceSyncPeriod  = 60 * time.Minute
+)
+
+// Reconciler reconciles a DatadogDowntime object
+type Reconciler struct {
+	client        client.Client
+	datadogClient *datadogclient.SharedClient
+	// v2Enabled selects the version of the DatadogAgents whose rollouts are silenced
+	v2Enabled bool
+	// extendedDaemonSetEnabled also checks the rollouts of the ExtendedDaemonSets of the DatadogAgents
+	extendedDaemonSetEnabled bool
+	log                      logr.Logger
+	scheme                   *runtime.Scheme
+	recorder                 record.EventRecorder
+}
+
+// NewReconciler returns a new Reconciler object
+func NewReconciler(client client.Client, ddClient *datadogclient.SharedClient, v2Enabled, extendedDaemonSetEnabled bool, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+	return &Reconciler{
+		client:                   client,
+		datadogClient:            ddClient,
+		v2Enabled:                v2Enabled,
+		extendedDaemonSetEnabled: extendedDaemonSetEnabled,
+		scheme:                   scheme,
+		log:                      log,
+		recorder:                 recorder,
+	}, nil
+}
+
+// Reconcile is similar to reconciler.Reconcile interface, but taking a context
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	return r.internalReconcile(ctx, request)
+}
+
+// Reconcile loop for DatadogDowntime
+func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.WithValues("datadogdowntime", req.NamespacedName)
+	logger.Info("Reconciling DatadogDowntime")
+	now := metav1.NewTime(time.Now())
+
+	// Get instance
+	instance := &datadoghqv1alpha1.DatadogDowntime{}
+	var result ctrl.Result
+	err := r.client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return result, nil
+		}
+		// Error reading the object - requeue the request
+		return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+
+	if result, err = r.handleFinalizer(logger, instance); ctrutils.ShouldReturn(result, err) {
+		return result, err
+	}
+
+	// Validate the DatadogDowntime spec
+	if err = datadoghqv1alpha1.IsValidDatadogDowntime(&instance.Spec); err != nil {
+		logger.Error(err, "invalid DatadogDowntime spec")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	// Get the monitors that must be silenced
+	targets, err := r.getTargets(instance)
+	if err != nil {
+		logger.Error(err, "error getting the monitors silenced by the downtime")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, ctrl.Result{RequeueAfter: defaultErrRequeuePeriod})
+	}
+
+	// The monitor IDs are part of the hash, so that the downtimes are updated when a referenced monitor is recreated
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(struct {
+		Spec    *datadoghqv1alpha1.DatadogDowntimeSpec `json:"spec"`
+		Targets []downtimeTarget                       `json:"targets"`
+	}{&instance.Spec, targets})
+	if err != nil {
+		logger.Error(err, "error generating hash")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	specChanged := instanceSpecHash != newStatus.CurrentHash
+	forceSync := newStatus.DowntimeLastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(newStatus.DowntimeLastForceSyncTime.Time)) <= 0
+	if err = r.syncDowntimes(logger, instance, newStatus, targets, specChanged, forceSync, now); err != nil {
+		logger.Error(err, "error syncing downtimes")
+	} else {
+		newStatus.CurrentHash = instanceSpecHash
+	}
+
+	// Requeue
+	result.RequeueAfter = defaultRequeuePeriod
+
+	// Update the status
+	return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+}
+
+// syncDowntimes creates, updates and cancels the downtimes in Datadog, so that there is one downtime per target
+// The downtimes are read from Datadog when forceSync is true, to recreate the ones deleted or canceled outside Kubernetes.
+func (r *Reconciler) syncDowntimes(logger logr.Logger, dt *datadoghqv1alpha1.DatadogDowntime, status *datadoghqv1alpha1.DatadogDowntimeStatus, targets []downtimeTarget, specChanged, forceSync bool, now metav1.Time) error {
+	datadogAuth, datadogClient := r.datadogClient.Get()
+
+	current := map[string]datadoghqv1alpha1.DatadogDowntimeInstance{}
+	for _, downtime := range status.Downtimes {
+		current[downtime.MonitorName] = downtime
+	}
+
+	var downtimes []datadoghqv1alpha1.DatadogDowntimeInstance
+	var errs []error
+	for _, target := range targets {
+		downtime, found := current[target.MonitorName]
+		delete(current, target.MonitorName)
+
+		if found && forceSync {
+			d, err := getDowntime(datadogAuth, datadogClient, downtime.ID)
+			switch {
+			case datadogclient.IsNotFound(err):
+				logger.Info("Downtime deleted in Datadog, recreating it", "Downtime ID", downtime.ID)
+				found = false
+			case err != nil:
+				errs = append(errs, err)
+				downtimes = append(downtimes, downtime)
+				continue
+			case isCanceled(d, now.Time):
+				logger.Info("Downtime canceled in Datadog, recreating it", "Downtime ID", downtime.ID)
+				found = false
+			default:
+				downtime.Active = d.GetActive()
+			}
+		}
+
+		if !found {
+			d, err := createDowntime(datadogAuth, datadogClient, buildDowntime(dt, target))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			downtime = datadoghqv1alpha1.DatadogDowntimeInstance{ID: int(d.GetId()), MonitorName: target.MonitorName, MonitorID: target.MonitorID, Active: d.GetActive()}
+			r.recordEvent(dt, buildEventInfo(dt.Name, dt.Namespace, datadog.CreationEvent))
+			logger.Info("Created downtime", "Downtime ID", downtime.ID, "Monitor ID", target.MonitorID)
+		} else if specChanged {
+			d, err := updateDowntime(datadogAuth, datadogClient, downtime.ID, buildDowntime(dt, target))
+			if err != nil {
+				errs = append(errs, err)
+				downtimes = append(downtimes, downtime)
+				continue
+			}
+			downtime.MonitorID = target.MonitorID
+			downtime.Active = d.GetActive()
+			r.recordEvent(dt, buildEventInfo(dt.Name, dt.Namespace, datadog.UpdateEvent))
+			logger.Info("Updated downtime", "Downtime ID", downtime.ID, "Monitor ID", target.MonitorID)
+		}
+		downtimes = append(downtimes, downtime)
+	}
+
+	// Cancel the downtimes of the monitors that aren't silenced anymore, for instance when a DatadogAgent rollout completes
+	for _, downtime := range status.Downtimes {
+		if _, stale := current[downtime.MonitorName]; !stale {
+			continue
+		}
+		if err := cancelDowntime(datadogAuth, datadogClient, downtime.ID); err != nil && !datadogclient.IsNotFound(err) {
+			errs = append(errs, err)
+			downtimes = append(downtimes, downtime)
+			continue
+		}
+		r.recordEvent(dt, buildEventInfo(dt.Name, dt.Namespace, datadog.DeletionEvent))
+		logger.Info("Canceled downtime", "Downtime ID", downtime.ID)
+	}
+
+	status.Downtimes = downtimes
+	status.Active = false
+	for _, downtime := range downtimes {
+		status.Active = status.Active || downtime.Active
+	}
+	if forceSync && len(errs) == 0 {
+		status.DowntimeLastForceSyncTime = &now
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
+
+func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogDowntime *datadoghqv1alpha1.DatadogDowntime, status *datadoghqv1alpha1.DatadogDowntimeStatus, currentErr error, result ctrl.Result) (ctrl.Result, error) {
+	// Update Error and Active conditions
+	condition.SetErrorActiveStatusConditions(&status.Conditions, "DatadogDowntime", datadogDowntime.Generation, currentErr)
+
+	if !apiequality.Semantic.DeepEqual(&datadogDowntime.Status, status) {
+		datadogDowntime.Status = *status
+		if err := r.client.Status().Update(context.TODO(), datadogDowntime); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Error(err, "unable to update DatadogDowntime status due to update conflict")
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, nil
+			}
+			logger.Error(err, "unable to update DatadogDowntime status")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,398 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+const (
+	resourcesName      = "foo"
+	resourcesNamespace = "bar"
+)
+
+// downtimesAPI is a stub of the Datadog downtimes API
+type downtimesAPI struct {
+	mutex     sync.Mutex
+	nextID    int64
+	downtimes map[int64]datadogapiclientv1.Downtime
+}
+
+func newDowntimesAPI() *downtimesAPI {
+	return &downtimesAPI{nextID: 1, downtimes: map[int64]datadogapiclientv1.Downtime{}}
+}
+
+func (a *downtimesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	var id int64
+	if path := strings.TrimPrefix(r.URL.Path, "/api/v1/downtime/"); path != r.URL.Path {
+		id, _ = strconv.ParseInt(path, 10, 64)
+		if _, found := a.downtimes[id]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		d := datadogapiclientv1.Downtime{}
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPost {
+			id = a.nextID
+			a.nextID++
+		}
+		d.SetId(id)
+		d.SetActive(true)
+		a.downtimes[id] = d
+	case http.MethodDelete:
+		d := a.downtimes[id]
+		d.SetCanceled(1)
+		d.SetActive(false)
+		a.downtimes[id] = d
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(a.downtimes[id])
+}
+
+// activeDowntimes returns the downtimes that aren't canceled, by ID
+func (a *downtimesAPI) activeDowntimes() map[int64]datadogapiclientv1.Downtime {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	active := map[int64]datadogapiclientv1.Downtime{}
+	for id, d := range a.downtimes {
+		if d.GetCanceled() == 0 {
+			active[id] = d
+		}
+	}
+	return active
+}
+
+func setupTestReconciler(t *testing.T, objects ...client.Object) (*Reconciler, *downtimesAPI) {
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, datadoghqv1alpha1.AddToScheme(s))
+	require.NoError(t, datadoghqv2alpha1.AddToScheme(s))
+	require.NoError(t, edsdatadoghqv1alpha1.AddToScheme(s))
+
+	api := newDowntimesAPI()
+	httpServer := httptest.NewServer(api)
+	t.Cleanup(httpServer.Close)
+
+	parsedAPIURL, _ := url.Parse(httpServer.URL)
+	testAuth := context.WithValue(context.Background(), datadogapiclientv1.ContextServerIndex, 1)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerVariables, map[string]string{
+		"name":     parsedAPIURL.Host,
+		"protocol": parsedAPIURL.Scheme,
+	})
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	return &Reconciler{
+		client:        fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build(),
+		datadogClient: datadogclient.NewSharedClient(logf.Log, datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: testAuth}),
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(100),
+		log:           logf.Log.WithName(t.Name()),
+	}, api
+}
+
+func newTestDowntime(spec datadoghqv1alpha1.DatadogDowntimeSpec) *datadoghqv1alpha1.DatadogDowntime {
+	return &datadoghqv1alpha1.DatadogDowntime{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+		Spec:       spec,
+	}
+}
+
+func newTestMonitor(name string, id int) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+	}
+}
+
+func reconcileDowntime(t *testing.T, r *Reconciler, count int) *datadoghqv1alpha1.DatadogDowntime {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}}
+	for i := 0; i < count; i++ {
+		_, err := r.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+	}
+
+	dt := &datadoghqv1alpha1.DatadogDowntime{}
+	require.NoError(t, r.client.Get(context.TODO(), request.NamespacedName, dt))
+	return dt
+}
+
+func TestReconciler_Reconcile_monitorRefs(t *testing.T) {
+	r, api := setupTestReconciler(t,
+		newTestDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+			Scope:       []string{"env:prod"},
+			MonitorRefs: []datadoghqv1alpha1.DatadogDowntimeMonitorReference{{Name: "high-cpu"}, {Name: "disk-usage"}},
+			Message:     "maintenance",
+		}),
+		newTestMonitor("high-cpu", 1234),
+		newTestMonitor("disk-usage", 0),
+	)
+
+	// The disk-usage monitor isn't created yet
+	dt := reconcileDowntime(t, r, 2)
+	assert.Empty(t, dt.Status.Downtimes)
+	assert.True(t, meta.IsStatusConditionTrue(dt.Status.Conditions, datadoghqv1alpha1.DatadogDowntimeConditionTypeError))
+	assert.Contains(t, meta.FindStatusCondition(dt.Status.Conditions, datadoghqv1alpha1.DatadogDowntimeConditionTypeError).Message, "disk-usage")
+
+	// One downtime per monitor
+	dm := &datadoghqv1alpha1.DatadogMonitor{}
+	require.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "disk-usage"}, dm))
+	dm.Status.ID = 5678
+	require.NoError(t, r.client.Status().Update(context.TODO(), dm))
+	dt = reconcileDowntime(t, r, 1)
+	require.Len(t, dt.Status.Downtimes, 2)
+	assert.True(t, dt.Status.Active)
+	assert.True(t, meta.IsStatusConditionTrue(dt.Status.Conditions, datadoghqv1alpha1.DatadogDowntimeConditionTypeActive))
+	downtimes := api.activeDowntimes()
+	require.Len(t, downtimes, 2)
+	for _, downtime := range dt.Status.Downtimes {
+		d := downtimes[int64(downtime.ID)]
+		assert.Equal(t, int64(downtime.MonitorID), d.GetMonitorId())
+		assert.Equal(t, []string{"env:prod"}, d.GetScope())
+		assert.Equal(t, "maintenance", d.GetMessage())
+	}
+
+	// Nothing changed
+	dt = reconcileDowntime(t, r, 1)
+	assert.Len(t, api.downtimes, 2)
+
+	// The monitor is removed from the downtime and the message is updated
+	dt.Spec.MonitorRefs = []datadoghqv1alpha1.DatadogDowntimeMonitorReference{{Name: "high-cpu"}}
+	dt.Spec.Message = "extended maintenance"
+	require.NoError(t, r.client.Update(context.TODO(), dt))
+	dt = reconcileDowntime(t, r, 1)
+	require.Len(t, dt.Status.Downtimes, 1)
+	downtimes = api.activeDowntimes()
+	require.Len(t, downtimes, 1)
+	d := downtimes[int64(dt.Status.Downtimes[0].ID)]
+	assert.Equal(t, int64(1234), d.GetMonitorId())
+	assert.Equal(t, "extended maintenance", d.GetMessage())
+
+	// The downtime is canceled in Datadog: it is recreated at the next force sync
+	datadogAuth, datadogClient := r.datadogClient.Get()
+	require.NoError(t, cancelDowntime(datadogAuth, datadogClient, dt.Status.Downtimes[0].ID))
+	dt.Status.DowntimeLastForceSyncTime = nil
+	require.NoError(t, r.client.Status().Update(context.TODO(), dt))
+	dt = reconcileDowntime(t, r, 1)
+	require.Len(t, dt.Status.Downtimes, 1)
+	assert.Len(t, api.activeDowntimes(), 1)
+	assert.Contains(t, api.activeDowntimes(), int64(dt.Status.Downtimes[0].ID))
+
+	// The downtimes are canceled when the DatadogDowntime is deleted
+	r.finalizeDatadogDowntime(r.log, dt)
+	assert.Empty(t, api.activeDowntimes())
+}
+
+func TestReconciler_getTargets_otherCredentials(t *testing.T) {
+	dm := newTestMonitor("high-cpu", 1234)
+	dm.Status.Credentials = &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "team"}
+	dt := newTestDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+		MonitorRefs: []datadoghqv1alpha1.DatadogDowntimeMonitorReference{{Name: "high-cpu"}},
+	})
+	r, _ := setupTestReconciler(t, dt, dm)
+
+	// The monitor can belong to another organization than the Operator credentials
+	_, err := r.getTargets(dt)
+	assert.EqualError(t, err, "the DatadogMonitor high-cpu isn't managed with the Operator credentials and can't be referenced")
+}
+
+func TestReconciler_Reconcile_datadogAgentRollout(t *testing.T) {
+	dda := &datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "datadog"}}
+	ds := newTestDaemonSet("datadog-agent", 1, 1, 3, 3)
+	r, api := setupTestReconciler(t,
+		newTestDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+			Scope:               []string{"kube_namespace:datadog"},
+			MonitorTags:         []string{"team:containers"},
+			DatadogAgentRollout: &datadoghqv1alpha1.DatadogDowntimeAgentRollout{Name: "datadog"},
+		}),
+		dda,
+		ds,
+	)
+	r.v2Enabled = true
+
+	// No rollout
+	dt := reconcileDowntime(t, r, 2)
+	assert.Empty(t, dt.Status.Downtimes)
+	assert.Empty(t, api.activeDowntimes())
+
+	// Rollout started
+	ds.Generation = 2
+	ds.Status.UpdatedNumberScheduled = 1
+	require.NoError(t, r.client.Update(context.TODO(), ds))
+	dt = reconcileDowntime(t, r, 1)
+	require.Len(t, dt.Status.Downtimes, 1)
+	downtimes := api.activeDowntimes()
+	require.Len(t, downtimes, 1)
+	d := downtimes[int64(dt.Status.Downtimes[0].ID)]
+	assert.Equal(t, []string{"team:containers"}, d.GetMonitorTags())
+	assert.Equal(t, []string{"kube_namespace:datadog"}, d.GetScope())
+
+	// Rollout completed
+	ds.Status.ObservedGeneration = 2
+	ds.Status.UpdatedNumberScheduled = 3
+	require.NoError(t, r.client.Update(context.TODO(), ds))
+	dt = reconcileDowntime(t, r, 1)
+	assert.Empty(t, dt.Status.Downtimes)
+	assert.False(t, dt.Status.Active)
+	assert.Empty(t, api.activeDowntimes())
+}
+
+func TestReconciler_isRollingOut(t *testing.T) {
+	v1DDA := &datadoghqv1alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "datadog"}}
+	v2DDA := &datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "datadog"}}
+	unavailableDS := newTestDaemonSet("datadog-agent", 1, 1, 3, 3)
+	unavailableDS.Status.NumberUnavailable = 2
+	otherDS := newTestDaemonSet("other-agent", 2, 1, 3, 0)
+	otherDS.Labels[apicommon.AgentDeploymentNameLabelKey] = "other"
+	canaryEDS := &edsdatadoghqv1alpha1.ExtendedDaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "datadog-agent", Labels: map[string]string{apicommon.AgentDeploymentNameLabelKey: "datadog"}},
+		Status: edsdatadoghqv1alpha1.ExtendedDaemonSetStatus{
+			Desired:  3,
+			UpToDate: 3,
+			Canary:   &edsdatadoghqv1alpha1.ExtendedDaemonSetStatusCanary{ReplicaSet: "datadog-agent-canary"},
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		v2Enabled                bool
+		extendedDaemonSetEnabled bool
+		objects                  []client.Object
+		want                     bool
+		wantErr                  bool
+	}{
+		{
+			name:      "missing DatadogAgent",
+			v2Enabled: true,
+			objects:   []client.Object{v1DDA},
+			wantErr:   true,
+		},
+		{
+			name:      "unavailable pods are not a rollout",
+			v2Enabled: true,
+			objects:   []client.Object{v2DDA, unavailableDS, otherDS},
+			want:      false,
+		},
+		{
+			name:      "new generation not observed yet",
+			v2Enabled: true,
+			objects:   []client.Object{v2DDA, newTestDaemonSet("datadog-agent", 2, 1, 3, 3)},
+			want:      true,
+		},
+		{
+			name:    "pods not updated with the v1 DatadogAgent",
+			objects: []client.Object{v1DDA, newTestDaemonSet("datadog-agent", 1, 1, 3, 2)},
+			want:    true,
+		},
+		{
+			name:      "cluster agent deployment not updated",
+			v2Enabled: true,
+			objects:   []client.Object{v2DDA, newTestDeployment("datadog-cluster-agent", 1, 1, 2, 1)},
+			want:      true,
+		},
+		{
+			name:                     "ExtendedDaemonSet canary",
+			v2Enabled:                true,
+			extendedDaemonSetEnabled: true,
+			objects:                  []client.Object{v2DDA, canaryEDS},
+			want:                     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupTestReconciler(t, tt.objects...)
+			r.v2Enabled = tt.v2Enabled
+			r.extendedDaemonSetEnabled = tt.extendedDaemonSetEnabled
+
+			got, err := r.isRollingOut(resourcesNamespace, "datadog")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newTestDaemonSet(name string, generation, observedGeneration int64, desired, updated int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  resourcesNamespace,
+			Name:       name,
+			Generation: generation,
+			Labels:     map[string]string{apicommon.AgentDeploymentNameLabelKey: "datadog"},
+		},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     observedGeneration,
+			DesiredNumberScheduled: desired,
+			UpdatedNumberScheduled: updated,
+			NumberAvailable:        updated,
+		},
+	}
+}
+
+func newTestDeployment(name string, generation, observedGeneration int64, desired, updated int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  resourcesNamespace,
+			Name:       name,
+			Generation: generation,
+			Labels:     map[string]string{apicommon.AgentDeploymentNameLabelKey: "datadog"},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: &desired},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: observedGeneration,
+			Replicas:           desired,
+			UpdatedReplicas:    updated,
+		},
+	}
+}
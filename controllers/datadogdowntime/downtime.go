@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// downtimeTarget is the monitor silenced by a downtime: a DatadogMonitor, or the monitors selected by tags if MonitorName is empty
+type downtimeTarget struct {
+	MonitorName string `json:"monitorName,omitempty"`
+	MonitorID   int    `json:"monitorId,omitempty"`
+}
+
+func buildDowntime(dt *datadoghqv1alpha1.DatadogDowntime, target downtimeTarget) datadogapiclientv1.Downtime {
+	spec := dt.Spec
+	d := datadogapiclientv1.NewDowntime()
+
+	scope := append([]string{}, spec.Scope...)
+	if len(scope) == 0 {
+		scope = []string{"*"}
+	}
+	sort.Strings(scope)
+	d.SetScope(scope)
+
+	if target.MonitorID != 0 {
+		d.SetMonitorId(int64(target.MonitorID))
+	} else {
+		monitorTags := append([]string{}, spec.MonitorTags...)
+		if len(monitorTags) == 0 {
+			monitorTags = []string{"*"}
+		}
+		sort.Strings(monitorTags)
+		d.SetMonitorTags(monitorTags)
+	}
+
+	if spec.Message != "" {
+		d.SetMessage(spec.Message)
+	}
+	if spec.Timezone != "" {
+		d.SetTimezone(spec.Timezone)
+	}
+	if spec.MuteFirstRecoveryNotification != nil {
+		d.SetMuteFirstRecoveryNotification(*spec.MuteFirstRecoveryNotification)
+	}
+
+	if spec.Start != nil {
+		d.SetStart(spec.Start.Unix())
+	}
+	// Explicitly unset the end and the recurrence, to remove them on updates
+	if spec.End != nil {
+		d.SetEnd(spec.End.Unix())
+	} else {
+		d.SetEndNil()
+	}
+	if spec.Recurrence != nil {
+		d.SetRecurrence(buildRecurrence(spec.Recurrence))
+	} else {
+		d.SetRecurrenceNil()
+	}
+
+	return *d
+}
+
+func buildRecurrence(recurrence *datadoghqv1alpha1.DatadogDowntimeRecurrence) datadogapiclientv1.DowntimeRecurrence {
+	r := datadogapiclientv1.NewDowntimeRecurrence()
+	r.SetType(string(recurrence.Type))
+	if recurrence.Period != nil {
+		r.SetPeriod(*recurrence.Period)
+	}
+	if len(recurrence.WeekDays) > 0 {
+		r.SetWeekDays(recurrence.WeekDays)
+	}
+	if recurrence.Rrule != "" {
+		r.SetRrule(recurrence.Rrule)
+	}
+	if recurrence.UntilDate != nil {
+		r.SetUntilDate(recurrence.UntilDate.Unix())
+	}
+	if recurrence.UntilOccurrences != nil {
+		r.SetUntilOccurrences(*recurrence.UntilOccurrences)
+	}
+
+	return *r
+}
+
+// isCanceled returns true if the downtime was canceled before its end, for instance from the Datadog UI
+func isCanceled(d datadogapiclientv1.Downtime, now time.Time) bool {
+	if d.GetCanceled() == 0 {
+		return false
+	}
+	end, ok := d.GetEndOk()
+
+	return !ok || end == nil || *end > now.Unix()
+}
+
+func getDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtimeID int) (datadogapiclientv1.Downtime, error) {
+	d, _, err := client.DowntimesApi.GetDowntime(auth, int64(downtimeID))
+	if err != nil {
+		return datadogapiclientv1.Downtime{}, datadogclient.TranslateClientError(err, "error getting downtime")
+	}
+
+	return d, nil
+}
+
+func createDowntime(auth context.Context, client *datadogapiclientv1.APIClient, d datadogapiclientv1.Downtime) (datadogapiclientv1.Downtime, error) {
+	dCreated, _, err := client.DowntimesApi.CreateDowntime(auth, d)
+	if err != nil {
+		return datadogapiclientv1.Downtime{}, datadogclient.TranslateClientError(err, "error creating downtime")
+	}
+
+	return dCreated, nil
+}
+
+func updateDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtimeID int, d datadogapiclientv1.Downtime) (datadogapiclientv1.Downtime, error) {
+	dUpdated, _, err := client.DowntimesApi.UpdateDowntime(auth, int64(downtimeID), d)
+	if err != nil {
+		return datadogapiclientv1.Downtime{}, datadogclient.TranslateClientError(err, "error updating downtime")
+	}
+
+	return dUpdated, nil
+}
+
+func cancelDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtimeID int) error {
+	if _, err := client.DowntimesApi.CancelDowntime(auth, int64(downtimeID)); err != nil {
+		return datadogclient.TranslateClientError(err, "error canceling downtime")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	datadogDowntimeFinalizer = "finalizer.downtime.datadoghq.com"
+)
+
+func (r *Reconciler) handleFinalizer(logger logr.Logger, dt *datadoghqv1alpha1.DatadogDowntime) (ctrl.Result, error) {
+	// Check if the DatadogDowntime instance is marked to be deleted, which is indicated by the deletion timestamp being set.
+	if dt.GetDeletionTimestamp() != nil {
+		if utils.ContainsString(dt.GetFinalizers(), datadogDowntimeFinalizer) {
+			r.finalizeDatadogDowntime(logger, dt)
+
+			dt.SetFinalizers(utils.RemoveString(dt.GetFinalizers(), datadogDowntimeFinalizer))
+			err := r.client.Update(context.TODO(), dt)
+			if err != nil {
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+			}
+		}
+
+		// Requeue until the object was properly deleted by Kubernetes
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Add finalizer for this resource if it doesn't already exist.
+	if !utils.ContainsString(dt.GetFinalizers(), datadogDowntimeFinalizer) {
+		if err := r.addFinalizer(logger, dt); err != nil {
+			return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+		}
+
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Proceed in reconcile loop.
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) finalizeDatadogDowntime(logger logr.Logger, dt *datadoghqv1alpha1.DatadogDowntime) {
+	datadogAuth, datadogClient := r.datadogClient.Get()
+	for _, downtime := range dt.Status.Downtimes {
+		if err := cancelDowntime(datadogAuth, datadogClient, downtime.ID); err != nil && !datadogclient.IsNotFound(err) {
+			logger.Error(err, "failed to finalize downtime", "Downtime ID", downtime.ID)
+
+			continue
+		}
+		logger.Info("Successfully finalized downtime", "Downtime ID", downtime.ID)
+	}
+	event := buildEventInfo(dt.Name, dt.Namespace, datadog.DeletionEvent)
+	r.recordEvent(dt, event)
+}
+
+func (r *Reconciler) addFinalizer(logger logr.Logger, dt *datadoghqv1alpha1.DatadogDowntime) error {
+	logger.Info("Adding Finalizer for the DatadogDowntime")
+
+	dt.SetFinalizers(append(dt.GetFinalizers(), datadogDowntimeFinalizer))
+
+	err := r.client.Update(context.TODO(), dt)
+	if err != nil {
+		logger.Error(err, "failed to update DatadogDowntime with finalizer")
+		return err
+	}
+
+	return nil
+}
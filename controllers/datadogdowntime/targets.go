@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
+)
+
+// getTargets returns the monitors that must currently be silenced by the downtimes of the DatadogDowntime
+// No target is returned if the DatadogDowntime is tied to the rollouts of a DatadogAgent that isn't rolling out.
+func (r *Reconciler) getTargets(dt *datadoghqv1alpha1.DatadogDowntime) ([]downtimeTarget, error) {
+	if rollout := dt.Spec.DatadogAgentRollout; rollout != nil {
+		rollingOut, err := r.isRollingOut(dt.Namespace, rollout.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !rollingOut {
+			return nil, nil
+		}
+	}
+
+	if len(dt.Spec.MonitorRefs) == 0 {
+		return []downtimeTarget{{}}, nil
+	}
+
+	targets := make([]downtimeTarget, 0, len(dt.Spec.MonitorRefs))
+	var pending []string
+	for _, ref := range dt.Spec.MonitorRefs {
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dt.Namespace, Name: ref.Name}, dm); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, ref.Name)
+				continue
+			}
+			return nil, err
+		}
+		if err := datadogmonitor.CheckOperatorCredentials(dm); err != nil {
+			return nil, err
+		}
+		if dm.Status.ID == 0 {
+			pending = append(pending, ref.Name)
+			continue
+		}
+		targets = append(targets, downtimeTarget{MonitorName: ref.Name, MonitorID: dm.Status.ID})
+	}
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("waiting for the referenced DatadogMonitors to be created: %s", strings.Join(pending, ", "))
+	}
+
+	return targets, nil
+}
+
+// isRollingOut returns true while the DaemonSets, ExtendedDaemonSets or Deployments of the DatadogAgent haven't
+// updated all their pods to their latest spec
+func (r *Reconciler) isRollingOut(namespace, name string) (bool, error) {
+	var dda client.Object = &datadoghqv2alpha1.DatadogAgent{}
+	if !r.v2Enabled {
+		dda = &datadoghqv1alpha1.DatadogAgent{}
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, dda); err != nil {
+		return false, fmt.Errorf("unable to get the DatadogAgent %s: %w", name, err)
+	}
+
+	listOptions := []client.ListOption{client.InNamespace(namespace), client.MatchingLabels{apicommon.AgentDeploymentNameLabelKey: name}}
+
+	dsList := &appsv1.DaemonSetList{}
+	if err := r.client.List(context.TODO(), dsList, listOptions...); err != nil {
+		return false, fmt.Errorf("unable to list the DaemonSets of the DatadogAgent %s: %w", name, err)
+	}
+	for _, ds := range dsList.Items {
+		if ds.Generation > ds.Status.ObservedGeneration || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+			return true, nil
+		}
+	}
+
+	if r.extendedDaemonSetEnabled {
+		edsList := &edsdatadoghqv1alpha1.ExtendedDaemonSetList{}
+		if err := r.client.List(context.TODO(), edsList, listOptions...); err != nil {
+			return false, fmt.Errorf("unable to list the ExtendedDaemonSets of the DatadogAgent %s: %w", name, err)
+		}
+		for _, eds := range edsList.Items {
+			// The ExtendedDaemonSet status doesn't report the observed generation, a canary is a rollout in progress
+			if eds.Status.Canary != nil || eds.Status.UpToDate < eds.Status.Desired {
+				return true, nil
+			}
+		}
+	}
+
+	deploymentList := &appsv1.DeploymentList{}
+	if err := r.client.List(context.TODO(), deploymentList, listOptions...); err != nil {
+		return false, fmt.Errorf("unable to list the Deployments of the DatadogAgent %s: %w", name, err)
+	}
+	for _, deployment := range deploymentList.Items {
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		if deployment.Generation > deployment.Status.ObservedGeneration || deployment.Status.UpdatedReplicas < desired {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// EnqueueDowntimesReferencingMonitor enqueues the DatadogDowntimes referencing the DatadogMonitor obj,
+// to update their downtimes when the ID of its monitor changes.
+func (r *Reconciler) EnqueueDowntimesReferencingMonitor(obj client.Object) []reconcile.Request {
+	return r.enqueueDowntimes(obj, func(dt *datadoghqv1alpha1.DatadogDowntime) bool {
+		for _, ref := range dt.Spec.MonitorRefs {
+			if ref.Name == obj.GetName() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// EnqueueDowntimesReferencingAgent enqueues the DatadogDowntimes tied to the rollouts of the DatadogAgent obj,
+// to create or cancel their downtimes when a rollout starts or completes.
+func (r *Reconciler) EnqueueDowntimesReferencingAgent(obj client.Object) []reconcile.Request {
+	return r.enqueueDowntimes(obj, func(dt *datadoghqv1alpha1.DatadogDowntime) bool {
+		return dt.Spec.DatadogAgentRollout != nil && dt.Spec.DatadogAgentRollout.Name == obj.GetName()
+	})
+}
+
+func (r *Reconciler) enqueueDowntimes(obj client.Object, references func(*datadoghqv1alpha1.DatadogDowntime) bool) []reconcile.Request {
+	dtList := &datadoghqv1alpha1.DatadogDowntimeList{}
+	if err := r.client.List(context.TODO(), dtList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "Unable to list the DatadogDowntimes", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range dtList.Items {
+		if references(&dtList.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dtList.Items[i].Namespace, Name: dtList.Items[i].Name}})
+		}
+	}
+
+	return requests
+}
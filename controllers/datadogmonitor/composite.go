@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2021 Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// monitorReferenceRegexp matches the references to other DatadogMonitors in a composite query, ${<name>}
+var monitorReferenceRegexp = regexp.MustCompile(`\$\{([a-z0-9]([-a-z0-9.]*[a-z0-9])?)\}`)
+
+// compositeReferences returns the sorted names of the DatadogMonitors referenced by a composite query
+func compositeReferences(query string) []string {
+	names := map[string]bool{}
+	for _, match := range monitorReferenceRegexp.FindAllStringSubmatch(query, -1) {
+		names[match[1]] = true
+	}
+
+	references := make([]string, 0, len(names))
+	for name := range names {
+		references = append(references, name)
+	}
+	sort.Strings(references)
+
+	return references
+}
+
+// resolveCompositeQuery replaces the DatadogMonitor references of a composite query with the IDs of their monitors
+// It also returns the names of the referenced DatadogMonitors whose monitor doesn't exist yet, the query can't be
+// rendered until they're created. The referenced monitors must be managed with the credentials of the composite
+// monitor, credentialsStatus, the IDs of another organization can't be used.
+func (r *Reconciler) resolveCompositeQuery(dm *datadoghqv1alpha1.DatadogMonitor, credentialsStatus *datadoghqv1alpha1.DatadogMonitorCredentialsStatus) (string, []string, error) {
+	ids := map[string]int{}
+	var pending []string
+	for _, name := range compositeReferences(dm.Spec.Query) {
+		if name == dm.Name {
+			return "", nil, fmt.Errorf("composite monitor %s can't reference itself", dm.Name)
+		}
+
+		referenced := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dm.Namespace, Name: name}, referenced); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, name)
+				continue
+			}
+			return "", nil, err
+		}
+		if referenced.Status.ID == 0 {
+			pending = append(pending, name)
+			continue
+		}
+		if credentialsChanged(referenced.Status.Credentials, credentialsStatus) {
+			return "", nil, fmt.Errorf("the DatadogMonitor %s is managed with other credentials than the composite monitor %s and can't be referenced", name, dm.Name)
+		}
+		ids[name] = referenced.Status.ID
+	}
+	if len(pending) > 0 {
+		return "", pending, nil
+	}
+
+	query := monitorReferenceRegexp.ReplaceAllStringFunc(dm.Spec.Query, func(reference string) string {
+		return strconv.Itoa(ids[monitorReferenceRegexp.FindStringSubmatch(reference)[1]])
+	})
+
+	return query, nil, nil
+}
+
+// EnqueueCompositeMonitors enqueues the composite DatadogMonitors referencing the DatadogMonitor obj, to render
+// their query again when the ID of its monitor changes.
+func (r *Reconciler) EnqueueCompositeMonitors(obj client.Object) []reconcile.Request {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(context.TODO(), dmList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "Unable to list the DatadogMonitors", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dm := range dmList.Items {
+		if dm.Spec.Type != datadoghqv1alpha1.DatadogMonitorTypeComposite {
+			continue
+		}
+		for _, name := range compositeReferences(dm.Spec.Query) {
+			if name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dm.Namespace, Name: dm.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2021 Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func newTestMonitor(name string, monitorType datadoghqv1alpha1.DatadogMonitorType, query string, id int) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name},
+		Spec:       datadoghqv1alpha1.DatadogMonitorSpec{Type: monitorType, Query: query},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+	}
+}
+
+func Test_compositeReferences(t *testing.T) {
+	assert.Equal(t, []string{"disk-usage", "high-cpu.web"}, compositeReferences("(${high-cpu.web} || ${disk-usage}) && !${disk-usage} && 1234"))
+	assert.Empty(t, compositeReferences("1234 && 5678"))
+	assert.Empty(t, compositeReferences("${Not_A_Name} && ${}"))
+}
+
+func TestReconciler_resolveCompositeQuery(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	teamCredentials := &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"},
+		Hash:   "team",
+	}
+	teamMonitor := newTestMonitor("team-errors", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):sum:trace.http.request.errors{*} > 10", 9012)
+	teamMonitor.Status.Credentials = teamCredentials
+
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			teamMonitor,
+			newTestMonitor("high-cpu", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):avg:system.cpu.user{*} > 90", 1234),
+			newTestMonitor("disk-usage", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):avg:system.disk.in_use{*} > 0.9", 5678),
+			newTestMonitor("not-created", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):avg:system.load.1{*} > 10", 0),
+		).Build(),
+		log: testLogger,
+	}
+
+	tests := []struct {
+		name        string
+		dm          *datadoghqv1alpha1.DatadogMonitor
+		credentials *datadoghqv1alpha1.DatadogMonitorCredentialsStatus
+		wantQuery   string
+		wantPending []string
+		wantErr     bool
+	}{
+		{
+			name:      "references resolved",
+			dm:        newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${high-cpu} && !${disk-usage} || ${high-cpu} && 42", 0),
+			wantQuery: "1234 && !5678 || 1234 && 42",
+		},
+		{
+			name:        "referenced monitors not created yet",
+			dm:          newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${not-created} && ${missing} && ${high-cpu}", 0),
+			wantPending: []string{"missing", "not-created"},
+		},
+		{
+			name:        "references managed with the same credentials",
+			dm:          newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${team-errors} && 42", 0),
+			credentials: teamCredentials,
+			wantQuery:   "9012 && 42",
+		},
+		{
+			name:    "reference managed with other credentials",
+			dm:      newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${team-errors} && ${high-cpu}", 0),
+			wantErr: true,
+		},
+		{
+			name:        "reference managed with the operator credentials",
+			dm:          newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${team-errors} && ${high-cpu}", 0),
+			credentials: teamCredentials,
+			wantErr:     true,
+		},
+		{
+			name:    "self reference",
+			dm:      newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${high-cpu} && ${foo}", 0),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, pending, err := r.resolveCompositeQuery(tt.dm, tt.credentials)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantPending, pending)
+		})
+	}
+}
+
+func TestReconciler_EnqueueCompositeMonitors(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	highCPU := newTestMonitor("high-cpu", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):avg:system.cpu.user{*} > 90", 1234)
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			highCPU,
+			newTestMonitor("composite-1", datadoghqv1alpha1.DatadogMonitorTypeComposite, "${high-cpu} && ${disk-usage}", 0),
+			newTestMonitor("composite-2", datadoghqv1alpha1.DatadogMonitorTypeComposite, "${disk-usage} && 42", 0),
+			// Only the query of composite monitors is rendered
+			newTestMonitor("not-composite", datadoghqv1alpha1.DatadogMonitorTypeMetric, "${high-cpu}", 0),
+		).Build(),
+		log: testLogger,
+	}
+
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: "composite-1"}},
+	}, r.EnqueueCompositeMonitors(highCPU))
+	require.NoError(t, r.client.Delete(context.TODO(), highCPU))
+	assert.Len(t, r.EnqueueCompositeMonitors(newTestMonitor("disk-usage", datadoghqv1alpha1.DatadogMonitorTypeMetric, "", 0)), 2)
+}
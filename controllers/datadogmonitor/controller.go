@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -54,31 +55,47 @@ var supportedMonitorTypes = map[string]bool{
 	string(datadogapiclientv1.MONITORTYPE_SLO_ALERT):             true,
 	string(datadogapiclientv1.MONITORTYPE_EVENT_V2_ALERT):        true,
 	string(datadogapiclientv1.MONITORTYPE_AUDIT_ALERT):           true,
+	string(datadogapiclientv1.MONITORTYPE_COMPOSITE):             true,
+	string(datadogapiclientv1.MONITORTYPE_CI_PIPELINES_ALERT):    true,
+	string(datadogapiclientv1.MONITORTYPE_CI_TESTS_ALERT):        true,
+	string(datadogapiclientv1.MONITORTYPE_ERROR_TRACKING_ALERT):  true,
 }
 
-const requiredTag = "generated:kubernetes"
+const (
+	requiredTag = "generated:kubernetes"
+	// orphanedTag replaces requiredTag on the monitors kept in Datadog when their DatadogMonitor is deleted
+	orphanedTag = "orphaned:kubernetes"
+)
 
 // Reconciler reconciles a DatadogMonitor object
 type Reconciler struct {
-	client        client.Client
-	datadogClient *datadogapiclientv1.APIClient
-	datadogAuth   context.Context
-	versionInfo   *version.Info
-	log           logr.Logger
-	scheme        *runtime.Scheme
-	recorder      record.EventRecorder
+	client client.Client
+	// apiReader reads the credentials Secrets, not to cache every Secret of the cluster
+	apiReader     client.Reader
+	datadogClient *datadogclient.SharedClient
+	// clients caches the Datadog API clients of the DatadogMonitors with their own credentials, by credentials hash
+	clients      map[string]datadogclient.DatadogClient
+	clientsMutex sync.Mutex
+	versionInfo  *version.Info
+	// deletionPolicy is the deletion policy of the DatadogMonitors without spec.controllerOptions.deletionPolicy
+	deletionPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+	log            logr.Logger
+	scheme         *runtime.Scheme
+	recorder       record.EventRecorder
 }
 
 // NewReconciler returns a new Reconciler object
-func NewReconciler(client client.Client, ddClient datadogclient.DatadogClient, versionInfo *version.Info, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+func NewReconciler(client client.Client, apiReader client.Reader, ddClient *datadogclient.SharedClient, versionInfo *version.Info, deletionPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
 	return &Reconciler{
-		client:        client,
-		datadogClient: ddClient.Client,
-		datadogAuth:   ddClient.Auth,
-		versionInfo:   versionInfo,
-		scheme:        scheme,
-		log:           log,
-		recorder:      recorder,
+		client:         client,
+		apiReader:      apiReader,
+		datadogClient:  ddClient,
+		clients:        map[string]datadogclient.DatadogClient{},
+		versionInfo:    versionInfo,
+		deletionPolicy: deletionPolicy,
+		scheme:         scheme,
+		log:            log,
+		recorder:       recorder,
 	}, nil
 }
 
@@ -121,7 +138,33 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 	}
 
-	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
+	// Get the Datadog API client of the monitor credentials
+	ddClient, credentialsStatus, err := r.getMonitorClient(instance)
+	if err != nil {
+		logger.Error(err, "error getting the monitor credentials")
+
+		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+	}
+
+	// Render the query of composite monitors with the IDs of the referenced DatadogMonitors.
+	// The rendered query is part of the hash, so that the monitor is updated when a referenced ID changes.
+	monitor := instance
+	if instance.Spec.Type == datadoghqv1alpha1.DatadogMonitorTypeComposite {
+		query, pending, resolveErr := r.resolveCompositeQuery(instance, credentialsStatus)
+		if resolveErr == nil && len(pending) > 0 {
+			resolveErr = fmt.Errorf("waiting for the referenced DatadogMonitors to be created: %s", strings.Join(pending, ", "))
+			result = ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}
+		}
+		if resolveErr != nil {
+			logger.Error(resolveErr, "error rendering composite query")
+
+			return r.updateStatusIfNeeded(logger, instance, now, newStatus, resolveErr, result)
+		}
+		monitor = instance.DeepCopy()
+		monitor.Spec.Query = query
+	}
+
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&monitor.Spec)
 	if err != nil {
 		logger.Error(err, "error generating hash")
 
@@ -130,13 +173,24 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 
 	statusSpecHash := instance.Status.CurrentHash
 
+	shouldAdopt := false
 	shouldCreate := false
 	shouldUpdate := false
 
-	// Check if we need to create the monitor, update the monitor definition, or update monitor state
+	// Move the monitor if the credentials changed to another organization
+	if instance.Status.ID != 0 && credentialsChanged(instance.Status.Credentials, credentialsStatus) {
+		if shouldCreate, err = r.migrateMonitor(logger, instance, ddClient, credentialsStatus, newStatus); err != nil {
+			logger.Error(err, "error moving the monitor to the new credentials", "Monitor ID", instance.Status.ID)
+
+			return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+		}
+	}
+
+	// Check if we need to create or adopt the monitor, update the monitor definition, or update monitor state
 	if instance.Status.ID == 0 {
-		shouldCreate = true
-	} else {
+		shouldAdopt = instance.Spec.AdoptMonitorID != 0
+		shouldCreate = !shouldAdopt
+	} else if !shouldCreate {
 		var m datadogapiclientv1.Monitor
 		if instanceSpecHash != statusSpecHash {
 			// Custom resource manifest has changed, need to update the API
@@ -145,22 +199,24 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		} else if instance.Status.MonitorLastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(instance.Status.MonitorLastForceSyncTime.Time)) <= 0 {
 			// Periodically force a sync with the API monitor to ensure parity
 			// Get monitor to make sure it exists before trying any updates. If it doesn't, set shouldCreate
-			m, err = r.get(logger, instance, newStatus, now)
+			m, err = r.get(logger, ddClient, instance, newStatus, now)
 			if err != nil {
 				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
-				if strings.Contains(err.Error(), "404 Not Found") {
+				if datadogclient.IsNotFound(err) {
 					shouldCreate = true
 				}
-			} else {
-				shouldUpdate = true
+			} else if shouldUpdate = r.detectDrift(logger, monitor, m, newStatus, now); !shouldUpdate {
+				// The changes made outside of the DatadogMonitor are kept until the next periodic sync
+				newStatus.MonitorLastForceSyncTime = &now
+				updateMonitorState(m, now, newStatus)
 			}
 		} else if instance.Status.MonitorStateLastUpdateTime == nil || (defaultRequeuePeriod-now.Sub(instance.Status.MonitorStateLastUpdateTime.Time)) <= 0 {
 			// If other conditions aren't met, then update monitor state
 			// Get monitor to make sure it exists before trying any updates. If it doesn't, set shouldCreate
-			m, err = r.get(logger, instance, newStatus, now)
+			m, err = r.get(logger, ddClient, instance, newStatus, now)
 			if err != nil {
 				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
-				if strings.Contains(err.Error(), "404 Not Found") {
+				if datadogclient.IsNotFound(err) {
 					shouldCreate = true
 				}
 			}
@@ -168,9 +224,25 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		}
 	}
 
-	// Create and update actions
-	if shouldCreate {
-		if isSupportedMonitorType(instance.Spec.Type) {
+	// Adopt, create and update actions
+	if shouldAdopt {
+		if IsSupportedMonitorType(instance.Spec.Type) {
+			logger.V(1).Info("Adopting monitor in Datadog", "Monitor ID", instance.Spec.AdoptMonitorID)
+			// Make sure required tags are present
+			if !apiutils.BoolValue(instance.Spec.ControllerOptions.DisableRequiredTags) {
+				if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
+					return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+				}
+			}
+			if err = r.adopt(logger, ddClient, credentialsStatus, monitor, newStatus, now, instanceSpecHash); err != nil {
+				logger.Error(err, "error adopting monitor", "Monitor ID", instance.Spec.AdoptMonitorID)
+			}
+		} else {
+			err = fmt.Errorf("monitor type %v not supported", instance.Spec.Type)
+			logger.Error(err, "error adopting monitor", "Monitor ID", instance.Spec.AdoptMonitorID)
+		}
+	} else if shouldCreate {
+		if IsSupportedMonitorType(instance.Spec.Type) {
 			logger.V(1).Info("Creating monitor in Datadog")
 			// Make sure required tags are present
 			if !apiutils.BoolValue(instance.Spec.ControllerOptions.DisableRequiredTags) {
@@ -178,7 +250,7 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 					return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 				}
 			}
-			if err = r.create(logger, instance, newStatus, now, instanceSpecHash); err != nil {
+			if err = r.create(logger, ddClient, credentialsStatus, monitor, newStatus, now, instanceSpecHash); err != nil {
 				logger.Error(err, "error creating monitor")
 			}
 		} else {
@@ -193,7 +265,7 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 				return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 			}
 		}
-		if err = r.update(logger, instance, newStatus, now, instanceSpecHash); err != nil {
+		if err = r.update(logger, ddClient, credentialsStatus, monitor, newStatus, now, instanceSpecHash); err != nil {
 			logger.Error(err, "error updating monitor", "Monitor ID", instance.Status.ID)
 		}
 	}
@@ -207,14 +279,16 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 	return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 }
 
-func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+func (r *Reconciler) create(logger logr.Logger, ddClient datadogclient.DatadogClient, credentialsStatus *datadoghqv1alpha1.DatadogMonitorCredentialsStatus, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+	datadogAuth, datadogClient := ddClient.Auth, ddClient.Client
+
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err := validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
 		return err
 	}
 
 	// Create monitor in Datadog
-	m, err := createMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor)
+	m, err := createMonitor(datadogAuth, logger, datadogClient, datadogMonitor)
 	if err != nil {
 		return err
 	}
@@ -230,6 +304,7 @@ func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	status.Primary = true
 	status.MonitorStateSyncStatus = ""
 	status.CurrentHash = instanceSpecHash
+	status.Credentials = credentialsStatus
 
 	// Set Created Condition
 	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeCreated, corev1.ConditionTrue, "DatadogMonitor Created")
@@ -238,15 +313,74 @@ func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	return nil
 }
 
-func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+// adopt takes ownership of the existing monitor AdoptMonitorID, and updates it with the DatadogMonitor spec
+func (r *Reconciler) adopt(logger logr.Logger, ddClient datadogclient.DatadogClient, credentialsStatus *datadoghqv1alpha1.DatadogMonitorCredentialsStatus, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+	datadogAuth, datadogClient := ddClient.Auth, ddClient.Client
+	monitorID := datadogMonitor.Spec.AdoptMonitorID
+
+	// Make sure the monitor isn't managed by another DatadogMonitor
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(context.TODO(), dmList); err != nil {
+		return fmt.Errorf("unable to list the DatadogMonitors: %w", err)
+	}
+	for _, dm := range dmList.Items {
+		if dm.Status.ID == monitorID && (dm.Namespace != datadogMonitor.Namespace || dm.Name != datadogMonitor.Name) {
+			return fmt.Errorf("monitor %d is already managed by the DatadogMonitor %s/%s", monitorID, dm.Namespace, dm.Name)
+		}
+	}
+
+	m, err := getMonitor(datadogAuth, datadogClient, monitorID)
+	if err != nil {
+		return err
+	}
+	if normalizedMonitorType(m.GetType()) != normalizedMonitorType(datadogapiclientv1.MonitorType(datadogMonitor.Spec.Type)) {
+		return fmt.Errorf("monitor %d can't be adopted: its type %s doesn't match spec.Type %s", monitorID, m.GetType(), datadogMonitor.Spec.Type)
+	}
+
+	// Validate monitor in Datadog
+	if err = validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
+		return err
+	}
+
+	// Update the adopted monitor in Datadog
+	adopted := datadogMonitor.DeepCopy()
+	adopted.Status.ID = monitorID
+	if _, err = updateMonitor(datadogAuth, logger, datadogClient, adopted); err != nil {
+		return err
+	}
+	event := buildEventInfo(datadogMonitor.Name, datadogMonitor.Namespace, datadog.UpdateEvent)
+	r.recordEvent(datadogMonitor, event)
+
+	// The adopted monitor is now managed by the DatadogMonitor, add its static information to status
+	status.ID = monitorID
+	creator := m.GetCreator()
+	status.Creator = creator.GetEmail()
+	createdTime := metav1.NewTime(m.GetCreated())
+	status.Created = &createdTime
+	status.Primary = true
+	status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusOK
+	status.MonitorLastForceSyncTime = &now
+	status.CurrentHash = instanceSpecHash
+	status.Credentials = credentialsStatus
+
+	// Set Created Condition
+	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeCreated, corev1.ConditionTrue, "DatadogMonitor Adopted")
+	logger.Info("Adopted an existing monitor", "Monitor Namespace", datadogMonitor.Namespace, "Monitor Name", datadogMonitor.Name, "Monitor ID", monitorID)
+
+	return nil
+}
+
+func (r *Reconciler) update(logger logr.Logger, ddClient datadogclient.DatadogClient, credentialsStatus *datadoghqv1alpha1.DatadogMonitorCredentialsStatus, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+	datadogAuth, datadogClient := ddClient.Auth, ddClient.Client
+
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err := validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusValidateError
 		return err
 	}
 
 	// Update monitor in Datadog
-	if _, err := updateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if _, err := updateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusUpdateError
 		return err
 	}
@@ -259,14 +393,15 @@ func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusOK
 	status.MonitorLastForceSyncTime = &now
 	status.CurrentHash = instanceSpecHash
+	status.Credentials = credentialsStatus
 	logger.Info("Updated DatadogMonitor", "Monitor Namespace", datadogMonitor.Namespace, "Monitor Name", datadogMonitor.Name, "Monitor ID", datadogMonitor.Status.ID)
 
 	return nil
 }
 
-func (r *Reconciler) get(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) (datadogapiclientv1.Monitor, error) {
+func (r *Reconciler) get(logger logr.Logger, ddClient datadogclient.DatadogClient, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) (datadogapiclientv1.Monitor, error) {
 	// Get monitor from Datadog and update resource status if needed
-	m, err := getMonitor(r.datadogAuth, r.datadogClient, datadogMonitor.Status.ID)
+	m, err := getMonitor(ddClient.Auth, ddClient.Client, datadogMonitor.Status.ID)
 	if err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusGetError
 		return m, err
@@ -385,7 +520,8 @@ func convertStateToStatus(monitor datadogapiclientv1.Monitor, newStatus *datadog
 	newStatus.DowntimeStatus = datadoghqv1alpha1.DatadogMonitorDowntimeStatus{}
 }
 
-func isSupportedMonitorType(monitorType datadoghqv1alpha1.DatadogMonitorType) bool {
+// IsSupportedMonitorType returns true if monitors of this type can be managed with a DatadogMonitor
+func IsSupportedMonitorType(monitorType datadoghqv1alpha1.DatadogMonitorType) bool {
 	return supportedMonitorTypes[string(monitorType)]
 }
 
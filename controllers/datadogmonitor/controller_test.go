@@ -29,6 +29,7 @@ import (
 	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 const (
@@ -398,7 +399,33 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 			},
 		},
 		{
-			name: "DatadogMonitor of unsupported type (composite)",
+			name: "DatadogMonitor composite, referenced monitor not created yet",
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				firstAction: func(c client.Client) {
+					dm := newTestMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${high-cpu} && ${disk-usage}", 0)
+					dm.Spec.Name = "test monitor"
+					dm.Spec.Message = "something is wrong"
+					_ = c.Create(context.TODO(), dm)
+					_ = c.Create(context.TODO(), newTestMonitor("high-cpu", datadoghqv1alpha1.DatadogMonitorTypeMetric, "avg(last_5m):avg:system.cpu.user{*} > 90", 1234))
+				},
+				firstReconcileCount: 2,
+			},
+			wantResult: reconcile.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod},
+			wantErr:    false,
+			wantFunc: func(c client.Client) error {
+				dm := &datadoghqv1alpha1.DatadogMonitor{}
+				if err := c.Get(context.TODO(), types.NamespacedName{Name: resourcesName, Namespace: resourcesNamespace}, dm); err != nil {
+					return err
+				}
+				assert.Equal(t, 0, dm.Status.ID)
+				assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeError, dm.Status.Conditions[0].Type)
+				assert.Contains(t, dm.Status.Conditions[0].Message, "waiting for the referenced DatadogMonitors to be created: disk-usage")
+				return nil
+			},
+		},
+		{
+			name: "DatadogMonitor of unsupported type (synthetics alert)",
 			args: args{
 				request: newRequest(resourcesNamespace, resourcesName),
 				firstAction: func(c client.Client) {
@@ -413,7 +440,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 						},
 						Spec: datadoghqv1alpha1.DatadogMonitorSpec{
 							Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.1",
-							Type:    datadoghqv1alpha1.DatadogMonitorTypeComposite,
+							Type:    "synthetics alert",
 							Name:    "test monitor",
 							Message: "something is wrong",
 						},
@@ -432,6 +459,44 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "DatadogMonitor of unsupported type (synthetics alert) adopting an existing monitor",
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				firstAction: func(c client.Client) {
+					_ = c.Create(context.TODO(), &datadoghqv1alpha1.DatadogMonitor{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "DatadogMonitor",
+							APIVersion: fmt.Sprintf("%s/%s", datadoghqv1alpha1.GroupVersion.Group, datadoghqv1alpha1.GroupVersion.Version),
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: resourcesNamespace,
+							Name:      resourcesName,
+						},
+						Spec: datadoghqv1alpha1.DatadogMonitorSpec{
+							Query:          "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.1",
+							Type:           "synthetics alert",
+							Name:           "test monitor",
+							Message:        "something is wrong",
+							AdoptMonitorID: 12345,
+						},
+					})
+				},
+				firstReconcileCount: 2,
+			},
+			wantResult: reconcile.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod},
+			wantErr:    false,
+			wantFunc: func(c client.Client) error {
+				dm := &datadoghqv1alpha1.DatadogMonitor{}
+				if err := c.Get(context.TODO(), types.NamespacedName{Name: resourcesName, Namespace: resourcesNamespace}, dm); err != nil {
+					return err
+				}
+				assert.Equal(t, 0, dm.Status.ID)
+				assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeError, dm.Status.Conditions[0].Type)
+				assert.Contains(t, dm.Status.Conditions[0].Message, "monitor type synthetics alert not supported")
+				return nil
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -449,8 +514,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 			// Set up
 			r := &Reconciler{
 				client:        fake.NewFakeClient(),
-				datadogClient: client,
-				datadogAuth:   testAuth,
+				datadogClient: datadogclient.NewSharedClient(testLogger, datadogclient.DatadogClient{Client: client, Auth: testAuth}),
 				scheme:        s,
 				recorder:      recorder,
 				log:           logf.Log.WithName(tt.name),
@@ -939,3 +1003,82 @@ func testAuditMonitor() *datadoghqv1alpha1.DatadogMonitor {
 		},
 	}
 }
+
+func TestReconciler_adopt(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	mID := 12345
+	jsonMonitor, _ := genericMonitor(mID).MarshalJSON()
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonMonitor)
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	ddClient := datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: setupTestAuth(httpServer.URL)}
+
+	newAdoptingMonitor := func(name string, monitorType datadoghqv1alpha1.DatadogMonitorType) *datadoghqv1alpha1.DatadogMonitor {
+		dm := newTestMonitor(name, monitorType, "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05", 0)
+		dm.Spec.AdoptMonitorID = mID
+		return dm
+	}
+
+	tests := []struct {
+		name       string
+		dm         *datadoghqv1alpha1.DatadogMonitor
+		existing   []client.Object
+		wantErr    string
+		wantStatus bool
+	}{
+		{
+			name:       "monitor adopted",
+			dm:         newAdoptingMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeMetric),
+			wantStatus: true,
+		},
+		{
+			name:       "metric alert monitor adopted as a query alert",
+			dm:         newAdoptingMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeQuery),
+			wantStatus: true,
+		},
+		{
+			name:     "monitor already managed by another DatadogMonitor",
+			dm:       newAdoptingMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeMetric),
+			existing: []client.Object{newTestMonitor("other", datadoghqv1alpha1.DatadogMonitorTypeMetric, "", mID)},
+			wantErr:  "monitor 12345 is already managed by the DatadogMonitor bar/other",
+		},
+		{
+			name:    "monitor type mismatch",
+			dm:      newAdoptingMonitor(resourcesName, datadoghqv1alpha1.DatadogMonitorTypeLog),
+			wantErr: "its type metric alert doesn't match spec.Type log alert",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{
+				client:   fake.NewClientBuilder().WithScheme(s).WithObjects(append(tt.existing, tt.dm)...).Build(),
+				recorder: record.NewFakeRecorder(10),
+				log:      logf.Log.WithName(t.Name()),
+			}
+
+			status := &datadoghqv1alpha1.DatadogMonitorStatus{}
+			now := metav1.Now()
+			err := r.adopt(r.log, ddClient, nil, tt.dm, status, now, "hash")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, 0, status.ID)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, mID, status.ID)
+			assert.True(t, status.Primary)
+			assert.Equal(t, "hash", status.CurrentHash)
+			assert.Equal(t, datadoghqv1alpha1.MonitorStateSyncStatusOK, status.MonitorStateSyncStatus)
+			assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeCreated, status.Conditions[0].Type)
+			assert.Equal(t, "DatadogMonitor Adopted", status.Conditions[0].Message)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2021 Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// CredentialsSecretIndexField is the field index of the DatadogMonitors on the Secret of their credentials
+const CredentialsSecretIndexField = "spec.credentials.secretName"
+
+// hashCredentials is used to identify the Datadog API clients of the DatadogMonitors with their own credentials
+// hashCredentials is NOT a security function
+func hashCredentials(creds config.Creds, site string) string {
+	h := fnv.New64()
+	for _, value := range []string{creds.APIKey, creds.AppKey, site} {
+		_, _ = h.Write([]byte(value))
+		_, _ = h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// getMonitorClient returns the Datadog API client managing the monitor, and the credentials status describing it
+// The operator client and a nil status are returned if the DatadogMonitor doesn't define its own credentials
+func (r *Reconciler) getMonitorClient(dm *datadoghqv1alpha1.DatadogMonitor) (datadogclient.DatadogClient, *datadoghqv1alpha1.DatadogMonitorCredentialsStatus, error) {
+	if dm.Spec.Credentials == nil {
+		datadogAuth, datadogClient := r.datadogClient.Get()
+		return datadogclient.DatadogClient{Client: datadogClient, Auth: datadogAuth}, nil, nil
+	}
+
+	creds, err := r.readCredentials(dm.Namespace, dm.Spec.Credentials)
+	if err != nil {
+		return datadogclient.DatadogClient{}, nil, err
+	}
+	ddClient, hash, err := r.getCachedClient(creds, dm.Spec.Site)
+	if err != nil {
+		return datadogclient.DatadogClient{}, nil, err
+	}
+
+	return ddClient, &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: dm.Spec.Credentials.DeepCopy(),
+		Site:   dm.Spec.Site,
+		Hash:   hash,
+	}, nil
+}
+
+// credentialsChanged returns true if the credentials of the DatadogMonitor are not the ones managing the monitor, recorded in its status
+func credentialsChanged(current, desired *datadoghqv1alpha1.DatadogMonitorCredentialsStatus) bool {
+	if current == nil || desired == nil {
+		return current != desired
+	}
+	return current.Hash != desired.Hash
+}
+
+// migrateMonitor handles a change of the DatadogMonitor credentials. If the new credentials can't get the monitor, they belong
+// to another organization: the monitor is removed with the former credentials, following the deletion policy, and the status is
+// reset so that it is created again. It returns true in this case. Otherwise, for instance when the keys are rotated, the monitor is kept.
+func (r *Reconciler) migrateMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, ddClient datadogclient.DatadogClient, credentialsStatus *datadoghqv1alpha1.DatadogMonitorCredentialsStatus, status *datadoghqv1alpha1.DatadogMonitorStatus) (bool, error) {
+	_, err := getMonitor(ddClient.Auth, ddClient.Client, dm.Status.ID)
+	if err == nil {
+		logger.Info("Credentials changed, the monitor is managed by the new credentials", "Monitor ID", dm.Status.ID)
+		status.Credentials = credentialsStatus
+		r.evictClient(dm)
+
+		return false, nil
+	}
+	if !datadogclient.IsNotFound(err) {
+		return false, err
+	}
+
+	logger.Info("Credentials changed to another organization, moving the monitor", "Monitor ID", dm.Status.ID)
+	if dm.Status.Primary {
+		if err = r.removeMonitor(logger, dm); err != nil && !datadogclient.IsNotFound(err) {
+			return false, fmt.Errorf("unable to remove the monitor %d managed by the former credentials: %w", dm.Status.ID, err)
+		}
+	}
+	r.evictClient(dm)
+
+	status.ID = 0
+	status.Primary = false
+	status.Creator = ""
+	status.Created = nil
+	status.CurrentHash = ""
+	status.Credentials = nil
+
+	return true, nil
+}
+
+// evictClient removes from the cache the Datadog API client of the credentials recorded in the status of the DatadogMonitor,
+// unless another DatadogMonitor is managed by the same credentials
+func (r *Reconciler) evictClient(dm *datadoghqv1alpha1.DatadogMonitor) {
+	if dm.Status.Credentials == nil {
+		return
+	}
+	hash := dm.Status.Credentials.Hash
+
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(context.TODO(), dmList); err != nil {
+		r.log.Error(err, "Unable to list the DatadogMonitors")
+		return
+	}
+	for _, other := range dmList.Items {
+		if other.Namespace == dm.Namespace && other.Name == dm.Name {
+			continue
+		}
+		if other.Status.Credentials != nil && other.Status.Credentials.Hash == hash {
+			return
+		}
+	}
+
+	r.clientsMutex.Lock()
+	defer r.clientsMutex.Unlock()
+	delete(r.clients, hash)
+}
+
+// getStatusClient returns the Datadog API client of the credentials that created the monitor, recorded in its status
+// The keys are read again from the Secret if the client isn't cached anymore, for instance after a restart. An error is
+// returned if the Secret doesn't hold these keys anymore: the monitor can't be managed with other credentials.
+func (r *Reconciler) getStatusClient(dm *datadoghqv1alpha1.DatadogMonitor) (datadogclient.DatadogClient, error) {
+	status := dm.Status.Credentials
+	if status == nil {
+		datadogAuth, datadogClient := r.datadogClient.Get()
+		return datadogclient.DatadogClient{Client: datadogClient, Auth: datadogAuth}, nil
+	}
+
+	r.clientsMutex.Lock()
+	ddClient, found := r.clients[status.Hash]
+	r.clientsMutex.Unlock()
+	if found {
+		return ddClient, nil
+	}
+
+	if status.Secret == nil {
+		return datadogclient.DatadogClient{}, fmt.Errorf("credentials %s not found", status.Hash)
+	}
+	creds, err := r.readCredentials(dm.Namespace, status.Secret)
+	if err != nil {
+		return datadogclient.DatadogClient{}, err
+	}
+	if hashCredentials(creds, status.Site) != status.Hash {
+		return datadogclient.DatadogClient{}, fmt.Errorf("the credentials secret %s/%s doesn't hold the keys that created the monitor anymore", dm.Namespace, status.Secret.SecretName)
+	}
+	ddClient, _, err = r.getCachedClient(creds, status.Site)
+
+	return ddClient, err
+}
+
+// getCachedClient returns the Datadog API client of the credentials and site, created if not cached yet
+func (r *Reconciler) getCachedClient(creds config.Creds, site string) (datadogclient.DatadogClient, string, error) {
+	hash := hashCredentials(creds, site)
+
+	r.clientsMutex.Lock()
+	defer r.clientsMutex.Unlock()
+	if ddClient, found := r.clients[hash]; found {
+		return ddClient, hash, nil
+	}
+
+	ddClient, err := datadogclient.InitDatadogClientForSite(r.log, creds, site)
+	if err != nil {
+		return datadogclient.DatadogClient{}, "", err
+	}
+	if r.clients == nil {
+		r.clients = map[string]datadogclient.DatadogClient{}
+	}
+	r.clients[hash] = ddClient
+
+	return ddClient, hash, nil
+}
+
+// readCredentials reads the API and application keys from the Secret of the DatadogMonitor credentials
+// The Secret is read with the APIReader: the manager only caches the metadata of the Secrets.
+func (r *Reconciler) readCredentials(namespace string, credentials *datadoghqv1alpha1.DatadogMonitorCredentials) (config.Creds, error) {
+	secret := &corev1.Secret{}
+	if err := r.apiReader.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: credentials.SecretName}, secret); err != nil {
+		return config.Creds{}, fmt.Errorf("unable to get the credentials secret %s/%s: %w", namespace, credentials.SecretName, err)
+	}
+
+	apiKeyName := credentials.APIKeyName
+	if apiKeyName == "" {
+		apiKeyName = apicommon.DefaultAPIKeyKey
+	}
+	appKeyName := credentials.AppKeyName
+	if appKeyName == "" {
+		appKeyName = apicommon.DefaultAPPKeyKey
+	}
+
+	creds := config.Creds{
+		APIKey: string(secret.Data[apiKeyName]),
+		AppKey: string(secret.Data[appKeyName]),
+	}
+	if creds.APIKey == "" || creds.AppKey == "" {
+		return config.Creds{}, fmt.Errorf("keys %s and %s must be defined in the credentials secret %s/%s", apiKeyName, appKeyName, namespace, credentials.SecretName)
+	}
+
+	return creds, nil
+}
+
+// CheckOperatorCredentials returns an error if the DatadogMonitor isn't managed with the Operator credentials: the DatadogSLOs
+// and DatadogDowntimes use the Operator credentials, they can't reference a monitor of another organization.
+func CheckOperatorCredentials(dm *datadoghqv1alpha1.DatadogMonitor) error {
+	if dm.Spec.Credentials != nil || dm.Status.Credentials != nil {
+		return fmt.Errorf("the DatadogMonitor %s isn't managed with the Operator credentials and can't be referenced", dm.Name)
+	}
+	return nil
+}
+
+// CredentialsSecretIndexer indexes a DatadogMonitor on the name of the Secret of its credentials, see CredentialsSecretIndexField.
+func CredentialsSecretIndexer(obj client.Object) []string {
+	dm, ok := obj.(*datadoghqv1alpha1.DatadogMonitor)
+	if !ok || dm.Spec.Credentials == nil {
+		return nil
+	}
+	return []string{dm.Spec.Credentials.SecretName}
+}
+
+// EnqueueMonitorsWithCredentials enqueues the DatadogMonitors whose credentials are in the Secret obj, to update their
+// Datadog API client when the keys change.
+func (r *Reconciler) EnqueueMonitorsWithCredentials(obj client.Object) []reconcile.Request {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(context.TODO(), dmList, client.InNamespace(obj.GetNamespace()), client.MatchingFields{CredentialsSecretIndexField: obj.GetName()}); err != nil {
+		r.log.Error(err, "Unable to list the DatadogMonitors", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(dmList.Items))
+	for _, dm := range dmList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dm.Namespace, Name: dm.Name}})
+	}
+
+	return requests
+}
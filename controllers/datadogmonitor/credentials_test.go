@@ -0,0 +1,288 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2021 Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+func Test_hashCredentials(t *testing.T) {
+	creds := config.Creds{APIKey: "api-key", AppKey: "app-key"}
+	assert.Equal(t, hashCredentials(creds, ""), hashCredentials(creds, ""))
+	assert.NotEqual(t, hashCredentials(creds, ""), hashCredentials(creds, "datadoghq.eu"))
+	assert.NotEqual(t, hashCredentials(creds, ""), hashCredentials(config.Creds{APIKey: "api-key", AppKey: "app-key-2"}, ""))
+	assert.NotEqual(t, hashCredentials(config.Creds{APIKey: "ab", AppKey: "c"}, ""), hashCredentials(config.Creds{APIKey: "a", AppKey: "bc"}, ""))
+}
+
+func TestReconciler_getMonitorClient(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "team-keys"},
+		Data: map[string][]byte{
+			"api_key":      []byte("team-api-key"),
+			"app_key":      []byte("team-app-key"),
+			"eu-app-key":   []byte("team-eu-app-key"),
+			"empty-string": []byte(""),
+		},
+	}
+	operatorClient := datadogapiclientv1.NewAPIClient(datadogapiclientv1.NewConfiguration())
+	r := &Reconciler{
+		client: fake.NewClientBuilder().Build(),
+		// The Secrets are read without the cache
+		apiReader:     fake.NewClientBuilder().WithObjects(secret).Build(),
+		datadogClient: datadogclient.NewSharedClient(testLogger, datadogclient.DatadogClient{Client: operatorClient, Auth: context.TODO()}),
+		log:           testLogger,
+	}
+
+	newMonitor := func(credentials *datadoghqv1alpha1.DatadogMonitorCredentials, site string) *datadoghqv1alpha1.DatadogMonitor {
+		return &datadoghqv1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+			Spec:       datadoghqv1alpha1.DatadogMonitorSpec{Credentials: credentials, Site: site},
+		}
+	}
+
+	// No credentials: the operator client is used
+	ddClient, status, err := r.getMonitorClient(newMonitor(nil, ""))
+	require.NoError(t, err)
+	assert.Same(t, operatorClient, ddClient.Client)
+	assert.Nil(t, status)
+
+	// Default keys of the Secret
+	credentials := &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"}
+	ddClient, status, err = r.getMonitorClient(newMonitor(credentials, ""))
+	require.NoError(t, err)
+	assert.NotSame(t, operatorClient, ddClient.Client)
+	assert.Equal(t, &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: credentials,
+		Hash:   hashCredentials(config.Creds{APIKey: "team-api-key", AppKey: "team-app-key"}, ""),
+	}, status)
+
+	// Same credentials: the cached client is reused
+	cachedClient, _, err := r.getMonitorClient(newMonitor(credentials, ""))
+	require.NoError(t, err)
+	assert.Same(t, ddClient.Client, cachedClient.Client)
+
+	// Custom keys and site
+	euCredentials := &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys", AppKeyName: "eu-app-key"}
+	euClient, status, err := r.getMonitorClient(newMonitor(euCredentials, "datadoghq.eu"))
+	require.NoError(t, err)
+	assert.NotSame(t, ddClient.Client, euClient.Client)
+	assert.Equal(t, "datadoghq.eu", status.Site)
+	assert.Equal(t, hashCredentials(config.Creds{APIKey: "team-api-key", AppKey: "team-eu-app-key"}, "datadoghq.eu"), status.Hash)
+	assert.Len(t, r.clients, 2)
+
+	// Missing Secret and empty key
+	_, _, err = r.getMonitorClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "missing"}, ""))
+	assert.Error(t, err)
+	_, _, err = r.getMonitorClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys", AppKeyName: "empty-string"}, ""))
+	assert.Error(t, err)
+}
+
+func TestReconciler_getStatusClient(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "team-keys"},
+		Data:       map[string][]byte{"api_key": []byte("team-api-key"), "app_key": []byte("team-app-key")},
+	}
+	operatorClient := datadogapiclientv1.NewAPIClient(datadogapiclientv1.NewConfiguration())
+	teamClient := datadogapiclientv1.NewAPIClient(datadogapiclientv1.NewConfiguration())
+	r := &Reconciler{
+		client: fake.NewClientBuilder().Build(),
+		// The Secrets are read without the cache
+		apiReader:     fake.NewClientBuilder().WithObjects(secret).Build(),
+		datadogClient: datadogclient.NewSharedClient(testLogger, datadogclient.DatadogClient{Client: operatorClient, Auth: context.TODO()}),
+		clients:       map[string]datadogclient.DatadogClient{"cached": {Client: teamClient, Auth: context.TODO()}},
+		log:           testLogger,
+	}
+
+	newMonitor := func(status *datadoghqv1alpha1.DatadogMonitorCredentialsStatus) *datadoghqv1alpha1.DatadogMonitor {
+		return &datadoghqv1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+			// The spec credentials changed since the creation of the monitor, they are ignored
+			Spec:   datadoghqv1alpha1.DatadogMonitorSpec{Credentials: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "other-keys"}},
+			Status: datadoghqv1alpha1.DatadogMonitorStatus{Credentials: status},
+		}
+	}
+
+	// Created with the operator credentials
+	ddClient, err := r.getStatusClient(newMonitor(nil))
+	require.NoError(t, err)
+	assert.Same(t, operatorClient, ddClient.Client)
+
+	// Client still cached
+	ddClient, err = r.getStatusClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "cached"}))
+	require.NoError(t, err)
+	assert.Same(t, teamClient, ddClient.Client)
+
+	// Client not cached anymore: the keys are read from the Secret that created the monitor
+	teamHash := hashCredentials(config.Creds{APIKey: "team-api-key", AppKey: "team-app-key"}, "")
+	ddClient, err = r.getStatusClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"},
+		Hash:   teamHash,
+	}))
+	require.NoError(t, err)
+	assert.NotSame(t, operatorClient, ddClient.Client)
+	assert.Contains(t, r.clients, teamHash)
+
+	// The Secret holds other keys now, for instance of another organization: they can't manage the monitor
+	_, err = r.getStatusClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"},
+		Hash:   "former-keys",
+	}))
+	assert.EqualError(t, err, "the credentials secret bar/team-keys doesn't hold the keys that created the monitor anymore")
+	assert.NotContains(t, r.clients, "former-keys")
+
+	// The Secret was deleted
+	_, err = r.getStatusClient(newMonitor(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "deleted-keys"},
+		Hash:   teamHash + "-deleted",
+	}))
+	assert.Error(t, err)
+}
+
+func TestReconciler_migrateMonitor(t *testing.T) {
+	newServer := func(found bool, requests *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*requests = append(*requests, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errors": ["Monitor not found"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": 12345, "type": "metric alert", "query": "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05"}`))
+		}))
+	}
+	newClient := func(httpServer *httptest.Server) datadogclient.DatadogClient {
+		testConfig := datadogapiclientv1.NewConfiguration()
+		testConfig.HTTPClient = httpServer.Client()
+		return datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: setupTestAuth(httpServer.URL)}
+	}
+	newCredentials := &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+		Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "new-keys"},
+		Hash:   "new",
+	}
+
+	tests := []struct {
+		name             string
+		newOrgFound      bool
+		wantMigrated     bool
+		wantOldRequests  []string
+		wantStatusID     int
+		wantCredentials  *datadoghqv1alpha1.DatadogMonitorCredentialsStatus
+		wantClientCached bool
+	}{
+		{
+			name:            "keys rotated, the monitor is kept",
+			newOrgFound:     true,
+			wantMigrated:    false,
+			wantStatusID:    12345,
+			wantCredentials: newCredentials,
+		},
+		{
+			name:            "another organization, the monitor is deleted with the former credentials",
+			newOrgFound:     false,
+			wantMigrated:    true,
+			wantOldRequests: []string{http.MethodDelete},
+			wantStatusID:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldRequests, newRequests []string
+			oldServer := newServer(true, &oldRequests)
+			defer oldServer.Close()
+			newServer := newServer(tt.newOrgFound, &newRequests)
+			defer newServer.Close()
+
+			r := &Reconciler{
+				client:   fake.NewClientBuilder().Build(),
+				clients:  map[string]datadogclient.DatadogClient{"old": newClient(oldServer)},
+				recorder: record.NewFakeRecorder(10),
+				log:      testLogger,
+			}
+			dm := &datadoghqv1alpha1.DatadogMonitor{
+				ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+				Status: datadoghqv1alpha1.DatadogMonitorStatus{
+					ID:          12345,
+					Primary:     true,
+					CurrentHash: "spec-hash",
+					Credentials: &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "old"},
+				},
+			}
+			status := dm.Status.DeepCopy()
+
+			migrated, err := r.migrateMonitor(testLogger, dm, newClient(newServer), newCredentials, status)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMigrated, migrated)
+			assert.Equal(t, []string{http.MethodGet}, newRequests)
+			assert.Equal(t, tt.wantOldRequests, oldRequests)
+			assert.Equal(t, tt.wantStatusID, status.ID)
+			assert.Equal(t, tt.wantCredentials, status.Credentials)
+			assert.NotContains(t, r.clients, "old")
+		})
+	}
+}
+
+func TestReconciler_evictClient(t *testing.T) {
+	newMonitor := func(name, hash string) *datadoghqv1alpha1.DatadogMonitor {
+		return &datadoghqv1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name},
+			Status:     datadoghqv1alpha1.DatadogMonitorStatus{Credentials: &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: hash}},
+		}
+	}
+	first, second := newMonitor("first", "shared"), newMonitor("second", "shared")
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithObjects(first, second).Build(),
+		clients: map[string]datadogclient.DatadogClient{
+			"shared": {Auth: context.TODO()},
+			"other":  {Auth: context.TODO()},
+		},
+		log: testLogger,
+	}
+
+	// The client is still used by the second DatadogMonitor
+	r.evictClient(first)
+	assert.Contains(t, r.clients, "shared")
+
+	require.NoError(t, r.client.Delete(context.TODO(), first))
+	r.evictClient(second)
+	assert.NotContains(t, r.clients, "shared")
+	assert.Contains(t, r.clients, "other")
+
+	// Monitor managed by the operator credentials
+	r.evictClient(&datadoghqv1alpha1.DatadogMonitor{})
+	assert.Len(t, r.clients, 1)
+}
+
+func TestCredentialsSecretIndexer(t *testing.T) {
+	assert.Nil(t, CredentialsSecretIndexer(&datadoghqv1alpha1.DatadogMonitor{}))
+	assert.Nil(t, CredentialsSecretIndexer(&corev1.Secret{}))
+	assert.Equal(t, []string{"team-keys"}, CredentialsSecretIndexer(&datadoghqv1alpha1.DatadogMonitor{
+		Spec: datadoghqv1alpha1.DatadogMonitorSpec{Credentials: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"}},
+	}))
+}
+
+func Test_credentialsChanged(t *testing.T) {
+	assert.False(t, credentialsChanged(nil, nil))
+	assert.True(t, credentialsChanged(nil, &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "a"}))
+	assert.True(t, credentialsChanged(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "a"}, nil))
+	assert.True(t, credentialsChanged(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "a"}, &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "b"}))
+	assert.False(t, credentialsChanged(&datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "a"}, &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{Hash: "a"}))
+}
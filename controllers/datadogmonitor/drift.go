@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+const driftDetectedEventReason = "DriftDetected"
+
+// getDriftPolicy returns the drift policy of the DatadogMonitor, Revert by default
+func getDriftPolicy(dm *datadoghqv1alpha1.DatadogMonitor) datadoghqv1alpha1.DatadogMonitorDriftPolicy {
+	if dm.Spec.ControllerOptions.DriftPolicy != "" {
+		return dm.Spec.ControllerOptions.DriftPolicy
+	}
+	return datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert
+}
+
+// detectDrift compares the monitor in Datadog with the DatadogMonitor, and reports the differences in the DriftDetected
+// condition and with an event. It returns true if the monitor should be updated with the DatadogMonitor spec.
+func (r *Reconciler) detectDrift(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, m datadogapiclientv1.Monitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) bool {
+	policy := getDriftPolicy(dm)
+	if policy == datadoghqv1alpha1.DatadogMonitorDriftPolicyIgnore {
+		condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDriftDetected, corev1.ConditionFalse, "")
+		return false
+	}
+
+	paths := monitorDrift(logger, dm, m)
+	if len(paths) == 0 {
+		condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDriftDetected, corev1.ConditionFalse, "")
+		return policy == datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert
+	}
+
+	msg := fmt.Sprintf("Monitor %d changed outside of the DatadogMonitor: %s", dm.Status.ID, strings.Join(paths, ", "))
+	if policy == datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert {
+		msg += ", reverting the changes"
+	}
+	logger.Info("Drift detected", "Monitor ID", dm.Status.ID, "Paths", paths, "Drift Policy", policy)
+	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDriftDetected, corev1.ConditionTrue, msg)
+	r.recorder.Event(dm, corev1.EventTypeWarning, driftDetectedEventReason, msg)
+
+	return policy == datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert
+}
+
+// monitorDrift returns the paths of the DatadogMonitor spec whose value differs in the monitor.
+// The options not set in the spec aren't compared, as Datadog sets them to default values.
+func monitorDrift(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, m datadogapiclientv1.Monitor) []string {
+	var paths []string
+	compare := func(path string, desired, actual interface{}) {
+		if !reflect.DeepEqual(desired, actual) {
+			paths = append(paths, path)
+		}
+	}
+
+	_, u := buildMonitor(logger, dm)
+	compare("spec.name", u.GetName(), m.GetName())
+	compare("spec.message", u.GetMessage(), m.GetMessage())
+	compare("spec.priority", u.GetPriority(), m.GetPriority())
+	compare("spec.query", u.GetQuery(), m.GetQuery())
+	compare("spec.type", normalizedMonitorType(u.GetType()), normalizedMonitorType(m.GetType()))
+	compare("spec.restrictedRoles", sortedStrings(u.GetRestrictedRoles()), sortedStrings(m.GetRestrictedRoles()))
+	compare("spec.tags", sortedStrings(u.GetTags()), sortedStrings(m.GetTags()))
+
+	desired, actual := u.GetOptions(), m.GetOptions()
+
+	desiredThresholds, actualThresholds := desired.GetThresholds(), actual.GetThresholds()
+	if v, ok := desiredThresholds.GetOkOk(); ok && v != nil {
+		compare("spec.options.thresholds.ok", *v, actualThresholds.GetOk())
+	}
+	if v, ok := desiredThresholds.GetWarningOk(); ok && v != nil {
+		compare("spec.options.thresholds.warning", *v, actualThresholds.GetWarning())
+	}
+	if v, ok := desiredThresholds.GetUnknownOk(); ok && v != nil {
+		compare("spec.options.thresholds.unknown", *v, actualThresholds.GetUnknown())
+	}
+	if v, ok := desiredThresholds.GetCriticalOk(); ok && v != nil {
+		compare("spec.options.thresholds.critical", *v, actualThresholds.GetCritical())
+	}
+	if v, ok := desiredThresholds.GetWarningRecoveryOk(); ok && v != nil {
+		compare("spec.options.thresholds.warningRecovery", *v, actualThresholds.GetWarningRecovery())
+	}
+	if v, ok := desiredThresholds.GetCriticalRecoveryOk(); ok && v != nil {
+		compare("spec.options.thresholds.criticalRecovery", *v, actualThresholds.GetCriticalRecovery())
+	}
+
+	desiredWindows, actualWindows := desired.GetThresholdWindows(), actual.GetThresholdWindows()
+	if v, ok := desiredWindows.GetRecoveryWindowOk(); ok && v != nil {
+		compare("spec.options.thresholdWindows.recoveryWindow", *v, actualWindows.GetRecoveryWindow())
+	}
+	if v, ok := desiredWindows.GetTriggerWindowOk(); ok && v != nil {
+		compare("spec.options.thresholdWindows.triggerWindow", *v, actualWindows.GetTriggerWindow())
+	}
+
+	if v, ok := desired.GetEscalationMessageOk(); ok && v != nil {
+		compare("spec.options.escalationMessage", *v, actual.GetEscalationMessage())
+	}
+	if v, ok := desired.GetEvaluationDelayOk(); ok && v != nil {
+		compare("spec.options.evaluationDelay", *v, actual.GetEvaluationDelay())
+	}
+	if v, ok := desired.GetIncludeTagsOk(); ok && v != nil {
+		compare("spec.options.includeTags", *v, actual.GetIncludeTags())
+	}
+	if v, ok := desired.GetLockedOk(); ok && v != nil {
+		compare("spec.options.locked", *v, actual.GetLocked())
+	}
+	if v, ok := desired.GetNewGroupDelayOk(); ok && v != nil {
+		compare("spec.options.newGroupDelay", *v, actual.GetNewGroupDelay())
+	}
+	if v, ok := desired.GetEnableLogsSampleOk(); ok && v != nil {
+		compare("spec.options.enableLogsSample", *v, actual.GetEnableLogsSample())
+	}
+	if v, ok := desired.GetNoDataTimeframeOk(); ok && v != nil {
+		compare("spec.options.noDataTimeframe", *v, actual.GetNoDataTimeframe())
+	}
+	if v, ok := desired.GetNotifyAuditOk(); ok && v != nil {
+		compare("spec.options.notifyAudit", *v, actual.GetNotifyAudit())
+	}
+	if v, ok := desired.GetNotifyNoDataOk(); ok && v != nil {
+		compare("spec.options.notifyNoData", *v, actual.GetNotifyNoData())
+	}
+	if v, ok := desired.GetRequireFullWindowOk(); ok && v != nil {
+		compare("spec.options.requireFullWindow", *v, actual.GetRequireFullWindow())
+	}
+	if v, ok := desired.GetRenotifyIntervalOk(); ok && v != nil {
+		compare("spec.options.renotifyInterval", *v, actual.GetRenotifyInterval())
+	}
+	if v, ok := desired.GetTimeoutHOk(); ok && v != nil {
+		compare("spec.options.timeoutH", *v, actual.GetTimeoutH())
+	}
+	if v, ok := desired.GetRenotifyOccurrencesOk(); ok && v != nil {
+		compare("spec.options.renotifyOccurrences", *v, actual.GetRenotifyOccurrences())
+	}
+	if v, ok := desired.GetRenotifyStatusesOk(); ok && v != nil {
+		compare("spec.options.renotifyStatuses", sortedRenotifyStatuses(*v), sortedRenotifyStatuses(actual.GetRenotifyStatuses()))
+	}
+	if v, ok := desired.GetNewHostDelayOk(); ok && v != nil {
+		compare("spec.options.newHostDelay", *v, actual.GetNewHostDelay())
+	}
+	if v, ok := desired.GetMinLocationFailedOk(); ok && v != nil {
+		compare("spec.options.minLocationFailed", *v, actual.GetMinLocationFailed())
+	}
+	if v, ok := desired.GetVariablesOk(); ok && v != nil {
+		desiredVariables, _ := json.Marshal(*v)
+		actualVariables, _ := json.Marshal(actual.GetVariables())
+		compare("spec.options.variables", string(desiredVariables), string(actualVariables))
+	}
+
+	// The options that the client doesn't support are compared on the DatadogMonitor spec
+	desiredOptions, actualOptions := dm.Spec.Options, BuildDatadogMonitorSpec(m).Options
+	if desiredOptions.OnMissingData != "" {
+		compare("spec.options.onMissingData", desiredOptions.OnMissingData, actualOptions.OnMissingData)
+	}
+	if desiredOptions.GroupRetentionDuration != "" {
+		compare("spec.options.groupRetentionDuration", desiredOptions.GroupRetentionDuration, actualOptions.GroupRetentionDuration)
+	}
+	if len(desiredOptions.NotifyBy) > 0 {
+		compare("spec.options.notifyBy", sortedStrings(desiredOptions.NotifyBy), sortedStrings(actualOptions.NotifyBy))
+	}
+	if desiredOptions.NotificationPresetName != "" {
+		compare("spec.options.notificationPresetName", desiredOptions.NotificationPresetName, actualOptions.NotificationPresetName)
+	}
+	if desiredOptions.SchedulingOptions != nil && desiredOptions.SchedulingOptions.EvaluationWindow != nil {
+		compare("spec.options.schedulingOptions", desiredOptions.SchedulingOptions, actualOptions.SchedulingOptions)
+	}
+
+	return paths
+}
+
+// normalizedMonitorType returns the monitor type, with query alert for metric alert as Datadog can convert between them
+func normalizedMonitorType(monitorType datadogapiclientv1.MonitorType) datadogapiclientv1.MonitorType {
+	if monitorType == datadogapiclientv1.MONITORTYPE_METRIC_ALERT {
+		return datadogapiclientv1.MONITORTYPE_QUERY_ALERT
+	}
+	return monitorType
+}
+
+// sortedStrings returns a sorted copy of the strings, empty rather than nil
+func sortedStrings(in []string) []string {
+	out := append([]string{}, in...)
+	sort.Strings(out)
+	return out
+}
+
+// sortedRenotifyStatuses returns a sorted copy of the renotify statuses, empty rather than nil
+func sortedRenotifyStatuses(in []datadogapiclientv1.MonitorRenotifyStatusType) []string {
+	out := make([]string, 0, len(in))
+	for _, status := range in {
+		out = append(out, string(status))
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"testing"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func newDriftTestMonitor() *datadoghqv1alpha1.DatadogMonitor {
+	critThreshold := "0.05"
+	renotifyInterval := int64(1440)
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+		Spec: datadoghqv1alpha1.DatadogMonitorSpec{
+			Query:           "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+			Type:            datadoghqv1alpha1.DatadogMonitorTypeMetric,
+			Name:            "Test monitor",
+			Message:         "Something went wrong",
+			RestrictedRoles: []string{"an-admin-uuid"},
+			Tags:            []string{"generated:kubernetes", "env:staging"},
+			Options: datadoghqv1alpha1.DatadogMonitorOptions{
+				RenotifyInterval: &renotifyInterval,
+				Thresholds: &datadoghqv1alpha1.DatadogMonitorOptionsThresholds{
+					Critical: &critThreshold,
+				},
+			},
+		},
+		Status: datadoghqv1alpha1.DatadogMonitorStatus{ID: 12345},
+	}
+}
+
+// remoteMonitor returns the monitor as read from Datadog, with the default values set by Datadog
+func remoteMonitor(dm *datadoghqv1alpha1.DatadogMonitor) datadogapiclientv1.Monitor {
+	m, _ := buildMonitor(testLogger, dm)
+	m.SetType(datadogapiclientv1.MONITORTYPE_QUERY_ALERT)
+	m.SetRestrictedRoles(dm.Spec.RestrictedRoles)
+	o := m.GetOptions()
+	o.SetNotifyNoData(false)
+	o.SetIncludeTags(true)
+	m.SetOptions(o)
+	return *m
+}
+
+func Test_monitorDrift(t *testing.T) {
+	dm := newDriftTestMonitor()
+
+	// Same monitor, the options set to default values by Datadog aren't compared
+	assert.Empty(t, monitorDrift(testLogger, dm, remoteMonitor(dm)))
+
+	// Changed in the Datadog UI
+	m := remoteMonitor(dm)
+	m.SetTags([]string{"env:staging", "generated:kubernetes", "team:web"})
+	m.SetMessage("Something went wrong @team-web")
+	o := m.GetOptions()
+	thresholds := o.GetThresholds()
+	thresholds.SetCritical(0.1)
+	thresholds.SetWarning(0.05)
+	o.SetThresholds(thresholds)
+	o.SetRenotifyInterval(60)
+	m.SetOptions(o)
+	assert.Equal(t, []string{
+		"spec.message",
+		"spec.tags",
+		"spec.options.thresholds.critical",
+		"spec.options.renotifyInterval",
+	}, monitorDrift(testLogger, dm, m))
+
+	// The options that the client doesn't support are compared too
+	dm.Spec.Options.NotifyBy = []string{"host", "device"}
+	dm.Spec.Options.OnMissingData = "show_no_data"
+	m = remoteMonitor(dm)
+	assert.Empty(t, monitorDrift(testLogger, dm, m))
+	o = m.GetOptions()
+	o.AdditionalProperties["notify_by"] = []interface{}{"device", "host"}
+	o.AdditionalProperties["on_missing_data"] = "resolve"
+	m.SetOptions(o)
+	assert.Equal(t, []string{"spec.options.onMissingData"}, monitorDrift(testLogger, dm, m))
+}
+
+func TestReconciler_detectDrift(t *testing.T) {
+	dm := newDriftTestMonitor()
+	drifted := remoteMonitor(dm)
+	drifted.SetName("Test monitor (edited)")
+
+	tests := []struct {
+		name             string
+		policy           datadoghqv1alpha1.DatadogMonitorDriftPolicy
+		monitor          datadogapiclientv1.Monitor
+		wantUpdate       bool
+		wantDrift        bool
+		wantConditionMsg string
+	}{
+		{
+			name:       "no drift",
+			monitor:    remoteMonitor(dm),
+			wantUpdate: true,
+		},
+		{
+			name:             "drift reverted by default",
+			monitor:          drifted,
+			wantUpdate:       true,
+			wantDrift:        true,
+			wantConditionMsg: "Monitor 12345 changed outside of the DatadogMonitor: spec.name, reverting the changes",
+		},
+		{
+			name:             "drift reported",
+			policy:           datadoghqv1alpha1.DatadogMonitorDriftPolicyReport,
+			monitor:          drifted,
+			wantDrift:        true,
+			wantConditionMsg: "Monitor 12345 changed outside of the DatadogMonitor: spec.name",
+		},
+		{
+			name:    "drift ignored",
+			policy:  datadoghqv1alpha1.DatadogMonitorDriftPolicyIgnore,
+			monitor: drifted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{recorder: recorder, log: testLogger}
+			dm := newDriftTestMonitor()
+			dm.Spec.ControllerOptions.DriftPolicy = tt.policy
+			status := dm.Status.DeepCopy()
+
+			assert.Equal(t, tt.wantUpdate, r.detectDrift(testLogger, dm, tt.monitor, status, metav1.Now()))
+			if !tt.wantDrift {
+				assert.Empty(t, status.Conditions)
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeDriftDetected, status.Conditions[0].Type)
+			assert.Equal(t, corev1.ConditionTrue, status.Conditions[0].Status)
+			assert.Equal(t, tt.wantConditionMsg, status.Conditions[0].Message)
+			assert.Equal(t, "Warning DriftDetected "+tt.wantConditionMsg, <-recorder.Events)
+
+			// The drift condition is cleared once the monitor matches the DatadogMonitor again
+			r.detectDrift(testLogger, dm, remoteMonitor(dm), status, metav1.Now())
+			assert.Equal(t, corev1.ConditionFalse, status.Conditions[0].Status)
+		})
+	}
+}
@@ -8,24 +8,36 @@ package datadogmonitor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 const (
 	datadogMonitorFinalizer = "finalizer.monitor.datadoghq.com"
+
+	finalizeFailedEventReason = "FinalizeFailed"
 )
 
 func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (ctrl.Result, error) {
 	// Check if the DatadogMonitor instance is marked to be deleted, which is indicated by the deletion timestamp being set.
 	if dm.GetDeletionTimestamp() != nil {
 		if utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer) {
-			r.finalizeDatadogMonitor(logger, dm)
+			if err := r.finalizeDatadogMonitor(logger, dm); err != nil {
+				// The finalizer is kept until the monitor is removed, not to leak it in Datadog
+				r.recorder.Event(dm, corev1.EventTypeWarning, finalizeFailedEventReason, err.Error())
+				_, statusErr := r.updateStatusIfNeeded(logger, dm, metav1.NewTime(time.Now()), dm.Status.DeepCopy(), err, ctrl.Result{})
+
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, statusErr
+			}
 
 			dm.SetFinalizers(utils.RemoveString(dm.GetFinalizers(), datadogMonitorFinalizer))
 			err := r.client.Update(context.TODO(), dm)
@@ -51,18 +63,58 @@ func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.D
 	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) {
+// finalizeDatadogMonitor removes the monitor of the DatadogMonitor, it returns an error if the monitor may still exist in Datadog
+func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	if dm.Status.Primary {
-		err := deleteMonitor(r.datadogAuth, r.datadogClient, dm.Status.ID)
-		if err != nil {
-			logger.Error(err, "failed to finalize monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+		if err := r.removeMonitor(logger, dm); err != nil {
+			if !datadogclient.IsNotFound(err) {
+				logger.Error(err, "failed to finalize monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
 
-			return
+				return fmt.Errorf("unable to remove the monitor %d: %w", dm.Status.ID, err)
+			}
+			logger.Info("The monitor was already deleted", "Monitor ID", fmt.Sprint(dm.Status.ID))
+		} else {
+			logger.Info("Successfully finalized DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
 		}
-		logger.Info("Successfully finalized DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
-		event := buildEventInfo(dm.Name, dm.Namespace, datadog.DeletionEvent)
-		r.recordEvent(dm, event)
+		r.evictClient(dm)
+	}
+
+	return nil
+}
+
+// removeMonitor deletes the monitor with the credentials that created it, or orphans it following the deletion policy
+func (r *Reconciler) removeMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
+	ddClient, err := r.getStatusClient(dm)
+	if err != nil {
+		return fmt.Errorf("failed to get the monitor credentials: %w", err)
+	}
+	if r.getDeletionPolicy(dm) == datadoghqv1alpha1.DatadogMonitorDeletionPolicyOrphan {
+		// Keep the monitor in Datadog, tagged so that it can be found and adopted again
+		if err = orphanMonitor(ddClient.Auth, ddClient.Client, dm.Status.ID); err != nil {
+			return err
+		}
+		logger.Info("The monitor is orphaned", "Monitor ID", fmt.Sprint(dm.Status.ID))
+
+		return nil
+	}
+	if err = deleteMonitor(ddClient.Auth, ddClient.Client, dm.Status.ID); err != nil {
+		return err
+	}
+	event := buildEventInfo(dm.Name, dm.Namespace, datadog.DeletionEvent)
+	r.recordEvent(dm, event)
+
+	return nil
+}
+
+// getDeletionPolicy returns the deletion policy of the DatadogMonitor, or the default one of the operator
+func (r *Reconciler) getDeletionPolicy(dm *datadoghqv1alpha1.DatadogMonitor) datadoghqv1alpha1.DatadogMonitorDeletionPolicy {
+	if dm.Spec.ControllerOptions.DeletionPolicy != "" {
+		return dm.Spec.ControllerOptions.DeletionPolicy
+	}
+	if r.deletionPolicy != "" {
+		return r.deletionPolicy
 	}
+	return datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete
 }
 
 func (r *Reconciler) addFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
@@ -7,18 +7,28 @@ package datadogmonitor
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 var (
@@ -85,3 +95,179 @@ func Test_handleFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func Test_finalizeDatadogMonitor_deletionPolicy(t *testing.T) {
+	mID := 12345
+	monitor := genericMonitor(mID)
+	monitor.Tags = append(monitor.Tags, "generated:kubernetes")
+	jsonMonitor, _ := monitor.MarshalJSON()
+
+	testCases := []struct {
+		name          string
+		policy        datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+		defaultPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+		wantDeleted   bool
+	}{
+		{
+			name:        "no deletion policy, the monitor is deleted",
+			wantDeleted: true,
+		},
+		{
+			name:   "Orphan deletion policy, the monitor is kept",
+			policy: datadoghqv1alpha1.DatadogMonitorDeletionPolicyOrphan,
+		},
+		{
+			name:          "Orphan default deletion policy, the monitor is kept",
+			defaultPolicy: datadoghqv1alpha1.DatadogMonitorDeletionPolicyOrphan,
+		},
+		{
+			name:          "Delete deletion policy overrides the default one",
+			policy:        datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete,
+			defaultPolicy: datadoghqv1alpha1.DatadogMonitorDeletionPolicyOrphan,
+			wantDeleted:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var deleted bool
+			var updateBody []byte
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodDelete:
+					deleted = true
+					_, _ = w.Write([]byte(fmt.Sprintf(`{"deleted_monitor_id": %d}`, mID)))
+				case http.MethodPut:
+					updateBody, _ = io.ReadAll(r.Body)
+					_, _ = w.Write(jsonMonitor)
+				default:
+					_, _ = w.Write(jsonMonitor)
+				}
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			r := &Reconciler{
+				datadogClient:  datadogclient.NewSharedClient(testLogger, datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: setupTestAuth(httpServer.URL)}),
+				deletionPolicy: test.defaultPolicy,
+				recorder:       record.NewFakeRecorder(10),
+				log:            testLogger,
+			}
+
+			dm := &datadoghqv1alpha1.DatadogMonitor{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+				Spec: datadoghqv1alpha1.DatadogMonitorSpec{
+					ControllerOptions: datadoghqv1alpha1.DatadogMonitorControllerOptions{DeletionPolicy: test.policy},
+				},
+				Status: datadoghqv1alpha1.DatadogMonitorStatus{ID: mID, Primary: true},
+			}
+			require.NoError(t, r.finalizeDatadogMonitor(testLogger, dm))
+
+			assert.Equal(t, test.wantDeleted, deleted)
+			if test.wantDeleted {
+				assert.Nil(t, updateBody)
+				return
+			}
+			update := datadogapiclientv1.MonitorUpdateRequest{}
+			require.NoError(t, json.Unmarshal(updateBody, &update))
+			assert.Equal(t, []string{"env:staging", "kube_cluster:test.staging", "kube_namespace:test", "orphaned:kubernetes"}, update.GetTags())
+		})
+	}
+}
+
+func Test_handleFinalizer_removeMonitorError(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	metaNow := metav1.NewTime(time.Now())
+	mID := 12345
+
+	testCases := []struct {
+		name                 string
+		statusCode           int
+		credentials          *datadoghqv1alpha1.DatadogMonitorCredentialsStatus
+		finalizerShouldExist bool
+	}{
+		{
+			name:       "the monitor is deleted",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "the monitor was already deleted",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:                 "the monitor can't be deleted",
+			statusCode:           http.StatusForbidden,
+			finalizerShouldExist: true,
+		},
+		{
+			name:       "the credentials that created the monitor can't be read",
+			statusCode: http.StatusOK,
+			credentials: &datadoghqv1alpha1.DatadogMonitorCredentialsStatus{
+				Secret: &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "deleted-keys"},
+				Hash:   "deleted",
+			},
+			finalizerShouldExist: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var deleted bool
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.statusCode)
+				if test.statusCode != http.StatusOK {
+					_, _ = w.Write([]byte(`{"errors": ["error"]}`))
+					return
+				}
+				deleted = true
+				_, _ = w.Write([]byte(fmt.Sprintf(`{"deleted_monitor_id": %d}`, mID)))
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			recorder := record.NewFakeRecorder(10)
+			dm := &datadoghqv1alpha1.DatadogMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "bar",
+					Name:              "foo",
+					DeletionTimestamp: &metaNow,
+					Finalizers:        []string{datadogMonitorFinalizer},
+				},
+				Status: datadoghqv1alpha1.DatadogMonitorStatus{ID: mID, Primary: true, Credentials: test.credentials},
+			}
+			k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dm).Build()
+			r := &Reconciler{
+				client:        k8sClient,
+				apiReader:     k8sClient,
+				datadogClient: datadogclient.NewSharedClient(testLogger, datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: setupTestAuth(httpServer.URL)}),
+				recorder:      recorder,
+				scheme:        s,
+				log:           testLogger,
+			}
+
+			result, err := r.handleFinalizer(testLogger, dm)
+			assert.NoError(t, err)
+			assert.True(t, result.Requeue)
+
+			assert.Equal(t, test.finalizerShouldExist, utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer))
+			if !test.finalizerShouldExist {
+				assert.Equal(t, test.statusCode == http.StatusOK, deleted)
+				return
+			}
+			// The failure is reported, the monitor is removed again later
+			assert.False(t, deleted)
+			updated := &datadoghqv1alpha1.DatadogMonitor{}
+			require.NoError(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(dm), updated))
+			assert.True(t, utils.ContainsString(updated.GetFinalizers(), datadogMonitorFinalizer))
+			require.NotEmpty(t, updated.Status.Conditions)
+			assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeError, updated.Status.Conditions[len(updated.Status.Conditions)-1].Type)
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, "Warning FinalizeFailed unable to remove the monitor 12345")
+		})
+	}
+}
@@ -7,9 +7,7 @@ package datadogmonitor
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"net/url"
 	"sort"
 	"strconv"
 
@@ -17,6 +15,7 @@ import (
 
 	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 func buildMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (*datadogapiclientv1.Monitor, *datadogapiclientv1.MonitorUpdateRequest) {
@@ -144,6 +143,36 @@ func buildMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (*da
 		o.SetTimeoutH(*options.TimeoutH)
 	}
 
+	if options.RenotifyOccurrences != nil {
+		o.SetRenotifyOccurrences(*options.RenotifyOccurrences)
+	}
+
+	if len(options.RenotifyStatuses) > 0 {
+		statuses := make([]datadogapiclientv1.MonitorRenotifyStatusType, 0, len(options.RenotifyStatuses))
+		for _, status := range options.RenotifyStatuses {
+			statuses = append(statuses, datadogapiclientv1.MonitorRenotifyStatusType(status))
+		}
+		o.SetRenotifyStatuses(statuses)
+	}
+
+	if options.NewHostDelay != nil {
+		o.SetNewHostDelay(*options.NewHostDelay)
+	}
+
+	if options.MinLocationFailed != nil {
+		o.SetMinLocationFailed(*options.MinLocationFailed)
+	}
+
+	if len(options.Variables) > 0 {
+		o.SetVariables(buildMonitorVariables(options.Variables))
+	}
+
+	// The client doesn't have fields for these options, they are sent as additional properties
+	additionalProperties := buildMonitorAdditionalOptions(options)
+	if len(additionalProperties) > 0 {
+		o.AdditionalProperties = additionalProperties
+	}
+
 	m := datadogapiclientv1.NewMonitor(query, monitorType)
 	{
 		m.SetName(name)
@@ -171,14 +200,174 @@ func buildMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (*da
 	return m, u
 }
 
+// buildMonitorVariables builds the formula and function queries of a monitor from the DatadogMonitor variables
+func buildMonitorVariables(variables []datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinition) []datadogapiclientv1.MonitorFormulaAndFunctionQueryDefinition {
+	queries := make([]datadogapiclientv1.MonitorFormulaAndFunctionQueryDefinition, 0, len(variables))
+	for _, v := range variables {
+		compute := datadogapiclientv1.NewMonitorFormulaAndFunctionEventQueryDefinitionCompute(datadogapiclientv1.MonitorFormulaAndFunctionEventAggregation(v.Compute.Aggregation))
+		compute.Interval = v.Compute.Interval
+		compute.Metric = v.Compute.Metric
+
+		q := datadogapiclientv1.NewMonitorFormulaAndFunctionEventQueryDefinition(*compute, datadogapiclientv1.MonitorFormulaAndFunctionEventsDataSource(v.DataSource), v.Name)
+		if v.Search != nil {
+			q.SetSearch(*datadogapiclientv1.NewMonitorFormulaAndFunctionEventQueryDefinitionSearch(v.Search.Query))
+		}
+		if len(v.Indexes) > 0 {
+			q.SetIndexes(v.Indexes)
+		}
+		for _, g := range v.GroupBy {
+			groupBy := datadogapiclientv1.NewMonitorFormulaAndFunctionEventQueryGroupBy(g.Facet)
+			groupBy.Limit = g.Limit
+			if g.Sort != nil {
+				groupBySort := datadogapiclientv1.NewMonitorFormulaAndFunctionEventQueryGroupBySort(datadogapiclientv1.MonitorFormulaAndFunctionEventAggregation(g.Sort.Aggregation))
+				groupBySort.Metric = g.Sort.Metric
+				if g.Sort.Order != "" {
+					groupBySort.SetOrder(datadogapiclientv1.QuerySortOrder(g.Sort.Order))
+				}
+				groupBy.SetSort(*groupBySort)
+			}
+			q.GroupBy = append(q.GroupBy, *groupBy)
+		}
+
+		queries = append(queries, datadogapiclientv1.MonitorFormulaAndFunctionEventQueryDefinitionAsMonitorFormulaAndFunctionQueryDefinition(q))
+	}
+	return queries
+}
+
+// BuildDatadogMonitorSpec converts a monitor read from Datadog to a DatadogMonitorSpec, it is the inverse of buildMonitor
+func BuildDatadogMonitorSpec(m datadogapiclientv1.Monitor) datadoghqv1alpha1.DatadogMonitorSpec {
+	spec := datadoghqv1alpha1.DatadogMonitorSpec{
+		Name:            m.GetName(),
+		Message:         m.GetMessage(),
+		Priority:        m.GetPriority(),
+		Query:           m.GetQuery(),
+		RestrictedRoles: m.GetRestrictedRoles(),
+		Type:            datadoghqv1alpha1.DatadogMonitorType(m.GetType()),
+	}
+
+	tags := append([]string{}, m.GetTags()...)
+	sort.Strings(tags)
+	if len(tags) > 0 {
+		spec.Tags = tags
+	}
+
+	o, ok := m.GetOptionsOk()
+	if !ok || o == nil {
+		return spec
+	}
+	options := &spec.Options
+
+	if t, ok := o.GetThresholdsOk(); ok && t != nil {
+		thresholds := &datadoghqv1alpha1.DatadogMonitorOptionsThresholds{}
+		for _, threshold := range []struct {
+			get   func() (*float64, bool)
+			field **string
+		}{
+			{t.GetOkOk, &thresholds.OK},
+			{t.GetWarningOk, &thresholds.Warning},
+			{t.GetUnknownOk, &thresholds.Unknown},
+			{t.GetCriticalOk, &thresholds.Critical},
+			{t.GetWarningRecoveryOk, &thresholds.WarningRecovery},
+			{t.GetCriticalRecoveryOk, &thresholds.CriticalRecovery},
+		} {
+			if v, ok := threshold.get(); ok && v != nil {
+				s := strconv.FormatFloat(*v, 'f', -1, 64)
+				*threshold.field = &s
+			}
+		}
+		if *thresholds != (datadoghqv1alpha1.DatadogMonitorOptionsThresholds{}) {
+			options.Thresholds = thresholds
+		}
+	}
+
+	if w, ok := o.GetThresholdWindowsOk(); ok && w != nil {
+		thresholdWindows := &datadoghqv1alpha1.DatadogMonitorOptionsThresholdWindows{}
+		if v, ok := w.GetRecoveryWindowOk(); ok && v != nil {
+			thresholdWindows.RecoveryWindow = v
+		}
+		if v, ok := w.GetTriggerWindowOk(); ok && v != nil {
+			thresholdWindows.TriggerWindow = v
+		}
+		if *thresholdWindows != (datadoghqv1alpha1.DatadogMonitorOptionsThresholdWindows{}) {
+			options.ThresholdWindows = thresholdWindows
+		}
+	}
+
+	options.EscalationMessage, _ = o.GetEscalationMessageOk()
+	options.EvaluationDelay, _ = o.GetEvaluationDelayOk()
+	options.IncludeTags, _ = o.GetIncludeTagsOk()
+	options.Locked, _ = o.GetLockedOk()
+	options.NewGroupDelay, _ = o.GetNewGroupDelayOk()
+	options.EnableLogsSample, _ = o.GetEnableLogsSampleOk()
+	options.NoDataTimeframe, _ = o.GetNoDataTimeframeOk()
+	options.NotifyAudit, _ = o.GetNotifyAuditOk()
+	options.NotifyNoData, _ = o.GetNotifyNoDataOk()
+	options.RequireFullWindow, _ = o.GetRequireFullWindowOk()
+	options.RenotifyInterval, _ = o.GetRenotifyIntervalOk()
+	options.TimeoutH, _ = o.GetTimeoutHOk()
+	options.RenotifyOccurrences, _ = o.GetRenotifyOccurrencesOk()
+	options.NewHostDelay, _ = o.GetNewHostDelayOk()
+	options.MinLocationFailed, _ = o.GetMinLocationFailedOk()
+
+	for _, status := range o.GetRenotifyStatuses() {
+		options.RenotifyStatuses = append(options.RenotifyStatuses, datadoghqv1alpha1.DatadogMonitorRenotifyStatus(status))
+	}
+
+	for _, variable := range o.GetVariables() {
+		if variable.MonitorFormulaAndFunctionEventQueryDefinition != nil {
+			options.Variables = append(options.Variables, buildDatadogMonitorVariable(*variable.MonitorFormulaAndFunctionEventQueryDefinition))
+		}
+	}
+
+	// The options that the client doesn't support are read from the additional properties, see SetMonitorAdditionalOptions
+	buildDatadogMonitorAdditionalOptions(options, o.AdditionalProperties)
+
+	return spec
+}
+
+// buildDatadogMonitorVariable builds a DatadogMonitor variable from a formula and function query of a monitor
+func buildDatadogMonitorVariable(q datadogapiclientv1.MonitorFormulaAndFunctionEventQueryDefinition) datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinition {
+	variable := datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinition{
+		Name:       q.GetName(),
+		DataSource: string(q.GetDataSource()),
+		Compute: datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute{
+			Aggregation: string(q.Compute.GetAggregation()),
+			Interval:    q.Compute.Interval,
+			Metric:      q.Compute.Metric,
+		},
+		Indexes: q.Indexes,
+	}
+	if s, ok := q.GetSearchOk(); ok && s != nil {
+		variable.Search = &datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch{Query: s.GetQuery()}
+	}
+	for _, g := range q.GroupBy {
+		groupBy := datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryGroupBy{
+			Facet: g.GetFacet(),
+			Limit: g.Limit,
+		}
+		if s, ok := g.GetSortOk(); ok && s != nil {
+			groupBy.Sort = &datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryGroupBySort{
+				Aggregation: string(s.GetAggregation()),
+				Metric:      s.Metric,
+				Order:       string(s.GetOrder()),
+			}
+		}
+		variable.GroupBy = append(variable.GroupBy, groupBy)
+	}
+	return variable
+}
+
 func getMonitor(auth context.Context, client *datadogapiclientv1.APIClient, monitorID int) (datadogapiclientv1.Monitor, error) {
 	groupStates := "all"
 	optionalParams := datadogapiclientv1.GetMonitorOptionalParameters{
 		GroupStates: &groupStates,
 	}
-	m, _, err := client.MonitorsApi.GetMonitor(auth, int64(monitorID), optionalParams)
+	m, httpResp, err := client.MonitorsApi.GetMonitor(auth, int64(monitorID), optionalParams)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error getting monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error getting monitor")
+	}
+	if err = SetMonitorAdditionalOptions(&m, httpResp); err != nil {
+		return datadogapiclientv1.Monitor{}, fmt.Errorf("error reading the options of monitor %d: %w", monitorID, err)
 	}
 
 	return m, nil
@@ -187,7 +376,7 @@ func getMonitor(auth context.Context, client *datadogapiclientv1.APIClient, moni
 func validateMonitor(auth context.Context, logger logr.Logger, client *datadogapiclientv1.APIClient, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	m, _ := buildMonitor(logger, dm)
 	if _, _, err := client.MonitorsApi.ValidateMonitor(auth, *m); err != nil {
-		return translateClientError(err, "error validating monitor")
+		return datadogclient.TranslateClientError(err, "error validating monitor")
 	}
 
 	return nil
@@ -197,7 +386,7 @@ func createMonitor(auth context.Context, logger logr.Logger, client *datadogapic
 	m, _ := buildMonitor(logger, dm)
 	mCreated, _, err := client.MonitorsApi.CreateMonitor(auth, *m)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error creating monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error creating monitor")
 	}
 
 	return mCreated, nil
@@ -208,7 +397,7 @@ func updateMonitor(auth context.Context, logger logr.Logger, client *datadogapic
 
 	mUpdated, _, err := client.MonitorsApi.UpdateMonitor(auth, int64(dm.Status.ID), *u)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error updating monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error updating monitor")
 	}
 
 	// TODO additional logic to handle downtimes (and silenced param if needed)
@@ -222,26 +411,32 @@ func deleteMonitor(auth context.Context, client *datadogapiclientv1.APIClient, m
 		Force: &force,
 	}
 	if _, _, err := client.MonitorsApi.DeleteMonitor(auth, int64(monitorID), optionalParams); err != nil {
-		return translateClientError(err, "error deleting monitor")
+		return datadogclient.TranslateClientError(err, "error deleting monitor")
 	}
 
 	return nil
 }
 
-func translateClientError(err error, msg string) error {
-	if msg == "" {
-		msg = "an error occurred"
+// orphanMonitor replaces the generated:kubernetes tag of the monitor with orphaned:kubernetes, when its DatadogMonitor is deleted without deleting it
+func orphanMonitor(auth context.Context, client *datadogapiclientv1.APIClient, monitorID int) error {
+	m, err := getMonitor(auth, client, monitorID)
+	if err != nil {
+		return err
 	}
 
-	var apiErr datadogapiclientv1.GenericOpenAPIError
-	var errURL *url.Error
-	if errors.As(err, &apiErr) {
-		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+	tags := []string{orphanedTag}
+	for _, tag := range m.GetTags() {
+		if tag != requiredTag && tag != orphanedTag {
+			tags = append(tags, tag)
+		}
 	}
+	sort.Strings(tags)
 
-	if errors.As(err, &errURL) {
-		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	u := datadogapiclientv1.NewMonitorUpdateRequest()
+	u.SetTags(tags)
+	if _, _, err = client.MonitorsApi.UpdateMonitor(auth, int64(monitorID), *u); err != nil {
+		return datadogclient.TranslateClientError(err, "error orphaning monitor")
 	}
 
-	return fmt.Errorf(msg+": %w", err)
+	return nil
 }
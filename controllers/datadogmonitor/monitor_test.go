@@ -7,8 +7,7 @@ package datadogmonitor
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -18,6 +17,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
@@ -136,6 +136,186 @@ func Test_buildMonitor(t *testing.T) {
 	assert.Equal(t, "kube_namespace:test", (monitorUR.GetTags())[2], "tags are not properly sorted")
 }
 
+func Test_BuildDatadogMonitorSpec(t *testing.T) {
+	evalDelay := int64(100)
+	escalationMsg := "This is an escalation message"
+	valTrue := true
+	renotifyInterval := int64(1440)
+	critThreshold := "0.05"
+	warnThreshold := "0.02"
+	recoveryWindow := "10m"
+
+	spec := datadoghqv1alpha1.DatadogMonitorSpec{
+		Query:    "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:     "metric alert",
+		Name:     "Test monitor",
+		Message:  "Something went wrong",
+		Priority: 3,
+		Tags:     []string{"env:staging", "kube_namespace:test"},
+		Options: datadoghqv1alpha1.DatadogMonitorOptions{
+			EvaluationDelay:   &evalDelay,
+			EscalationMessage: &escalationMsg,
+			NotifyNoData:      &valTrue,
+			RenotifyInterval:  &renotifyInterval,
+			Thresholds: &datadoghqv1alpha1.DatadogMonitorOptionsThresholds{
+				Critical: &critThreshold,
+				Warning:  &warnThreshold,
+			},
+			ThresholdWindows: &datadoghqv1alpha1.DatadogMonitorOptionsThresholdWindows{
+				RecoveryWindow: &recoveryWindow,
+			},
+		},
+	}
+
+	// Converting the built monitor back gives the original spec
+	monitor, _ := buildMonitor(testLogger, &datadoghqv1alpha1.DatadogMonitor{Spec: *spec.DeepCopy()})
+	assert.Equal(t, spec, BuildDatadogMonitorSpec(*monitor))
+
+	// A monitor without options gives a spec without options
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorSpec{
+		Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:    "metric alert",
+		Name:    "Test monitor",
+		Message: "Something went wrong",
+		Tags:    []string{"env:staging", "kube_cluster:test.staging", "kube_namespace:test"},
+	}, BuildDatadogMonitorSpec(genericMonitor(12345)))
+}
+
+func Test_buildMonitor_formulaAndFunctionOptions(t *testing.T) {
+	renotifyOccurrences := int64(3)
+	newHostDelay := int64(600)
+	minLocationFailed := int64(2)
+	interval := int64(60000)
+	limit := int64(10)
+	dayStarts := "04:00"
+
+	spec := datadoghqv1alpha1.DatadogMonitorSpec{
+		Query:   `formula("query1").last("15m") > 5`,
+		Type:    datadoghqv1alpha1.DatadogMonitorTypeCIPipelines,
+		Name:    "Test monitor",
+		Message: "Something went wrong",
+		Options: datadoghqv1alpha1.DatadogMonitorOptions{
+			RenotifyOccurrences:    &renotifyOccurrences,
+			RenotifyStatuses:       []datadoghqv1alpha1.DatadogMonitorRenotifyStatus{"alert", "no data"},
+			NewHostDelay:           &newHostDelay,
+			MinLocationFailed:      &minLocationFailed,
+			OnMissingData:          "show_and_notify_no_data",
+			GroupRetentionDuration: "2d",
+			NotifyBy:               []string{"@ci.pipeline.name"},
+			NotificationPresetName: "hide_query",
+			SchedulingOptions: &datadoghqv1alpha1.DatadogMonitorOptionsSchedulingOptions{
+				EvaluationWindow: &datadoghqv1alpha1.DatadogMonitorOptionsEvaluationWindow{DayStarts: &dayStarts},
+			},
+			Variables: []datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinition{
+				{
+					Name:       "query1",
+					DataSource: "ci_pipelines",
+					Compute:    datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinitionCompute{Aggregation: "count", Interval: &interval},
+					Search:     &datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryDefinitionSearch{Query: "@ci.status:error"},
+					Indexes:    []string{"main"},
+					GroupBy: []datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryGroupBy{
+						{
+							Facet: "@ci.pipeline.name",
+							Limit: &limit,
+							Sort:  &datadoghqv1alpha1.DatadogMonitorFormulaAndFunctionEventQueryGroupBySort{Aggregation: "count", Order: "desc"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	monitor, _ := buildMonitor(testLogger, &datadoghqv1alpha1.DatadogMonitor{Spec: *spec.DeepCopy()})
+	options := monitor.GetOptions()
+
+	// The options unknown to the client are sent with their API names
+	raw, err := json.Marshal(options)
+	require.NoError(t, err)
+	var sent map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &sent))
+	assert.Equal(t, "show_and_notify_no_data", sent["on_missing_data"])
+	assert.Equal(t, "2d", sent["group_retention_duration"])
+	assert.Equal(t, []interface{}{"@ci.pipeline.name"}, sent["notify_by"])
+	assert.Equal(t, "hide_query", sent["notification_preset_name"])
+	assert.Equal(t, map[string]interface{}{"evaluation_window": map[string]interface{}{"day_starts": "04:00"}}, sent["scheduling_options"])
+
+	// The options known to the client are read back from the API response
+	var received datadogapiclientv1.MonitorOptions
+	require.NoError(t, json.Unmarshal(raw, &received))
+	assert.Equal(t, int64(3), received.GetRenotifyOccurrences())
+	assert.Equal(t, []datadogapiclientv1.MonitorRenotifyStatusType{datadogapiclientv1.MONITORRENOTIFYSTATUSTYPE_ALERT, datadogapiclientv1.MONITORRENOTIFYSTATUSTYPE_NO_DATA}, received.GetRenotifyStatuses())
+	assert.Equal(t, int64(600), received.GetNewHostDelay())
+	assert.Equal(t, int64(2), received.GetMinLocationFailed())
+	require.Len(t, received.GetVariables(), 1)
+	query := received.GetVariables()[0].MonitorFormulaAndFunctionEventQueryDefinition
+	require.NotNil(t, query)
+	assert.Equal(t, datadogapiclientv1.MONITORFORMULAANDFUNCTIONEVENTSDATASOURCE_CI_PIPELINES, query.GetDataSource())
+	assert.Equal(t, datadogapiclientv1.QUERYSORTORDER_DESC, query.GroupBy[0].Sort.GetOrder())
+
+	// The client drops the options unknown to it, they are read back from the API response
+	assert.Empty(t, received.AdditionalProperties)
+	monitor.SetId(12345)
+	jsonMonitor, err := monitor.MarshalJSON()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonMonitor)
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	client := datadogapiclientv1.NewAPIClient(testConfig)
+	read, err := getMonitor(setupTestAuth(httpServer.URL), client, 12345)
+	require.NoError(t, err)
+
+	// Converting the monitor read from Datadog back gives the same options
+	assert.Equal(t, spec, BuildDatadogMonitorSpec(read))
+
+	// Nothing drifts when the monitor is read back
+	dm := &datadoghqv1alpha1.DatadogMonitor{Spec: *spec.DeepCopy()}
+	assert.Empty(t, monitorDrift(testLogger, dm, read))
+}
+
+func TestSetMonitorsAdditionalOptions(t *testing.T) {
+	hourStarts := int32(6)
+	body := `[
+		{"id": 1, "type": "query alert", "query": "avg(last_5m):avg:system.load.1{*} > 1", "options": {"notify_by": ["host"], "group_retention_duration": "1d", "unknown_option": true}},
+		{"id": 2, "type": "query alert", "query": "avg(last_5m):avg:system.load.1{*} > 2", "options": {"scheduling_options": {"evaluation_window": {"hour_starts": 6}}}},
+		{"id": 3, "type": "query alert", "query": "avg(last_5m):avg:system.load.1{*} > 3", "options": {}}
+	]`
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	client := datadogapiclientv1.NewAPIClient(testConfig)
+	monitors, httpResp, err := client.MonitorsApi.ListMonitors(setupTestAuth(httpServer.URL))
+	require.NoError(t, err)
+	require.NoError(t, SetMonitorsAdditionalOptions(monitors, httpResp))
+	require.Len(t, monitors, 3)
+
+	options := BuildDatadogMonitorSpec(monitors[0]).Options
+	assert.Equal(t, []string{"host"}, options.NotifyBy)
+	assert.Equal(t, "1d", options.GroupRetentionDuration)
+	assert.Nil(t, options.SchedulingOptions)
+	// Only the options the DatadogMonitor supports are kept
+	o := monitors[0].GetOptions()
+	assert.NotContains(t, o.AdditionalProperties, "unknown_option")
+
+	options = BuildDatadogMonitorSpec(monitors[1]).Options
+	assert.Empty(t, options.NotifyBy)
+	assert.Equal(t, &datadoghqv1alpha1.DatadogMonitorOptionsSchedulingOptions{
+		EvaluationWindow: &datadoghqv1alpha1.DatadogMonitorOptionsEvaluationWindow{HourStarts: &hourStarts},
+	}, options.SchedulingOptions)
+
+	o = monitors[2].GetOptions()
+	assert.Empty(t, o.AdditionalProperties)
+}
+
 func Test_getMonitor(t *testing.T) {
 	mID := 12345
 	expectedMonitor := genericMonitor(mID)
@@ -325,58 +505,3 @@ func setupTestAuth(apiURL string) context.Context {
 
 	return testAuth
 }
-
-func Test_translateClientError(t *testing.T) {
-	var ErrGeneric = errors.New("generic error")
-
-	testCases := []struct {
-		name                   string
-		error                  error
-		message                string
-		expectedErrorType      error
-		expectedError          error
-		expectedErrorInterface interface{}
-	}{
-		{
-			name:              "no message, generic error",
-			error:             ErrGeneric,
-			message:           "",
-			expectedErrorType: ErrGeneric,
-		},
-		{
-			name:              "generic message, generic error",
-			error:             ErrGeneric,
-			message:           "generic message",
-			expectedErrorType: ErrGeneric,
-		},
-		{
-			name:                   "generic message, error type datadogapiclientv1.GenericOpenAPIError",
-			error:                  datadogapiclientv1.GenericOpenAPIError{},
-			message:                "generic message",
-			expectedErrorInterface: &datadogapiclientv1.GenericOpenAPIError{},
-		},
-		{
-			name:          "generic message, error type *url.Error",
-			error:         &url.Error{Err: fmt.Errorf("generic url error")},
-			message:       "generic message",
-			expectedError: fmt.Errorf("generic message (url.Error):  \"\": generic url error"),
-		},
-	}
-	for _, test := range testCases {
-		t.Run(test.name, func(t *testing.T) {
-			result := translateClientError(test.error, test.message)
-
-			if test.expectedErrorType != nil {
-				assert.True(t, errors.Is(result, test.expectedErrorType))
-			}
-
-			if test.expectedErrorInterface != nil {
-				assert.True(t, errors.As(result, test.expectedErrorInterface))
-			}
-
-			if test.expectedError != nil {
-				assert.Equal(t, test.expectedError, result)
-			}
-		})
-	}
-}
@@ -0,0 +1,166 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// additionalOptionKeys are the API names of the monitor options that the client doesn't support
+var additionalOptionKeys = []string{"on_missing_data", "group_retention_duration", "notify_by", "notification_preset_name", "scheduling_options"}
+
+// monitorAdditionalOptions are the monitor options that the client doesn't support, as sent to the API
+type monitorAdditionalOptions struct {
+	OnMissingData          string   `json:"on_missing_data,omitempty"`
+	GroupRetentionDuration string   `json:"group_retention_duration,omitempty"`
+	NotifyBy               []string `json:"notify_by,omitempty"`
+	NotificationPresetName string   `json:"notification_preset_name,omitempty"`
+	SchedulingOptions      *struct {
+		EvaluationWindow *struct {
+			DayStarts   *string `json:"day_starts,omitempty"`
+			HourStarts  *int32  `json:"hour_starts,omitempty"`
+			MonthStarts *int32  `json:"month_starts,omitempty"`
+		} `json:"evaluation_window,omitempty"`
+	} `json:"scheduling_options,omitempty"`
+}
+
+// buildMonitorAdditionalOptions returns the monitor options that the client doesn't support, keyed by their API name
+func buildMonitorAdditionalOptions(options datadoghqv1alpha1.DatadogMonitorOptions) map[string]interface{} {
+	additionalOptions := map[string]interface{}{}
+
+	if options.OnMissingData != "" {
+		additionalOptions["on_missing_data"] = options.OnMissingData
+	}
+
+	if options.GroupRetentionDuration != "" {
+		additionalOptions["group_retention_duration"] = options.GroupRetentionDuration
+	}
+
+	if len(options.NotifyBy) > 0 {
+		additionalOptions["notify_by"] = options.NotifyBy
+	}
+
+	if options.NotificationPresetName != "" {
+		additionalOptions["notification_preset_name"] = options.NotificationPresetName
+	}
+
+	if options.SchedulingOptions != nil && options.SchedulingOptions.EvaluationWindow != nil {
+		w := options.SchedulingOptions.EvaluationWindow
+		evaluationWindow := map[string]interface{}{}
+		if w.DayStarts != nil {
+			evaluationWindow["day_starts"] = *w.DayStarts
+		}
+		if w.HourStarts != nil {
+			evaluationWindow["hour_starts"] = *w.HourStarts
+		}
+		if w.MonthStarts != nil {
+			evaluationWindow["month_starts"] = *w.MonthStarts
+		}
+		additionalOptions["scheduling_options"] = map[string]interface{}{"evaluation_window": evaluationWindow}
+	}
+
+	return additionalOptions
+}
+
+// buildDatadogMonitorAdditionalOptions sets the options of the DatadogMonitor that the client doesn't support,
+// from the additional properties of the monitor options. It is the inverse of buildMonitorAdditionalOptions.
+func buildDatadogMonitorAdditionalOptions(options *datadoghqv1alpha1.DatadogMonitorOptions, additionalProperties map[string]interface{}) {
+	if len(additionalProperties) == 0 {
+		return
+	}
+	// The values are either built by buildMonitorAdditionalOptions or decoded from JSON, convert them through JSON
+	data, err := json.Marshal(additionalProperties)
+	if err != nil {
+		return
+	}
+	additional := monitorAdditionalOptions{}
+	if err = json.Unmarshal(data, &additional); err != nil {
+		return
+	}
+
+	options.OnMissingData = additional.OnMissingData
+	options.GroupRetentionDuration = additional.GroupRetentionDuration
+	options.NotifyBy = additional.NotifyBy
+	options.NotificationPresetName = additional.NotificationPresetName
+	if additional.SchedulingOptions != nil && additional.SchedulingOptions.EvaluationWindow != nil {
+		w := additional.SchedulingOptions.EvaluationWindow
+		options.SchedulingOptions = &datadoghqv1alpha1.DatadogMonitorOptionsSchedulingOptions{
+			EvaluationWindow: &datadoghqv1alpha1.DatadogMonitorOptionsEvaluationWindow{
+				DayStarts:   w.DayStarts,
+				HourStarts:  w.HourStarts,
+				MonthStarts: w.MonthStarts,
+			},
+		}
+	}
+}
+
+// SetMonitorAdditionalOptions sets the options that the client doesn't support, and drops when decoding the monitor,
+// as additional properties of the monitor options. They are read from the body of the API response of the monitor.
+func SetMonitorAdditionalOptions(m *datadogapiclientv1.Monitor, httpResp *http.Response) error {
+	body, err := readResponseBody(httpResp)
+	if err != nil || body == nil {
+		return err
+	}
+	return setMonitorAdditionalOptions(m, body)
+}
+
+// SetMonitorsAdditionalOptions is SetMonitorAdditionalOptions for the API response of a list of monitors.
+func SetMonitorsAdditionalOptions(monitors []datadogapiclientv1.Monitor, httpResp *http.Response) error {
+	body, err := readResponseBody(httpResp)
+	if err != nil || body == nil {
+		return err
+	}
+	var rawMonitors []json.RawMessage
+	if err = json.Unmarshal(body, &rawMonitors); err != nil {
+		return err
+	}
+	for i := range monitors {
+		if i >= len(rawMonitors) {
+			break
+		}
+		if err = setMonitorAdditionalOptions(&monitors[i], rawMonitors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setMonitorAdditionalOptions(m *datadogapiclientv1.Monitor, rawMonitor []byte) error {
+	raw := struct {
+		Options map[string]interface{} `json:"options"`
+	}{}
+	if err := json.Unmarshal(rawMonitor, &raw); err != nil {
+		return err
+	}
+
+	additionalProperties := map[string]interface{}{}
+	for _, key := range additionalOptionKeys {
+		if v, found := raw.Options[key]; found && v != nil {
+			additionalProperties[key] = v
+		}
+	}
+	if len(additionalProperties) == 0 {
+		return nil
+	}
+
+	o := m.GetOptions()
+	o.AdditionalProperties = additionalProperties
+	m.SetOptions(o)
+	return nil
+}
+
+// readResponseBody returns the body of the API response, the client replaces it with a buffer after decoding it
+func readResponseBody(httpResp *http.Response) ([]byte, error) {
+	if httpResp == nil || httpResp.Body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(httpResp.Body)
+}
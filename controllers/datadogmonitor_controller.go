@@ -9,11 +9,15 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
@@ -22,18 +26,23 @@ import (
 
 // DatadogMonitorReconciler reconciles a DatadogMonitor object.
 type DatadogMonitorReconciler struct {
-	Client      client.Client
-	DDClient    datadogclient.DatadogClient
+	Client client.Client
+	// APIReader reads the objects that are not cached by the manager, like the credentials Secrets
+	APIReader   client.Reader
+	DDClient    *datadogclient.SharedClient
 	VersionInfo *version.Info
-	Log         logr.Logger
-	Scheme      *runtime.Scheme
-	Recorder    record.EventRecorder
-	internal    *datadogmonitor.Reconciler
+	// DeletionPolicy is the deletion policy of the DatadogMonitors without spec.controllerOptions.deletionPolicy
+	DeletionPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+	Log            logr.Logger
+	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
+	internal       *datadogmonitor.Reconciler
 }
 
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile loop for DatadogMonitor.
 func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -42,14 +51,23 @@ func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 // SetupWithManager creates a new DatadogMonitor controller.
 func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	internal, err := datadogmonitor.NewReconciler(r.Client, r.DDClient, r.VersionInfo, r.Scheme, r.Log, r.Recorder)
+	internal, err := datadogmonitor.NewReconciler(r.Client, r.APIReader, r.DDClient, r.VersionInfo, r.DeletionPolicy, r.Scheme, r.Log, r.Recorder)
 	if err != nil {
 		return err
 	}
 	r.internal = internal
 
+	if err = mgr.GetFieldIndexer().IndexField(context.TODO(), &datadoghqv1alpha1.DatadogMonitor{}, datadogmonitor.CredentialsSecretIndexField, datadogmonitor.CredentialsSecretIndexer); err != nil {
+		return err
+	}
+
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&datadoghqv1alpha1.DatadogMonitor{})
+		For(&datadoghqv1alpha1.DatadogMonitor{}).
+		// Render the query of the composite monitors again when the ID of a monitor they reference changes
+		Watches(&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}}, handler.EnqueueRequestsFromMapFunc(r.internal.EnqueueCompositeMonitors)).
+		// Use the new keys when the Secret of the monitor credentials changes. Only the metadata of the Secrets
+		// is cached, their keys are read with the APIReader.
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.internal.EnqueueMonitorsWithCredentials), ctrlbuilder.OnlyMetadata)
 
 	err = builder.Complete(r)
 	if err != nil {
@@ -0,0 +1,252 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	ctrutils "github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	defaultRequeuePeriod    = 60 * time.Second
+	defaultErrRequeuePeriod = 5 * time.Second
+	defaultForceSyncPeriod  = 60 * time.Minute
+	defaultSLIRefreshPeriod = 5 * time.Minute
+)
+
+// Reconciler reconciles a DatadogSLO object
+type Reconciler struct {
+	client        client.Client
+	datadogClient *datadogclient.SharedClient
+	log           logr.Logger
+	scheme        *runtime.Scheme
+	recorder      record.EventRecorder
+}
+
+// NewReconciler returns a new Reconciler object
+func NewReconciler(client client.Client, ddClient *datadogclient.SharedClient, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+	return &Reconciler{
+		client:        client,
+		datadogClient: ddClient,
+		scheme:        scheme,
+		log:           log,
+		recorder:      recorder,
+	}, nil
+}
+
+// Reconcile is similar to reconciler.Reconcile interface, but taking a context
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	return r.internalReconcile(ctx, request)
+}
+
+// Reconcile loop for DatadogSLO
+func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.WithValues("datadogslo", req.NamespacedName)
+	logger.Info("Reconciling DatadogSLO")
+	now := metav1.NewTime(time.Now())
+
+	// Get instance
+	instance := &datadoghqv1alpha1.DatadogSLO{}
+	var result ctrl.Result
+	err := r.client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return result, nil
+		}
+		// Error reading the object - requeue the request
+		return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+
+	if result, err = r.handleFinalizer(logger, instance); ctrutils.ShouldReturn(result, err) {
+		return result, err
+	}
+
+	// Validate the DatadogSLO spec
+	if err = datadoghqv1alpha1.IsValidDatadogSLO(&instance.Spec); err != nil {
+		logger.Error(err, "invalid DatadogSLO spec")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	// Get the IDs of the monitors of a monitor SLO
+	monitorIDs, err := r.getMonitorIDs(instance)
+	if err != nil {
+		logger.Error(err, "error getting the monitors of the SLO")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, ctrl.Result{RequeueAfter: defaultErrRequeuePeriod})
+	}
+
+	// The monitor IDs are part of the hash, so that the SLO is updated when a referenced monitor is recreated
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(struct {
+		Spec       *datadoghqv1alpha1.DatadogSLOSpec `json:"spec"`
+		MonitorIDs []int64                           `json:"monitorIDs"`
+	}{&instance.Spec, monitorIDs})
+	if err != nil {
+		logger.Error(err, "error generating hash")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	datadogAuth, datadogClient := r.datadogClient.Get()
+	shouldCreate := false
+	shouldUpdate := false
+
+	// Check if we need to create or update the SLO
+	if newStatus.ID == "" {
+		shouldCreate = true
+	} else if instanceSpecHash != newStatus.CurrentHash {
+		// Custom resource manifest has changed, need to update the API
+		logger.V(1).Info("DatadogSLO manifest has changed")
+		shouldUpdate = true
+	} else if newStatus.SLOLastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(newStatus.SLOLastForceSyncTime.Time)) <= 0 {
+		// Periodically force a sync with the API SLO to ensure parity
+		// Get SLO to make sure it exists before trying any updates. If it doesn't, set shouldCreate
+		if _, err = getSLO(datadogAuth, datadogClient, newStatus.ID); err != nil {
+			logger.Error(err, "error getting SLO", "SLO ID", newStatus.ID)
+			if datadogclient.IsNotFound(err) {
+				shouldCreate = true
+			}
+		} else {
+			shouldUpdate = true
+		}
+	}
+
+	// Create and update actions
+	if shouldCreate {
+		logger.V(1).Info("Creating SLO in Datadog")
+		if err = r.create(logger, instance, monitorIDs, newStatus, now, instanceSpecHash); err != nil {
+			logger.Error(err, "error creating SLO")
+		}
+	} else if shouldUpdate {
+		logger.V(1).Info("Updating SLO in Datadog")
+		if err = r.update(logger, instance, monitorIDs, newStatus, now, instanceSpecHash); err != nil {
+			logger.Error(err, "error updating SLO", "SLO ID", newStatus.ID)
+		}
+	}
+
+	// Refresh the SLI values
+	if err == nil && (newStatus.SLILastUpdateTime == nil || (defaultSLIRefreshPeriod-now.Sub(newStatus.SLILastUpdateTime.Time)) <= 0) {
+		if err = r.updateSLIs(instance, newStatus, now); err != nil {
+			logger.Error(err, "error getting SLO history", "SLO ID", newStatus.ID)
+		}
+	}
+
+	// Requeue
+	result.RequeueAfter = defaultRequeuePeriod
+
+	// Update the status
+	return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+}
+
+func (r *Reconciler) create(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO, monitorIDs []int64, status *datadoghqv1alpha1.DatadogSLOStatus, now metav1.Time, instanceSpecHash string) error {
+	datadogAuth, datadogClient := r.datadogClient.Get()
+
+	// Create SLO in Datadog
+	req, _ := buildSLO(slo, monitorIDs)
+	s, err := createSLO(datadogAuth, datadogClient, req)
+	if err != nil {
+		return err
+	}
+	event := buildEventInfo(slo.Name, slo.Namespace, datadog.CreationEvent)
+	r.recordEvent(slo, event)
+
+	// As this is a new SLO, add static information to status
+	status.ID = s.GetId()
+	creator := s.GetCreator()
+	status.Creator = creator.GetEmail()
+	createdTime := metav1.NewTime(time.Unix(s.GetCreatedAt(), 0))
+	status.Created = &createdTime
+	status.MonitorIDs = monitorIDs
+	status.Thresholds = nil
+	status.SLILastUpdateTime = nil
+	status.SLOLastForceSyncTime = &now
+	status.CurrentHash = instanceSpecHash
+	logger.Info("Created a new DatadogSLO", "SLO Namespace", slo.Namespace, "SLO Name", slo.Name, "SLO ID", status.ID)
+
+	return nil
+}
+
+func (r *Reconciler) update(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO, monitorIDs []int64, status *datadoghqv1alpha1.DatadogSLOStatus, now metav1.Time, instanceSpecHash string) error {
+	datadogAuth, datadogClient := r.datadogClient.Get()
+
+	// Update SLO in Datadog
+	_, s := buildSLO(slo, monitorIDs)
+	if _, err := updateSLO(datadogAuth, datadogClient, status.ID, s); err != nil {
+		return err
+	}
+	event := buildEventInfo(slo.Name, slo.Namespace, datadog.UpdateEvent)
+	r.recordEvent(slo, event)
+
+	status.MonitorIDs = monitorIDs
+	// The thresholds may have changed, refresh the SLI values
+	status.SLILastUpdateTime = nil
+	status.SLOLastForceSyncTime = &now
+	status.CurrentHash = instanceSpecHash
+	logger.Info("Updated DatadogSLO", "SLO Namespace", slo.Namespace, "SLO Name", slo.Name, "SLO ID", status.ID)
+
+	return nil
+}
+
+// updateSLIs reads the SLO history over the timeframe of each threshold to report its SLI and remaining error budget
+func (r *Reconciler) updateSLIs(slo *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, now metav1.Time) error {
+	datadogAuth, datadogClient := r.datadogClient.Get()
+
+	history := map[datadoghqv1alpha1.DatadogSLOTimeframe]datadogapiclientv1.SLOHistoryResponseData{}
+	for _, threshold := range slo.Spec.Thresholds {
+		h, err := getSLOHistory(datadogAuth, datadogClient, status.ID, threshold.Timeframe, now.Time)
+		if err != nil {
+			return err
+		}
+		history[threshold.Timeframe] = h
+	}
+
+	status.Thresholds = buildThresholdsStatus(slo.Spec.Thresholds, history)
+	status.SLILastUpdateTime = &now
+
+	return nil
+}
+
+func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogSLO *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, currentErr error, result ctrl.Result) (ctrl.Result, error) {
+	// Update Error and Active conditions
+	condition.SetErrorActiveStatusConditions(&status.Conditions, "DatadogSLO", datadogSLO.Generation, currentErr)
+
+	if !apiequality.Semantic.DeepEqual(&datadogSLO.Status, status) {
+		datadogSLO.Status = *status
+		if err := r.client.Status().Update(context.TODO(), datadogSLO); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Error(err, "unable to update DatadogSLO status due to update conflict")
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, nil
+			}
+			logger.Error(err, "unable to update DatadogSLO status")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	return result, nil
+}
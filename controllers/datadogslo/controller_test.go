@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	resourcesName      = "foo"
+	resourcesNamespace = "bar"
+)
+
+// sloAPI is a stub of the Datadog SLO API
+type sloAPI struct {
+	mutex  sync.Mutex
+	nextID int
+	slos   map[string]datadogapiclientv1.ServiceLevelObjective
+	sli    float64
+}
+
+func newSLOAPI() *sloAPI {
+	return &sloAPI{nextID: 1, slos: map[string]datadogapiclientv1.ServiceLevelObjective{}, sli: 99.95}
+}
+
+func (a *sloAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/slo"), "/")
+	id := ""
+	if len(path) > 1 {
+		id = path[1]
+		if _, found := a.slos[id]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
+	switch {
+	case len(path) > 2 && path[2] == "history":
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOHistoryResponse{
+			Data: &datadogapiclientv1.SLOHistoryResponseData{Overall: &datadogapiclientv1.SLOHistorySLIData{SliValue: &a.sli}},
+		})
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		s := datadogapiclientv1.ServiceLevelObjective{}
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPost {
+			id = fmt.Sprintf("slo%d", a.nextID)
+			a.nextID++
+			s.SetCreatedAt(1654041600)
+			s.SetCreator(datadogapiclientv1.Creator{Email: apiutils.NewStringPointer("operator@datadoghq.com")})
+		}
+		s.SetId(id)
+		a.slos[id] = s
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOListResponse{Data: []datadogapiclientv1.ServiceLevelObjective{s}})
+	case r.Method == http.MethodDelete:
+		delete(a.slos, id)
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLODeleteResponse{Data: []string{id}})
+	default:
+		s := a.slos[id]
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOResponse{Data: &datadogapiclientv1.SLOResponseData{Id: s.Id, Name: &s.Name}})
+	}
+}
+
+func (a *sloAPI) get(id string) (datadogapiclientv1.ServiceLevelObjective, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	s, found := a.slos[id]
+	return s, found
+}
+
+func setupTestReconciler(t *testing.T, objects ...client.Object) (*Reconciler, *sloAPI) {
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, datadoghqv1alpha1.AddToScheme(s))
+
+	api := newSLOAPI()
+	httpServer := httptest.NewServer(api)
+	t.Cleanup(httpServer.Close)
+
+	parsedAPIURL, _ := url.Parse(httpServer.URL)
+	testAuth := context.WithValue(context.Background(), datadogapiclientv1.ContextServerIndex, 1)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerVariables, map[string]string{
+		"name":     parsedAPIURL.Host,
+		"protocol": parsedAPIURL.Scheme,
+	})
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	testConfig.SetUnstableOperationEnabled("GetSLOHistory", true)
+
+	return &Reconciler{
+		client:        fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build(),
+		datadogClient: datadogclient.NewSharedClient(logf.Log, datadogclient.DatadogClient{Client: datadogapiclientv1.NewAPIClient(testConfig), Auth: testAuth}),
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(100),
+		log:           logf.Log.WithName(t.Name()),
+	}, api
+}
+
+func newTestSLO(spec datadoghqv1alpha1.DatadogSLOSpec) *datadoghqv1alpha1.DatadogSLO {
+	return &datadoghqv1alpha1.DatadogSLO{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+		Spec:       spec,
+	}
+}
+
+func newTestMonitor(name string, id int) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+	}
+}
+
+func reconcileSLO(t *testing.T, r *Reconciler, count int) *datadoghqv1alpha1.DatadogSLO {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}}
+	for i := 0; i < count; i++ {
+		_, err := r.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+	}
+
+	slo := &datadoghqv1alpha1.DatadogSLO{}
+	require.NoError(t, r.client.Get(context.TODO(), request.NamespacedName, slo))
+	return slo
+}
+
+func TestReconciler_Reconcile_metricSLO(t *testing.T) {
+	r, api := setupTestReconciler(t, newTestSLO(datadoghqv1alpha1.DatadogSLOSpec{
+		Name: "web availability",
+		Type: datadoghqv1alpha1.DatadogSLOTypeMetric,
+		Query: &datadoghqv1alpha1.DatadogSLOQuery{
+			Numerator:   "sum:requests.success{service:web}.as_count()",
+			Denominator: "sum:requests.total{service:web}.as_count()",
+		},
+		Thresholds: []datadoghqv1alpha1.DatadogSLOThreshold{{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe30d, Target: "99.9"}},
+		Tags:       []string{"service:web"},
+	}))
+
+	// Creation
+	slo := reconcileSLO(t, r, 2)
+	require.NotEmpty(t, slo.Status.ID)
+	assert.Equal(t, "operator@datadoghq.com", slo.Status.Creator)
+	assert.NotEmpty(t, slo.Status.CurrentHash)
+	assert.True(t, meta.IsStatusConditionTrue(slo.Status.Conditions, datadoghqv1alpha1.DatadogSLOConditionTypeActive))
+	assert.Equal(t, []datadoghqv1alpha1.DatadogSLOThresholdStatus{{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe30d, SLI: "99.950", ErrorBudgetRemaining: "50.00"}}, slo.Status.Thresholds)
+	s, found := api.get(slo.Status.ID)
+	require.True(t, found)
+	assert.Equal(t, "web availability", s.GetName())
+	assert.Equal(t, datadogapiclientv1.SLOTYPE_METRIC, s.GetType())
+	assert.Equal(t, []string{"generated:kubernetes", "service:web"}, s.GetTags())
+	query := s.GetQuery()
+	assert.Equal(t, "sum:requests.total{service:web}.as_count()", query.GetDenominator())
+
+	// Update of the thresholds
+	slo.Spec.Thresholds = append(slo.Spec.Thresholds, datadoghqv1alpha1.DatadogSLOThreshold{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, Target: "99"})
+	require.NoError(t, r.client.Update(context.TODO(), slo))
+	slo = reconcileSLO(t, r, 1)
+	s, _ = api.get(slo.Status.ID)
+	assert.Len(t, s.GetThresholds(), 2)
+	require.Len(t, slo.Status.Thresholds, 2)
+	assert.Equal(t, datadoghqv1alpha1.DatadogSLOThresholdStatus{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, SLI: "99.950", ErrorBudgetRemaining: "95.00"}, slo.Status.Thresholds[1])
+
+	// The SLO is deleted in Datadog: it is recreated at the next force sync
+	id := slo.Status.ID
+	datadogAuth, datadogClient := r.datadogClient.Get()
+	require.NoError(t, deleteSLO(datadogAuth, datadogClient, id))
+	slo.Status.SLOLastForceSyncTime = nil
+	require.NoError(t, r.client.Status().Update(context.TODO(), slo))
+	slo = reconcileSLO(t, r, 1)
+	assert.NotEqual(t, id, slo.Status.ID)
+	_, found = api.get(slo.Status.ID)
+	assert.True(t, found)
+
+	// The SLO is deleted when the DatadogSLO is deleted
+	r.finalizeDatadogSLO(r.log, slo)
+	_, found = api.get(slo.Status.ID)
+	assert.False(t, found)
+}
+
+func TestReconciler_Reconcile_monitorSLO(t *testing.T) {
+	r, api := setupTestReconciler(t,
+		newTestSLO(datadoghqv1alpha1.DatadogSLOSpec{
+			Name:        "web latency",
+			Type:        datadoghqv1alpha1.DatadogSLOTypeMonitor,
+			MonitorIDs:  []int64{42},
+			MonitorRefs: []datadoghqv1alpha1.DatadogSLOMonitorReference{{Name: "web-latency"}},
+			Thresholds:  []datadoghqv1alpha1.DatadogSLOThreshold{{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, Target: "99"}},
+		}),
+		newTestMonitor("web-latency", 0),
+	)
+
+	// The web-latency monitor isn't created yet
+	slo := reconcileSLO(t, r, 2)
+	assert.Empty(t, slo.Status.ID)
+	assert.True(t, meta.IsStatusConditionTrue(slo.Status.Conditions, datadoghqv1alpha1.DatadogSLOConditionTypeError))
+	assert.Contains(t, meta.FindStatusCondition(slo.Status.Conditions, datadoghqv1alpha1.DatadogSLOConditionTypeError).Message, "web-latency")
+
+	dm := &datadoghqv1alpha1.DatadogMonitor{}
+	require.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "web-latency"}, dm))
+	dm.Status.ID = 1234
+	require.NoError(t, r.client.Status().Update(context.TODO(), dm))
+	slo = reconcileSLO(t, r, 1)
+	require.NotEmpty(t, slo.Status.ID)
+	assert.Equal(t, []int64{42, 1234}, slo.Status.MonitorIDs)
+	s, _ := api.get(slo.Status.ID)
+	assert.Equal(t, []int64{42, 1234}, s.GetMonitorIds())
+
+	// The referenced monitor is recreated with a new ID
+	dm.Status.ID = 5
+	require.NoError(t, r.client.Status().Update(context.TODO(), dm))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}}}, r.EnqueueSLOsReferencingMonitor(dm))
+	slo = reconcileSLO(t, r, 1)
+	assert.Equal(t, []int64{5, 42}, slo.Status.MonitorIDs)
+	s, _ = api.get(slo.Status.ID)
+	assert.Equal(t, []int64{5, 42}, s.GetMonitorIds())
+
+	// The referenced monitor is moved to the organization of other credentials
+	dm.Spec.Credentials = &datadoghqv1alpha1.DatadogMonitorCredentials{SecretName: "team-keys"}
+	require.NoError(t, r.client.Update(context.TODO(), dm))
+	slo = reconcileSLO(t, r, 1)
+	assert.True(t, meta.IsStatusConditionTrue(slo.Status.Conditions, datadoghqv1alpha1.DatadogSLOConditionTypeError))
+	assert.Contains(t, meta.FindStatusCondition(slo.Status.Conditions, datadoghqv1alpha1.DatadogSLOConditionTypeError).Message, "isn't managed with the Operator credentials")
+	assert.Equal(t, []int64{5, 42}, slo.Status.MonitorIDs)
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
+)
+
+// getMonitorIDs returns the sorted IDs of the monitors of the SLO: the MonitorIDs and the IDs of the referenced DatadogMonitors
+func (r *Reconciler) getMonitorIDs(slo *datadoghqv1alpha1.DatadogSLO) ([]int64, error) {
+	if slo.Spec.Type != datadoghqv1alpha1.DatadogSLOTypeMonitor {
+		return nil, nil
+	}
+
+	ids := map[int64]struct{}{}
+	for _, id := range slo.Spec.MonitorIDs {
+		ids[id] = struct{}{}
+	}
+
+	var pending []string
+	for _, ref := range slo.Spec.MonitorRefs {
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: slo.Namespace, Name: ref.Name}, dm); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, ref.Name)
+				continue
+			}
+			return nil, err
+		}
+		if err := datadogmonitor.CheckOperatorCredentials(dm); err != nil {
+			return nil, err
+		}
+		if dm.Status.ID == 0 {
+			pending = append(pending, ref.Name)
+			continue
+		}
+		ids[int64(dm.Status.ID)] = struct{}{}
+	}
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("waiting for the referenced DatadogMonitors to be created: %s", strings.Join(pending, ", "))
+	}
+
+	monitorIDs := make([]int64, 0, len(ids))
+	for id := range ids {
+		monitorIDs = append(monitorIDs, id)
+	}
+	sort.Slice(monitorIDs, func(i, j int) bool { return monitorIDs[i] < monitorIDs[j] })
+
+	return monitorIDs, nil
+}
+
+// EnqueueSLOsReferencingMonitor enqueues the DatadogSLOs referencing the DatadogMonitor obj,
+// to update their SLO when the ID of its monitor changes.
+func (r *Reconciler) EnqueueSLOsReferencingMonitor(obj client.Object) []reconcile.Request {
+	sloList := &datadoghqv1alpha1.DatadogSLOList{}
+	if err := r.client.List(context.TODO(), sloList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "Unable to list the DatadogSLOs", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, slo := range sloList.Items {
+		for _, ref := range slo.Spec.MonitorRefs {
+			if ref.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: slo.Namespace, Name: slo.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
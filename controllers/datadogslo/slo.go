@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const requiredTag = "generated:kubernetes"
+
+var timeframeDurations = map[datadoghqv1alpha1.DatadogSLOTimeframe]time.Duration{
+	datadoghqv1alpha1.DatadogSLOTimeframe7d:  7 * 24 * time.Hour,
+	datadoghqv1alpha1.DatadogSLOTimeframe30d: 30 * 24 * time.Hour,
+	datadoghqv1alpha1.DatadogSLOTimeframe90d: 90 * 24 * time.Hour,
+}
+
+// UnstableOperations are the unstable operations of the Datadog API used by the DatadogSLO controller:
+// the SLO history reports the SLI values of the DatadogSLOs
+var UnstableOperations = []string{"GetSLOHistory"}
+
+func buildSLO(slo *datadoghqv1alpha1.DatadogSLO, monitorIDs []int64) (*datadogapiclientv1.ServiceLevelObjectiveRequest, *datadogapiclientv1.ServiceLevelObjective) {
+	spec := slo.Spec
+	sloType := datadogapiclientv1.SLOType(spec.Type)
+
+	thresholds := make([]datadogapiclientv1.SLOThreshold, 0, len(spec.Thresholds))
+	for _, threshold := range spec.Thresholds {
+		// The thresholds are validated by IsValidDatadogSLO
+		target, _ := strconv.ParseFloat(threshold.Target, 64)
+		t := datadogapiclientv1.NewSLOThreshold(target, datadogapiclientv1.SLOTimeframe(threshold.Timeframe))
+		if threshold.Warning != nil {
+			warning, _ := strconv.ParseFloat(*threshold.Warning, 64)
+			t.SetWarning(warning)
+		}
+		thresholds = append(thresholds, *t)
+	}
+
+	tags := append([]string{}, spec.Tags...)
+	found := false
+	for _, tag := range tags {
+		found = found || tag == requiredTag
+	}
+	if !found {
+		tags = append(tags, requiredTag)
+	}
+	sort.Strings(tags)
+
+	req := datadogapiclientv1.NewServiceLevelObjectiveRequest(spec.Name, thresholds, sloType)
+	s := datadogapiclientv1.NewServiceLevelObjective(spec.Name, thresholds, sloType)
+	// Always set the description, to remove it on updates
+	req.SetDescription(spec.Description)
+	s.SetDescription(spec.Description)
+	req.SetTags(tags)
+	s.SetTags(tags)
+
+	switch spec.Type {
+	case datadoghqv1alpha1.DatadogSLOTypeMetric:
+		query := datadogapiclientv1.NewServiceLevelObjectiveQuery(spec.Query.Denominator, spec.Query.Numerator)
+		req.SetQuery(*query)
+		s.SetQuery(*query)
+	case datadoghqv1alpha1.DatadogSLOTypeMonitor:
+		req.SetMonitorIds(monitorIDs)
+		s.SetMonitorIds(monitorIDs)
+		if len(spec.Groups) > 0 {
+			groups := append([]string{}, spec.Groups...)
+			sort.Strings(groups)
+			req.SetGroups(groups)
+			s.SetGroups(groups)
+		}
+	}
+
+	return req, s
+}
+
+// buildThresholdsStatus computes the SLI and the remaining error budget of each threshold from the SLO history of its timeframe
+func buildThresholdsStatus(thresholds []datadoghqv1alpha1.DatadogSLOThreshold, history map[datadoghqv1alpha1.DatadogSLOTimeframe]datadogapiclientv1.SLOHistoryResponseData) []datadoghqv1alpha1.DatadogSLOThresholdStatus {
+	statuses := make([]datadoghqv1alpha1.DatadogSLOThresholdStatus, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		status := datadoghqv1alpha1.DatadogSLOThresholdStatus{Timeframe: threshold.Timeframe}
+		data := history[threshold.Timeframe]
+		overall := data.GetOverall()
+		if sli, ok := overall.GetSliValueOk(); ok && sli != nil {
+			status.SLI = strconv.FormatFloat(*sli, 'f', 3, 64)
+			if target, err := strconv.ParseFloat(threshold.Target, 64); err == nil && target < 100 {
+				status.ErrorBudgetRemaining = strconv.FormatFloat((*sli-target)/(100-target)*100, 'f', 2, 64)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func getSLO(auth context.Context, client *datadogapiclientv1.APIClient, sloID string) (datadogapiclientv1.SLOResponseData, error) {
+	s, _, err := client.ServiceLevelObjectivesApi.GetSLO(auth, sloID)
+	if err != nil {
+		return datadogapiclientv1.SLOResponseData{}, datadogclient.TranslateClientError(err, "error getting SLO")
+	}
+
+	return s.GetData(), nil
+}
+
+func getSLOHistory(auth context.Context, client *datadogapiclientv1.APIClient, sloID string, timeframe datadoghqv1alpha1.DatadogSLOTimeframe, now time.Time) (datadogapiclientv1.SLOHistoryResponseData, error) {
+	from := now.Add(-timeframeDurations[timeframe])
+	h, _, err := client.ServiceLevelObjectivesApi.GetSLOHistory(auth, sloID, from.Unix(), now.Unix())
+	if err != nil {
+		return datadogapiclientv1.SLOHistoryResponseData{}, datadogclient.TranslateClientError(err, "error getting SLO history")
+	}
+
+	return h.GetData(), nil
+}
+
+func createSLO(auth context.Context, client *datadogapiclientv1.APIClient, s *datadogapiclientv1.ServiceLevelObjectiveRequest) (datadogapiclientv1.ServiceLevelObjective, error) {
+	sCreated, _, err := client.ServiceLevelObjectivesApi.CreateSLO(auth, *s)
+	if err != nil {
+		return datadogapiclientv1.ServiceLevelObjective{}, datadogclient.TranslateClientError(err, "error creating SLO")
+	}
+	if len(sCreated.GetData()) == 0 {
+		return datadogapiclientv1.ServiceLevelObjective{}, fmt.Errorf("error creating SLO: empty response")
+	}
+
+	return sCreated.GetData()[0], nil
+}
+
+func updateSLO(auth context.Context, client *datadogapiclientv1.APIClient, sloID string, s *datadogapiclientv1.ServiceLevelObjective) (datadogapiclientv1.ServiceLevelObjective, error) {
+	sUpdated, _, err := client.ServiceLevelObjectivesApi.UpdateSLO(auth, sloID, *s)
+	if err != nil {
+		return datadogapiclientv1.ServiceLevelObjective{}, datadogclient.TranslateClientError(err, "error updating SLO")
+	}
+	if len(sUpdated.GetData()) == 0 {
+		return datadogapiclientv1.ServiceLevelObjective{}, fmt.Errorf("error updating SLO: empty response")
+	}
+
+	return sUpdated.GetData()[0], nil
+}
+
+func deleteSLO(auth context.Context, client *datadogapiclientv1.APIClient, sloID string) error {
+	if _, _, err := client.ServiceLevelObjectivesApi.DeleteSLO(auth, sloID); err != nil {
+		return datadogclient.TranslateClientError(err, "error deleting SLO")
+	}
+
+	return nil
+}
@@ -13,8 +13,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent"
 	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogslo"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -26,19 +28,31 @@ import (
 )
 
 const (
-	agentControllerName   = "DatadogAgent"
-	monitorControllerName = "DatadogMonitor"
+	agentControllerName    = "DatadogAgent"
+	monitorControllerName  = "DatadogMonitor"
+	downtimeControllerName = "DatadogDowntime"
+	sloControllerName      = "DatadogSLO"
 )
 
 // SetupOptions defines options for setting up controllers to ease testing
 type SetupOptions struct {
-	SupportExtendedDaemonset ExtendedDaemonsetOptions
-	SupportCilium            bool
-	Creds                    config.Creds
-	DatadogAgentEnabled      bool
-	DatadogMonitorEnabled    bool
-	OperatorMetricsEnabled   bool
-	V2APIEnabled             bool
+	SupportExtendedDaemonset       ExtendedDaemonsetOptions
+	SupportCilium                  bool
+	Creds                          config.Creds
+	CredentialManager              *config.CredentialManager
+	DatadogAgentEnabled            bool
+	DatadogMonitorEnabled          bool
+	DatadogMonitorDeletionPolicy   datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+	DatadogDowntimeEnabled         bool
+	DatadogSLOEnabled              bool
+	OperatorMetricsEnabled         bool
+	V2APIEnabled                   bool
+	ServerSideApplyEnabled         bool
+	UserAgent                      string
+	DatadogAgentExtensionEnabled   bool
+	ExtensionClusterRulesEnabled   bool
+	DaemonSetCanary                DaemonSetCanaryOptions
+	AgentConflictPreventionEnabled bool
 }
 
 // ExtendedDaemonsetOptions defines ExtendedDaemonset options
@@ -55,11 +69,20 @@ type ExtendedDaemonsetOptions struct {
 	CanaryAutoFailMaxRestarts  int
 }
 
+// DaemonSetCanaryOptions defines the canary options of the Agent DaemonSet
+type DaemonSetCanaryOptions struct {
+	Enabled        bool
+	NodeLabelKey   string
+	NodeLabelValue string
+}
+
 type starterFunc func(logr.Logger, manager.Manager, *version.Info, kubernetes.PlatformInfo, SetupOptions) error
 
 var controllerStarters = map[string]starterFunc{
-	agentControllerName:   startDatadogAgent,
-	monitorControllerName: startDatadogMonitor,
+	agentControllerName:    startDatadogAgent,
+	monitorControllerName:  startDatadogMonitor,
+	downtimeControllerName: startDatadogDowntime,
+	sloControllerName:      startDatadogSLO,
 }
 
 // SetupControllers starts all controllers (also used by e2e tests)
@@ -112,6 +135,7 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, vInfo *version.I
 
 	return (&DatadogAgentReconciler{
 		Client:       mgr.GetClient(),
+		APIReader:    mgr.GetAPIReader(),
 		VersionInfo:  vInfo,
 		PlatformInfo: pInfo,
 		Log:          ctrl.Log.WithName("controllers").WithName(agentControllerName),
@@ -129,9 +153,19 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, vInfo *version.I
 				CanaryAutoFailEnabled:      options.SupportExtendedDaemonset.CanaryAutoFailEnabled,
 				CanaryAutoFailMaxRestarts:  int32(options.SupportExtendedDaemonset.CanaryAutoFailMaxRestarts),
 			},
-			SupportCilium:          options.SupportCilium,
-			OperatorMetricsEnabled: options.OperatorMetricsEnabled,
-			V2Enabled:              options.V2APIEnabled,
+			SupportCilium:                options.SupportCilium,
+			OperatorMetricsEnabled:       options.OperatorMetricsEnabled,
+			V2Enabled:                    options.V2APIEnabled,
+			ServerSideApplyEnabled:       options.ServerSideApplyEnabled,
+			UpdateFieldManager:           kubernetes.UpdateFieldManager(options.UserAgent),
+			DatadogAgentExtensionEnabled: options.DatadogAgentExtensionEnabled,
+			ExtensionClusterRulesEnabled: options.ExtensionClusterRulesEnabled,
+			DaemonSetCanaryOptions: componentagent.DaemonSetCanaryOptions{
+				Enabled:        options.DaemonSetCanary.Enabled,
+				NodeLabelKey:   options.DaemonSetCanary.NodeLabelKey,
+				NodeLabelValue: options.DaemonSetCanary.NodeLabelValue,
+			},
+			AgentConflictPreventionEnabled: options.AgentConflictPreventionEnabled,
 		},
 	}).SetupWithManager(mgr)
 }
@@ -143,17 +177,99 @@ func startDatadogMonitor(logger logr.Logger, mgr manager.Manager, vInfo *version
 		return nil
 	}
 
+	log := ctrl.Log.WithName("controllers").WithName(monitorControllerName)
 	ddClient, err := datadogclient.InitDatadogClient(logger, options.Creds)
 	if err != nil {
 		return fmt.Errorf("unable to create Datadog API Client: %w", err)
 	}
+	sharedClient := datadogclient.NewSharedClient(log, ddClient)
 
-	return (&DatadogMonitorReconciler{
-		Client:      mgr.GetClient(),
-		DDClient:    ddClient,
-		VersionInfo: vInfo,
-		Log:         ctrl.Log.WithName("controllers").WithName(monitorControllerName),
-		Scheme:      mgr.GetScheme(),
-		Recorder:    mgr.GetEventRecorderFor(monitorControllerName),
-	}).SetupWithManager(mgr)
+	reconciler := &DatadogMonitorReconciler{
+		Client:         mgr.GetClient(),
+		APIReader:      mgr.GetAPIReader(),
+		DDClient:       sharedClient,
+		VersionInfo:    vInfo,
+		DeletionPolicy: options.DatadogMonitorDeletionPolicy,
+		Log:            log,
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor(monitorControllerName),
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	// Replace the Datadog API client when the credentials are rotated
+	if options.CredentialManager != nil {
+		options.CredentialManager.RegisterCallback(sharedClient.UpdateCredentials)
+	}
+
+	return nil
+}
+
+func startDatadogDowntime(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if !options.DatadogDowntimeEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", downtimeControllerName)
+
+		return nil
+	}
+
+	log := ctrl.Log.WithName("controllers").WithName(downtimeControllerName)
+	ddClient, err := datadogclient.InitDatadogClient(logger, options.Creds)
+	if err != nil {
+		return fmt.Errorf("unable to create Datadog API Client: %w", err)
+	}
+	sharedClient := datadogclient.NewSharedClient(log, ddClient)
+
+	reconciler := &DatadogDowntimeReconciler{
+		Client:                   mgr.GetClient(),
+		DDClient:                 sharedClient,
+		Log:                      log,
+		Scheme:                   mgr.GetScheme(),
+		Recorder:                 mgr.GetEventRecorderFor(downtimeControllerName),
+		V2Enabled:                options.V2APIEnabled,
+		ExtendedDaemonSetEnabled: options.SupportExtendedDaemonset.Enabled,
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	// Replace the Datadog API client when the credentials are rotated
+	if options.CredentialManager != nil {
+		options.CredentialManager.RegisterCallback(sharedClient.UpdateCredentials)
+	}
+
+	return nil
+}
+
+func startDatadogSLO(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if !options.DatadogSLOEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", sloControllerName)
+
+		return nil
+	}
+
+	log := ctrl.Log.WithName("controllers").WithName(sloControllerName)
+	ddClient, err := datadogclient.InitDatadogClient(logger, options.Creds, datadogslo.UnstableOperations...)
+	if err != nil {
+		return fmt.Errorf("unable to create Datadog API Client: %w", err)
+	}
+	sharedClient := datadogclient.NewSharedClient(log, ddClient, datadogslo.UnstableOperations...)
+
+	reconciler := &DatadogSLOReconciler{
+		Client:   mgr.GetClient(),
+		DDClient: sharedClient,
+		Log:      log,
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(sloControllerName),
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	// Replace the Datadog API client when the credentials are rotated
+	if options.CredentialManager != nil {
+		options.CredentialManager.RegisterCallback(sharedClient.UpdateCredentials)
+	}
+
+	return nil
 }
@@ -37,6 +37,7 @@ import (
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/controllers"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/debug"
 	"github.com/DataDog/datadog-operator/pkg/secrets"
@@ -84,6 +85,12 @@ const (
 	// default to 0, to use default value from EDS.
 	defaultCanaryAutoPauseMaxRestarts = 0
 	defaultCanaryAutoFailMaxRestarts  = 0
+
+	defaultDaemonsetCanaryNodeLabel = "agent.datadoghq.com/canary-node=true"
+
+	// The token of the HTTP secret provider is read from the environment, not from a flag
+	secretBackendHTTPTokenEnvVar        = "DD_SECRET_BACKEND_HTTP_TOKEN"
+	defaultSecretBackendHTTPTokenHeader = "X-Vault-Token"
 )
 
 type options struct {
@@ -101,26 +108,41 @@ type options struct {
 	leaderElectionLeaseDuration time.Duration
 
 	// Controllers options
-	supportExtendedDaemonset      bool
-	edsMaxPodUnavailable          string
-	edsMaxPodSchedulerFailure     string
-	edsCanaryDuration             time.Duration
-	edsCanaryReplicas             string
-	edsCanaryAutoPauseEnabled     bool
-	edsCanaryAutoPauseMaxRestarts int
-	edsCanaryAutoFailEnabled      bool
-	edsCanaryAutoFailMaxRestarts  int
-	supportCilium                 bool
-	datadogAgentEnabled           bool
-	datadogMonitorEnabled         bool
-	operatorMetricsEnabled        bool
-	webhookEnabled                bool
-	v2APIEnabled                  bool
-	maximumGoroutines             int
+	supportExtendedDaemonset       bool
+	edsMaxPodUnavailable           string
+	edsMaxPodSchedulerFailure      string
+	edsCanaryDuration              time.Duration
+	edsCanaryReplicas              string
+	edsCanaryAutoPauseEnabled      bool
+	edsCanaryAutoPauseMaxRestarts  int
+	edsCanaryAutoFailEnabled       bool
+	edsCanaryAutoFailMaxRestarts   int
+	supportCilium                  bool
+	datadogAgentEnabled            bool
+	datadogMonitorEnabled          bool
+	datadogMonitorDeletionPolicy   string
+	datadogDowntimeEnabled         bool
+	datadogSLOEnabled              bool
+	operatorMetricsEnabled         bool
+	webhookEnabled                 bool
+	v2APIEnabled                   bool
+	serverSideApplyEnabled         bool
+	datadogAgentExtensionEnabled   bool
+	extensionClusterRulesEnabled   bool
+	agentConflictPreventionEnabled bool
+	daemonsetCanaryEnabled         bool
+	daemonsetCanaryNodeLabel       string
+	maximumGoroutines              int
 
 	// Secret Backend options
-	secretBackendCommand string
-	secretBackendArgs    stringSlice
+	secretBackendCommand         string
+	secretBackendArgs            stringSlice
+	secretBackendHTTPURL         string
+	secretBackendHTTPTokenHeader string
+	secretBackendK8sEnabled      bool
+	secretBackendFileEnabled     bool
+	secretBackendEnvEnabled      bool
+	credentialsRefreshInterval   time.Duration
 }
 
 func (opts *options) Parse() {
@@ -141,12 +163,25 @@ func (opts *options) Parse() {
 	// Custom flags
 	flag.StringVar(&opts.secretBackendCommand, "secretBackendCommand", "", "Secret backend command")
 	flag.Var(&opts.secretBackendArgs, "secretBackendArgs", "Space separated arguments of the secret backend command")
+	flag.StringVar(&opts.secretBackendHTTPURL, "secretBackendHTTPURL", "", "Base URL of the secrets with the ENC[http:<path>#<field>] handle, for instance the Vault API URL")
+	flag.DurationVar(&opts.credentialsRefreshInterval, "credentialsRefreshInterval", config.DefaultCredentialsCacheTTL, "Interval at which the operator API and App keys are resolved again through the secret backend, 0 to never resolve them again")
+	flag.StringVar(&opts.secretBackendHTTPTokenHeader, "secretBackendHTTPTokenHeader", defaultSecretBackendHTTPTokenHeader, "Header of the requests for the ENC[http:...] secrets containing the "+secretBackendHTTPTokenEnvVar+" environment variable")
+	flag.BoolVar(&opts.secretBackendK8sEnabled, "secretBackendKubernetesEnabled", false, "Enable the ENC[k8s:<namespace>/<name>/<key>] secrets, the DatadogAgents can only read the Secrets of their namespace")
+	flag.BoolVar(&opts.secretBackendFileEnabled, "secretBackendFileEnabled", false, "Enable the ENC[file:<path>] secrets in the operator configuration, they are refused in the DatadogAgents")
+	flag.BoolVar(&opts.secretBackendEnvEnabled, "secretBackendEnvEnabled", false, "Enable the ENC[env:<name>] secrets in the operator configuration, they are refused in the DatadogAgents")
 	flag.BoolVar(&opts.supportCilium, "supportCilium", false, "Support usage of Cilium network policies.")
 	flag.BoolVar(&opts.datadogAgentEnabled, "datadogAgentEnabled", true, "Enable the DatadogAgent controller")
 	flag.BoolVar(&opts.datadogMonitorEnabled, "datadogMonitorEnabled", false, "Enable the DatadogMonitor controller")
+	flag.StringVar(&opts.datadogMonitorDeletionPolicy, "datadogMonitorDeletionPolicy", string(datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete), "Default deletion policy of the DatadogMonitors: Delete to delete the monitor in Datadog with its DatadogMonitor, Orphan to keep it")
+	flag.BoolVar(&opts.datadogDowntimeEnabled, "datadogDowntimeEnabled", false, "Enable the DatadogDowntime controller")
+	flag.BoolVar(&opts.datadogSLOEnabled, "datadogSLOEnabled", false, "Enable the DatadogSLO controller")
 	flag.BoolVar(&opts.operatorMetricsEnabled, "operatorMetricsEnabled", true, "Enable sending operator metrics to Datadog")
 	flag.BoolVar(&opts.v2APIEnabled, "v2APIEnabled", true, "Enable the v2 api")
-	flag.BoolVar(&opts.webhookEnabled, "webhookEnabled", false, "Enable CRD conversion webhook.")
+	flag.BoolVar(&opts.serverSideApplyEnabled, "serverSideApplyEnabled", false, "Use server-side apply to create and update the resources managed by the v2 DatadogAgent controller")
+	flag.BoolVar(&opts.datadogAgentExtensionEnabled, "datadogAgentExtensionEnabled", false, "Apply the DatadogAgentExtensions to the DatadogAgents of their namespace (requires the v2 api)")
+	flag.BoolVar(&opts.extensionClusterRulesEnabled, "datadogAgentExtensionClusterPolicyRulesEnabled", false, "Allow the DatadogAgentExtensions to grant cluster-wide RBAC to the Agent service accounts with clusterPolicyRules")
+	flag.BoolVar(&opts.agentConflictPreventionEnabled, "agentConflictPreventionEnabled", false, "Don't deploy the node Agent of a DatadogAgent on nodes already running the node Agent of an older DatadogAgent (requires the v2 api)")
+	flag.BoolVar(&opts.webhookEnabled, "webhookEnabled", false, "Enable CRD conversion webhook and DatadogAgent validating webhook.")
 	flag.IntVar(&opts.maximumGoroutines, "maximumGoroutines", defaultMaximumGoroutines, "Override health check threshold for maximum number of goroutines.")
 
 	// ExtendedDaemonset configuration
@@ -160,6 +195,10 @@ func (opts *options) Parse() {
 	flag.BoolVar(&opts.edsCanaryAutoFailEnabled, "edsCanaryAutoFailEnabled", defaultCanaryAutoFailEnabled, "ExtendedDaemonset canary auto fail enabled")
 	flag.IntVar(&opts.edsCanaryAutoFailMaxRestarts, "edsCanaryAutoFailMaxRestarts", defaultCanaryAutoFailMaxRestarts, "ExtendedDaemonset canary auto fail max restart count")
 
+	// DaemonSet canary configuration, the edsCanary* flags are also used when the ExtendedDaemonset is not supported
+	flag.BoolVar(&opts.daemonsetCanaryEnabled, "daemonsetCanaryEnabled", false, "Roll out the Agent DaemonSet with a canary DaemonSet running on the nodes with the daemonsetCanaryNodeLabel label (requires the v2 api)")
+	flag.StringVar(&opts.daemonsetCanaryNodeLabel, "daemonsetCanaryNodeLabel", defaultDaemonsetCanaryNodeLabel, "Label of the nodes running the canary DaemonSet, as <key>=<value>")
+
 	// Parsing flags
 	flag.Parse()
 }
@@ -206,10 +245,32 @@ func run(opts *options) error {
 	// Dispatch CLI flags to each package
 	secrets.SetSecretBackendCommand(opts.secretBackendCommand)
 	secrets.SetSecretBackendArgs(opts.secretBackendArgs)
+	secrets.SetBuiltinProviders(secrets.BuiltinProviders{
+		Kubernetes: opts.secretBackendK8sEnabled,
+		File:       opts.secretBackendFileEnabled,
+		Env:        opts.secretBackendEnvEnabled,
+	})
+	config.SetCredentialsCacheTTL(opts.credentialsRefreshInterval)
+	httpDecryptorOptions := secrets.HTTPDecryptorOptions{URL: opts.secretBackendHTTPURL}
+	if token := os.Getenv(secretBackendHTTPTokenEnvVar); token != "" {
+		httpDecryptorOptions.Headers = map[string]string{opts.secretBackendHTTPTokenHeader: token}
+	}
+	secrets.SetHTTPDecryptorOptions(httpDecryptorOptions)
 
 	renewDeadline := opts.leaderElectionLeaseDuration / 2
 	retryPeriod := opts.leaderElectionLeaseDuration / 4
 
+	canaryNodeLabelKey, canaryNodeLabelValue, found := strings.Cut(opts.daemonsetCanaryNodeLabel, "=")
+	if opts.daemonsetCanaryEnabled && (!found || canaryNodeLabelKey == "") {
+		return setupErrorf(setupLog, fmt.Errorf("invalid label %q, expected <key>=<value>", opts.daemonsetCanaryNodeLabel), "Unable to setup the DaemonSet canary")
+	}
+
+	switch datadoghqv1alpha1.DatadogMonitorDeletionPolicy(opts.datadogMonitorDeletionPolicy) {
+	case datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete, datadoghqv1alpha1.DatadogMonitorDeletionPolicyOrphan:
+	default:
+		return setupErrorf(setupLog, fmt.Errorf("invalid deletion policy %q, expected Delete or Orphan", opts.datadogMonitorDeletionPolicy), "Unable to setup the DatadogMonitor controller")
+	}
+
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.UserAgent = "datadog-operator"
 	mgr, err := ctrl.NewManager(restConfig, config.ManagerOptionsWithNamespaces(setupLog, ctrl.Options{
@@ -229,14 +290,30 @@ func run(opts *options) error {
 
 	}
 
+	// The Kubernetes Secrets of the ENC[k8s:...] secrets are read before the manager cache is started
+	secrets.SetKubernetesReader(mgr.GetAPIReader())
+
 	// Custom setup
 	customSetupHealthChecks(setupLog, mgr, &opts.maximumGoroutines)
 	customSetupEndpoints(opts.pprofActive, mgr)
 
-	creds, err := config.NewCredentialManager().GetCredentials()
+	credsManager := config.NewCredentialManager()
+	creds, err := credsManager.GetCredentials()
 	if err != nil && opts.datadogMonitorEnabled {
 		return setupErrorf(setupLog, err, "Unable to get credentials for DatadogMonitor")
 	}
+	if err != nil && opts.datadogDowntimeEnabled {
+		return setupErrorf(setupLog, err, "Unable to get credentials for DatadogDowntime")
+	}
+	if err != nil && opts.datadogSLOEnabled {
+		return setupErrorf(setupLog, err, "Unable to get credentials for DatadogSLO")
+	}
+	if err == nil {
+		// Resolve the credentials periodically, so that the rotated keys are used without restarting the operator
+		if err = mgr.Add(credsManager); err != nil {
+			return setupErrorf(setupLog, err, "Unable to add the credentials refresh to the manager")
+		}
+	}
 
 	options := controllers.SetupOptions{
 		SupportExtendedDaemonset: controllers.ExtendedDaemonsetOptions{
@@ -250,12 +327,26 @@ func run(opts *options) error {
 			CanaryAutoFailMaxRestarts:  opts.edsCanaryAutoFailMaxRestarts,
 			MaxPodSchedulerFailure:     opts.edsMaxPodSchedulerFailure,
 		},
-		SupportCilium:          opts.supportCilium,
-		Creds:                  creds,
-		DatadogAgentEnabled:    opts.datadogAgentEnabled,
-		DatadogMonitorEnabled:  opts.datadogMonitorEnabled,
-		OperatorMetricsEnabled: opts.operatorMetricsEnabled,
-		V2APIEnabled:           opts.v2APIEnabled,
+		SupportCilium:                  opts.supportCilium,
+		Creds:                          creds,
+		CredentialManager:              credsManager,
+		DatadogAgentEnabled:            opts.datadogAgentEnabled,
+		DatadogMonitorEnabled:          opts.datadogMonitorEnabled,
+		DatadogMonitorDeletionPolicy:   datadoghqv1alpha1.DatadogMonitorDeletionPolicy(opts.datadogMonitorDeletionPolicy),
+		DatadogDowntimeEnabled:         opts.datadogDowntimeEnabled,
+		DatadogSLOEnabled:              opts.datadogSLOEnabled,
+		OperatorMetricsEnabled:         opts.operatorMetricsEnabled,
+		V2APIEnabled:                   opts.v2APIEnabled,
+		ServerSideApplyEnabled:         opts.serverSideApplyEnabled,
+		UserAgent:                      restConfig.UserAgent,
+		DatadogAgentExtensionEnabled:   opts.datadogAgentExtensionEnabled,
+		ExtensionClusterRulesEnabled:   opts.extensionClusterRulesEnabled,
+		AgentConflictPreventionEnabled: opts.agentConflictPreventionEnabled,
+		DaemonSetCanary: controllers.DaemonSetCanaryOptions{
+			Enabled:        opts.daemonsetCanaryEnabled,
+			NodeLabelKey:   canaryNodeLabelKey,
+			NodeLabelValue: canaryNodeLabelValue,
+		},
 	}
 
 	if err = controllers.SetupControllers(setupLog, mgr, options); err != nil {
@@ -263,7 +354,7 @@ func run(opts *options) error {
 	}
 
 	if opts.webhookEnabled && opts.datadogAgentEnabled {
-		if err = (&datadoghqv2alpha1.DatadogAgent{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = (&datadoghqv2alpha1.DatadogAgent{}).SetupWebhookWithManager(mgr, datadogagent.IsValidV2RequiredComponents); err != nil {
 			return setupErrorf(setupLog, err, "unable to create webhook", "webhook", "DatadogAgent")
 		}
 	}
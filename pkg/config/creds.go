@@ -6,6 +6,7 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"os"
 	"sync"
@@ -13,10 +14,44 @@ import (
 
 	"github.com/DataDog/datadog-operator/pkg/secrets"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// DefaultCredentialsCacheTTL is the default duration after which the credentials are resolved again.
+const DefaultCredentialsCacheTTL = 10 * time.Minute
+
+var (
+	credentialsCacheTTL = DefaultCredentialsCacheTTL
+
+	credentialsValid = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "datadog_operator",
+		Name:      "credentials_valid",
+		Help:      "1 if the last resolution of the operator API and App keys succeeded, 0 otherwise",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(credentialsValid)
+}
+
+// SetCredentialsCacheTTL sets the duration after which the credentials are resolved again.
+// A zero duration caches the credentials forever.
+func SetCredentialsCacheTTL(ttl time.Duration) {
+	credentialsCacheTTL = ttl
+}
+
+// CredentialsCacheTTL returns the duration after which the credentials are resolved again.
+func CredentialsCacheTTL() time.Duration {
+	return credentialsCacheTTL
+}
+
+// CredentialsChangeCallback is called with the new credentials when they change.
+type CredentialsChangeCallback func(Creds) error
+
 // Creds holds the api and app keys.
 type Creds struct {
 	APIKey string
@@ -24,18 +59,24 @@ type Creds struct {
 }
 
 // CredentialManager provides the credentials from the operator configuration.
+// The credentials are cached for CredentialsCacheTTL, and can be refreshed periodically with Start.
 type CredentialManager struct {
 	secretBackend    secrets.Decryptor
 	creds            Creds
+	credsExpiration  time.Time
+	cacheTTL         time.Duration
 	credsMutex       sync.Mutex
 	decryptorBackoff wait.Backoff
+	callbacks        []CredentialsChangeCallback
+	callbacksMutex   sync.Mutex
 }
 
 // NewCredentialManager returns a CredentialManager.
 func NewCredentialManager() *CredentialManager {
 	return &CredentialManager{
-		secretBackend: secrets.NewSecretBackend(),
+		secretBackend: secrets.NewDecryptor(),
 		creds:         Creds{},
+		cacheTTL:      credentialsCacheTTL,
 		decryptorBackoff: wait.Backoff{
 			Steps:    5,
 			Duration: 10 * time.Millisecond,
@@ -53,6 +94,66 @@ func (cm *CredentialManager) GetCredentials() (Creds, error) {
 		return creds, nil
 	}
 
+	return cm.Refresh()
+}
+
+// Refresh resolves the credentials again, even if they are cached, and calls the registered
+// callbacks if they changed. If the resolution fails, the callbacks aren't called and the
+// credentials_valid metric is set to 0.
+func (cm *CredentialManager) Refresh() (Creds, error) {
+	creds, err := cm.resolveCredentials()
+	if err != nil {
+		credentialsValid.Set(0)
+		return Creds{}, err
+	}
+	credentialsValid.Set(1)
+
+	previous := cm.cacheCreds(creds)
+	if previous.APIKey != "" && previous != creds {
+		cm.callbacksMutex.Lock()
+		defer cm.callbacksMutex.Unlock()
+		for _, callback := range cm.callbacks {
+			if err = callback(creds); err != nil {
+				return creds, err
+			}
+		}
+	}
+
+	return creds, nil
+}
+
+// RegisterCallback registers a function called with the new credentials when they change,
+// for instance to replace the Datadog API clients using the previous ones.
+func (cm *CredentialManager) RegisterCallback(callback CredentialsChangeCallback) {
+	cm.callbacksMutex.Lock()
+	defer cm.callbacksMutex.Unlock()
+	cm.callbacks = append(cm.callbacks, callback)
+}
+
+// Start refreshes the credentials every cache TTL until the context is done.
+// Start implements the controller-runtime Runnable interface.
+func (cm *CredentialManager) Start(ctx context.Context) error {
+	if cm.cacheTTL == 0 {
+		return nil
+	}
+
+	logger := logf.Log.WithName("CredentialManager")
+	ticker := time.NewTicker(cm.cacheTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := cm.Refresh(); err != nil {
+				logger.Error(err, "Unable to refresh the credentials, keeping the previous ones")
+			}
+		}
+	}
+}
+
+// resolveCredentials reads the credentials from the environment and decrypts them if needed.
+func (cm *CredentialManager) resolveCredentials() (Creds, error) {
 	apiKey := os.Getenv(DDAPIKeyEnvVar)
 	appKey := os.Getenv(DDAppKeyEnvVar)
 
@@ -89,21 +190,25 @@ func (cm *CredentialManager) GetCredentials() (Creds, error) {
 		}
 	}
 
-	creds := Creds{APIKey: apiKey, AppKey: appKey}
-	cm.cacheCreds(creds)
-
-	return creds, nil
+	return Creds{APIKey: apiKey, AppKey: appKey}, nil
 }
 
-func (cm *CredentialManager) cacheCreds(creds Creds) {
+// cacheCreds caches the credentials for the cache TTL and returns the previous ones.
+func (cm *CredentialManager) cacheCreds(creds Creds) Creds {
 	cm.credsMutex.Lock()
 	defer cm.credsMutex.Unlock()
+	previous := cm.creds
 	cm.creds = creds
+	cm.credsExpiration = time.Now().Add(cm.cacheTTL)
+	return previous
 }
 
 func (cm *CredentialManager) getCredsFromCache() (Creds, bool) {
 	cm.credsMutex.Lock()
 	defer cm.credsMutex.Unlock()
+	if cm.cacheTTL > 0 && time.Now().After(cm.credsExpiration) {
+		return Creds{}, false
+	}
 	if cm.creds.APIKey != "" && cm.creds.AppKey != "" {
 		return cm.creds, true
 	}
@@ -8,6 +8,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-operator/pkg/secrets"
 
@@ -213,3 +214,54 @@ func Test_getCredentials(t *testing.T) {
 		})
 	}
 }
+
+func Test_refreshCredentials(t *testing.T) {
+	t.Setenv("DD_API_KEY", "foo")
+	t.Setenv("DD_APP_KEY", "bar")
+
+	credsManager := NewCredentialManager()
+	var rotated []Creds
+	credsManager.RegisterCallback(func(creds Creds) error {
+		rotated = append(rotated, creds)
+		return nil
+	})
+
+	creds, err := credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.EqualValues(t, Creds{APIKey: "foo", AppKey: "bar"}, creds)
+	assert.Empty(t, rotated, "the callbacks aren't called for the first credentials")
+
+	// The keys are rotated: the cached credentials are used until they expire
+	t.Setenv("DD_API_KEY", "new-foo")
+	creds, err = credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.EqualValues(t, Creds{APIKey: "foo", AppKey: "bar"}, creds)
+
+	credsManager.credsExpiration = time.Now().Add(-time.Second)
+	creds, err = credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.EqualValues(t, Creds{APIKey: "new-foo", AppKey: "bar"}, creds)
+	assert.Equal(t, []Creds{{APIKey: "new-foo", AppKey: "bar"}}, rotated)
+
+	// Unchanged credentials don't call the callbacks
+	_, err = credsManager.Refresh()
+	assert.NoError(t, err)
+	assert.Len(t, rotated, 1)
+
+	// A failed resolution doesn't call the callbacks
+	os.Unsetenv("DD_APP_KEY")
+	_, err = credsManager.Refresh()
+	assert.Error(t, err)
+	assert.Len(t, rotated, 1)
+}
+
+func Test_getCredsFromCache_noTTL(t *testing.T) {
+	credsManager := NewCredentialManager()
+	credsManager.cacheTTL = 0
+	credsManager.cacheCreds(Creds{APIKey: "foo", AppKey: "bar"})
+	credsManager.credsExpiration = time.Now().Add(-time.Hour)
+
+	creds, cached := credsManager.getCredsFromCache()
+	assert.True(t, cached)
+	assert.EqualValues(t, Creds{APIKey: "foo", AppKey: "bar"}, creds)
+}
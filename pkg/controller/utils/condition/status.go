@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package condition
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeActive means the resource is in sync with Datadog
+	ConditionTypeActive = "Active"
+	// ConditionTypeError means the resource has an error
+	ConditionTypeError = "Error"
+)
+
+// SetErrorActiveStatusConditions sets the Error and Active conditions of a resource of the given kind to True or False
+func SetErrorActiveStatusConditions(conditions *[]metav1.Condition, kind string, generation int64, err error) {
+	if err != nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{Type: ConditionTypeError, Status: metav1.ConditionTrue, Reason: "Error", Message: err.Error(), ObservedGeneration: generation})
+		meta.SetStatusCondition(conditions, metav1.Condition{Type: ConditionTypeActive, Status: metav1.ConditionFalse, Reason: "Error", Message: kind + " error", ObservedGeneration: generation})
+	} else {
+		meta.SetStatusCondition(conditions, metav1.Condition{Type: ConditionTypeError, Status: metav1.ConditionFalse, Reason: "Synced", ObservedGeneration: generation})
+		meta.SetStatusCondition(conditions, metav1.Condition{Type: ConditionTypeActive, Status: metav1.ConditionTrue, Reason: "Synced", Message: kind + " ready", ObservedGeneration: generation})
+	}
+}
@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package condition
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetErrorActiveStatusConditions(t *testing.T) {
+	var conditions []metav1.Condition
+
+	SetErrorActiveStatusConditions(&conditions, "DatadogSLO", 2, errors.New("invalid query"))
+	assert.Len(t, conditions, 2)
+	assert.True(t, meta.IsStatusConditionTrue(conditions, ConditionTypeError))
+	assert.Equal(t, "invalid query", meta.FindStatusCondition(conditions, ConditionTypeError).Message)
+	assert.True(t, meta.IsStatusConditionFalse(conditions, ConditionTypeActive))
+	assert.Equal(t, "DatadogSLO error", meta.FindStatusCondition(conditions, ConditionTypeActive).Message)
+
+	SetErrorActiveStatusConditions(&conditions, "DatadogSLO", 3, nil)
+	assert.Len(t, conditions, 2)
+	assert.True(t, meta.IsStatusConditionFalse(conditions, ConditionTypeError))
+	assert.True(t, meta.IsStatusConditionTrue(conditions, ConditionTypeActive))
+	assert.Equal(t, "DatadogSLO ready", meta.FindStatusCondition(conditions, ConditionTypeActive).Message)
+	assert.Equal(t, int64(3), meta.FindStatusCondition(conditions, ConditionTypeActive).ObservedGeneration)
+}
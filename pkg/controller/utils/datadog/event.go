@@ -13,6 +13,14 @@ type Event struct {
 	Type  EventType
 }
 
+// DriftCorrection contains the required information to send the drift corrected metric
+type DriftCorrection struct {
+	// Kind of the resource that drifted
+	Kind string
+	// Field is the path of the first field that drifted
+	Field string
+}
+
 // EventType enumerates the possible event types to be sent
 type EventType string
 
@@ -22,6 +22,7 @@ type MetricForwardersManager interface {
 	Unregister(MonitoredObject)
 	ProcessError(MonitoredObject, error)
 	ProcessEvent(MonitoredObject, Event)
+	ProcessDriftCorrection(MonitoredObject, DriftCorrection)
 	MetricsForwarderStatusForObj(obj MonitoredObject) *ConditionCommon
 	SetEnabledFeatures(obj MonitoredObject, features []feature.Feature)
 }
@@ -33,7 +34,6 @@ type ForwardersManager struct {
 	platformInfo *kubernetes.PlatformInfo
 	v2Enabled    bool
 	forwarders   map[string]*metricsForwarder
-	decryptor    secrets.Decryptor
 	wg           sync.WaitGroup
 	sync.Mutex
 }
@@ -46,7 +46,6 @@ func NewForwardersManager(k8sClient client.Client, v2Enabled bool, platformInfo
 		platformInfo: platformInfo,
 		v2Enabled:    v2Enabled,
 		forwarders:   make(map[string]*metricsForwarder),
-		decryptor:    secrets.NewSecretBackend(),
 		wg:           sync.WaitGroup{},
 	}
 }
@@ -66,7 +65,8 @@ func (f *ForwardersManager) Register(obj client.Object) {
 	id := getObjID(obj) // nolint: ifshort
 	if _, found := f.forwarders[id]; !found {
 		log.Info("New Datadog metrics forwarder registered", "ID", id)
-		f.forwarders[id] = newMetricsForwarder(f.k8sClient, f.decryptor, obj, obj.GetObjectKind(), f.v2Enabled, f.platformInfo)
+		// The credentials of the DatadogAgent can only reference the Secrets of its namespace
+		f.forwarders[id] = newMetricsForwarder(f.k8sClient, secrets.NewNamespacedDecryptor(obj.GetNamespace()), obj, obj.GetObjectKind(), f.v2Enabled, f.platformInfo)
 		f.wg.Add(1)
 		go f.forwarders[id].start(&f.wg)
 	}
@@ -120,6 +120,24 @@ func (f *ForwardersManager) ProcessEvent(obj MonitoredObject, event Event) {
 	forwarder.eventChan <- event
 }
 
+// ProcessDriftCorrection dispatches the corrected drifts to their corresponding metric forwarders
+func (f *ForwardersManager) ProcessDriftCorrection(obj MonitoredObject, drift DriftCorrection) {
+	id := getObjID(obj)
+	forwarder, err := f.getForwarder(id)
+	if err != nil {
+		log.Error(err, "cannot process drift correction")
+
+		return
+	}
+	if forwarder.isDriftChanFull() {
+		// Discard sending the drift to avoid blocking this method
+		log.Error(fmt.Errorf("metrics forwarder %s: blocked drift forwarding", id), "cannot process drift correction")
+
+		return
+	}
+	forwarder.driftChan <- drift
+}
+
 // MetricsForwarderStatusForObj used to retrieve the Metrics forwarder status for a given object
 func (f *ForwardersManager) MetricsForwarderStatusForObj(obj MonitoredObject) *ConditionCommon {
 	id := getObjID(obj)
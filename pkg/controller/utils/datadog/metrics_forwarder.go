@@ -56,6 +56,11 @@ const (
 	reconcileErrTagFormat       = "reconcile_err:%s"
 	featureEnabledValue         = 1.0
 	featureEnabledFormat        = "%s.%s.feature.enabled"
+	driftCorrectedValue         = 1.0
+	driftCorrectedMetricFormat  = "%s.drift.corrected"
+	resourceKindTagFormat       = "resource_kind:%s"
+	driftedFieldTagFormat       = "drifted_field:%s"
+	countType                   = "count"
 	datadogOperatorSourceType   = "datadog"
 	defaultbaseURL              = "https://api.datadoghq.com"
 )
@@ -74,6 +79,7 @@ type delegatedAPI interface {
 	delegatedSendDeploymentMetric(float64, string, []string) error
 	delegatedSendReconcileMetric(float64, []string) error
 	delegatedSendFeatureMetric(string) error
+	delegatedSendDriftCorrectedMetric(float64, []string) error
 	delegatedSendEvent(string, EventType) error
 	delegatedValidateCreds(string, string) (*api.Client, error)
 }
@@ -117,12 +123,15 @@ type metricsForwarder struct {
 	stopChan            chan struct{}
 	errorChan           chan error
 	eventChan           chan Event
+	driftChan           chan DriftCorrection
 	lastReconcileErr    error
 	namespacedName      types.NamespacedName
 	logger              logr.Logger
 	delegator           delegatedAPI
 	decryptor           secrets.Decryptor
 	creds               sync.Map
+	credsExpiration     time.Time
+	credsCacheTTL       time.Duration
 	baseURL             string
 	status              *ConditionCommon
 	credsManager        *config.CredentialManager
@@ -144,9 +153,11 @@ func newMetricsForwarder(k8sClient client.Client, decryptor secrets.Decryptor, o
 		stopChan:            make(chan struct{}),
 		errorChan:           make(chan error, 100),
 		eventChan:           make(chan Event, 10),
+		driftChan:           make(chan DriftCorrection, 10),
 		lastReconcileErr:    errInitValue,
 		decryptor:           decryptor,
 		creds:               sync.Map{},
+		credsCacheTTL:       config.CredentialsCacheTTL(),
 		baseURL:             defaultbaseURL,
 		logger:              log.WithValues("CustomResource.Namespace", obj.GetNamespace(), "CustomResource.Name", obj.GetName()),
 		credsManager:        config.NewCredentialManager(),
@@ -210,6 +221,10 @@ func (mf *metricsForwarder) start(wg *sync.WaitGroup) {
 			if err := mf.forwardEvent(event); err != nil {
 				mf.logger.Error(err, "an error occurred while sending event")
 			}
+		case drift := <-mf.driftChan:
+			if err := mf.sendDriftCorrectedMetric(drift); err != nil {
+				mf.logger.Error(err, "an error occurred while sending drift metric")
+			}
 		}
 	}
 }
@@ -730,7 +745,12 @@ func (mf *metricsForwarder) resolveSecretsIfNeeded(apiKey, appKey string) (strin
 }
 
 // getSecretsFromCache returns the cached and decrypted values of encrypted creds
+// The cached values expire after the credentials cache TTL, so that the rotated secrets are decrypted again
 func (mf *metricsForwarder) getSecretsFromCache(encAPIKey, encAppKey string) (string, string, bool) {
+	if mf.credsCacheTTL > 0 && time.Now().After(mf.credsExpiration) {
+		return "", "", false
+	}
+
 	decAPIKey, found := mf.creds.Load(encAPIKey)
 	if !found {
 		return "", "", false
@@ -750,6 +770,7 @@ func (mf *metricsForwarder) resetSecretsCache(newSecrets map[string]string) {
 	for k, v := range newSecrets {
 		mf.creds.Store(k, v)
 	}
+	mf.credsExpiration = time.Now().Add(mf.credsCacheTTL)
 }
 
 // cleanSecretsCache deletes all cached secrets
@@ -863,6 +884,33 @@ func (mf *metricsForwarder) delegatedSendFeatureMetric(feature string) error {
 	return mf.datadogClient.PostMetrics(series)
 }
 
+// sendDriftCorrectedMetric is used to forward drift corrected metrics to Datadog
+func (mf *metricsForwarder) sendDriftCorrectedMetric(drift DriftCorrection) error {
+	tags := mf.tagsWithExtraTag(resourceKindTagFormat, drift.Kind)
+	tags = append(tags, fmt.Sprintf(driftedFieldTagFormat, drift.Field))
+	return mf.delegator.delegatedSendDriftCorrectedMetric(driftCorrectedValue, tags)
+}
+
+// delegatedSendDriftCorrectedMetric is separated from sendDriftCorrectedMetric to facilitate mocking the Datadog API
+func (mf *metricsForwarder) delegatedSendDriftCorrectedMetric(metricValue float64, tags []string) error {
+	ts := float64(time.Now().Unix())
+	metricName := fmt.Sprintf(driftCorrectedMetricFormat, mf.metricsPrefix)
+	series := []api.Metric{
+		{
+			Metric: api.String(metricName),
+			Points: []api.DataPoint{
+				{
+					api.Float64(ts),
+					api.Float64(metricValue),
+				},
+			},
+			Type: api.String(countType),
+			Tags: tags,
+		},
+	}
+	return mf.datadogClient.PostMetrics(series)
+}
+
 // isErrChanFull returs if the errorChan is full
 func (mf *metricsForwarder) isErrChanFull() bool {
 	return len(mf.errorChan) == cap(mf.errorChan)
@@ -873,6 +921,11 @@ func (mf *metricsForwarder) isEventChanFull() bool {
 	return len(mf.eventChan) == cap(mf.eventChan)
 }
 
+// isDriftChanFull returs if the driftChan is full
+func (mf *metricsForwarder) isDriftChanFull() bool {
+	return len(mf.driftChan) == cap(mf.driftChan)
+}
+
 func getbaseURL(dda *v1alpha1.DatadogAgent) string {
 	if apiutils.BoolValue(dda.Spec.Agent.Enabled) && dda.Spec.Agent.Config != nil && dda.Spec.Agent.Config.DDUrl != nil {
 		return *dda.Spec.Agent.Config.DDUrl
@@ -58,6 +58,11 @@ func (c *fakeMetricsForwarder) delegatedSendFeatureMetric(feature string) error
 	return nil
 }
 
+func (c *fakeMetricsForwarder) delegatedSendDriftCorrectedMetric(metricValue float64, tags []string) error {
+	c.Called(metricValue, tags)
+	return nil
+}
+
 func (c *fakeMetricsForwarder) delegatedValidateCreds(apiKey, appKey string) (*api.Client, error) {
 	c.Called(apiKey, appKey)
 	if strings.Contains(apiKey, "invalid") || strings.Contains(appKey, "invalid") {
@@ -1534,3 +1539,25 @@ func TestMetricsForwarder_sendFeatureMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsForwarder_sendDriftCorrectedMetric(t *testing.T) {
+	fmf := &fakeMetricsForwarder{}
+	nsn := types.NamespacedName{
+		Namespace: "foo",
+		Name:      "bar",
+	}
+	mf := &metricsForwarder{
+		namespacedName:      nsn,
+		delegator:           fmf,
+		monitoredObjectKind: "DatadogAgent",
+	}
+	mf.initGlobalTags()
+
+	wantTags := []string{"cr_namespace:foo", "cr_name:bar", "resource_kind:clusterroles", "drifted_field:rules"}
+	fmf.On("delegatedSendDriftCorrectedMetric", 1.0, wantTags)
+
+	err := mf.sendDriftCorrectedMetric(DriftCorrection{Kind: "clusterroles", Field: "rules"})
+	assert.NoError(t, err)
+	fmf.AssertCalled(t, "delegatedSendDriftCorrectedMetric", 1.0, wantTags)
+	fmf.AssertNumberOfCalls(t, "delegatedSendDriftCorrectedMetric", 1)
+}
@@ -30,7 +30,14 @@ type DatadogClient struct {
 }
 
 // InitDatadogClient initializes the Datadog API Client and establishes credentials.
-func InitDatadogClient(logger logr.Logger, creds config.Creds) (DatadogClient, error) {
+// unstableOperations are the unstable operations of the Datadog API enabled on the client.
+func InitDatadogClient(logger logr.Logger, creds config.Creds, unstableOperations ...string) (DatadogClient, error) {
+	return InitDatadogClientForSite(logger, creds, "", unstableOperations...)
+}
+
+// InitDatadogClientForSite initializes the Datadog API Client of a Datadog site and establishes credentials.
+// The site of the operator, DD_URL or DD_SITE, is used if site is empty.
+func InitDatadogClientForSite(logger logr.Logger, creds config.Creds, site string, unstableOperations ...string) (DatadogClient, error) {
 	if creds.APIKey == "" || creds.AppKey == "" {
 		return DatadogClient{}, errors.New("error obtaining API key and/or app key")
 	}
@@ -49,9 +56,14 @@ func InitDatadogClient(logger logr.Logger, creds config.Creds) (DatadogClient, e
 		},
 	)
 	configV1 := datadogapiclientv1.NewConfiguration()
+	for _, operation := range unstableOperations {
+		configV1.SetUnstableOperationEnabled(operation, true)
+	}
 
 	apiURL := ""
-	if os.Getenv(config.DDURLEnvVar) != "" {
+	if site != "" {
+		apiURL = prefix + strings.TrimSpace(site)
+	} else if os.Getenv(config.DDURLEnvVar) != "" {
 		apiURL = os.Getenv(config.DDURLEnvVar)
 	} else if site := os.Getenv(apicommon.DDSite); site != "" {
 		apiURL = prefix + strings.TrimSpace(site)
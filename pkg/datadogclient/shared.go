@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+// SharedClient is the Datadog API client of a controller, replaced when the credentials of the operator are rotated.
+// It is safe for concurrent use.
+type SharedClient struct {
+	logger             logr.Logger
+	unstableOperations []string
+
+	mutex  sync.RWMutex
+	client DatadogClient
+}
+
+// NewSharedClient returns a SharedClient using ddClient. unstableOperations are enabled on the clients
+// created when the credentials are rotated, they must also be enabled on ddClient.
+func NewSharedClient(logger logr.Logger, ddClient DatadogClient, unstableOperations ...string) *SharedClient {
+	return &SharedClient{
+		logger:             logger,
+		unstableOperations: unstableOperations,
+		client:             ddClient,
+	}
+}
+
+// Get returns the current Datadog API authentication context and client
+func (c *SharedClient) Get() (context.Context, *datadogapiclientv1.APIClient) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.client.Auth, c.client.Client
+}
+
+// Update replaces the Datadog API client
+func (c *SharedClient) Update(ddClient DatadogClient) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.client = ddClient
+}
+
+// UpdateCredentials replaces the Datadog API client with one using the new credentials.
+func (c *SharedClient) UpdateCredentials(creds config.Creds) error {
+	ddClient, err := InitDatadogClient(c.logger, creds, c.unstableOperations...)
+	if err != nil {
+		return err
+	}
+	c.logger.Info("Credentials changed, replacing the Datadog API client")
+	c.Update(ddClient)
+	return nil
+}
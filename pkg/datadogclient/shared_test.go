@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestSharedClient_UpdateCredentials(t *testing.T) {
+	ddClient, err := InitDatadogClient(logr.Discard(), config.Creds{APIKey: "api-key", AppKey: "app-key"})
+	require.NoError(t, err)
+	assert.False(t, ddClient.Client.GetConfig().IsUnstableOperationEnabled("GetSLOHistory"))
+
+	sharedClient := NewSharedClient(logr.Discard(), ddClient, "GetSLOHistory")
+	_, client := sharedClient.Get()
+	assert.Same(t, ddClient.Client, client)
+
+	// Invalid credentials: the client is kept
+	assert.Error(t, sharedClient.UpdateCredentials(config.Creds{APIKey: "new-api-key"}))
+	_, client = sharedClient.Get()
+	assert.Same(t, ddClient.Client, client)
+
+	// The unstable operations are enabled on the new client
+	require.NoError(t, sharedClient.UpdateCredentials(config.Creds{APIKey: "new-api-key", AppKey: "new-app-key"}))
+	_, client = sharedClient.Get()
+	assert.NotSame(t, ddClient.Client, client)
+	assert.True(t, client.GetConfig().IsUnstableOperationEnabled("GetSLOHistory"))
+}
@@ -0,0 +1,379 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package equality
+
+import (
+	"strings"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+// The functions of this file set in the desired object `a` the fields that the api-server
+// defaults or allocates when they are not set, so that `a` can be compared with the object
+// `b` currently in the api-server. The static defaults are set to their value, the allocated
+// fields and the defaults that depend on the cluster are copied from `b`.
+
+const (
+	defaultWebhookTimeoutSeconds                 = 10
+	defaultWebhookServicePort                    = 443
+	defaultHorizontalPodAutoscalerMinReplicas    = 1
+	defaultHorizontalPodAutoscalerCPUUtilization = 80
+)
+
+func setServiceSpecDefaults(a, b *corev1.ServiceSpec) {
+	if a.Type == "" {
+		a.Type = corev1.ServiceTypeClusterIP
+	}
+	if a.SessionAffinity == "" {
+		a.SessionAffinity = corev1.ServiceAffinityNone
+	}
+	if a.SessionAffinityConfig == nil {
+		a.SessionAffinityConfig = b.SessionAffinityConfig
+	}
+	if a.ExternalTrafficPolicy == "" && (a.Type == corev1.ServiceTypeNodePort || a.Type == corev1.ServiceTypeLoadBalancer) {
+		a.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	}
+	// Allocated by the api-server, or defaulted depending on the cluster version and configuration
+	if a.ClusterIP == "" {
+		a.ClusterIP = b.ClusterIP
+	}
+	if a.ClusterIPs == nil {
+		a.ClusterIPs = b.ClusterIPs
+	}
+	if a.IPFamilies == nil {
+		a.IPFamilies = b.IPFamilies
+	}
+	if a.IPFamilyPolicy == nil {
+		a.IPFamilyPolicy = b.IPFamilyPolicy
+	}
+	if a.HealthCheckNodePort == 0 {
+		a.HealthCheckNodePort = b.HealthCheckNodePort
+	}
+	if a.InternalTrafficPolicy == nil {
+		a.InternalTrafficPolicy = b.InternalTrafficPolicy
+	}
+	if a.AllocateLoadBalancerNodePorts == nil {
+		a.AllocateLoadBalancerNodePorts = b.AllocateLoadBalancerNodePorts
+	}
+
+	for i := range a.Ports {
+		port := &a.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		if port.NodePort == 0 && i < len(b.Ports) && b.Ports[i].Port == port.Port {
+			port.NodePort = b.Ports[i].NodePort
+		}
+	}
+}
+
+func setMutatingWebhookDefaults(a, b *admissionregistrationv1.MutatingWebhook) {
+	if a.FailurePolicy == nil {
+		failurePolicy := admissionregistrationv1.Fail
+		a.FailurePolicy = &failurePolicy
+	}
+	if a.MatchPolicy == nil {
+		matchPolicy := admissionregistrationv1.Equivalent
+		a.MatchPolicy = &matchPolicy
+	}
+	if a.NamespaceSelector == nil {
+		a.NamespaceSelector = &metav1.LabelSelector{}
+	}
+	if a.ObjectSelector == nil {
+		a.ObjectSelector = &metav1.LabelSelector{}
+	}
+	if a.TimeoutSeconds == nil {
+		a.TimeoutSeconds = apiutils.NewInt32Pointer(defaultWebhookTimeoutSeconds)
+	}
+	if a.ReinvocationPolicy == nil {
+		reinvocationPolicy := admissionregistrationv1.NeverReinvocationPolicy
+		a.ReinvocationPolicy = &reinvocationPolicy
+	}
+	if a.SideEffects == nil {
+		a.SideEffects = b.SideEffects
+	}
+	if a.AdmissionReviewVersions == nil {
+		a.AdmissionReviewVersions = b.AdmissionReviewVersions
+	}
+	if a.ClientConfig.Service != nil && a.ClientConfig.Service.Port == nil {
+		a.ClientConfig.Service.Port = apiutils.NewInt32Pointer(defaultWebhookServicePort)
+	}
+	// The CA bundle can be injected in the api-server, for instance by cert-manager
+	if len(a.ClientConfig.CABundle) == 0 {
+		a.ClientConfig.CABundle = b.ClientConfig.CABundle
+	}
+	for i := range a.Rules {
+		if a.Rules[i].Scope == nil {
+			scope := admissionregistrationv1.AllScopes
+			a.Rules[i].Scope = &scope
+		}
+	}
+}
+
+func setNetworkPolicySpecDefaults(a *networkingv1.NetworkPolicySpec) {
+	if len(a.PolicyTypes) == 0 {
+		a.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(a.Egress) > 0 {
+			a.PolicyTypes = append(a.PolicyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+	for i := range a.Ingress {
+		setNetworkPolicyPortsDefaults(a.Ingress[i].Ports)
+	}
+	for i := range a.Egress {
+		setNetworkPolicyPortsDefaults(a.Egress[i].Ports)
+	}
+}
+
+func setNetworkPolicyPortsDefaults(ports []networkingv1.NetworkPolicyPort) {
+	for i := range ports {
+		if ports[i].Protocol == nil {
+			protocol := corev1.ProtocolTCP
+			ports[i].Protocol = &protocol
+		}
+	}
+}
+
+func setPodSecurityPolicySpecDefaults(a *policyv1beta1.PodSecurityPolicySpec) {
+	if a.AllowPrivilegeEscalation == nil {
+		a.AllowPrivilegeEscalation = apiutils.NewBoolPointer(true)
+	}
+}
+
+func setSecurityContextConstraintsDefaults(a, b *securityv1.SecurityContextConstraints) {
+	if a.FSGroup.Type == "" {
+		a.FSGroup.Type = securityv1.FSGroupStrategyRunAsAny
+	}
+	if a.SupplementalGroups.Type == "" {
+		a.SupplementalGroups.Type = securityv1.SupplementalGroupsStrategyRunAsAny
+	}
+	// The default volumes depend on allowHostDirVolumePlugin
+	if len(a.Volumes) == 0 {
+		a.Volumes = b.Volumes
+	}
+}
+
+func setHorizontalPodAutoscalerSpecDefaults(a, b *autoscalingv2.HorizontalPodAutoscalerSpec) {
+	if a.MinReplicas == nil {
+		a.MinReplicas = apiutils.NewInt32Pointer(defaultHorizontalPodAutoscalerMinReplicas)
+	}
+	if len(a.Metrics) == 0 {
+		a.Metrics = []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: apiutils.NewInt32Pointer(defaultHorizontalPodAutoscalerCPUUtilization),
+					},
+				},
+			},
+		}
+	}
+	// The scaling rules of a behavior are defaulted depending on the direction of the scaling
+	if a.Behavior != nil && b.Behavior != nil {
+		a.Behavior.ScaleUp = horizontalPodAutoscalerScalingRulesDefaults(a.Behavior.ScaleUp, b.Behavior.ScaleUp)
+		a.Behavior.ScaleDown = horizontalPodAutoscalerScalingRulesDefaults(a.Behavior.ScaleDown, b.Behavior.ScaleDown)
+	}
+}
+
+func horizontalPodAutoscalerScalingRulesDefaults(a, b *autoscalingv2.HPAScalingRules) *autoscalingv2.HPAScalingRules {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.StabilizationWindowSeconds == nil {
+		a.StabilizationWindowSeconds = b.StabilizationWindowSeconds
+	}
+	if a.SelectPolicy == nil {
+		a.SelectPolicy = b.SelectPolicy
+	}
+	if len(a.Policies) == 0 {
+		a.Policies = b.Policies
+	}
+	return a
+}
+
+const (
+	defaultTerminationGracePeriodSeconds = 30
+	defaultProbeTimeoutSeconds           = 1
+	defaultProbePeriodSeconds            = 10
+	defaultProbeSuccessThreshold         = 1
+	defaultProbeFailureThreshold         = 3
+	defaultVolumeMode                    = 0o644
+	defaultTokenExpirationSeconds        = 3600
+)
+
+func setPodTemplateDefaults(a *corev1.PodTemplateSpec) {
+	spec := &a.Spec
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = corev1.DNSClusterFirst
+	}
+	if spec.TerminationGracePeriodSeconds == nil {
+		spec.TerminationGracePeriodSeconds = apiutils.NewInt64Pointer(defaultTerminationGracePeriodSeconds)
+	}
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if spec.SchedulerName == "" {
+		spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	// The api-server keeps the deprecated field in sync
+	if spec.DeprecatedServiceAccount == "" {
+		spec.DeprecatedServiceAccount = spec.ServiceAccountName
+	}
+
+	for i := range spec.Volumes {
+		setVolumeDefaults(&spec.Volumes[i])
+	}
+	for i := range spec.InitContainers {
+		setContainerDefaults(&spec.InitContainers[i], spec.HostNetwork)
+	}
+	for i := range spec.Containers {
+		setContainerDefaults(&spec.Containers[i], spec.HostNetwork)
+	}
+}
+
+func setContainerDefaults(a *corev1.Container, hostNetwork bool) {
+	if a.TerminationMessagePath == "" {
+		a.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if a.TerminationMessagePolicy == "" {
+		a.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	if a.ImagePullPolicy == "" {
+		a.ImagePullPolicy = defaultImagePullPolicy(a.Image)
+	}
+	for i := range a.Ports {
+		port := &a.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if hostNetwork && port.HostPort == 0 {
+			port.HostPort = port.ContainerPort
+		}
+	}
+	for i := range a.Env {
+		if a.Env[i].ValueFrom != nil {
+			setObjectFieldSelectorDefaults(a.Env[i].ValueFrom.FieldRef)
+		}
+	}
+	for _, probe := range []*corev1.Probe{a.LivenessProbe, a.ReadinessProbe, a.StartupProbe} {
+		if probe == nil {
+			continue
+		}
+		if probe.TimeoutSeconds == 0 {
+			probe.TimeoutSeconds = defaultProbeTimeoutSeconds
+		}
+		if probe.PeriodSeconds == 0 {
+			probe.PeriodSeconds = defaultProbePeriodSeconds
+		}
+		if probe.SuccessThreshold == 0 {
+			probe.SuccessThreshold = defaultProbeSuccessThreshold
+		}
+		if probe.FailureThreshold == 0 {
+			probe.FailureThreshold = defaultProbeFailureThreshold
+		}
+		setHTTPGetActionDefaults(probe.HTTPGet)
+	}
+	if a.Lifecycle != nil {
+		for _, handler := range []*corev1.LifecycleHandler{a.Lifecycle.PostStart, a.Lifecycle.PreStop} {
+			if handler != nil {
+				setHTTPGetActionDefaults(handler.HTTPGet)
+			}
+		}
+	}
+}
+
+// defaultImagePullPolicy returns the pull policy of an image without one: Always for the latest tag, IfNotPresent otherwise
+func defaultImagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+func setHTTPGetActionDefaults(a *corev1.HTTPGetAction) {
+	if a == nil {
+		return
+	}
+	if a.Path == "" {
+		a.Path = "/"
+	}
+	if a.Scheme == "" {
+		a.Scheme = corev1.URISchemeHTTP
+	}
+}
+
+func setObjectFieldSelectorDefaults(a *corev1.ObjectFieldSelector) {
+	if a != nil && a.APIVersion == "" {
+		a.APIVersion = "v1"
+	}
+}
+
+func setVolumeDefaults(a *corev1.Volume) {
+	switch {
+	case a.Secret != nil:
+		if a.Secret.DefaultMode == nil {
+			a.Secret.DefaultMode = apiutils.NewInt32Pointer(defaultVolumeMode)
+		}
+	case a.ConfigMap != nil:
+		if a.ConfigMap.DefaultMode == nil {
+			a.ConfigMap.DefaultMode = apiutils.NewInt32Pointer(defaultVolumeMode)
+		}
+	case a.DownwardAPI != nil:
+		if a.DownwardAPI.DefaultMode == nil {
+			a.DownwardAPI.DefaultMode = apiutils.NewInt32Pointer(defaultVolumeMode)
+		}
+		for i := range a.DownwardAPI.Items {
+			setObjectFieldSelectorDefaults(a.DownwardAPI.Items[i].FieldRef)
+		}
+	case a.Projected != nil:
+		if a.Projected.DefaultMode == nil {
+			a.Projected.DefaultMode = apiutils.NewInt32Pointer(defaultVolumeMode)
+		}
+		for _, source := range a.Projected.Sources {
+			if source.DownwardAPI != nil {
+				for i := range source.DownwardAPI.Items {
+					setObjectFieldSelectorDefaults(source.DownwardAPI.Items[i].FieldRef)
+				}
+			}
+			if source.ServiceAccountToken != nil && source.ServiceAccountToken.ExpirationSeconds == nil {
+				source.ServiceAccountToken.ExpirationSeconds = apiutils.NewInt64Pointer(defaultTokenExpirationSeconds)
+			}
+		}
+	case a.HostPath != nil:
+		if a.HostPath.Type == nil {
+			hostPathType := corev1.HostPathUnset
+			a.HostPath.Type = &hostPathType
+		}
+	}
+}
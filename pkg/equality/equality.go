@@ -6,7 +6,13 @@
 package equality
 
 import (
+	"reflect"
+	"sort"
+	"strings"
+
+	securityv1 "github.com/openshift/api/security/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -22,218 +28,430 @@ import (
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 )
 
+const (
+	// unknownField is returned by DriftedField when the two objects can't be compared,
+	// for instance if they don't have the expected type.
+	unknownField = "object"
+)
+
 // IsEqualObject return true if the two object are equal.
+// `a` is the desired object and `b` the object currently in the api-server: the fields that
+// are not set in `a` and defaulted or allocated by the api-server in `b` are ignored.
 func IsEqualObject(kind kubernetes.ObjectKind, a, b client.Object) bool {
-	if !IsEqualOperatorObjectMeta(a, b) {
-		return false
+	return DriftedField(kind, a, b) == ""
+}
+
+// DriftedField returns the path of the first field that differs between the desired object `a`
+// and the object `b` currently in the api-server, for instance "spec.ports" or "rules".
+// It returns an empty string if the two objects are equal.
+func DriftedField(kind kubernetes.ObjectKind, a, b client.Object) string {
+	if field := operatorObjectMetaDriftedField(a, b); field != "" {
+		return field
 	}
 
 	switch kind {
 	case kubernetes.ConfigMapKind:
-		return IsEqualConfigMap(a, b)
+		return configMapDriftedField(a, b)
 	case kubernetes.ClusterRolesKind:
-		return IsEqualClusterRoles(a, b)
+		return clusterRolesDriftedField(a, b)
 	case kubernetes.ClusterRoleBindingKind:
-		return IsEqualClusterRoleBinding(a, b)
+		return clusterRoleBindingDriftedField(a, b)
 	case kubernetes.RolesKind:
-		return IsEqualRoles(a, b)
+		return rolesDriftedField(a, b)
 	case kubernetes.RoleBindingKind:
-		return IsEqualRoleBinding(a, b)
+		return roleBindingDriftedField(a, b)
 	case kubernetes.MutatingWebhookConfigurationsKind:
-		return IsEqualMutatingWebhookConfigurations(a, b)
+		return mutatingWebhookConfigurationsDriftedField(a, b)
 	case kubernetes.APIServiceKind:
-		return IsEqualAPIService(a, b)
+		return apiServiceDriftedField(a, b)
 	case kubernetes.SecretsKind:
-		return IsEqualSecrets(a, b)
+		return secretsDriftedField(a, b)
 	case kubernetes.ServicesKind:
-		return IsEqualServices(a, b)
+		return servicesDriftedField(a, b)
 	case kubernetes.ServiceAccountsKind:
-		return IsEqualServiceAccounts(a, b)
+		return serviceAccountsDriftedField(a, b)
 	case kubernetes.PodDisruptionBudgetsKind:
-		return IsEqualPodDisruptionBudgets(a, b)
+		return podDisruptionBudgetsDriftedField(a, b)
+	case kubernetes.HorizontalPodAutoscalersKind:
+		return horizontalPodAutoscalersDriftedField(a, b)
 	case kubernetes.NetworkPoliciesKind:
-		return IsEqualNetworkPolicies(a, b)
+		return networkPoliciesDriftedField(a, b)
 	case kubernetes.PodSecurityPoliciesKind:
-		return IsEqualPodSecurityPolicies(a, b)
+		return podSecurityPoliciesDriftedField(a, b)
 	case kubernetes.CiliumNetworkPoliciesKind:
-		return IsEqualCiliumNetworkPolicies(a, b)
+		return ciliumNetworkPoliciesDriftedField(a, b)
+	case kubernetes.SecurityContextConstraintsKind:
+		return securityContextConstraintsDriftedField(a, b)
 	default:
-		return false
+		return unstructuredDriftedField(a, b)
 	}
 }
 
 // IsEqualConfigMap return true if the two ConfigMap are equal
 func IsEqualConfigMap(a, b client.Object) bool {
+	return configMapDriftedField(a, b) == ""
+}
+
+func configMapDriftedField(a, b client.Object) string {
 	cmA, okA := a.(*corev1.ConfigMap)
 	cmB, okB := b.(*corev1.ConfigMap)
 	if okA && okB && cmA != nil && cmB != nil {
 		if !apiutils.IsEqualStruct(cmA.Data, cmB.Data) {
-			return false
+			return "data"
 		}
-		if !apiutils.IsEqualStruct(cmA.BinaryData, cmA.BinaryData) {
-			return false
+		if !apiutils.IsEqualStruct(cmA.BinaryData, cmB.BinaryData) {
+			return "binaryData"
 		}
-		return true
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualClusterRoles return true if the two ClusterRole are equal
 func IsEqualClusterRoles(a, b client.Object) bool {
+	return clusterRolesDriftedField(a, b) == ""
+}
+
+func clusterRolesDriftedField(a, b client.Object) string {
 	crA, okA := a.(*rbacv1.ClusterRole)
 	crB, okB := b.(*rbacv1.ClusterRole)
 	if okA && okB && crA != nil && crB != nil {
-		return apiequality.Semantic.DeepEqual(crA.Rules, crB.Rules)
+		if !apiequality.Semantic.DeepEqual(crA.Rules, crB.Rules) {
+			return "rules"
+		}
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualClusterRoleBinding return true if the two ClusterRoleBinding are equal
 func IsEqualClusterRoleBinding(objA, objB client.Object) bool {
+	return clusterRoleBindingDriftedField(objA, objB) == ""
+}
+
+func clusterRoleBindingDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*rbacv1.ClusterRoleBinding)
 	b, okB := objB.(*rbacv1.ClusterRoleBinding)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.RoleRef, b.RoleRef) && apiequality.Semantic.DeepEqual(a.Subjects, b.Subjects)
+		return roleBindingFieldsDriftedField(a.RoleRef, b.RoleRef, a.Subjects, b.Subjects)
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualRoles return true if the two Roles are equal
 func IsEqualRoles(objA, objB client.Object) bool {
+	return rolesDriftedField(objA, objB) == ""
+}
+
+func rolesDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*rbacv1.Role)
 	b, okB := objB.(*rbacv1.Role)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Rules, b.Rules)
+		if !apiequality.Semantic.DeepEqual(a.Rules, b.Rules) {
+			return "rules"
+		}
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualRoleBinding return true if the two RoleBinding are equal
 func IsEqualRoleBinding(objA, objB client.Object) bool {
+	return roleBindingDriftedField(objA, objB) == ""
+}
+
+func roleBindingDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*rbacv1.RoleBinding)
 	b, okB := objB.(*rbacv1.RoleBinding)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.RoleRef, b.RoleRef) && apiequality.Semantic.DeepEqual(a.Subjects, b.Subjects)
+		return roleBindingFieldsDriftedField(a.RoleRef, b.RoleRef, a.Subjects, b.Subjects)
 	}
-	return false
+	return unknownField
+}
+
+func roleBindingFieldsDriftedField(roleRefA, roleRefB rbacv1.RoleRef, subjectsA, subjectsB []rbacv1.Subject) string {
+	if !apiequality.Semantic.DeepEqual(roleRefA, roleRefB) {
+		return "roleRef"
+	}
+	if !apiequality.Semantic.DeepEqual(subjectsA, subjectsB) {
+		return "subjects"
+	}
+	return ""
 }
 
 // IsEqualMutatingWebhookConfigurations return true if the two MutatingWebhookConfigurations are equal
 func IsEqualMutatingWebhookConfigurations(objA, objB client.Object) bool {
+	return mutatingWebhookConfigurationsDriftedField(objA, objB) == ""
+}
+
+func mutatingWebhookConfigurationsDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*admissionregistrationv1.MutatingWebhookConfiguration)
 	b, okB := objB.(*admissionregistrationv1.MutatingWebhookConfiguration)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Webhooks, b.Webhooks)
+		if len(a.Webhooks) != len(b.Webhooks) {
+			return "webhooks"
+		}
+		// The api-server defaults several fields of the webhooks (timeoutSeconds, matchPolicy...).
+		for i := range a.Webhooks {
+			webhook := a.Webhooks[i].DeepCopy()
+			setMutatingWebhookDefaults(webhook, &b.Webhooks[i])
+			if field := driftedField("webhooks", *webhook, b.Webhooks[i]); field != "" {
+				return field
+			}
+		}
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualAPIService return true if the two APIService are equal
 func IsEqualAPIService(objA, objB client.Object) bool {
+	return apiServiceDriftedField(objA, objB) == ""
+}
+
+func apiServiceDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*apiregistrationv1.APIService)
 	b, okB := objB.(*apiregistrationv1.APIService)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
+		return driftedField("spec", a.Spec, b.Spec)
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualSecrets return true if the two Secrets are equal
 func IsEqualSecrets(a, b client.Object) bool {
-	sA, okA := a.(*corev1.ConfigMap)
-	sB, okB := b.(*corev1.ConfigMap)
+	return secretsDriftedField(a, b) == ""
+}
+
+func secretsDriftedField(a, b client.Object) string {
+	sA, okA := a.(*corev1.Secret)
+	sB, okB := b.(*corev1.Secret)
 	if okA && okB && sA != nil && sB != nil {
-		if !apiutils.IsEqualStruct(sA.Data, sB.Data) {
-			return false
+		// StringData is write-only: the api-server merges it into Data.
+		dataA := make(map[string][]byte, len(sA.Data)+len(sA.StringData))
+		for key, value := range sA.Data {
+			dataA[key] = value
+		}
+		for key, value := range sA.StringData {
+			dataA[key] = []byte(value)
 		}
-		if !apiutils.IsEqualStruct(sA.BinaryData, sA.BinaryData) {
-			return false
+		if !apiequality.Semantic.DeepEqual(dataA, sB.Data) {
+			return "data"
 		}
-		return true
+		if sA.Type != "" && sA.Type != sB.Type {
+			return "type"
+		}
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualServices return true if the two Services are equal
 func IsEqualServices(objA, objB client.Object) bool {
+	return servicesDriftedField(objA, objB) == ""
+}
+
+func servicesDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*corev1.Service)
 	b, okB := objB.(*corev1.Service)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
+		// The api-server defaults several fields of the spec (sessionAffinity, ipFamilies, ports[].targetPort...).
+		spec := a.Spec.DeepCopy()
+		setServiceSpecDefaults(spec, &b.Spec)
+		return driftedField("spec", *spec, b.Spec)
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualServiceAccounts return true if the two ServiceAccounts are equal
 func IsEqualServiceAccounts(objA, objB client.Object) bool {
+	return serviceAccountsDriftedField(objA, objB) == ""
+}
+
+func serviceAccountsDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*corev1.ServiceAccount)
 	b, okB := objB.(*corev1.ServiceAccount)
 	if okA && okB && a != nil && b != nil {
-		return true
+		// `secrets` is not compared: the token controller can add the service account token secret.
+		if !apiequality.Semantic.DeepEqual(a.ImagePullSecrets, b.ImagePullSecrets) {
+			return "imagePullSecrets"
+		}
+		if !apiequality.Semantic.DeepEqual(a.AutomountServiceAccountToken, b.AutomountServiceAccountToken) {
+			return "automountServiceAccountToken"
+		}
+		return ""
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualPodDisruptionBudgets return true if the two PodDisruptionBudgets are equal
 func IsEqualPodDisruptionBudgets(objA, objB client.Object) bool {
+	return podDisruptionBudgetsDriftedField(objA, objB) == ""
+}
+
+func podDisruptionBudgetsDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*policyv1.PodDisruptionBudget)
 	b, okB := objB.(*policyv1.PodDisruptionBudget)
-
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
-	} else {
-		ax, okA := objA.(*policyv1beta1.PodDisruptionBudget)
-		bx, okB := objB.(*policyv1beta1.PodDisruptionBudget)
-		if okA && okB && ax != nil && bx != nil {
-			return apiequality.Semantic.DeepEqual(ax.Spec, bx.Spec)
-		}
+		return driftedField("spec", a.Spec, b.Spec)
+	}
+
+	ax, okA := objA.(*policyv1beta1.PodDisruptionBudget)
+	bx, okB := objB.(*policyv1beta1.PodDisruptionBudget)
+	if okA && okB && ax != nil && bx != nil {
+		return driftedField("spec", ax.Spec, bx.Spec)
 	}
 
-	return false
+	return unknownField
+}
+
+// IsEqualHorizontalPodAutoscalers return true if the two HorizontalPodAutoscalers are equal
+func IsEqualHorizontalPodAutoscalers(objA, objB client.Object) bool {
+	return horizontalPodAutoscalersDriftedField(objA, objB) == ""
+}
+
+func horizontalPodAutoscalersDriftedField(objA, objB client.Object) string {
+	a, okA := objA.(*autoscalingv2.HorizontalPodAutoscaler)
+	b, okB := objB.(*autoscalingv2.HorizontalPodAutoscaler)
+	if okA && okB && a != nil && b != nil {
+		// The api-server defaults the minReplicas, the metrics and the scaling rules of the behavior.
+		spec := a.Spec.DeepCopy()
+		setHorizontalPodAutoscalerSpecDefaults(spec, &b.Spec)
+		return driftedField("spec", *spec, b.Spec)
+	}
+	return unknownField
 }
 
 // IsEqualNetworkPolicies return true if the two NetworkPolicies are equal
 func IsEqualNetworkPolicies(objA, objB client.Object) bool {
+	return networkPoliciesDriftedField(objA, objB) == ""
+}
+
+func networkPoliciesDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*networkingv1.NetworkPolicy)
 	b, okB := objB.(*networkingv1.NetworkPolicy)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
+		// The api-server defaults the policyTypes and the ports protocol.
+		spec := a.Spec.DeepCopy()
+		setNetworkPolicySpecDefaults(spec)
+		return driftedField("spec", *spec, b.Spec)
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualPodSecurityPolicies return true if the two PodSecurityPolicies are equal
 func IsEqualPodSecurityPolicies(objA, objB client.Object) bool {
+	return podSecurityPoliciesDriftedField(objA, objB) == ""
+}
+
+func podSecurityPoliciesDriftedField(objA, objB client.Object) string {
 	a, okA := objA.(*policyv1beta1.PodSecurityPolicy)
 	b, okB := objB.(*policyv1beta1.PodSecurityPolicy)
 	if okA && okB && a != nil && b != nil {
-		return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
+		spec := a.Spec.DeepCopy()
+		setPodSecurityPolicySpecDefaults(spec)
+		return driftedField("spec", *spec, b.Spec)
 	}
-	return false
+	return unknownField
 }
 
 // IsEqualCiliumNetworkPolicies return true if the two CiliumNetworkPolicies are equal
 func IsEqualCiliumNetworkPolicies(objA, objB client.Object) bool {
+	return ciliumNetworkPoliciesDriftedField(objA, objB) == ""
+}
+
+func ciliumNetworkPoliciesDriftedField(objA, objB client.Object) string {
 	unstructuredA, errA := runtime.DefaultUnstructuredConverter.ToUnstructured(objA)
 	if errA != nil {
-		return false
+		return unknownField
 	}
 
 	unstructuredB, errB := runtime.DefaultUnstructuredConverter.ToUnstructured(objB)
 	if errB != nil {
-		return false
+		return unknownField
+	}
+
+	if !apiequality.Semantic.DeepEqual(unstructuredA["specs"], unstructuredB["specs"]) {
+		return "specs"
+	}
+	return ""
+}
+
+// IsEqualSecurityContextConstraints return true if the two SecurityContextConstraints are equal
+func IsEqualSecurityContextConstraints(objA, objB client.Object) bool {
+	return securityContextConstraintsDriftedField(objA, objB) == ""
+}
+
+func securityContextConstraintsDriftedField(objA, objB client.Object) string {
+	a, okA := objA.(*securityv1.SecurityContextConstraints)
+	b, okB := objB.(*securityv1.SecurityContextConstraints)
+	if okA && okB && a != nil && b != nil {
+		// SecurityContextConstraints don't have a spec: every field except the metadata is compared.
+		aNoMeta, bNoMeta := a.DeepCopy(), b.DeepCopy()
+		aNoMeta.TypeMeta, bNoMeta.TypeMeta = metav1.TypeMeta{}, metav1.TypeMeta{}
+		aNoMeta.ObjectMeta, bNoMeta.ObjectMeta = metav1.ObjectMeta{}, metav1.ObjectMeta{}
+		setSecurityContextConstraintsDefaults(aNoMeta, bNoMeta)
+		return driftedField("", *aNoMeta, *bNoMeta)
+	}
+	return unknownField
+}
+
+// unstructuredDriftedField is used for the kinds without a dedicated comparator:
+// every top-level field, except the metadata and the status, is compared.
+func unstructuredDriftedField(objA, objB client.Object) string {
+	unstructuredA, errA := runtime.DefaultUnstructuredConverter.ToUnstructured(objA)
+	if errA != nil {
+		return unknownField
+	}
+
+	unstructuredB, errB := runtime.DefaultUnstructuredConverter.ToUnstructured(objB)
+	if errB != nil {
+		return unknownField
+	}
+
+	for _, key := range sortedKeys(unstructuredA, unstructuredB) {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		if !apiequality.Semantic.DeepEqual(unstructuredA[key], unstructuredB[key]) {
+			return key
+		}
 	}
+	return ""
+}
 
-	return apiequality.Semantic.DeepEqual(unstructuredA["specs"], unstructuredB["specs"])
+// IsEqualPodTemplate return true if the two pod templates are equal. `a` is the desired template and `b` the
+// template currently in the api-server: the fields that are not set in `a` and defaulted by the api-server are ignored.
+func IsEqualPodTemplate(a, b *corev1.PodTemplateSpec) bool {
+	template := a.DeepCopy()
+	setPodTemplateDefaults(template)
+	return apiequality.Semantic.DeepEqual(*template, *b)
 }
 
 // IsEqualOperatorObjectMeta return true if the meta information added by the Operator are equal:
 // Annotations, Labels, OwnerReference
 func IsEqualOperatorObjectMeta(a, b metav1.Object) bool {
-	if a.GetName() != b.GetName() || a.GetNamespace() != b.GetNamespace() {
-		return false
+	return operatorObjectMetaDriftedField(a, b) == ""
+}
+
+func operatorObjectMetaDriftedField(a, b metav1.Object) string {
+	if a.GetName() != b.GetName() {
+		return "metadata.name"
+	}
+	if a.GetNamespace() != b.GetNamespace() {
+		return "metadata.namespace"
+	}
+	if !apiequality.Semantic.DeepEqual(a.GetOwnerReferences(), b.GetOwnerReferences()) {
+		return "metadata.ownerReferences"
+	}
+	if !IsEqualOperatorAnnotations(a, b) {
+		return "metadata.annotations"
+	}
+	if !IsEqualOperatorLabels(a, b) {
+		return "metadata.labels"
 	}
-	return apiequality.Semantic.DeepEqual(a.GetOwnerReferences(), b.GetOwnerReferences()) && IsEqualOperatorAnnotations(a, b) && IsEqualOperatorLabels(a, b)
+	return ""
 }
 
 // IsEqualOperatorAnnotations use to check if Operator annotations are equal between 2 Objects
@@ -247,3 +465,60 @@ func IsEqualOperatorLabels(a, b metav1.Object) bool {
 	// TODO compare Operator labels only
 	return true
 }
+
+// driftedField compares the desired value `a` with the current value `b`. If the two values are
+// structs, it returns the path of the first field that differs (for instance "spec.ports"),
+// otherwise it returns `path`.
+func driftedField(path string, a, b interface{}) string {
+	if apiequality.Semantic.DeepEqual(a, b) {
+		return ""
+	}
+
+	valueA := reflect.ValueOf(a)
+	valueB := reflect.ValueOf(b)
+	if valueA.Kind() != reflect.Struct || valueA.Type() != valueB.Type() {
+		return path
+	}
+	for i := 0; i < valueA.NumField(); i++ {
+		structField := valueA.Type().Field(i)
+		if structField.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		if !apiequality.Semantic.DeepEqual(valueA.Field(i).Interface(), valueB.Field(i).Interface()) {
+			return joinFieldPath(path, jsonFieldName(structField))
+		}
+	}
+	return path
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// sortedKeys returns the sorted keys of the maps, without duplicates
+func sortedKeys(maps ...map[string]interface{}) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
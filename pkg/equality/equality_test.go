@@ -0,0 +1,319 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package equality
+
+import (
+	"testing"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestDriftedField(t *testing.T) {
+	meta := metav1.ObjectMeta{Namespace: "bar", Name: "foo"}
+	apiServerMeta := metav1.ObjectMeta{Namespace: "bar", Name: "foo", ResourceVersion: "42", UID: "uid"}
+	sideEffectNone := admissionregistrationv1.SideEffectClassNone
+	sideEffectSome := admissionregistrationv1.SideEffectClassSome
+	tcp := corev1.ProtocolTCP
+	udp := corev1.ProtocolUDP
+	// apiServerWebhook returns the webhook as defaulted by the api-server
+	apiServerWebhook := func(sideEffects *admissionregistrationv1.SideEffectClass, rules ...admissionregistrationv1.RuleWithOperations) admissionregistrationv1.MutatingWebhook {
+		failurePolicy := admissionregistrationv1.Fail
+		matchPolicy := admissionregistrationv1.Equivalent
+		reinvocationPolicy := admissionregistrationv1.NeverReinvocationPolicy
+		scope := admissionregistrationv1.AllScopes
+		for i := range rules {
+			rules[i].Scope = &scope
+		}
+		return admissionregistrationv1.MutatingWebhook{
+			Name:               "datadog.webhook.agent.config",
+			Rules:              rules,
+			SideEffects:        sideEffects,
+			FailurePolicy:      &failurePolicy,
+			MatchPolicy:        &matchPolicy,
+			NamespaceSelector:  &metav1.LabelSelector{},
+			ObjectSelector:     &metav1.LabelSelector{},
+			TimeoutSeconds:     apiutils.NewInt32Pointer(10),
+			ReinvocationPolicy: &reinvocationPolicy,
+		}
+	}
+	podsRule := admissionregistrationv1.RuleWithOperations{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+	}
+	servicesRule := admissionregistrationv1.RuleWithOperations{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"services"}},
+	}
+	// apiServerServiceSpec returns the Service spec as defaulted and allocated by the api-server
+	apiServerServiceSpec := func(ports ...int32) corev1.ServiceSpec {
+		spec := corev1.ServiceSpec{
+			ClusterIP:       "10.0.0.1",
+			ClusterIPs:      []string{"10.0.0.1"},
+			IPFamilies:      []corev1.IPFamily{corev1.IPv4Protocol},
+			Type:            corev1.ServiceTypeClusterIP,
+			SessionAffinity: corev1.ServiceAffinityNone,
+		}
+		for _, port := range ports {
+			spec.Ports = append(spec.Ports, corev1.ServicePort{Port: port, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(int(port))})
+		}
+		return spec
+	}
+	// apiServerNetworkPolicySpec returns the NetworkPolicy spec as defaulted by the api-server
+	apiServerNetworkPolicySpec := func(egress ...networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicySpec {
+		return networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		}
+	}
+	dnsEgress := networkingv1.NetworkPolicyEgressRule{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &intstr.IntOrString{IntVal: 53}}}}
+	intakeEgress := networkingv1.NetworkPolicyEgressRule{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &intstr.IntOrString{IntVal: 443}}}}
+
+	tests := []struct {
+		name    string
+		kind    kubernetes.ObjectKind
+		desired client.Object
+		current client.Object
+		want    string
+	}{
+		{
+			name:    "different names",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: meta},
+			current: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "baz"}},
+			want:    "metadata.name",
+		},
+		{
+			name:    "equal ConfigMaps",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: meta, Data: map[string]string{"foo": "bar"}},
+			current: &corev1.ConfigMap{ObjectMeta: apiServerMeta, Data: map[string]string{"foo": "bar"}},
+		},
+		{
+			name:    "ConfigMap binaryData drift",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: meta},
+			current: &corev1.ConfigMap{ObjectMeta: apiServerMeta, BinaryData: map[string][]byte{"foo": []byte("bar")}},
+			want:    "binaryData",
+		},
+		{
+			name:    "ClusterRole rules drift",
+			kind:    kubernetes.ClusterRolesKind,
+			desired: &rbacv1.ClusterRole{ObjectMeta: meta, Rules: []rbacv1.PolicyRule{{Verbs: []string{"get"}}}},
+			current: &rbacv1.ClusterRole{ObjectMeta: apiServerMeta, Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}}}},
+			want:    "rules",
+		},
+		{
+			name:    "RoleBinding subjects drift",
+			kind:    kubernetes.RoleBindingKind,
+			desired: &rbacv1.RoleBinding{ObjectMeta: meta, Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: "foo"}}},
+			current: &rbacv1.RoleBinding{ObjectMeta: apiServerMeta},
+			want:    "subjects",
+		},
+		{
+			name:    "equal Secrets with stringData",
+			kind:    kubernetes.SecretsKind,
+			desired: &corev1.Secret{ObjectMeta: meta, StringData: map[string]string{"api_key": "0000"}},
+			current: &corev1.Secret{ObjectMeta: apiServerMeta, Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"api_key": []byte("0000")}},
+		},
+		{
+			name:    "Secret data drift",
+			kind:    kubernetes.SecretsKind,
+			desired: &corev1.Secret{ObjectMeta: meta, Data: map[string][]byte{"api_key": []byte("0000")}},
+			current: &corev1.Secret{ObjectMeta: apiServerMeta, Data: map[string][]byte{"api_key": []byte("1111")}},
+			want:    "data",
+		},
+		{
+			name: "Service with fields defaulted by the api-server",
+			kind: kubernetes.ServicesKind,
+			desired: &corev1.Service{ObjectMeta: meta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5005}},
+			}},
+			current: &corev1.Service{ObjectMeta: apiServerMeta, Spec: apiServerServiceSpec(5005)},
+		},
+		{
+			name: "Service port removed from the desired object",
+			kind: kubernetes.ServicesKind,
+			desired: &corev1.Service{ObjectMeta: meta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5005}},
+			}},
+			current: &corev1.Service{ObjectMeta: apiServerMeta, Spec: apiServerServiceSpec(5005, 8126)},
+			want:    "spec.ports",
+		},
+		{
+			name: "Service port added outside of the operator",
+			kind: kubernetes.ServicesKind,
+			desired: &corev1.Service{ObjectMeta: meta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5005}, {Port: 8126}},
+			}},
+			current: &corev1.Service{ObjectMeta: apiServerMeta, Spec: apiServerServiceSpec(5005)},
+			want:    "spec.ports",
+		},
+		{
+			name: "Service sessionAffinity changed outside of the operator",
+			kind: kubernetes.ServicesKind,
+			desired: &corev1.Service{ObjectMeta: meta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5005}},
+			}},
+			current: &corev1.Service{ObjectMeta: apiServerMeta, Spec: func() corev1.ServiceSpec {
+				spec := apiServerServiceSpec(5005)
+				spec.SessionAffinity = corev1.ServiceAffinityClientIP
+				return spec
+			}()},
+			want: "spec.sessionAffinity",
+		},
+		{
+			name: "Service ports drift",
+			kind: kubernetes.ServicesKind,
+			desired: &corev1.Service{ObjectMeta: meta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5005, Protocol: corev1.ProtocolTCP}},
+			}},
+			current: &corev1.Service{ObjectMeta: apiServerMeta, Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 5006, Protocol: corev1.ProtocolTCP}},
+			}},
+			want: "spec.ports",
+		},
+		{
+			name:    "ServiceAccount with a token secret added by the api-server",
+			kind:    kubernetes.ServiceAccountsKind,
+			desired: &corev1.ServiceAccount{ObjectMeta: meta},
+			current: &corev1.ServiceAccount{ObjectMeta: apiServerMeta, Secrets: []corev1.ObjectReference{{Name: "foo-token"}}},
+		},
+		{
+			name:    "ServiceAccount automountServiceAccountToken drift",
+			kind:    kubernetes.ServiceAccountsKind,
+			desired: &corev1.ServiceAccount{ObjectMeta: meta, AutomountServiceAccountToken: apiutils.NewBoolPointer(true)},
+			current: &corev1.ServiceAccount{ObjectMeta: apiServerMeta, AutomountServiceAccountToken: apiutils.NewBoolPointer(false)},
+			want:    "automountServiceAccountToken",
+		},
+		{
+			name: "MutatingWebhookConfiguration with fields defaulted by the api-server",
+			kind: kubernetes.MutatingWebhookConfigurationsKind,
+			desired: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: meta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "datadog.webhook.agent.config", SideEffects: &sideEffectNone, Rules: []admissionregistrationv1.RuleWithOperations{podsRule}},
+			}},
+			current: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: apiServerMeta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				apiServerWebhook(&sideEffectNone, podsRule),
+			}},
+		},
+		{
+			name: "MutatingWebhookConfiguration rule removed from the desired object",
+			kind: kubernetes.MutatingWebhookConfigurationsKind,
+			desired: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: meta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "datadog.webhook.agent.config", SideEffects: &sideEffectNone, Rules: []admissionregistrationv1.RuleWithOperations{podsRule}},
+			}},
+			current: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: apiServerMeta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				apiServerWebhook(&sideEffectNone, podsRule, servicesRule),
+			}},
+			want: "webhooks.rules",
+		},
+		{
+			name: "MutatingWebhookConfiguration webhook drift",
+			kind: kubernetes.MutatingWebhookConfigurationsKind,
+			desired: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: meta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "datadog.webhook.agent.config", SideEffects: &sideEffectNone},
+			}},
+			current: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: apiServerMeta, Webhooks: []admissionregistrationv1.MutatingWebhook{
+				apiServerWebhook(&sideEffectSome),
+			}},
+			want: "webhooks.sideEffects",
+		},
+		{
+			name:    "NetworkPolicy with fields defaulted by the api-server",
+			kind:    kubernetes.NetworkPoliciesKind,
+			desired: &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: networkingv1.NetworkPolicySpec{Egress: []networkingv1.NetworkPolicyEgressRule{{Ports: []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{IntVal: 443}}}}}}},
+			current: &networkingv1.NetworkPolicy{ObjectMeta: apiServerMeta, Spec: apiServerNetworkPolicySpec(intakeEgress)},
+		},
+		{
+			name:    "NetworkPolicy egress rule removed from the desired object",
+			kind:    kubernetes.NetworkPoliciesKind,
+			desired: &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: networkingv1.NetworkPolicySpec{Egress: []networkingv1.NetworkPolicyEgressRule{intakeEgress}}},
+			current: &networkingv1.NetworkPolicy{ObjectMeta: apiServerMeta, Spec: apiServerNetworkPolicySpec(intakeEgress, dnsEgress)},
+			want:    "spec.egress",
+		},
+		{
+			name:    "NetworkPolicy egress rule added outside of the operator",
+			kind:    kubernetes.NetworkPoliciesKind,
+			desired: &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: networkingv1.NetworkPolicySpec{Egress: []networkingv1.NetworkPolicyEgressRule{intakeEgress}}},
+			current: &networkingv1.NetworkPolicy{ObjectMeta: apiServerMeta, Spec: apiServerNetworkPolicySpec(intakeEgress, networkingv1.NetworkPolicyEgressRule{})},
+			want:    "spec.egress",
+		},
+		{
+			name:    "HorizontalPodAutoscaler with fields defaulted by the api-server",
+			kind:    kubernetes.HorizontalPodAutoscalersKind,
+			desired: &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: meta, Spec: autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 5}},
+			current: &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: apiServerMeta, Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				MaxReplicas: 5,
+				MinReplicas: apiutils.NewInt32Pointer(1),
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: apiutils.NewInt32Pointer(80)},
+					},
+				}},
+			}},
+		},
+		{
+			name:    "HorizontalPodAutoscaler minReplicas drift",
+			kind:    kubernetes.HorizontalPodAutoscalersKind,
+			desired: &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: meta, Spec: autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 5, MinReplicas: apiutils.NewInt32Pointer(2)}},
+			current: &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: apiServerMeta, Spec: autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 5, MinReplicas: apiutils.NewInt32Pointer(1)}},
+			want:    "spec.minReplicas",
+		},
+		{
+			name:    "SecurityContextConstraints drift",
+			kind:    kubernetes.SecurityContextConstraintsKind,
+			desired: &securityv1.SecurityContextConstraints{ObjectMeta: meta, AllowHostPID: true},
+			current: &securityv1.SecurityContextConstraints{ObjectMeta: apiServerMeta},
+			want:    "allowHostPID",
+		},
+		{
+			name:    "equal SecurityContextConstraints",
+			kind:    kubernetes.SecurityContextConstraintsKind,
+			desired: &securityv1.SecurityContextConstraints{ObjectMeta: meta, AllowHostPID: true},
+			current: &securityv1.SecurityContextConstraints{
+				ObjectMeta:         apiServerMeta,
+				AllowHostPID:       true,
+				FSGroup:            securityv1.FSGroupStrategyOptions{Type: securityv1.FSGroupStrategyRunAsAny},
+				SupplementalGroups: securityv1.SupplementalGroupsStrategyOptions{Type: securityv1.SupplementalGroupsStrategyRunAsAny},
+				Volumes:            []securityv1.FSType{securityv1.FSTypeAll},
+			},
+		},
+		{
+			name:    "unknown kind",
+			kind:    "foos",
+			desired: &corev1.Pod{ObjectMeta: meta, Spec: corev1.PodSpec{NodeName: "node1"}},
+			current: &corev1.Pod{ObjectMeta: apiServerMeta, Spec: corev1.PodSpec{NodeName: "node2"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			want:    "spec",
+		},
+		{
+			name:    "unexpected type",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: meta},
+			current: &corev1.Secret{ObjectMeta: apiServerMeta},
+			want:    "object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DriftedField(tt.kind, tt.desired, tt.current))
+			assert.Equal(t, tt.want == "", IsEqualObject(tt.kind, tt.desired, tt.current))
+		})
+	}
+}
@@ -46,6 +46,8 @@ const (
 	ServiceAccountsKind = "serviceaccounts"
 	// PodDisruptionBudgetsKind PodDisruptionBudgets resource kind
 	PodDisruptionBudgetsKind = "poddisruptionbudgets"
+	// HorizontalPodAutoscalersKind HorizontalPodAutoscalers resource kind
+	HorizontalPodAutoscalersKind = "horizontalpodautoscalers"
 	// NetworkPoliciesKind NetworkPolicies resource kind
 	NetworkPoliciesKind = "networkpolicies"
 	// PodSecurityPoliciesKind PodSecurityPolicies resource kind
@@ -54,10 +56,16 @@ const (
 	CiliumNetworkPoliciesKind = "ciliumnetworkpolicies"
 	// SecurityContextConstraintsKind SecurityContextConstraints resource kind
 	SecurityContextConstraintsKind = "securitycontextconstraints"
+	// DeploymentsKind Deployments resource kind
+	DeploymentsKind = "deployments"
+	// DaemonSetsKind DaemonSets resource kind
+	DaemonSetsKind = "daemonsets"
+	// ExtendedDaemonSetsKind ExtendedDaemonSets resource kind
+	ExtendedDaemonSetsKind = "extendeddaemonsets"
 )
 
 // GetResourcesKind return the list of all possible ObjectKind supported as DatadogAgent dependencies
-func getResourcesKind(withCiliumResources, withPodSecurityPolicy bool) []ObjectKind {
+func getResourcesKind(withCiliumResources, withPodSecurityPolicy, withHPAV2 bool) []ObjectKind {
 	resources := []ObjectKind{
 		ConfigMapKind,
 		ClusterRolesKind,
@@ -82,5 +90,9 @@ func getResourcesKind(withCiliumResources, withPodSecurityPolicy bool) []ObjectK
 		resources = append(resources, PodSecurityPoliciesKind)
 	}
 
+	if withHPAV2 {
+		resources = append(resources, HorizontalPodAutoscalersKind)
+	}
+
 	return resources
 }
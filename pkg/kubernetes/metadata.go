@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetes
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/gobwas/glob"
+)
+
+// MergeAnnotationsLabels returns the new annotations or labels of an object, with the previous ones that are not managed
+// by the operator kept if they match the glob filter, or if they contain "datadoghq.com".
+func MergeAnnotationsLabels(logger logr.Logger, previousVal map[string]string, newVal map[string]string, filter string) map[string]string {
+	var globFilter glob.Glob
+	var err error
+	if filter != "" {
+		globFilter, err = glob.Compile(filter)
+		if err != nil {
+			logger.Error(err, "Unable to parse glob filter for metadata/annotations - discarding everything", "filter", filter)
+		}
+	}
+
+	mergedMap := make(map[string]string, len(newVal))
+	for k, v := range newVal {
+		mergedMap[k] = v
+	}
+
+	// Copy from previous if not in new match and matches globfilter
+	for k, v := range previousVal {
+		if _, found := newVal[k]; !found {
+			if (globFilter != nil && globFilter.Match(k)) || strings.Contains(k, "datadoghq.com") {
+				mergedMap[k] = v
+			}
+		}
+	}
+
+	return mergedMap
+}
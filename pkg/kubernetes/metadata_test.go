@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestMergeAnnotationsLabels(t *testing.T) {
+	type args struct {
+		previousVal map[string]string
+		newVal      map[string]string
+		filter      string
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]string
+	}{
+		{
+			name: "basic test",
+			args: args{
+				previousVal: map[string]string{
+					"foo":               "bar",
+					"foo-datadoghq.com": "dog-bar",
+					"foo-removed":       "foo",
+					"foo.match":         "foomatch",
+				},
+				newVal: map[string]string{
+					"foo": "baz",
+				},
+				filter: "*.match",
+			},
+			want: map[string]string{
+				"foo":               "baz",
+				"foo-datadoghq.com": "dog-bar",
+				"foo.match":         "foomatch",
+			},
+		},
+		{
+			name: "no filter test",
+			args: args{
+				previousVal: map[string]string{
+					"foo":               "bar",
+					"foo-datadoghq.com": "dog-bar",
+					"foo-removed":       "foo",
+					"foo.match":         "foomatch",
+				},
+				newVal: map[string]string{
+					"foo": "baz",
+				},
+			},
+			want: map[string]string{
+				"foo":               "baz",
+				"foo-datadoghq.com": "dog-bar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := logf.Log.WithName(t.Name())
+			got := MergeAnnotationsLabels(logger, tt.args.previousVal, tt.args.newVal, tt.args.filter)
+			diff := cmp.Diff(tt.want, got)
+			assert.Empty(t, diff)
+		})
+	}
+}
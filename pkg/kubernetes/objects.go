@@ -8,6 +8,7 @@ package kubernetes
 import (
 	securityv1 "github.com/openshift/api/security/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -43,6 +44,8 @@ func ObjectFromKind(kind ObjectKind, platformInfo PlatformInfo) client.Object {
 		return &corev1.ServiceAccount{}
 	case PodDisruptionBudgetsKind:
 		return platformInfo.CreatePDBObject()
+	case HorizontalPodAutoscalersKind:
+		return &autoscalingv2.HorizontalPodAutoscaler{}
 	case NetworkPoliciesKind:
 		return &networkingv1.NetworkPolicy{}
 	case PodSecurityPoliciesKind:
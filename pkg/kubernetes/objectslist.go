@@ -7,6 +7,7 @@ package kubernetes
 
 import (
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -42,6 +43,8 @@ func ObjectListFromKind(kind ObjectKind, platformInfo PlatformInfo) client.Objec
 		return &corev1.ServiceAccountList{}
 	case PodDisruptionBudgetsKind:
 		return platformInfo.CreatePDBObjectList()
+	case HorizontalPodAutoscalersKind:
+		return &autoscalingv2.HorizontalPodAutoscalerList{}
 	case NetworkPoliciesKind:
 		return &networkingv1.NetworkPolicyList{}
 	case PodSecurityPoliciesKind:
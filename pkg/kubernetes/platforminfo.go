@@ -6,8 +6,13 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/pkg/utils"
 )
 
+// hpaV2MinimumVersion is the first Kubernetes version serving the autoscaling/v2 API.
+const hpaV2MinimumVersion = "1.23-0"
+
 type PlatformInfo struct {
 	versionInfo          *version.Info
 	apiPreferredVersions map[string]string
@@ -89,7 +94,15 @@ func (platformInfo *PlatformInfo) CreatePDBObjectList() client.ObjectList {
 }
 
 func (platformInfo *PlatformInfo) GetAgentResourcesKind(withCiliumResources bool) []ObjectKind {
-	return getResourcesKind(withCiliumResources, platformInfo.supportsPSP())
+	return getResourcesKind(withCiliumResources, platformInfo.supportsPSP(), platformInfo.SupportsHPAV2())
+}
+
+// SupportsHPAV2 returns true if the autoscaling/v2 HorizontalPodAutoscaler API is served by the cluster.
+func (platformInfo *PlatformInfo) SupportsHPAV2() bool {
+	if platformInfo.versionInfo == nil || platformInfo.versionInfo.GitVersion == "" {
+		return false
+	}
+	return utils.IsAboveMinVersion(platformInfo.versionInfo.GitVersion, hpaV2MinimumVersion)
 }
 
 func (platformInfo *PlatformInfo) supportsPSP() bool {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 func Test_createPlatformInfoFromAPIObjects(t *testing.T) {
@@ -141,6 +142,43 @@ func Test_getPDBFlag(t *testing.T) {
 	}
 }
 
+func Test_SupportsHPAV2(t *testing.T) {
+	tests := []struct {
+		name        string
+		versionInfo *version.Info
+		want        bool
+	}{
+		{
+			name:        "unknown version",
+			versionInfo: nil,
+			want:        false,
+		},
+		{
+			name:        "1.22",
+			versionInfo: &version.Info{GitVersion: "v1.22.9-eks-a64ea69"},
+			want:        false,
+		},
+		{
+			name:        "1.23",
+			versionInfo: &version.Info{GitVersion: "v1.23.0"},
+			want:        true,
+		},
+		{
+			name:        "1.25 pre-release",
+			versionInfo: &version.Info{GitVersion: "v1.25.0-rc.1"},
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platformInfo := NewPlatformInfoFromVersionMaps(tt.versionInfo, map[string]string{}, map[string]string{})
+			assert.Equal(t, tt.want, platformInfo.SupportsHPAV2())
+			assert.Equal(t, tt.want, containsObjectKind(platformInfo.GetAgentResourcesKind(false), HorizontalPodAutoscalersKind))
+		})
+	}
+}
+
 func Test_getDatadogAgentVersions(t *testing.T) {
 	tests := []struct {
 		name            string
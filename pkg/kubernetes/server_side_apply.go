@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// FieldManager is the field manager used by the operator when it applies resources with server-side apply.
+const FieldManager = "datadog-operator"
+
+// ApplyObject creates or updates an object with server-side apply, using FieldManager as field manager.
+// Only the fields set in `obj` are owned by the operator: the fields set by other controllers,
+// like the replicas of a Deployment managed by an HorizontalPodAutoscaler, are left untouched.
+// `current` is the object currently in the api-server, or nil if it doesn't exist yet. The fields
+// previously owned by the operator with regular updates, done by `updateManager` (see UpdateFieldManager),
+// are transferred to FieldManager first, so that the fields removed from `obj` are also removed
+// from the object in the api-server.
+func ApplyObject(ctx context.Context, c client.Client, obj client.Object, current client.Object, updateManager string) error {
+	if current != nil {
+		if err := migrateManagedFieldsToApply(ctx, c, current, updateManager); err != nil {
+			return err
+		}
+	}
+
+	applyObj, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unable to apply object %s/%s: not a client.Object", obj.GetNamespace(), obj.GetName())
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	applyObj.GetObjectKind().SetGroupVersionKind(gvk)
+	applyObj.SetResourceVersion("")
+	applyObj.SetManagedFields(nil)
+
+	return c.Patch(ctx, applyObj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// migrateManagedFieldsToApply transfers to FieldManager the fields owned by the operator with regular updates.
+func migrateManagedFieldsToApply(ctx context.Context, c client.Client, current client.Object, updateManager string) error {
+	managedFields, changed, err := upgradeManagedFields(current.GetManagedFields(), updateManager)
+	if err != nil || !changed {
+		return err
+	}
+
+	patchBase := client.MergeFromWithOptions(current.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	current.SetManagedFields(managedFields)
+	return c.Patch(ctx, current, patchBase)
+}
+
+// UpdateFieldManager returns the field manager used by the api-server for the regular updates
+// done by a client with the `userAgent` user agent: the prefix of the user agent. The default
+// user agent of client-go is used when `userAgent` is empty.
+func UpdateFieldManager(userAgent string) string {
+	if userAgent == "" {
+		userAgent = rest.DefaultKubernetesUserAgent()
+	}
+	return strings.Split(userAgent, "/")[0]
+}
+
+// upgradeManagedFields converts the managedFields entries of the regular updates done by `updateManager`
+// into a single Apply entry owned by FieldManager. It returns false if there is nothing to convert,
+// for instance if the object has already been applied by FieldManager.
+func upgradeManagedFields(entries []metav1.ManagedFieldsEntry, updateManager string) ([]metav1.ManagedFieldsEntry, bool, error) {
+	var updateEntries []metav1.ManagedFieldsEntry
+	var otherEntries []metav1.ManagedFieldsEntry
+	for _, entry := range entries {
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			// already migrated
+			return entries, false, nil
+		}
+		if entry.Manager == updateManager && entry.Operation == metav1.ManagedFieldsOperationUpdate && entry.Subresource == "" && entry.FieldsV1 != nil {
+			updateEntries = append(updateEntries, entry)
+			continue
+		}
+		otherEntries = append(otherEntries, entry)
+	}
+	if len(updateEntries) == 0 {
+		return entries, false, nil
+	}
+
+	// There is one entry per API version used to update the object, but only one Apply entry is allowed per manager.
+	fields := &fieldpath.Set{}
+	for _, entry := range updateEntries {
+		entryFields := &fieldpath.Set{}
+		if err := entryFields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, false, fmt.Errorf("unable to parse the managed fields of %s: %w", entry.Manager, err)
+		}
+		fields = fields.Union(entryFields)
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return nil, false, err
+	}
+
+	lastUpdate := updateEntries[len(updateEntries)-1]
+	applyEntry := metav1.ManagedFieldsEntry{
+		Manager:    FieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: lastUpdate.APIVersion,
+		Time:       lastUpdate.Time,
+		FieldsType: lastUpdate.FieldsType,
+		FieldsV1:   &metav1.FieldsV1{Raw: raw},
+	}
+	return append(otherEntries, applyEntry), true, nil
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_upgradeManagedFields(t *testing.T) {
+	// The operator user agent, set in main.go
+	updateManager := UpdateFieldManager("datadog-operator")
+	fieldsV1 := func(raw string) *metav1.FieldsV1 {
+		return &metav1.FieldsV1{Raw: []byte(raw)}
+	}
+	hpaEntry := metav1.ManagedFieldsEntry{
+		Manager:     "kube-controller-manager",
+		Operation:   metav1.ManagedFieldsOperationUpdate,
+		Subresource: "scale",
+		APIVersion:  "apps/v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    fieldsV1(`{"f:spec":{"f:replicas":{}}}`),
+	}
+
+	tests := []struct {
+		name        string
+		entries     []metav1.ManagedFieldsEntry
+		wantChanged bool
+		wantEntries []metav1.ManagedFieldsEntry
+	}{
+		{
+			name:    "no entry",
+			entries: nil,
+		},
+		{
+			name:    "no entry from the operator",
+			entries: []metav1.ManagedFieldsEntry{hpaEntry},
+		},
+		{
+			name: "already applied",
+			entries: []metav1.ManagedFieldsEntry{
+				{Manager: updateManager, Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "apps/v1", FieldsType: "FieldsV1", FieldsV1: fieldsV1(`{"f:spec":{"f:replicas":{}}}`)},
+				{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, APIVersion: "apps/v1", FieldsType: "FieldsV1", FieldsV1: fieldsV1(`{"f:spec":{"f:template":{}}}`)},
+			},
+		},
+		{
+			name: "update entries are merged into one apply entry",
+			entries: []metav1.ManagedFieldsEntry{
+				{Manager: updateManager, Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "apps/v1beta1", FieldsType: "FieldsV1", FieldsV1: fieldsV1(`{"f:spec":{"f:replicas":{}}}`)},
+				hpaEntry,
+				{Manager: updateManager, Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "apps/v1", FieldsType: "FieldsV1", FieldsV1: fieldsV1(`{"f:spec":{"f:template":{}}}`)},
+			},
+			wantChanged: true,
+			wantEntries: []metav1.ManagedFieldsEntry{
+				hpaEntry,
+				{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, APIVersion: "apps/v1", FieldsType: "FieldsV1", FieldsV1: fieldsV1(`{"f:spec":{"f:replicas":{},"f:template":{}}}`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := upgradeManagedFields(tt.entries, updateManager)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, changed)
+			if tt.wantChanged {
+				assert.Equal(t, tt.wantEntries, got)
+			} else {
+				assert.Equal(t, tt.entries, got)
+			}
+		})
+	}
+}
+
+func Test_UpdateFieldManager(t *testing.T) {
+	assert.Equal(t, "datadog-operator", UpdateFieldManager("datadog-operator"))
+	assert.Equal(t, "datadog-operator", UpdateFieldManager("datadog-operator/v1.0.0 (linux/amd64)"))
+	// client-go default user agent, from the binary name
+	assert.NotEmpty(t, UpdateFieldManager(""))
+}
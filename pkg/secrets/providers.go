@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultProviderTimeout = 5 * time.Second
+	defaultFileMaxSize     = 8192
+)
+
+// NewKubernetesSecretDecryptor returns a new KubernetesSecretDecryptor instance
+// If namespace is not empty, only the Secrets of this namespace can be read
+func NewKubernetesSecretDecryptor(reader client.Reader, namespace string) *KubernetesSecretDecryptor {
+	return &KubernetesSecretDecryptor{
+		reader:    reader,
+		namespace: namespace,
+		timeout:   defaultProviderTimeout,
+	}
+}
+
+// Decrypt reads the secrets from the key of a Kubernetes Secret, ENC[k8s:<namespace>/<name>/<key>]
+func (d *KubernetesSecretDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	return decryptWith(KubernetesSecretPrefix, encrypted, func(path string) (string, error) {
+		if d.reader == nil {
+			return "", errors.New("kubernetes client not configured")
+		}
+		parts := strings.SplitN(path, "/", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return "", fmt.Errorf("wrong format, want <namespace>/<name>/<key>, got: %s", path)
+		}
+		if d.namespace != "" && parts[0] != d.namespace {
+			return "", fmt.Errorf("secret %s/%s is not in the namespace %s", parts[0], parts[1], d.namespace)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		defer cancel()
+
+		secret := &corev1.Secret{}
+		if err := d.reader.Get(ctx, client.ObjectKey{Namespace: parts[0], Name: parts[1]}, secret); err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+				return "", err
+			}
+			return "", NewDecryptorError(err, true)
+		}
+		value, found := secret.Data[parts[2]]
+		if !found {
+			return "", fmt.Errorf("key %s not found in secret %s/%s", parts[2], parts[0], parts[1])
+		}
+		return string(value), nil
+	})
+}
+
+// NewFileDecryptor returns a new FileDecryptor instance
+func NewFileDecryptor() *FileDecryptor {
+	return &FileDecryptor{
+		maxSize: defaultFileMaxSize,
+	}
+}
+
+// Decrypt reads the secrets from the content of a file, ENC[file:<path>]
+func (d *FileDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	return decryptWith(FilePrefix, encrypted, func(path string) (string, error) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "", errors.New("secret does not exist")
+			}
+			return "", err
+		}
+		if fi.Size() > d.maxSize {
+			return "", errors.New("secret exceeds max allowed size")
+		}
+
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return string(value), nil
+	})
+}
+
+// NewEnvDecryptor returns a new EnvDecryptor instance
+func NewEnvDecryptor() *EnvDecryptor {
+	return &EnvDecryptor{}
+}
+
+// Decrypt reads the secrets from the environment variables of the operator, ENC[env:<name>]
+func (d *EnvDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	return decryptWith(EnvPrefix, encrypted, func(name string) (string, error) {
+		value, found := os.LookupEnv(name)
+		if !found {
+			return "", fmt.Errorf("environment variable %s not set", name)
+		}
+		return value, nil
+	})
+}
+
+// NewHTTPDecryptor returns a new HTTPDecryptor instance
+func NewHTTPDecryptor(options HTTPDecryptorOptions) *HTTPDecryptor {
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = defaultProviderTimeout
+	}
+	return &HTTPDecryptor{
+		options:       options,
+		client:        &http.Client{Timeout: timeout},
+		maxOutputSize: defaultCmdOutputMaxSize,
+	}
+}
+
+// Decrypt reads the secrets from the JSON documents served at <URL>/<path>, ENC[http:<path>#<field>]
+// The field is a dot-separated path in the JSON document. For instance, the api_key of the Vault KV version 2
+// secret datadog of the secret mount is read with ENC[http:secret/data/datadog#data.data.api_key], and the URL
+// of the Vault server API, https://vault:8200/v1. Without field, the response body is the secret.
+// The connection errors and the 429 and 5xx responses are retriable.
+func (d *HTTPDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	return decryptWith(HTTPPrefix, encrypted, func(handle string) (string, error) {
+		path, field, _ := strings.Cut(handle, "#")
+
+		req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(d.options.URL, "/")+"/"+strings.TrimPrefix(path, "/"), nil)
+		if err != nil {
+			return "", err
+		}
+		for name, value := range d.options.Headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return "", NewDecryptorError(err, true)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, d.maxOutputSize+1))
+		if err != nil {
+			return "", NewDecryptorError(err, true)
+		}
+		if int64(len(body)) > d.maxOutputSize {
+			return "", fmt.Errorf("response was too long: exceeded %d bytes", d.maxOutputSize)
+		}
+		if resp.StatusCode != http.StatusOK {
+			retriable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+			return "", NewDecryptorError(fmt.Errorf("unexpected status code %d", resp.StatusCode), retriable)
+		}
+
+		if field == "" {
+			return string(body), nil
+		}
+		var document interface{}
+		if err = json.Unmarshal(body, &document); err != nil {
+			return "", fmt.Errorf("failed to unmarshal json response: %w", err)
+		}
+		return jsonField(document, field)
+	})
+}
+
+// jsonField returns the string or number at the dot-separated path of a JSON document
+func jsonField(document interface{}, field string) (string, error) {
+	value := document
+	for _, key := range strings.Split(field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("field %s not found in the response", field)
+		}
+		if value, ok = object[key]; !ok {
+			return "", fmt.Errorf("field %s not found in the response", field)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64, bool:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("field %s is not a string", field)
+}
+
+func newRefusedDecryptor(reason string) *refusedDecryptor {
+	return &refusedDecryptor{
+		reason: reason,
+	}
+}
+
+// Decrypt returns a non retriable error for the first secret
+func (d *refusedDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	if len(encrypted) == 0 {
+		return map[string]string{}, nil
+	}
+	return nil, NewDecryptorError(fmt.Errorf("cannot decrypt '%s': %s", encrypted[0], d.reason), false)
+}
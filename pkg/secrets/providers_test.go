@@ -0,0 +1,228 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type decryptorTest struct {
+	name          string
+	encrypted     []string
+	want          map[string]string
+	wantErr       bool
+	wantRetriable bool
+}
+
+func runDecryptorTests(t *testing.T, decryptor Decryptor, tests []decryptorTest) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decryptor.Decrypt(tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if Retriable(err) != tt.wantRetriable {
+				t.Errorf("Decrypt() retriable = %v, want %v", Retriable(err), tt.wantRetriable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decrypt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubernetesSecretDecryptor_Decrypt(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "datadog-secret"},
+		Data:       map[string][]byte{"api-key": []byte("decrypted_api_key")},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	decryptor := NewKubernetesSecretDecryptor(reader, "")
+
+	runDecryptorTests(t, decryptor, []decryptorTest{
+		{
+			name:      "nominal case",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/api-key]"},
+			want:      map[string]string{"ENC[k8s:datadog/datadog-secret/api-key]": "decrypted_api_key"},
+		},
+		{
+			name:      "key not found",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/app-key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "secret not found",
+			encrypted: []string{"ENC[k8s:default/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "wrong format",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret]"},
+			wantErr:   true,
+		},
+	})
+
+	runDecryptorTests(t, NewKubernetesSecretDecryptor(nil, ""), []decryptorTest{
+		{
+			name:      "client not configured",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+	})
+
+	runDecryptorTests(t, NewKubernetesSecretDecryptor(reader, "datadog"), []decryptorTest{
+		{
+			name:      "secret in the namespace",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/api-key]"},
+			want:      map[string]string{"ENC[k8s:datadog/datadog-secret/api-key]": "decrypted_api_key"},
+		},
+		{
+			name:      "secret in another namespace",
+			encrypted: []string{"ENC[k8s:kube-system/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+	})
+}
+
+func TestFileDecryptor_Decrypt(t *testing.T) {
+	dir := t.TempDir()
+	apiKeyPath := filepath.Join(dir, "api_key")
+	if err := os.WriteFile(apiKeyPath, []byte("decrypted_api_key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tooLargePath := filepath.Join(dir, "too_large")
+	if err := os.WriteFile(tooLargePath, make([]byte, defaultFileMaxSize+1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runDecryptorTests(t, NewFileDecryptor(), []decryptorTest{
+		{
+			name:      "nominal case",
+			encrypted: []string{fmt.Sprintf("ENC[file:%s]", apiKeyPath)},
+			want:      map[string]string{fmt.Sprintf("ENC[file:%s]", apiKeyPath): "decrypted_api_key"},
+		},
+		{
+			name:      "file not found",
+			encrypted: []string{fmt.Sprintf("ENC[file:%s]", filepath.Join(dir, "app_key"))},
+			wantErr:   true,
+		},
+		{
+			name:      "file too large",
+			encrypted: []string{fmt.Sprintf("ENC[file:%s]", tooLargePath)},
+			wantErr:   true,
+		},
+	})
+}
+
+func TestEnvDecryptor_Decrypt(t *testing.T) {
+	t.Setenv("DD_TEST_API_KEY", "decrypted_api_key")
+	t.Setenv("DD_TEST_EMPTY", "")
+
+	runDecryptorTests(t, NewEnvDecryptor(), []decryptorTest{
+		{
+			name:      "nominal case",
+			encrypted: []string{"ENC[env:DD_TEST_API_KEY]"},
+			want:      map[string]string{"ENC[env:DD_TEST_API_KEY]": "decrypted_api_key"},
+		},
+		{
+			name:      "not set",
+			encrypted: []string{"ENC[env:DD_TEST_NOT_SET]"},
+			wantErr:   true,
+		},
+		{
+			name:      "empty",
+			encrypted: []string{"ENC[env:DD_TEST_EMPTY]"},
+			wantErr:   true,
+		},
+		{
+			name:      "wrong prefix",
+			encrypted: []string{"ENC[file:DD_TEST_API_KEY]"},
+			wantErr:   true,
+		},
+	})
+}
+
+func TestHTTPDecryptor_Decrypt(t *testing.T) {
+	// Stub of the Vault KV version 2 API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/datadog":
+			fmt.Fprint(w, `{"data": {"data": {"api_key": "decrypted_api_key", "app_key": "decrypted_app_key"}, "metadata": {"version": 3}}}`)
+		case "/v1/raw/api_key":
+			fmt.Fprint(w, "decrypted_api_key")
+		case "/v1/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	decryptor := NewHTTPDecryptor(HTTPDecryptorOptions{
+		URL:     server.URL + "/v1/",
+		Headers: map[string]string{"X-Vault-Token": "token"},
+	})
+	runDecryptorTests(t, decryptor, []decryptorTest{
+		{
+			name:      "vault kv secrets",
+			encrypted: []string{"ENC[http:secret/data/datadog#data.data.api_key]", "ENC[http:secret/data/datadog#data.data.app_key]"},
+			want: map[string]string{
+				"ENC[http:secret/data/datadog#data.data.api_key]": "decrypted_api_key",
+				"ENC[http:secret/data/datadog#data.data.app_key]": "decrypted_app_key",
+			},
+		},
+		{
+			name:      "raw response body",
+			encrypted: []string{"ENC[http:raw/api_key]"},
+			want:      map[string]string{"ENC[http:raw/api_key]": "decrypted_api_key"},
+		},
+		{
+			name:      "field not found",
+			encrypted: []string{"ENC[http:secret/data/datadog#data.api_key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "field not a string",
+			encrypted: []string{"ENC[http:secret/data/datadog#data.metadata]"},
+			wantErr:   true,
+		},
+		{
+			name:      "secret not found",
+			encrypted: []string{"ENC[http:secret/data/missing#data.data.api_key]"},
+			wantErr:   true,
+		},
+		{
+			name:          "server unavailable",
+			encrypted:     []string{"ENC[http:unavailable#data.data.api_key]"},
+			wantErr:       true,
+			wantRetriable: true,
+		},
+	})
+
+	runDecryptorTests(t, NewHTTPDecryptor(HTTPDecryptorOptions{URL: server.URL + "/v1"}), []decryptorTest{
+		{
+			name:      "missing token",
+			encrypted: []string{"ENC[http:secret/data/datadog#data.data.api_key]"},
+			wantErr:   true,
+		},
+	})
+}
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// KubernetesSecretPrefix is the handle prefix of the secrets read from Kubernetes Secrets
+	KubernetesSecretPrefix = "k8s"
+	// FilePrefix is the handle prefix of the secrets read from files
+	FilePrefix = "file"
+	// EnvPrefix is the handle prefix of the secrets read from environment variables
+	EnvPrefix = "env"
+	// HTTPPrefix is the handle prefix of the secrets read from an HTTP server
+	HTTPPrefix = "http"
+)
+
+// NewRegistry returns a new Registry instance
+// The secrets whose handle prefix isn't registered are decrypted by the fallback Decryptor, if not nil
+func NewRegistry(fallback Decryptor) *Registry {
+	return &Registry{
+		decryptors: map[string]Decryptor{},
+		fallback:   fallback,
+	}
+}
+
+// Register registers the Decryptor of the secrets whose handle starts with <prefix>:
+// The Decryptor gets the encrypted secrets unchanged, in the ENC[<prefix>:<path>] format
+func (r *Registry) Register(prefix string, decryptor Decryptor) {
+	r.decryptors[prefix] = decryptor
+}
+
+// Decrypt decrypts the secrets with the Decryptor registered for the prefix of their handle
+// The first error is returned, with the retry semantics of the Decryptor that returned it
+func (r *Registry) Decrypt(encrypted []string) (map[string]string, error) {
+	groups := map[string][]string{}
+	for _, secret := range encrypted {
+		handle, err := extractHandle(secret)
+		if err != nil {
+			return nil, NewDecryptorError(err, false)
+		}
+		prefix := handlePrefix(handle)
+		if _, found := r.decryptors[prefix]; !found {
+			if r.fallback == nil {
+				return nil, NewDecryptorError(fmt.Errorf("no secret provider registered for '%s'", handle), false)
+			}
+			prefix = ""
+		}
+		groups[prefix] = append(groups[prefix], secret)
+	}
+
+	prefixes := make([]string, 0, len(groups))
+	for prefix := range groups {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	decrypted := map[string]string{}
+	for _, prefix := range prefixes {
+		decryptor := r.fallback
+		if prefix != "" {
+			decryptor = r.decryptors[prefix]
+		}
+		values, err := decryptor.Decrypt(groups[prefix])
+		if err != nil {
+			return nil, err
+		}
+		for secret, value := range values {
+			decrypted[secret] = value
+		}
+	}
+
+	return decrypted, nil
+}
+
+// handlePrefix returns the provider prefix of a handle, <prefix>:<path>
+func handlePrefix(handle string) string {
+	prefix, _, found := strings.Cut(handle, ":")
+	if !found {
+		return ""
+	}
+	return prefix
+}
+
+// decryptWith decrypts the secrets of a provider by calling fetch with the path of each handle, ENC[<prefix>:<path>]
+// The errors returned by fetch that aren't a DecryptorError are not retriable
+func decryptWith(prefix string, encrypted []string, fetch func(path string) (string, error)) (map[string]string, error) {
+	decrypted := map[string]string{}
+	for _, secret := range encrypted {
+		handle, err := extractHandle(secret)
+		if err != nil {
+			return nil, NewDecryptorError(err, false)
+		}
+		path := strings.TrimPrefix(handle, prefix+":")
+		if path == handle || path == "" {
+			return nil, NewDecryptorError(fmt.Errorf("wrong format, want ENC[%s:<path>], got: %s", prefix, secret), false)
+		}
+
+		value, err := fetch(path)
+		if err != nil {
+			var decryptorErr *DecryptorError
+			if errors.As(err, &decryptorErr) {
+				return nil, NewDecryptorError(fmt.Errorf("an error occurred while decrypting '%s': %w", handle, decryptorErr.Unwrap()), decryptorErr.IsRetriable())
+			}
+			return nil, NewDecryptorError(fmt.Errorf("an error occurred while decrypting '%s': %w", handle, err), false)
+		}
+		if value == "" {
+			return nil, NewDecryptorError(fmt.Errorf("decrypted secret for '%s' is empty", handle), false)
+		}
+		decrypted[secret] = value
+	}
+
+	return decrypted, nil
+}
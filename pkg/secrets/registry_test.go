@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRegistry_Decrypt(t *testing.T) {
+	tests := []struct {
+		name          string
+		fallback      bool
+		encrypted     []string
+		want          map[string]string
+		wantErr       bool
+		wantRetriable bool
+	}{
+		{
+			name:      "dispatch by prefix",
+			fallback:  true,
+			encrypted: []string{"ENC[env:DD_TEST_API_KEY]", "ENC[app_key]"},
+			want: map[string]string{
+				"ENC[env:DD_TEST_API_KEY]": "decrypted_api_key",
+				"ENC[app_key]":             "DEC[ENC[app_key]]",
+			},
+		},
+		{
+			name:      "unknown prefix sent to the fallback",
+			fallback:  true,
+			encrypted: []string{"ENC[vault:secret/api_key]"},
+			want: map[string]string{
+				"ENC[vault:secret/api_key]": "DEC[ENC[vault:secret/api_key]]",
+			},
+		},
+		{
+			name:      "no fallback",
+			encrypted: []string{"ENC[app_key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "provider error",
+			fallback:  true,
+			encrypted: []string{"ENC[env:DD_TEST_NOT_SET]", "ENC[app_key]"},
+			wantErr:   true,
+		},
+		{
+			name:          "retriable fallback error",
+			fallback:      true,
+			encrypted:     []string{"ENC[env:DD_TEST_API_KEY]", "ENC[retry]"},
+			wantErr:       true,
+			wantRetriable: true,
+		},
+		{
+			name:      "wrong format",
+			fallback:  true,
+			encrypted: []string{"api_key"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DD_TEST_API_KEY", "decrypted_api_key")
+
+			var registry *Registry
+			if tt.fallback {
+				maxRetries := 0
+				if tt.wantRetriable {
+					maxRetries = 2
+				}
+				fallback := NewDummyDecryptor(maxRetries)
+				fallback.On("Decrypt", mock.Anything)
+				registry = NewRegistry(fallback)
+			} else {
+				registry = NewRegistry(nil)
+			}
+			registry.Register(EnvPrefix, NewEnvDecryptor())
+
+			got, err := registry.Decrypt(tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Registry.Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if Retriable(err) != tt.wantRetriable {
+				t.Errorf("Registry.Decrypt() retriable = %v, want %v", Retriable(err), tt.wantRetriable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Registry.Decrypt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,11 +14,16 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
 	secretBackendCommand = ""
 	secretBackendArgs    = []string{}
+	kubernetesReader     client.Reader
+	httpDecryptorOptions = HTTPDecryptorOptions{}
+	builtinProviders     = BuiltinProviders{}
 )
 
 const (
@@ -39,6 +44,57 @@ func SetSecretBackendArgs(args []string) {
 	secretBackendArgs = args
 }
 
+// SetKubernetesReader set the client used to read the Kubernetes Secrets
+func SetKubernetesReader(reader client.Reader) {
+	kubernetesReader = reader
+}
+
+// SetHTTPDecryptorOptions set the options of the HTTP provider, which is only registered if the URL is set
+func SetHTTPDecryptorOptions(options HTTPDecryptorOptions) {
+	httpDecryptorOptions = options
+}
+
+// SetBuiltinProviders set the built-in providers enabled by the operator flags
+func SetBuiltinProviders(providers BuiltinProviders) {
+	builtinProviders = providers
+}
+
+// NewDecryptor returns a new Registry instance with the enabled built-in providers, for the secrets of the operator configuration
+// The secrets without an enabled built-in provider prefix are decrypted by the secret backend command
+func NewDecryptor() Decryptor {
+	registry := NewRegistry(NewSecretBackend())
+	if builtinProviders.Kubernetes {
+		registry.Register(KubernetesSecretPrefix, NewKubernetesSecretDecryptor(kubernetesReader, ""))
+	}
+	if builtinProviders.File {
+		registry.Register(FilePrefix, NewFileDecryptor())
+	}
+	if builtinProviders.Env {
+		registry.Register(EnvPrefix, NewEnvDecryptor())
+	}
+	if httpDecryptorOptions.URL != "" {
+		registry.Register(HTTPPrefix, NewHTTPDecryptor(httpDecryptorOptions))
+	}
+	return registry
+}
+
+// NewNamespacedDecryptor returns a new Registry instance for the secrets of a custom resource of the namespace
+// The k8s handles can only read the Secrets of the namespace, and the other built-in providers are refused:
+// the files and environment of the operator, and the secrets the operator can read over HTTP, are not exposed
+// to the users allowed to create custom resources. The secrets without prefix are decrypted by the secret backend command
+func NewNamespacedDecryptor(namespace string) Decryptor {
+	registry := NewRegistry(NewSecretBackend())
+	if builtinProviders.Kubernetes {
+		registry.Register(KubernetesSecretPrefix, NewKubernetesSecretDecryptor(kubernetesReader, namespace))
+	} else {
+		registry.Register(KubernetesSecretPrefix, newRefusedDecryptor("the k8s secret provider is not enabled in the operator"))
+	}
+	registry.Register(FilePrefix, newRefusedDecryptor("the file handles are not allowed in a custom resource"))
+	registry.Register(EnvPrefix, newRefusedDecryptor("the env handles are not allowed in a custom resource"))
+	registry.Register(HTTPPrefix, newRefusedDecryptor("the http handles are not allowed in a custom resource"))
+	return registry
+}
+
 // NewSecretBackend returns a new SecretBackend instance
 func NewSecretBackend() *SecretBackend {
 	return &SecretBackend{
@@ -8,6 +8,11 @@ package secrets
 import (
 	"reflect"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestSecretBackend_execCommand(t *testing.T) {
@@ -183,3 +188,77 @@ func TestSecretBackend_fetchSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDecryptor(t *testing.T) {
+	t.Setenv("DD_TEST_API_KEY", "decrypted_api_key")
+	defer SetBuiltinProviders(BuiltinProviders{})
+
+	// The disabled providers fall back to the secret backend command, which isn't configured
+	SetBuiltinProviders(BuiltinProviders{})
+	runDecryptorTests(t, NewDecryptor(), []decryptorTest{
+		{
+			name:      "env provider disabled",
+			encrypted: []string{"ENC[env:DD_TEST_API_KEY]"},
+			wantErr:   true,
+		},
+	})
+
+	SetBuiltinProviders(BuiltinProviders{Env: true})
+	runDecryptorTests(t, NewDecryptor(), []decryptorTest{
+		{
+			name:      "env provider enabled",
+			encrypted: []string{"ENC[env:DD_TEST_API_KEY]"},
+			want:      map[string]string{"ENC[env:DD_TEST_API_KEY]": "decrypted_api_key"},
+		},
+	})
+}
+
+func TestNewNamespacedDecryptor(t *testing.T) {
+	t.Setenv("DD_TEST_API_KEY", "decrypted_api_key")
+	defer SetBuiltinProviders(BuiltinProviders{})
+	defer SetKubernetesReader(nil)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "datadog-secret"},
+		Data:       map[string][]byte{"api-key": []byte("decrypted_api_key")},
+	}
+	SetKubernetesReader(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build())
+
+	SetBuiltinProviders(BuiltinProviders{})
+	runDecryptorTests(t, NewNamespacedDecryptor("datadog"), []decryptorTest{
+		{
+			name:      "k8s provider disabled",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+	})
+
+	SetBuiltinProviders(BuiltinProviders{Kubernetes: true, File: true, Env: true})
+	runDecryptorTests(t, NewNamespacedDecryptor("datadog"), []decryptorTest{
+		{
+			name:      "secret in the namespace",
+			encrypted: []string{"ENC[k8s:datadog/datadog-secret/api-key]"},
+			want:      map[string]string{"ENC[k8s:datadog/datadog-secret/api-key]": "decrypted_api_key"},
+		},
+		{
+			name:      "secret in another namespace",
+			encrypted: []string{"ENC[k8s:kube-system/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "env refused",
+			encrypted: []string{"ENC[env:DD_TEST_API_KEY]"},
+			wantErr:   true,
+		},
+		{
+			name:      "file refused",
+			encrypted: []string{"ENC[file:/etc/passwd]"},
+			wantErr:   true,
+		},
+		{
+			name:      "http refused",
+			encrypted: []string{"ENC[http:secret/data/datadog#data.data.api_key]"},
+			wantErr:   true,
+		},
+	})
+}
@@ -8,9 +8,11 @@ package secrets
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/stretchr/testify/mock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // DecryptorError describes the error returned by a Decryptor
@@ -53,7 +55,7 @@ func Retriable(err error) bool {
 }
 
 // Decryptor is used to decrypt encrypted secrets
-// Decryptor is implemented by SecretBackend
+// Decryptor is implemented by SecretBackend, Registry and the built-in providers
 type Decryptor interface {
 	Decrypt([]string) (map[string]string, error)
 }
@@ -67,6 +69,68 @@ type SecretBackend struct {
 	cmdTimeout       time.Duration
 }
 
+// Registry dispatches the secrets to the Decryptor registered for the prefix of their handle
+// Registry implements the Decryptor interface
+type Registry struct {
+	decryptors map[string]Decryptor
+	fallback   Decryptor
+}
+
+// BuiltinProviders enables the built-in providers reading the secrets with the operator permissions, which are disabled by default
+// The http provider is enabled by its URL, see HTTPDecryptorOptions
+type BuiltinProviders struct {
+	// Kubernetes enables the k8s provider
+	Kubernetes bool
+	// File enables the file provider
+	File bool
+	// Env enables the env provider
+	Env bool
+}
+
+// KubernetesSecretDecryptor reads secrets from Kubernetes Secrets, ENC[k8s:<namespace>/<name>/<key>]
+// KubernetesSecretDecryptor implements the Decryptor interface
+type KubernetesSecretDecryptor struct {
+	reader client.Reader
+	// namespace restricts the Secrets that can be read, if not empty
+	namespace string
+	timeout   time.Duration
+}
+
+// FileDecryptor reads secrets from files, ENC[file:<path>]
+// FileDecryptor implements the Decryptor interface
+type FileDecryptor struct {
+	maxSize int64
+}
+
+// EnvDecryptor reads secrets from environment variables, ENC[env:<name>]
+// EnvDecryptor implements the Decryptor interface
+type EnvDecryptor struct{}
+
+// HTTPDecryptorOptions configures the HTTPDecryptor
+type HTTPDecryptorOptions struct {
+	// URL is the base URL of the secrets, the handle path is appended to it
+	URL string
+	// Headers are added to every request, for instance the Vault token
+	Headers map[string]string
+	// Timeout of each request
+	Timeout time.Duration
+}
+
+// HTTPDecryptor reads secrets from JSON documents served over HTTP, ENC[http:<path>#<field>]
+// HTTPDecryptor is compatible with the Vault KV secrets engine
+// HTTPDecryptor implements the Decryptor interface
+type HTTPDecryptor struct {
+	options       HTTPDecryptorOptions
+	client        *http.Client
+	maxOutputSize int64
+}
+
+// refusedDecryptor refuses the secrets of a provider that can't be used
+// refusedDecryptor implements the Decryptor interface
+type refusedDecryptor struct {
+	reason string
+}
+
 // Secret defines the structure for secrets in JSON output
 type Secret struct {
 	Value    string `json:"value,omitempty"`